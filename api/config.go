@@ -1,7 +1,37 @@
 package api
 
+// Authentication providers of api requests
+const (
+	AuthProviderHeader = "header"
+	AuthProviderJWT    = "jwt"
+	AuthProviderToken  = "token"
+)
+
 // Config for api configuration variables
 type Config struct {
-	EnableCORS bool
-	Listen     string
+	EnableCORS             bool
+	Listen                 string
+	SlackVerificationToken string
+	Auth                   AuthConfig
+}
+
+// AuthConfig selects providers authenticating api requests, providers are tried in given order
+type AuthConfig struct {
+	Providers []string
+	// Header with user login trusted by header provider
+	Header string
+	JWT    JWTConfig
+	// AllowAnonymous lets requests without credentials in with empty user login
+	AllowAnonymous bool
+	// Admins are logins of users permitted to manage notifications, patterns and tags of all users
+	Admins []string
+}
+
+// JWTConfig for validation of JWT bearer tokens issued by OIDC provider
+type JWTConfig struct {
+	JWKSFile   string
+	KeyFiles   []string
+	Issuer     string
+	Audience   string
+	LoginClaim string
 }
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetAuditRecords gets configuration changes made in given interval, empty entity or user matches all records.
+// Changes of team owned objects are returned only to team members
+func GetAuditRecords(dataBase moira.Database, entity, user string, from, to int64, userLogin string) (*dto.AuditRecordsList, *api.ErrorResponse) {
+	records, err := dataBase.GetAuditRecords(from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	teamIDs, err := dataBase.GetUserTeamIDs(userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	userTeams := make(map[string]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		userTeams[teamID] = true
+	}
+	triggerTeams := make(map[string]string)
+	filtered := make([]*moira.AuditRecord, 0, len(records))
+	for _, record := range records {
+		if (entity != "" && record.Entity != entity) || (user != "" && record.User != user) {
+			continue
+		}
+		recordTeamIDs, err := getAuditRecordTeamIDs(dataBase, record, triggerTeams)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if isAuditRecordVisible(recordTeamIDs, userTeams) {
+			filtered = append(filtered, record)
+		}
+	}
+	return &dto.AuditRecordsList{List: filtered}, nil
+}
+
+// getAuditRecordTeamIDs returns teams owning changed object before and after change. Maintenance, throttling
+// and acknowledgement states have no team, so current team of their trigger is taken, triggerTeams caches it by trigger ID
+func getAuditRecordTeamIDs(dataBase moira.Database, record *moira.AuditRecord, triggerTeams map[string]string) ([]string, error) {
+	switch record.Entity {
+	case moira.AuditEntityTrigger, moira.AuditEntityContact, moira.AuditEntitySubscription:
+		teamIDs := make([]string, 0, 2)
+		for _, state := range []json.RawMessage{record.Before, record.After} {
+			if state == nil {
+				continue
+			}
+			var owner struct {
+				TeamID string `json:"team_id"`
+			}
+			if err := json.Unmarshal(state, &owner); err != nil {
+				return nil, fmt.Errorf("Failed to parse audit state of %s %s: %s", record.Entity, record.EntityID, err.Error())
+			}
+			teamIDs = append(teamIDs, owner.TeamID)
+		}
+		return teamIDs, nil
+	case moira.AuditEntityMaintenance, moira.AuditEntityThrottling, moira.AuditEntityAcknowledgement:
+		teamID, ok := triggerTeams[record.EntityID]
+		if !ok {
+			trigger, err := dataBase.GetTrigger(record.EntityID)
+			if err != nil && err != database.ErrNil {
+				return nil, err
+			}
+			teamID = trigger.TeamID
+			triggerTeams[record.EntityID] = teamID
+		}
+		return []string{teamID}, nil
+	}
+	return nil, nil
+}
+
+func isAuditRecordVisible(teamIDs []string, userTeams map[string]bool) bool {
+	for _, teamID := range teamIDs {
+		if teamID != "" && !userTeams[teamID] {
+			return false
+		}
+	}
+	return true
+}
+
+// auditLogger logs audit records failed to be saved, it is set by SetAuditLogger
+var auditLogger moira.Logger
+
+// SetAuditLogger sets logger for audit records failed to be saved
+func SetAuditLogger(logger moira.Logger) {
+	auditLogger = logger
+}
+
+// addAuditRecord records change of entity made by user, see moira.NewAuditRecord. Change is already saved
+// when it is recorded, so failure to record it is logged and is not reported to user
+func addAuditRecord(dataBase moira.Database, userLogin, entity, entityID string, before, after interface{}) {
+	record, err := moira.NewAuditRecord(userLogin, entity, entityID, before, after, time.Now().Unix())
+	if err == nil {
+		err = dataBase.AddAuditRecord(record)
+	}
+	if err != nil && auditLogger != nil {
+		auditLogger.Errorf("Failed to add audit record of %s %s changed by %s: %s", entity, entityID, userLogin, err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestGetAuditRecords(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	records := []*moira.AuditRecord{
+		{ID: "1", User: "user1", Entity: moira.AuditEntityTrigger},
+		{ID: "2", User: "user2", Entity: moira.AuditEntityTrigger},
+		{ID: "3", User: "user1", Entity: moira.AuditEntityContact},
+	}
+
+	Convey("Filter by entity and user", t, func() {
+		dataBase.EXPECT().GetAuditRecords(int64(0), int64(100)).Return(records, nil).Times(4)
+		dataBase.EXPECT().GetUserTeamIDs("caller").Return([]string{}, nil).Times(4)
+
+		actual, err := GetAuditRecords(dataBase, "", "", 0, 100, "caller")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.AuditRecordsList{List: records})
+
+		actual, err = GetAuditRecords(dataBase, moira.AuditEntityTrigger, "", 0, 100, "caller")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.AuditRecordsList{List: records[:2]})
+
+		actual, err = GetAuditRecords(dataBase, "", "user1", 0, 100, "caller")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.AuditRecordsList{List: []*moira.AuditRecord{records[0], records[2]}})
+
+		actual, err = GetAuditRecords(dataBase, moira.AuditEntityContact, "user2", 0, 100, "caller")
+		So(err, ShouldBeNil)
+		So(actual.List, ShouldBeEmpty)
+	})
+
+	Convey("Changes of team objects are visible to team members only", t, func() {
+		teamRecords := []*moira.AuditRecord{
+			{ID: "1", Entity: moira.AuditEntityTrigger, EntityID: "t1", After: json.RawMessage(`{"id":"t1","team_id":"team1"}`)},
+			{ID: "2", Entity: moira.AuditEntityTrigger, EntityID: "t2", After: json.RawMessage(`{"id":"t2","team_id":"team2"}`)},
+			{ID: "3", Entity: moira.AuditEntityContact, EntityID: "c1", Before: json.RawMessage(`{"id":"c1","team_id":"team2"}`)},
+			{ID: "4", Entity: moira.AuditEntitySubscription, EntityID: "s1", Before: json.RawMessage(`{"id":"s1","team_id":"team2"}`), After: json.RawMessage(`{"id":"s1"}`)},
+			{ID: "5", Entity: moira.AuditEntityMaintenance, EntityID: "t2", After: json.RawMessage(`{"m1":100}`)},
+			{ID: "6", Entity: moira.AuditEntityThrottling, EntityID: "t2", Before: json.RawMessage(`100`)},
+			{ID: "7", Entity: moira.AuditEntityTag, EntityID: "tag", Before: json.RawMessage(`"tag"`)},
+			{ID: "8", Entity: moira.AuditEntityTrigger, EntityID: "t3", After: json.RawMessage(`{"id":"t3"}`)},
+		}
+		dataBase.EXPECT().GetAuditRecords(int64(0), int64(100)).Return(teamRecords, nil)
+		dataBase.EXPECT().GetUserTeamIDs("caller").Return([]string{"team1"}, nil)
+		dataBase.EXPECT().GetTrigger("t2").Return(moira.Trigger{ID: "t2", TeamID: "team2"}, nil)
+
+		actual, err := GetAuditRecords(dataBase, "", "", 0, 100, "caller")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.AuditRecordsList{List: []*moira.AuditRecord{teamRecords[0], teamRecords[6], teamRecords[7]}})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not read audit records")
+		dataBase.EXPECT().GetAuditRecords(int64(0), int64(100)).Return(nil, expected)
+		actual, err := GetAuditRecords(dataBase, "", "", 0, 100, "caller")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestAddAuditRecord(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	trigger := &moira.Trigger{ID: "trigger"}
+	triggerJSON, _ := json.Marshal(trigger)
+
+	Convey("Record is saved", t, func() {
+		var saved *moira.AuditRecord
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) { saved = record }).Return(nil)
+		addAuditRecord(dataBase, "user", moira.AuditEntityTrigger, "trigger", nil, trigger)
+		So(saved.Action, ShouldEqual, moira.AuditActionCreate)
+		So(string(saved.After), ShouldEqual, string(triggerJSON))
+		So(saved.User, ShouldEqual, "user")
+		So(saved.Timestamp, ShouldNotEqual, 0)
+	})
+
+	Convey("Error is logged and not returned", t, func() {
+		logger, _ := logging.GetLogger("Test")
+		SetAuditLogger(logger)
+		defer SetAuditLogger(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(fmt.Errorf("Oooops! Can not write audit record"))
+		addAuditRecord(dataBase, "user", moira.AuditEntityTag, "tag", "tag", nil)
+	})
+}
@@ -0,0 +1,466 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// configChange is planned change with entity states needed to apply it
+type configChange struct {
+	dto.ConfigChange
+	before interface{}
+	after  interface{}
+}
+
+// ExportConfig gets triggers user can edit and subscriptions of user and user teams having all given tags
+func ExportConfig(dataBase moira.Database, tags []string, userLogin string) (*dto.Config, *api.ErrorResponse) {
+	teams, err := getUserTeams(dataBase, userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	triggers, err := getConfigTriggers(dataBase, tags, userLogin, teams)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	subscriptions, err := getConfigSubscriptions(dataBase, tags, userLogin, teams)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	config := &dto.Config{
+		Triggers:      make([]dto.TriggerModel, 0, len(triggers)),
+		Subscriptions: subscriptions,
+	}
+	for _, trigger := range triggers {
+		config.Triggers = append(config.Triggers, dto.CreateTriggerModel(trigger))
+	}
+	return config, nil
+}
+
+// PlanConfig compares configuration with saved triggers and subscriptions having all given tags and returns changes
+// needed to apply it. Saved entities user can edit and missing in configuration are planned for deletion,
+// so tags must be given to limit configuration scope
+func PlanConfig(dataBase moira.Database, config *dto.Config, tags []string, userLogin string) (*dto.ConfigPlan, *api.ErrorResponse) {
+	plan, _, errorResponse := planConfig(dataBase, config, tags, userLogin)
+	return plan, errorResponse
+}
+
+// ApplyConfig applies configuration if its plan has given checksum. All changes are checked before any of them is applied,
+// so invalid trigger does not leave configuration applied partially. Trigger metrics are cleaned up
+// by time series names of triggers, triggers without known time series names keep their metrics
+func ApplyConfig(dataBase moira.Database, config *dto.Config, tags []string, checksum string, timeSeriesNames map[string]map[string]bool, userLogin string) (*dto.ConfigPlan, *api.ErrorResponse) {
+	plan, changes, errorResponse := planConfig(dataBase, config, tags, userLogin)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	if plan.Checksum != checksum {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("Plan checksum mismatch, configuration or saved entities have changed since plan was reviewed"))
+	}
+	if errorResponse := checkConfigChanges(dataBase, changes); errorResponse != nil {
+		return nil, errorResponse
+	}
+	for _, change := range changes {
+		if errorResponse := applyConfigChange(dataBase, change, timeSeriesNames[change.ID], userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+	}
+	return plan, nil
+}
+
+// checkConfigChanges checks aggregate and inhibition cycles like trigger save does, but on saved triggers merged
+// with all configuration changes, so cycle made by several configured triggers is found before any of them is saved.
+// Subscriptions are checked by plan
+func checkConfigChanges(dataBase moira.Database, changes []*configChange) *api.ErrorResponse {
+	hasSavedTriggers := false
+	for _, change := range changes {
+		if change.Entity == moira.AuditEntityTrigger && change.Action != moira.AuditActionDelete {
+			hasSavedTriggers = true
+		}
+	}
+	if !hasSavedTriggers {
+		return nil
+	}
+	triggers, err := getConfigMergedTriggers(dataBase, changes)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for _, change := range changes {
+		if change.Entity != moira.AuditEntityTrigger || change.Action == moira.AuditActionDelete {
+			continue
+		}
+		var saved *moira.Trigger
+		for _, trigger := range triggers {
+			if trigger.ID == change.ID {
+				saved = trigger
+				break
+			}
+		}
+		if saved.Aggregate != nil {
+			if cycle := findConfigAggregateCycle(saved, saved, triggers, make(map[string]bool)); cycle != nil {
+				return api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger dependencies contain cycle: %s", strings.Join(cycle, " -> ")))
+			}
+		}
+		if saved.Inhibits != nil {
+			parents := make([]*moira.Trigger, 0)
+			for _, trigger := range triggers {
+				if trigger.Inhibits != nil && trigger.ID != saved.ID {
+					parents = append(parents, trigger)
+				}
+			}
+			if cycle := findInhibitionCycle(saved, saved, parents, make(map[string]bool)); cycle != nil {
+				return api.ErrorInvalidRequest(fmt.Errorf("Trigger inhibition contains cycle: %s", strings.Join(cycle, " -> ")))
+			}
+		}
+	}
+	return nil
+}
+
+// getConfigMergedTriggers returns saved triggers as they will be after configuration changes are applied
+func getConfigMergedTriggers(dataBase moira.Database, changes []*configChange) ([]*moira.Trigger, error) {
+	triggerIDs, err := dataBase.GetTriggerIDs()
+	if err != nil {
+		return nil, err
+	}
+	saved, err := dataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]*configChange, len(changes))
+	for _, change := range changes {
+		if change.Entity == moira.AuditEntityTrigger {
+			changed[change.ID] = change
+		}
+	}
+	triggers := make([]*moira.Trigger, 0, len(saved)+len(changed))
+	for _, trigger := range saved {
+		if trigger != nil && changed[trigger.ID] == nil {
+			triggers = append(triggers, trigger)
+		}
+	}
+	for _, change := range changes {
+		if change.Entity != moira.AuditEntityTrigger || change.Action == moira.AuditActionDelete {
+			continue
+		}
+		trigger := *change.after.(*moira.Trigger)
+		trigger.ID = change.ID
+		triggers = append(triggers, &trigger)
+	}
+	return triggers, nil
+}
+
+// findConfigAggregateCycle walks inputs of current aggregate trigger among given triggers and returns path to saved trigger if any
+func findConfigAggregateCycle(saved, current *moira.Trigger, triggers []*moira.Trigger, visited map[string]bool) []string {
+	if current.Aggregate.IsDependentOn(saved) {
+		return []string{current.ID, saved.ID}
+	}
+	for _, input := range triggers {
+		if input.ID == saved.ID || visited[input.ID] || input.Aggregate == nil || !current.Aggregate.IsDependentOn(input) {
+			continue
+		}
+		visited[input.ID] = true
+		if cycle := findConfigAggregateCycle(saved, input, triggers, visited); cycle != nil {
+			return append([]string{current.ID}, cycle...)
+		}
+	}
+	return nil
+}
+
+func applyConfigChange(dataBase moira.Database, change *configChange, timeSeriesNames map[string]bool, userLogin string) *api.ErrorResponse {
+	switch change.Entity {
+	case moira.AuditEntityTrigger:
+		if change.Action == moira.AuditActionDelete {
+			return RemoveTrigger(dataBase, change.ID, userLogin)
+		}
+		// dependencies are checked by checkConfigChanges on triggers with all changes applied,
+		// partially applied configuration may have cycles which are gone when it is applied completely
+		existing, _ := change.before.(*moira.Trigger)
+		_, errorResponse := saveCheckedTrigger(dataBase, existing, change.after.(*moira.Trigger), change.ID, timeSeriesNames, userLogin)
+		return errorResponse
+	case moira.AuditEntitySubscription:
+		if change.Action == moira.AuditActionDelete {
+			return RemoveSubscription(dataBase, *change.before.(*moira.SubscriptionData), userLogin)
+		}
+		subscription := change.after.(*moira.SubscriptionData)
+		if err := dataBase.SaveSubscription(subscription); err != nil {
+			return api.ErrorInternalServer(err)
+		}
+		addAuditRecord(dataBase, userLogin, moira.AuditEntitySubscription, change.ID, change.before, subscription)
+		return nil
+	}
+	return nil
+}
+
+// planConfig returns plan and changes ordered for applying: saved and new triggers go first, so subscriptions
+// and aggregate triggers can refer them, and deletions go last
+func planConfig(dataBase moira.Database, config *dto.Config, tags []string, userLogin string) (*dto.ConfigPlan, []*configChange, *api.ErrorResponse) {
+	if len(tags) == 0 {
+		return nil, nil, api.ErrorInvalidRequest(fmt.Errorf("Configuration tags must be set, saved triggers and subscriptions having them are deleted if missing in configuration"))
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, api.ErrorInvalidRequest(err)
+	}
+	teams, err := getUserTeams(dataBase, userLogin)
+	if err != nil {
+		return nil, nil, api.ErrorInternalServer(err)
+	}
+	triggerChanges, errorResponse := planConfigTriggers(dataBase, config.Triggers, tags, userLogin, teams)
+	if errorResponse != nil {
+		return nil, nil, errorResponse
+	}
+	subscriptionChanges, errorResponse := planConfigSubscriptions(dataBase, config.Subscriptions, tags, userLogin, teams)
+	if errorResponse != nil {
+		return nil, nil, errorResponse
+	}
+
+	changes := make([]*configChange, 0, len(triggerChanges)+len(subscriptionChanges))
+	changes = appendConfigChanges(changes, triggerChanges, false)
+	changes = appendConfigChanges(changes, subscriptionChanges, false)
+	changes = appendConfigChanges(changes, subscriptionChanges, true)
+	changes = appendConfigChanges(changes, triggerChanges, true)
+
+	plan := &dto.ConfigPlan{Changes: make([]dto.ConfigChange, 0, len(changes))}
+	for _, change := range changes {
+		plan.Changes = append(plan.Changes, change.ConfigChange)
+	}
+	bytes, err := json.Marshal(plan.Changes)
+	if err != nil {
+		return nil, nil, api.ErrorInternalServer(err)
+	}
+	hash := sha256.Sum256(bytes)
+	plan.Checksum = hex.EncodeToString(hash[:])
+	return plan, changes, nil
+}
+
+func appendConfigChanges(changes []*configChange, entityChanges []*configChange, deletions bool) []*configChange {
+	for _, change := range entityChanges {
+		if (change.Action == moira.AuditActionDelete) == deletions {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func planConfigTriggers(dataBase moira.Database, triggers []dto.TriggerModel, tags []string, userLogin string, teams []moira.Team) ([]*configChange, *api.ErrorResponse) {
+	saved, err := getConfigTriggers(dataBase, tags, userLogin, teams)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	configured := make(map[string]bool, len(triggers))
+	changes := make([]*configChange, 0)
+	for i := range triggers {
+		trigger := &triggers[i]
+		configured[trigger.ID] = true
+		if !hasAllTags(trigger.Tags, tags) {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Trigger %s does not have all tags %v", trigger.ID, tags))
+		}
+		var existing *moira.Trigger
+		existingTrigger, err := dataBase.GetTrigger(trigger.ID)
+		if err != nil && err != database.ErrNil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if err == nil {
+			existing = &existingTrigger
+			if errorResponse := CheckUserPermissionsForTeamObject(dataBase, existing.TeamID, userLogin); errorResponse != nil {
+				return nil, errorResponse
+			}
+		}
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, trigger.TeamID, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+		var before interface{}
+		if existing != nil {
+			before = dto.CreateTriggerModel(existing)
+		}
+		change, err := newConfigChange(moira.AuditEntityTrigger, trigger.ID, before, trigger)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if change != nil {
+			change.before, change.after = existing, trigger.ToMoiraTrigger()
+			changes = append(changes, change)
+		}
+	}
+	for _, trigger := range saved {
+		if configured[trigger.ID] {
+			continue
+		}
+		change, err := newConfigChange(moira.AuditEntityTrigger, trigger.ID, dto.CreateTriggerModel(trigger), nil)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		change.before = trigger
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func planConfigSubscriptions(dataBase moira.Database, subscriptions []moira.SubscriptionData, tags []string, userLogin string, teams []moira.Team) ([]*configChange, *api.ErrorResponse) {
+	saved, err := getConfigSubscriptions(dataBase, tags, userLogin, teams)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	configured := make(map[string]bool, len(subscriptions))
+	changes := make([]*configChange, 0)
+	for i := range subscriptions {
+		subscription := subscriptions[i]
+		configured[subscription.ID] = true
+		if !hasAllTags(subscription.Tags, tags) {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Subscription %s does not have all tags %v", subscription.ID, tags))
+		}
+		var existing *moira.SubscriptionData
+		exists, err := isSubscriptionExists(dataBase, subscription.ID)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if exists {
+			existingSubscription, errorResponse := CheckUserPermissionsForSubscription(dataBase, subscription.ID, userLogin)
+			if errorResponse != nil {
+				return nil, errorResponse
+			}
+			existing = &existingSubscription
+			// subscription keeps its owner like in subscription update
+			subscription.User = existing.User
+		} else {
+			subscription.User = userLogin
+		}
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, subscription.TeamID, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+		var before interface{}
+		if existing != nil {
+			before = existing
+		}
+		change, err := newConfigChange(moira.AuditEntitySubscription, subscription.ID, before, subscription)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if change != nil {
+			if errorResponse := checkConfigSubscriptionContacts(dataBase, &subscription); errorResponse != nil {
+				return nil, errorResponse
+			}
+			change.before, change.after = before, &subscription
+			changes = append(changes, change)
+		}
+	}
+	for i := range saved {
+		subscription := saved[i]
+		if configured[subscription.ID] {
+			continue
+		}
+		change, err := newConfigChange(moira.AuditEntitySubscription, subscription.ID, subscription, nil)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		change.before = &subscription
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// checkConfigSubscriptionContacts checks that created or updated subscription refers existing contacts,
+// contacts are not part of configuration and must be created before it is applied
+func checkConfigSubscriptionContacts(dataBase moira.Database, subscription *moira.SubscriptionData) *api.ErrorResponse {
+	for _, contactID := range subscription.Contacts {
+		exists, err := isContactExists(dataBase, contactID)
+		if err != nil {
+			return api.ErrorInternalServer(err)
+		}
+		if !exists {
+			return api.ErrorInvalidRequest(fmt.Errorf("Subscription %s has unknown contact %s", subscription.ID, contactID))
+		}
+	}
+	return nil
+}
+
+// newConfigChange returns change of entity from before to after state, nil state means that entity does not exist.
+// Unchanged entity has no change
+func newConfigChange(entity, id string, before, after interface{}) (*configChange, error) {
+	change := &configChange{ConfigChange: dto.ConfigChange{Entity: entity, ID: id}}
+	switch {
+	case before == nil:
+		change.Action = moira.AuditActionCreate
+		before = struct{}{}
+	case after == nil:
+		change.Action = moira.AuditActionDelete
+		after = struct{}{}
+	default:
+		change.Action = moira.AuditActionUpdate
+	}
+	var err error
+	if change.Changes, err = getFieldChanges(before, after); err != nil {
+		return nil, err
+	}
+	if change.Action == moira.AuditActionUpdate && len(change.Changes) == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// getConfigTriggers returns triggers having all given tags which user can edit: triggers without team
+// and triggers of given user teams where user is editor
+func getConfigTriggers(dataBase moira.Database, tags []string, userLogin string, teams []moira.Team) ([]*moira.Trigger, error) {
+	triggerIDs, err := dataBase.GetTriggerCheckIDs(tags, false)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := dataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	editableTeams := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		editableTeams[team.ID] = team.HasRole(userLogin, moira.TeamRoleEditor)
+	}
+	result := make([]*moira.Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		if trigger != nil && (trigger.TeamID == "" || editableTeams[trigger.TeamID]) {
+			result = append(result, trigger)
+		}
+	}
+	return result, nil
+}
+
+// getConfigSubscriptions returns subscriptions having all given tags which user can edit: personal subscriptions
+// and subscriptions of given user teams where user is editor
+func getConfigSubscriptions(dataBase moira.Database, tags []string, userLogin string, teams []moira.Team) ([]moira.SubscriptionData, error) {
+	editableTeams := make([]moira.Team, 0, len(teams))
+	for _, team := range teams {
+		if team.HasRole(userLogin, moira.TeamRoleEditor) {
+			editableTeams = append(editableTeams, team)
+		}
+	}
+	subscriptions, err := getUserSubscriptions(dataBase, userLogin, editableTeams)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]moira.SubscriptionData, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if hasAllTags(subscription.Tags, tags) {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func hasAllTags(entityTags []string, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, entityTag := range entityTags {
+			if entityTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
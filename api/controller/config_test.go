@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestExportConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	trigger := &moira.Trigger{ID: "trigger", Name: "trigger", Tags: []string{"service"}, Targets: []string{"my.metric"}}
+	subscriptions := []*moira.SubscriptionData{
+		{ID: "subscription1", User: "user", Tags: []string{"service", "other"}, Contacts: []string{"contact"}},
+		{ID: "subscription2", User: "user", Tags: []string{"other"}, Contacts: []string{"contact"}},
+	}
+
+	Convey("Export triggers and user subscriptions having tags", t, func() {
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"trigger", "removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"trigger", "removed"}).Return([]*moira.Trigger{trigger, nil}, nil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{"subscription1", "subscription2"}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{"subscription1", "subscription2"}).Return(subscriptions, nil)
+		config, err := ExportConfig(dataBase, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+		So(config, ShouldResemble, &dto.Config{
+			Triggers:      []dto.TriggerModel{dto.CreateTriggerModel(trigger)},
+			Subscriptions: []moira.SubscriptionData{*subscriptions[0]},
+		})
+	})
+
+	Convey("Export only team triggers user can edit", t, func() {
+		teams := map[string]moira.Team{
+			"viewed": {ID: "viewed", Members: map[string]string{"user": moira.TeamRoleViewer}},
+			"edited": {ID: "edited", Members: map[string]string{"user": moira.TeamRoleEditor}},
+		}
+		viewed := &moira.Trigger{ID: "viewed", Tags: []string{"service"}, TeamID: "viewed"}
+		edited := &moira.Trigger{ID: "edited", Tags: []string{"service"}, TeamID: "edited"}
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"viewed", "edited"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"viewed", "edited"}).Return([]*moira.Trigger{viewed, edited}, nil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{"viewed", "edited"}, nil)
+		dataBase.EXPECT().GetTeam("viewed").Return(teams["viewed"], nil)
+		dataBase.EXPECT().GetTeam("edited").Return(teams["edited"], nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetTeamSubscriptionIDs("edited").Return([]string{}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+		config, err := ExportConfig(dataBase, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+		So(config.Triggers, ShouldResemble, []dto.TriggerModel{dto.CreateTriggerModel(edited)})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not read triggers")
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggerCheckIDs([]string(nil), false).Return(nil, expected)
+		config, err := ExportConfig(dataBase, nil, "user")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(config, ShouldBeNil)
+	})
+}
+
+func TestPlanConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	saved := &moira.Trigger{ID: "saved", Name: "saved", Tags: []string{"service"}, Targets: []string{"my.metric"}, Patterns: []string{"my.metric"}}
+	removed := &moira.Trigger{ID: "removed", Name: "removed", Tags: []string{"service"}, Targets: []string{"other.metric"}, Patterns: []string{"other.metric"}}
+	subscription := moira.SubscriptionData{ID: "subscription", User: "user", Tags: []string{"service"}, Contacts: []string{"contact"}, Enabled: true}
+
+	updated := dto.CreateTriggerModel(saved)
+	updated.Name = "renamed"
+	config := &dto.Config{
+		Triggers: []dto.TriggerModel{
+			updated,
+			{ID: "new", Name: "new", Tags: []string{"service"}, Targets: []string{"new.metric"}, Patterns: []string{"new.metric"}},
+		},
+		Subscriptions: []moira.SubscriptionData{
+			{ID: "subscription", Tags: []string{"service"}, Contacts: []string{"contact"}, Enabled: true},
+		},
+	}
+
+	expectPlan := func() {
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"saved", "removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"saved", "removed"}).Return([]*moira.Trigger{saved, removed}, nil)
+		dataBase.EXPECT().GetTrigger("saved").Return(*saved, nil)
+		dataBase.EXPECT().GetTrigger("new").Return(moira.Trigger{}, database.ErrNil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{"subscription"}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{"subscription"}).Return([]*moira.SubscriptionData{&subscription}, nil)
+		dataBase.EXPECT().GetSubscription("subscription").Return(subscription, nil).Times(2)
+	}
+
+	Convey("Plan creates, updates and deletes triggers and skips unchanged subscription", t, func() {
+		expectPlan()
+		plan, err := PlanConfig(dataBase, config, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+		So(plan.Checksum, ShouldNotBeEmpty)
+		So(plan.Changes, ShouldHaveLength, 3)
+		So(plan.Changes[0], ShouldResemble, dto.ConfigChange{
+			Entity:  moira.AuditEntityTrigger,
+			ID:      "saved",
+			Action:  moira.AuditActionUpdate,
+			Changes: []dto.FieldChange{{Field: "name", Old: "saved", New: "renamed"}},
+		})
+		So(plan.Changes[1].ID, ShouldEqual, "new")
+		So(plan.Changes[1].Action, ShouldEqual, moira.AuditActionCreate)
+		So(plan.Changes[1].Changes, ShouldContain, dto.FieldChange{Field: "name", Old: nil, New: "new"})
+		So(plan.Changes[2].ID, ShouldEqual, "removed")
+		So(plan.Changes[2].Action, ShouldEqual, moira.AuditActionDelete)
+		So(plan.Changes[2].Changes, ShouldContain, dto.FieldChange{Field: "name", Old: "removed", New: nil})
+
+		Convey("Same configuration has same plan checksum", func() {
+			expectPlan()
+			second, err := PlanConfig(dataBase, config, []string{"service"}, "user")
+			So(err, ShouldBeNil)
+			So(second.Checksum, ShouldEqual, plan.Checksum)
+		})
+	})
+
+	Convey("Trigger out of tags scope", t, func() {
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"other"}, false).Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		plan, err := PlanConfig(dataBase, config, []string{"other"}, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger saved does not have all tags [other]")))
+		So(plan, ShouldBeNil)
+	})
+
+	Convey("Config without tags", t, func() {
+		plan, err := PlanConfig(dataBase, config, nil, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Configuration tags must be set, saved triggers and subscriptions having them are deleted if missing in configuration")))
+		So(plan, ShouldBeNil)
+	})
+
+	Convey("Team triggers and subscriptions user can not edit are not deleted", t, func() {
+		team := moira.Team{ID: "team", Members: map[string]string{"user": moira.TeamRoleViewer}}
+		teamTrigger := &moira.Trigger{ID: "team-trigger", Tags: []string{"service"}, TeamID: "team"}
+		teamSubscription := &moira.SubscriptionData{ID: "team-subscription", Tags: []string{"service"}, TeamID: "team"}
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"team-trigger"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"team-trigger"}).Return([]*moira.Trigger{teamTrigger}, nil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{"team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(team, nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{"team-subscription"}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{"team-subscription"}).Return([]*moira.SubscriptionData{teamSubscription}, nil)
+		plan, err := PlanConfig(dataBase, &dto.Config{}, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+		So(plan.Changes, ShouldBeEmpty)
+	})
+
+	Convey("Invalid config", t, func() {
+		plan, err := PlanConfig(dataBase, &dto.Config{Triggers: []dto.TriggerModel{{Name: "trigger"}}}, []string{"service"}, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger 1 must have id")))
+		So(plan, ShouldBeNil)
+	})
+}
+
+func TestApplyConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	removed := &moira.Trigger{ID: "removed", Name: "removed", Tags: []string{"service"}, Targets: []string{"other.metric"}}
+	config := &dto.Config{
+		Triggers:      []dto.TriggerModel{},
+		Subscriptions: []moira.SubscriptionData{{ID: "subscription", Tags: []string{"service"}, Contacts: []string{"contact"}}},
+	}
+
+	expectPlan := func() {
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"removed"}).Return([]*moira.Trigger{removed}, nil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+		dataBase.EXPECT().GetSubscription("subscription").Return(moira.SubscriptionData{}, database.ErrNil)
+		dataBase.EXPECT().GetContact("contact").Return(moira.ContactData{ID: "contact", User: "user"}, nil)
+	}
+
+	Convey("Apply reviewed plan", t, func() {
+		expectPlan()
+		plan, err := PlanConfig(dataBase, config, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+
+		expectPlan()
+		dataBase.EXPECT().SaveSubscription(&moira.SubscriptionData{ID: "subscription", User: "user", Tags: []string{"service"}, Contacts: []string{"contact"}}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntitySubscription)
+			So(record.Action, ShouldEqual, moira.AuditActionCreate)
+		}).Return(nil)
+		dataBase.EXPECT().GetTrigger("removed").Return(*removed, nil)
+		dataBase.EXPECT().RemoveTrigger("removed").Return(nil)
+		dataBase.EXPECT().RemoveTriggerLastCheck("removed").Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityTrigger)
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
+		applied, err := ApplyConfig(dataBase, config, []string{"service"}, plan.Checksum, nil, "user")
+		So(err, ShouldBeNil)
+		So(applied, ShouldResemble, plan)
+	})
+
+	Convey("Plan has changed", t, func() {
+		expectPlan()
+		applied, err := ApplyConfig(dataBase, config, []string{"service"}, "checksum", nil, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Plan checksum mismatch, configuration or saved entities have changed since plan was reviewed")))
+		So(applied, ShouldBeNil)
+	})
+
+	Convey("Nothing is applied if any trigger is invalid", t, func() {
+		config := &dto.Config{
+			Triggers: []dto.TriggerModel{
+				{ID: "first", Name: "first", Tags: []string{"service"}, Targets: []string{"my.metric"}},
+				{ID: "second", Name: "second", Tags: []string{"service"}, Targets: []string{"my.metric"}, Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"parent"}, States: []string{"ERROR"}}},
+			},
+			Subscriptions: []moira.SubscriptionData{},
+		}
+		expectPlan := func() {
+			dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{}, nil)
+			dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+			dataBase.EXPECT().GetTrigger("first").Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().GetTrigger("second").Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+			dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{}, nil)
+			dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+		}
+		expectPlan()
+		plan, err := PlanConfig(dataBase, config, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+
+		expectPlan()
+		dataBase.EXPECT().GetTriggerIDs().Return([]string{"parent"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"parent"}).Return([]*moira.Trigger{
+			{ID: "parent", Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"second"}, States: []string{"ERROR"}}},
+		}, nil)
+		applied, err := ApplyConfig(dataBase, config, []string{"service"}, plan.Checksum, nil, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger inhibition contains cycle: second -> parent -> second")))
+		So(applied, ShouldBeNil)
+	})
+
+	Convey("Cycle of configured triggers is found before they are saved", t, func() {
+		config := &dto.Config{
+			Triggers: []dto.TriggerModel{
+				{ID: "first", Name: "first", Tags: []string{"service", "first"}, Aggregate: &moira.AggregateSettings{Tags: []string{"second"}}},
+				{ID: "second", Name: "second", Tags: []string{"service", "second"}, Aggregate: &moira.AggregateSettings{Tags: []string{"first"}}},
+			},
+			Subscriptions: []moira.SubscriptionData{},
+		}
+		expectPlan := func() {
+			dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{}, nil)
+			dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+			dataBase.EXPECT().GetTrigger("first").Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().GetTrigger("second").Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+			dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{}, nil)
+			dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+		}
+		expectPlan()
+		plan, err := PlanConfig(dataBase, config, []string{"service"}, "user")
+		So(err, ShouldBeNil)
+
+		expectPlan()
+		dataBase.EXPECT().GetTriggerIDs().Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		applied, err := ApplyConfig(dataBase, config, []string{"service"}, plan.Checksum, nil, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger dependencies contain cycle: first -> second -> first")))
+		So(applied, ShouldBeNil)
+	})
+
+	Convey("Subscription with unknown contact", t, func() {
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"service"}, false).Return([]string{"removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"removed"}).Return([]*moira.Trigger{removed}, nil)
+		dataBase.EXPECT().GetUserTeamIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("user").Return([]string{}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+		dataBase.EXPECT().GetSubscription("subscription").Return(moira.SubscriptionData{}, database.ErrNil)
+		dataBase.EXPECT().GetContact("contact").Return(moira.ContactData{}, database.ErrNil)
+		applied, err := ApplyConfig(dataBase, config, []string{"service"}, "checksum", nil, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Subscription subscription has unknown contact contact")))
+		So(applied, ShouldBeNil)
+	})
+}
@@ -13,24 +13,36 @@ import (
 	"github.com/moira-alert/moira/database"
 )
 
-// GetAllContacts gets all moira contacts
-func GetAllContacts(database moira.Database) (*dto.ContactList, *api.ErrorResponse) {
-	contacts, err := database.GetAllContacts()
+// GetAllContacts gets contacts of user and of teams user is member of
+func GetAllContacts(database moira.Database, userLogin string) (*dto.ContactList, *api.ErrorResponse) {
+	teams, err := getUserTeams(database, userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	contacts, err := getUserContacts(database, userLogin, teams)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
 	contactsList := dto.ContactList{
-		List: contacts,
+		List: make([]*moira.ContactData, 0, len(contacts)),
+	}
+	for i := range contacts {
+		contactsList.List = append(contactsList.List, &contacts[i])
 	}
 	return &contactsList, nil
 }
 
-// CreateContact creates new notification contact for current user
+// CreateContact creates new notification contact for current user, contact given team is shared with team members
 func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin string) *api.ErrorResponse {
+	if errorResponse := CheckUserPermissionsForTeamObject(dataBase, contact.TeamID, userLogin); errorResponse != nil {
+		return errorResponse
+	}
 	contactData := moira.ContactData{
-		User:  userLogin,
-		Type:  contact.Type,
-		Value: contact.Value,
+		User:           userLogin,
+		TeamID:         contact.TeamID,
+		Type:           contact.Type,
+		Value:          contact.Value,
+		DigestInterval: contact.DigestInterval,
 	}
 	if contact.ID == "" {
 		contactData.ID = uuid.NewV4().String()
@@ -47,29 +59,55 @@ func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin stri
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityContact, contactData.ID, nil, contactData)
 	contact.User = userLogin
 	contact.ID = contactData.ID
 	return nil
 }
 
-// UpdateContact updates notification contact for current user
-func UpdateContact(dataBase moira.Database, contactDTO dto.Contact, contactData moira.ContactData) (dto.Contact, *api.ErrorResponse) {
+// UpdateContact updates notification contact, contact keeps its owner and can be moved only to team where current user is editor
+func UpdateContact(dataBase moira.Database, contactDTO dto.Contact, contactData moira.ContactData, userLogin string) (dto.Contact, *api.ErrorResponse) {
+	if contactDTO.TeamID != contactData.TeamID {
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, contactDTO.TeamID, userLogin); errorResponse != nil {
+			return contactDTO, errorResponse
+		}
+	}
+	before := contactData
+	contactData.TeamID = contactDTO.TeamID
 	contactData.Type = contactDTO.Type
 	contactData.Value = contactDTO.Value
+	contactData.DigestInterval = contactDTO.DigestInterval
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return contactDTO, api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityContact, contactData.ID, before, contactData)
 	contactDTO.User = contactData.User
 	contactDTO.ID = contactData.ID
 	return contactDTO, nil
 }
 
-// RemoveContact deletes notification contact for current user and remove contactID from all subscriptions
-func RemoveContact(database moira.Database, contactID string, userLogin string) *api.ErrorResponse {
-	subscriptionIDs, err := database.GetUserSubscriptionIDs(userLogin)
+// RemoveContact deletes notification contact and remove its ID from all subscriptions of contact owner and team
+func RemoveContact(database moira.Database, contactData moira.ContactData, userLogin string) *api.ErrorResponse {
+	contactID := contactData.ID
+	subscriptionIDs, err := database.GetUserSubscriptionIDs(contactData.User)
 	if err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	if contactData.TeamID != "" {
+		teamSubscriptionIDs, err := database.GetTeamSubscriptionIDs(contactData.TeamID)
+		if err != nil {
+			return api.ErrorInternalServer(err)
+		}
+		userSubscriptionIDs := make(map[string]bool, len(subscriptionIDs))
+		for _, subscriptionID := range subscriptionIDs {
+			userSubscriptionIDs[subscriptionID] = true
+		}
+		for _, subscriptionID := range teamSubscriptionIDs {
+			if !userSubscriptionIDs[subscriptionID] {
+				subscriptionIDs = append(subscriptionIDs, subscriptionID)
+			}
+		}
+	}
 
 	subscriptions, err := database.GetSubscriptions(subscriptionIDs)
 	if err != nil {
@@ -99,6 +137,7 @@ func RemoveContact(database moira.Database, contactID string, userLogin string)
 		return api.ErrorInternalServer(err)
 	}
 
+	addAuditRecord(database, userLogin, moira.AuditEntityContact, contactID, contactData, nil)
 	return nil
 }
 
@@ -119,7 +158,8 @@ func SendTestContactNotification(dataBase moira.Database, contactID string) *api
 	return nil
 }
 
-// CheckUserPermissionsForContact checks contact for existence and permissions for given user
+// CheckUserPermissionsForContact checks contact for existence and permissions for given user,
+// team contacts can be changed by team editors and admins
 func CheckUserPermissionsForContact(dataBase moira.Database, contactID string, userLogin string) (moira.ContactData, *api.ErrorResponse) {
 	contactData, err := dataBase.GetContact(contactID)
 	if err != nil {
@@ -128,12 +168,46 @@ func CheckUserPermissionsForContact(dataBase moira.Database, contactID string, u
 		}
 		return contactData, api.ErrorInternalServer(err)
 	}
+	if contactData.TeamID != "" {
+		return contactData, CheckUserPermissionsForTeamObject(dataBase, contactData.TeamID, userLogin)
+	}
 	if contactData.User != userLogin {
 		return contactData, api.ErrorForbidden("You have not permissions")
 	}
 	return contactData, nil
 }
 
+// getUserContacts returns personal contacts of user and contacts of given user teams
+func getUserContacts(dataBase moira.Database, userLogin string, teams []moira.Team) ([]moira.ContactData, error) {
+	contactIDs, err := dataBase.GetUserContactIDs(userLogin)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		teamContactIDs, err := dataBase.GetTeamContactIDs(team.ID)
+		if err != nil {
+			return nil, err
+		}
+		contactIDs = append(contactIDs, teamContactIDs...)
+		teamIDs[team.ID] = true
+	}
+	contacts, err := dataBase.GetContacts(contactIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]moira.ContactData, 0, len(contacts))
+	seen := make(map[string]bool, len(contacts))
+	for _, contact := range contacts {
+		if contact == nil || seen[contact.ID] || (contact.TeamID != "" && !teamIDs[contact.TeamID]) {
+			continue
+		}
+		seen[contact.ID] = true
+		result = append(result, *contact)
+	}
+	return result, nil
+}
+
 func isContactExists(dataBase moira.Database, contactID string) (bool, error) {
 	_, err := dataBase.GetContact(contactID)
 	if err == database.ErrNil {
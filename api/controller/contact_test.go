@@ -16,39 +16,49 @@ import (
 func TestGetAllContacts(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+	userLogin := "user"
 
-	Convey("Error get all contacts", t, func() {
-		expected := fmt.Errorf("Oooops! Can not get all contacts")
-		dataBase.EXPECT().GetAllContacts().Return(nil, expected)
-		contacts, err := GetAllContacts(dataBase)
+	Convey("Error get user contacts", t, func() {
+		expected := fmt.Errorf("Oooops! Can not get user contacts")
+		dataBase.EXPECT().GetUserTeamIDs(userLogin).Return(nil, nil)
+		dataBase.EXPECT().GetUserContactIDs(userLogin).Return(nil, expected)
+		contacts, err := GetAllContacts(dataBase, userLogin)
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(contacts, ShouldBeNil)
 	})
 
-	Convey("Get contacts", t, func() {
+	Convey("Get contacts of user and user teams", t, func() {
 		contacts := []*moira.ContactData{
 			{
 				ID:    uuid.NewV4().String(),
 				Type:  "mail",
-				User:  "user1",
+				User:  userLogin,
 				Value: "good@mail.com",
 			},
 			{
-				ID:    uuid.NewV4().String(),
-				Type:  "pushover",
-				User:  "user2",
-				Value: "ggg1",
+				ID:     uuid.NewV4().String(),
+				Type:   "pushover",
+				User:   "user2",
+				Value:  "ggg1",
+				TeamID: "team",
 			},
 		}
-		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
-		actual, err := GetAllContacts(dataBase)
+		dataBase.EXPECT().GetUserTeamIDs(userLogin).Return([]string{"team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{userLogin: moira.TeamRoleViewer}}, nil)
+		dataBase.EXPECT().GetUserContactIDs(userLogin).Return([]string{contacts[0].ID}, nil)
+		dataBase.EXPECT().GetTeamContactIDs("team").Return([]string{contacts[1].ID}, nil)
+		dataBase.EXPECT().GetContacts([]string{contacts[0].ID, contacts[1].ID}).Return(contacts, nil)
+		actual, err := GetAllContacts(dataBase, userLogin)
 		So(err, ShouldBeNil)
 		So(actual, ShouldResemble, &dto.ContactList{List: contacts})
 	})
 
 	Convey("No contacts", t, func() {
-		dataBase.EXPECT().GetAllContacts().Return(make([]*moira.ContactData, 0), nil)
-		contacts, err := GetAllContacts(dataBase)
+		dataBase.EXPECT().GetUserTeamIDs(userLogin).Return(nil, nil)
+		dataBase.EXPECT().GetUserContactIDs(userLogin).Return(nil, nil)
+		dataBase.EXPECT().GetContacts(nil).Return(nil, nil)
+		contacts, err := GetAllContacts(dataBase, userLogin)
 		So(err, ShouldBeNil)
 		So(contacts, ShouldResemble, &dto.ContactList{List: make([]*moira.ContactData, 0)})
 	})
@@ -66,6 +76,7 @@ func TestCreateContact(t *testing.T) {
 			Type:  "mail",
 		}
 		dataBase.EXPECT().SaveContact(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
 		err := CreateContact(dataBase, contact, userLogin)
 		So(err, ShouldBeNil)
 		So(contact.User, ShouldResemble, userLogin)
@@ -79,6 +90,7 @@ func TestCreateContact(t *testing.T) {
 		}
 		dataBase.EXPECT().GetContact(contact.ID).Return(moira.ContactData{}, database.ErrNil)
 		dataBase.EXPECT().SaveContact(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
 		err := CreateContact(dataBase, contact, userLogin)
 		So(err, ShouldBeNil)
 		So(contact.User, ShouldResemble, userLogin)
@@ -144,12 +156,49 @@ func TestUpdateContact(t *testing.T) {
 			User:  userLogin,
 		}
 		dataBase.EXPECT().SaveContact(&contact).Return(nil)
-		expectedContact, err := UpdateContact(dataBase, contactDTO, moira.ContactData{ID: contactID, User: userLogin})
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityContact)
+			So(record.Action, ShouldEqual, moira.AuditActionUpdate)
+			So(string(record.Before), ShouldContainSubstring, `"value":""`)
+			So(string(record.After), ShouldContainSubstring, `"value":"some@mail.com"`)
+		}).Return(nil)
+		expectedContact, err := UpdateContact(dataBase, contactDTO, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 		So(err, ShouldBeNil)
 		So(expectedContact.User, ShouldResemble, userLogin)
 		So(expectedContact.ID, ShouldResemble, contactID)
 	})
 
+	Convey("Move to team", t, func() {
+		contactDTO := dto.Contact{
+			Value:  "some@mail.com",
+			Type:   "mail",
+			TeamID: "team",
+		}
+		contact := moira.ContactData{ID: uuid.NewV4().String(), User: userLogin}
+
+		Convey("User is team editor", func() {
+			expected := moira.ContactData{Value: contactDTO.Value, Type: contactDTO.Type, ID: contact.ID, User: userLogin, TeamID: "team"}
+			dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{userLogin: moira.TeamRoleEditor}}, nil)
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+			actual, err := UpdateContact(dataBase, contactDTO, contact, userLogin)
+			So(err, ShouldBeNil)
+			So(actual.TeamID, ShouldEqual, "team")
+		})
+
+		Convey("User is not team member", func() {
+			dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleAdmin}}, nil)
+			_, err := UpdateContact(dataBase, contactDTO, contact, userLogin)
+			So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+		})
+
+		Convey("Team does not exist", func() {
+			dataBase.EXPECT().GetTeam("team").Return(moira.Team{}, database.ErrNil)
+			_, err := UpdateContact(dataBase, contactDTO, contact, userLogin)
+			So(err, ShouldResemble, api.ErrorNotFound("Team with ID 'team' does not exists"))
+		})
+	})
+
 	Convey("Error save", t, func() {
 		contactDTO := dto.Contact{
 			Value: "some@mail.com",
@@ -164,7 +213,7 @@ func TestUpdateContact(t *testing.T) {
 		}
 		err := fmt.Errorf("Oooops")
 		dataBase.EXPECT().SaveContact(&contact).Return(err)
-		exprectedContact, actual := UpdateContact(dataBase, contactDTO, contact)
+		exprectedContact, actual := UpdateContact(dataBase, contactDTO, contact, userLogin)
 		So(actual, ShouldResemble, api.ErrorInternalServer(err))
 		So(exprectedContact.User, ShouldResemble, contactDTO.User)
 		So(exprectedContact.ID, ShouldResemble, contactDTO.ID)
@@ -182,7 +231,8 @@ func TestRemoveContact(t *testing.T) {
 		dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
 		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 		dataBase.EXPECT().SaveSubscriptions(make([]*moira.SubscriptionData, 0)).Return(nil)
-		err := RemoveContact(dataBase, contactID, userLogin)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 		So(err, ShouldBeNil)
 	})
 
@@ -196,7 +246,8 @@ func TestRemoveContact(t *testing.T) {
 		dataBase.EXPECT().GetSubscriptions([]string{subscription.ID}).Return([]*moira.SubscriptionData{subscription}, nil)
 		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 		dataBase.EXPECT().SaveSubscriptions(make([]*moira.SubscriptionData, 0)).Return(nil)
-		err := RemoveContact(dataBase, contactID, userLogin)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 		So(err, ShouldBeNil)
 	})
 
@@ -212,22 +263,40 @@ func TestRemoveContact(t *testing.T) {
 		dataBase.EXPECT().GetSubscriptions([]string{subscription.ID}).Return([]*moira.SubscriptionData{&subscription}, nil)
 		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 		dataBase.EXPECT().SaveSubscriptions([]*moira.SubscriptionData{&expectedSub}).Return(nil)
-		err := RemoveContact(dataBase, contactID, userLogin)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 		So(err, ShouldBeNil)
 	})
 
+	Convey("Delete team contact from team subscriptions", t, func() {
+		userSubscription := moira.SubscriptionData{Contacts: []string{contactID}, ID: uuid.NewV4().String()}
+		teamSubscription := moira.SubscriptionData{Contacts: []string{contactID}, ID: uuid.NewV4().String(), TeamID: "team"}
+		subscriptionIDs := []string{userSubscription.ID, teamSubscription.ID}
+
+		dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return([]string{userSubscription.ID}, nil)
+		dataBase.EXPECT().GetTeamSubscriptionIDs("team").Return(subscriptionIDs, nil)
+		dataBase.EXPECT().GetSubscriptions(subscriptionIDs).Return([]*moira.SubscriptionData{&userSubscription, &teamSubscription}, nil)
+		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
+		dataBase.EXPECT().SaveSubscriptions(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin, TeamID: "team"}, userLogin)
+		So(err, ShouldBeNil)
+		So(userSubscription.Contacts, ShouldBeEmpty)
+		So(teamSubscription.Contacts, ShouldBeEmpty)
+	})
+
 	Convey("Error tests", t, func() {
 		Convey("GetUserSubscriptionIDs", func() {
 			expectedError := fmt.Errorf("Oooops! Can not read user subscription ids")
 			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(nil, expectedError)
-			err := RemoveContact(dataBase, contactID, userLogin)
+			err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
 		})
 		Convey("GetSubscriptions", func() {
 			expectedError := fmt.Errorf("Oooops! Can not read user subscriptions")
 			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
 			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(nil, expectedError)
-			err := RemoveContact(dataBase, contactID, userLogin)
+			err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
 		})
 		Convey("RemoveContact", func() {
@@ -235,7 +304,7 @@ func TestRemoveContact(t *testing.T) {
 			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
 			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
 			dataBase.EXPECT().RemoveContact(contactID).Return(expectedError)
-			err := RemoveContact(dataBase, contactID, userLogin)
+			err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
 		})
 		Convey("SaveSubscriptions", func() {
@@ -244,7 +313,7 @@ func TestRemoveContact(t *testing.T) {
 			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
 			dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 			dataBase.EXPECT().SaveSubscriptions(make([]*moira.SubscriptionData, 0)).Return(expectedError)
-			err := RemoveContact(dataBase, contactID, userLogin)
+			err := RemoveContact(dataBase, moira.ContactData{ID: contactID, User: userLogin}, userLogin)
 			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
 		})
 	})
@@ -299,6 +368,33 @@ func TestCheckUserPermissionsForContact(t *testing.T) {
 		So(expectedContact, ShouldResemble, actualContact)
 	})
 
+	Convey("Team contact", t, func() {
+		actualContact := moira.ContactData{ID: id, User: "diffUser", TeamID: "team"}
+		team := moira.Team{ID: "team", Members: map[string]string{userLogin: moira.TeamRoleAdmin, "viewer": moira.TeamRoleViewer}}
+
+		Convey("Admin", func() {
+			dataBase.EXPECT().GetContact(id).Return(actualContact, nil)
+			dataBase.EXPECT().GetTeam("team").Return(team, nil)
+			expectedContact, expected := CheckUserPermissionsForContact(dataBase, id, userLogin)
+			So(expected, ShouldBeNil)
+			So(expectedContact, ShouldResemble, actualContact)
+		})
+
+		Convey("Viewer", func() {
+			dataBase.EXPECT().GetContact(id).Return(actualContact, nil)
+			dataBase.EXPECT().GetTeam("team").Return(team, nil)
+			_, expected := CheckUserPermissionsForContact(dataBase, id, "viewer")
+			So(expected, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+		})
+
+		Convey("Contact owner who left team", func() {
+			dataBase.EXPECT().GetContact(id).Return(actualContact, nil)
+			dataBase.EXPECT().GetTeam("team").Return(team, nil)
+			_, expected := CheckUserPermissionsForContact(dataBase, id, "diffUser")
+			So(expected, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+		})
+	})
+
 	Convey("Error get contact", t, func() {
 		err := fmt.Errorf("Oooops! Can not read contact")
 		dataBase.EXPECT().GetContact(id).Return(moira.ContactData{User: userLogin}, err)
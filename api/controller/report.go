@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/report"
+)
+
+// GetReport builds availability report of triggers having all given tags within given time range
+func GetReport(dataBase moira.Database, tags []string, from, to int64) (*dto.Report, *api.ErrorResponse) {
+	if now := time.Now().Unix(); to > now {
+		to = now
+	}
+	if from > to {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to"))
+	}
+	availabilityReport, err := report.Build(dataBase, tags, from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.Report{Report: availabilityReport}, nil
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	tags := []string{"api"}
+
+	Convey("Build report", t, func() {
+		dataBase.EXPECT().GetTriggerCheckIDs(tags, false).Return([]string{"t1"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"t1"}).Return([]*moira.Trigger{{ID: "t1", Tags: tags}}, nil)
+		dataBase.EXPECT().GetTriggerStateHistory("t1", int64(1000), int64(2000)).Return([]*moira.TriggerStateSnapshot{{Timestamp: 1000, State: "OK"}}, nil)
+		dataBase.EXPECT().GetNotificationEvents("t1", int64(0), int64(99)).Return([]*moira.NotificationEvent{}, nil)
+		actual, err := GetReport(dataBase, tags, 1000, 2000)
+		So(err, ShouldBeNil)
+		So(actual.From, ShouldEqual, 1000)
+		So(actual.To, ShouldEqual, 2000)
+		So(actual.Triggers, ShouldHaveLength, 1)
+		So(actual.Triggers[0].Percentages["OK"], ShouldEqual, 100)
+		So(actual.Tags, ShouldHaveLength, 1)
+	})
+
+	Convey("Invalid time range", t, func() {
+		actual, err := GetReport(dataBase, tags, 2000, 1000)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to")))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Build report error", t, func() {
+		expected := fmt.Errorf("oops")
+		dataBase.EXPECT().GetTriggerCheckIDs(tags, false).Return(nil, expected)
+		actual, err := GetReport(dataBase, tags, 1000, 2000)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/senders/actions"
+)
+
+const slackMessenger = "slack"
+
+// HandleSlackInteraction applies action of pressed notification button on behalf of moira user owning slack contact.
+// Slack user is matched by member ID, because user names can be changed by anyone to any value
+func HandleSlackInteraction(database moira.Database, logger moira.Logger, verificationToken string, interaction dto.SlackInteraction) (*dto.SlackInteractionResponse, *api.ErrorResponse) {
+	if subtle.ConstantTimeCompare([]byte(interaction.Token), []byte(verificationToken)) != 1 {
+		return nil, api.ErrorForbidden("Invalid verification token")
+	}
+	if len(interaction.Actions) == 0 {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("No actions in interaction"))
+	}
+	action, err := actions.Parse(interaction.Actions[0].Value)
+	if err != nil {
+		return nil, api.ErrorInvalidRequest(err)
+	}
+	userLogin, err := actions.GetUserLogin(database, slackMessenger, interaction.User.ID)
+	if err != nil {
+		return getSlackErrorResponse(err)
+	}
+	reply, err := action.Apply(database, logger, userLogin, time.Now().Unix())
+	if err != nil {
+		return getSlackErrorResponse(err)
+	}
+	return &dto.SlackInteractionResponse{ResponseType: "in_channel", Text: reply}, nil
+}
+
+// getSlackErrorResponse shows errors caused by user only to user who pressed the button and passes other errors through
+func getSlackErrorResponse(err error) (*dto.SlackInteractionResponse, *api.ErrorResponse) {
+	switch err.(type) {
+	case actions.ErrUnknownUser, actions.ErrNotPermitted:
+		return &dto.SlackInteractionResponse{ResponseType: "ephemeral", Text: err.Error()}, nil
+	}
+	if err == database.ErrNil {
+		return &dto.SlackInteractionResponse{ResponseType: "ephemeral", Text: "Trigger not found"}, nil
+	}
+	return nil, api.ErrorInternalServer(err)
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/op/go-logging"
+	"github.com/satori/go.uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestHandleSlackInteraction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+	triggerID := uuid.NewV4().String()
+	interaction := dto.SlackInteraction{
+		Token:   "token",
+		User:    dto.SlackUser{ID: "U123", Name: "user"},
+		Actions: []dto.SlackActionChoice{{Name: "ack", Value: "ack|" + triggerID}},
+	}
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetIDByUsername("slack", "U123").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{{Type: "slack", Value: "U123", User: "login"}}, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, []string(nil), gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "login")
+			So(record.Entity, ShouldEqual, moira.AuditEntityAcknowledgement)
+		}).Return(nil)
+		response, err := HandleSlackInteraction(dataBase, logger, "token", interaction)
+		So(err, ShouldBeNil)
+		So(response, ShouldResemble, &dto.SlackInteractionResponse{ResponseType: "in_channel", Text: "Acknowledged by login"})
+	})
+
+	Convey("User is not matched by changeable name", t, func() {
+		dataBase.EXPECT().GetIDByUsername("slack", "U123").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{{Type: "slack", Value: "@user", User: "login"}}, nil)
+		response, err := HandleSlackInteraction(dataBase, logger, "token", interaction)
+		So(err, ShouldBeNil)
+		So(response, ShouldResemble, &dto.SlackInteractionResponse{ResponseType: "ephemeral", Text: "User U123 has no contacts in Moira"})
+	})
+
+	Convey("User not in trigger team", t, func() {
+		dataBase.EXPECT().GetIDByUsername("slack", "U123").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{{Type: "slack", Value: "U123", User: "login"}}, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleEditor}}, nil)
+		response, err := HandleSlackInteraction(dataBase, logger, "token", interaction)
+		So(err, ShouldBeNil)
+		So(response, ShouldResemble, &dto.SlackInteractionResponse{ResponseType: "ephemeral", Text: "User login is not permitted to change trigger " + triggerID})
+	})
+
+	Convey("Invalid token", t, func() {
+		response, err := HandleSlackInteraction(dataBase, logger, "other", interaction)
+		So(response, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorForbidden("Invalid verification token"))
+	})
+}
@@ -13,29 +13,24 @@ import (
 	"github.com/moira-alert/moira/database"
 )
 
-// GetUserSubscriptions get all user subscriptions
+// GetUserSubscriptions get all user subscriptions and subscriptions of user teams
 func GetUserSubscriptions(database moira.Database, userLogin string) (*dto.SubscriptionList, *api.ErrorResponse) {
-	subscriptionIDs, err := database.GetUserSubscriptionIDs(userLogin)
+	teams, err := getUserTeams(database, userLogin)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
-	subscriptions, err := database.GetSubscriptions(subscriptionIDs)
+	subscriptions, err := getUserSubscriptions(database, userLogin, teams)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
-	subscriptionsList := &dto.SubscriptionList{
-		List: make([]moira.SubscriptionData, 0),
-	}
-	for _, subscription := range subscriptions {
-		if subscription != nil {
-			subscriptionsList.List = append(subscriptionsList.List, *subscription)
-		}
-	}
-	return subscriptionsList, nil
+	return &dto.SubscriptionList{List: subscriptions}, nil
 }
 
-// CreateSubscription create or update subscription
+// CreateSubscription create or update subscription, subscription given team is shared with team members
 func CreateSubscription(dataBase moira.Database, userLogin string, subscription *dto.Subscription) *api.ErrorResponse {
+	if errorResponse := CheckUserPermissionsForTeamObject(dataBase, subscription.TeamID, userLogin); errorResponse != nil {
+		return errorResponse
+	}
 	if subscription.ID == "" {
 		subscription.ID = uuid.NewV4().String()
 	} else {
@@ -53,25 +48,33 @@ func CreateSubscription(dataBase moira.Database, userLogin string, subscription
 	if err := dataBase.SaveSubscription(&data); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntitySubscription, data.ID, nil, data)
 	return nil
 }
 
-// UpdateSubscription updates existing subscription
-func UpdateSubscription(dataBase moira.Database, subscriptionID string, userLogin string, subscription *dto.Subscription) *api.ErrorResponse {
-	subscription.ID = subscriptionID
-	subscription.User = userLogin
+// UpdateSubscription updates existing subscription, subscription keeps its owner and can be moved only to team where current user is editor
+func UpdateSubscription(dataBase moira.Database, subscriptionData moira.SubscriptionData, userLogin string, subscription *dto.Subscription) *api.ErrorResponse {
+	if subscription.TeamID != subscriptionData.TeamID {
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, subscription.TeamID, userLogin); errorResponse != nil {
+			return errorResponse
+		}
+	}
+	subscription.ID = subscriptionData.ID
+	subscription.User = subscriptionData.User
 	data := moira.SubscriptionData(*subscription)
 	if err := dataBase.SaveSubscription(&data); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntitySubscription, data.ID, subscriptionData, data)
 	return nil
 }
 
 // RemoveSubscription deletes subscription
-func RemoveSubscription(database moira.Database, subscriptionID string) *api.ErrorResponse {
-	if err := database.RemoveSubscription(subscriptionID); err != nil {
+func RemoveSubscription(database moira.Database, subscriptionData moira.SubscriptionData, userLogin string) *api.ErrorResponse {
+	if err := database.RemoveSubscription(subscriptionData.ID); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(database, userLogin, moira.AuditEntitySubscription, subscriptionData.ID, subscriptionData, nil)
 	return nil
 }
 
@@ -94,7 +97,8 @@ func SendTestNotification(database moira.Database, subscriptionID string) *api.E
 	return nil
 }
 
-// CheckUserPermissionsForSubscription checks subscription for existence and permissions for given user
+// CheckUserPermissionsForSubscription checks subscription for existence and permissions for given user,
+// team subscriptions can be changed by team editors and admins
 func CheckUserPermissionsForSubscription(dataBase moira.Database, subscriptionID string, userLogin string) (moira.SubscriptionData, *api.ErrorResponse) {
 	subscription, err := dataBase.GetSubscription(subscriptionID)
 	if err != nil {
@@ -103,12 +107,46 @@ func CheckUserPermissionsForSubscription(dataBase moira.Database, subscriptionID
 		}
 		return subscription, api.ErrorInternalServer(err)
 	}
+	if subscription.TeamID != "" {
+		return subscription, CheckUserPermissionsForTeamObject(dataBase, subscription.TeamID, userLogin)
+	}
 	if subscription.User != userLogin {
 		return subscription, api.ErrorForbidden("You have not permissions")
 	}
 	return subscription, nil
 }
 
+// getUserSubscriptions returns personal subscriptions of user and subscriptions of given user teams
+func getUserSubscriptions(dataBase moira.Database, userLogin string, teams []moira.Team) ([]moira.SubscriptionData, error) {
+	subscriptionIDs, err := dataBase.GetUserSubscriptionIDs(userLogin)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		teamSubscriptionIDs, err := dataBase.GetTeamSubscriptionIDs(team.ID)
+		if err != nil {
+			return nil, err
+		}
+		subscriptionIDs = append(subscriptionIDs, teamSubscriptionIDs...)
+		teamIDs[team.ID] = true
+	}
+	subscriptions, err := dataBase.GetSubscriptions(subscriptionIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]moira.SubscriptionData, 0, len(subscriptions))
+	seen := make(map[string]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription == nil || seen[subscription.ID] || (subscription.TeamID != "" && !teamIDs[subscription.TeamID]) {
+			continue
+		}
+		seen[subscription.ID] = true
+		result = append(result, *subscription)
+	}
+	return result, nil
+}
+
 func isSubscriptionExists(dataBase moira.Database, subscriptionID string) (bool, error) {
 	_, err := dataBase.GetSubscription(subscriptionID)
 	if err == database.ErrNil {
@@ -18,6 +18,7 @@ func TestGetUserSubscriptions(t *testing.T) {
 	defer mockCtrl.Finish()
 	database := mock_moira_alert.NewMockDatabase(mockCtrl)
 	login := "user"
+	database.EXPECT().GetUserTeamIDs(login).Return(make([]string, 0), nil).AnyTimes()
 
 	Convey("Two subscriptions", t, func() {
 		subscriptionIDs := []string{uuid.NewV4().String(), uuid.NewV4().String()}
@@ -74,12 +75,38 @@ func TestUpdateSubscription(t *testing.T) {
 			User: userLogin,
 		}
 		dataBase.EXPECT().SaveSubscription(&subscription).Return(nil)
-		err := UpdateSubscription(dataBase, subscriptionID, userLogin, subscriptionDTO)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := UpdateSubscription(dataBase, subscription, userLogin, subscriptionDTO)
 		So(err, ShouldBeNil)
 		So(subscriptionDTO.User, ShouldResemble, userLogin)
 		So(subscriptionDTO.ID, ShouldResemble, subscriptionID)
 	})
 
+	Convey("Team subscription keeps its owner", t, func() {
+		subscriptionDTO := &dto.Subscription{TeamID: "team"}
+		subscription := moira.SubscriptionData{
+			ID:     uuid.NewV4().String(),
+			User:   "owner",
+			TeamID: "team",
+		}
+		dataBase.EXPECT().SaveSubscription(&subscription).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		err := UpdateSubscription(dataBase, subscription, userLogin, subscriptionDTO)
+		So(err, ShouldBeNil)
+		So(subscriptionDTO.User, ShouldResemble, "owner")
+	})
+
+	Convey("Subscription can not be moved to team where user is not editor", t, func() {
+		subscriptionDTO := &dto.Subscription{TeamID: "team"}
+		subscription := moira.SubscriptionData{
+			ID:   uuid.NewV4().String(),
+			User: userLogin,
+		}
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{userLogin: moira.TeamRoleViewer}}, nil)
+		err := UpdateSubscription(dataBase, subscription, userLogin, subscriptionDTO)
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+
 	Convey("Error save", t, func() {
 		subscriptionDTO := &dto.Subscription{}
 		subscriptionID := uuid.NewV4().String()
@@ -89,7 +116,7 @@ func TestUpdateSubscription(t *testing.T) {
 		}
 		err := fmt.Errorf("Oooops")
 		dataBase.EXPECT().SaveSubscription(&subscription).Return(err)
-		actual := UpdateSubscription(dataBase, subscriptionID, userLogin, subscriptionDTO)
+		actual := UpdateSubscription(dataBase, subscription, userLogin, subscriptionDTO)
 		So(actual, ShouldResemble, api.ErrorInternalServer(err))
 		So(subscriptionDTO.User, ShouldResemble, userLogin)
 		So(subscriptionDTO.ID, ShouldResemble, subscriptionID)
@@ -101,17 +128,24 @@ func TestRemoveSubscription(t *testing.T) {
 	defer mockCtrl.Finish()
 	database := mock_moira_alert.NewMockDatabase(mockCtrl)
 	id := uuid.NewV4().String()
+	subscription := moira.SubscriptionData{ID: id, User: "owner"}
 
 	Convey("Success", t, func() {
 		database.EXPECT().RemoveSubscription(id).Return(nil)
-		err := RemoveSubscription(database, id)
+		database.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "user")
+			So(record.Entity, ShouldEqual, moira.AuditEntitySubscription)
+			So(record.EntityID, ShouldEqual, id)
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
+		err := RemoveSubscription(database, subscription, "user")
 		So(err, ShouldBeNil)
 	})
 
 	Convey("Error", t, func() {
 		expected := fmt.Errorf("Oooops! Can not remove subscription")
 		database.EXPECT().RemoveSubscription(id).Return(expected)
-		err := RemoveSubscription(database, id)
+		err := RemoveSubscription(database, subscription, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
@@ -145,6 +179,7 @@ func TestCreateSubscription(t *testing.T) {
 	Convey("Success create", t, func() {
 		subscription := dto.Subscription{ID: ""}
 		dataBase.EXPECT().SaveSubscription(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
 		err := CreateSubscription(dataBase, login, &subscription)
 		So(err, ShouldBeNil)
 	})
@@ -155,6 +190,7 @@ func TestCreateSubscription(t *testing.T) {
 		}
 		dataBase.EXPECT().GetSubscription(sub.ID).Return(moira.SubscriptionData{}, database.ErrNil)
 		dataBase.EXPECT().SaveSubscription(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
 		err := CreateSubscription(dataBase, login, sub)
 		So(err, ShouldBeNil)
 		So(sub.User, ShouldResemble, login)
@@ -219,6 +255,26 @@ func TestCheckUserPermissionsForSubscription(t *testing.T) {
 		So(expectedSub, ShouldResemble, actualSub)
 	})
 
+	Convey("Team subscription", t, func() {
+		actualSub := moira.SubscriptionData{ID: id, User: "diffUser", TeamID: "team"}
+		team := moira.Team{ID: "team", Members: map[string]string{userLogin: moira.TeamRoleEditor, "viewer": moira.TeamRoleViewer}}
+
+		Convey("Editor", func() {
+			dataBase.EXPECT().GetSubscription(id).Return(actualSub, nil)
+			dataBase.EXPECT().GetTeam("team").Return(team, nil)
+			expectedSub, expected := CheckUserPermissionsForSubscription(dataBase, id, userLogin)
+			So(expected, ShouldBeNil)
+			So(expectedSub, ShouldResemble, actualSub)
+		})
+
+		Convey("Viewer", func() {
+			dataBase.EXPECT().GetSubscription(id).Return(actualSub, nil)
+			dataBase.EXPECT().GetTeam("team").Return(team, nil)
+			_, expected := CheckUserPermissionsForSubscription(dataBase, id, "viewer")
+			So(expected, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+		})
+	})
+
 	Convey("Error get contact", t, func() {
 		err := fmt.Errorf("Oooops! Can not read contact")
 		dataBase.EXPECT().GetSubscription(id).Return(moira.SubscriptionData{}, err)
@@ -79,7 +79,7 @@ func getTagNamesSorted(database moira.Database) ([]string, error) {
 }
 
 // RemoveTag deletes tag by name
-func RemoveTag(database moira.Database, tagName string) (*dto.MessageResponse, *api.ErrorResponse) {
+func RemoveTag(database moira.Database, tagName string, userLogin string) (*dto.MessageResponse, *api.ErrorResponse) {
 	triggerIDs, err := database.GetTagTriggerIDs(tagName)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
@@ -91,5 +91,6 @@ func RemoveTag(database moira.Database, tagName string) (*dto.MessageResponse, *
 	if err = database.RemoveTag(tagName); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
+	addAuditRecord(database, userLogin, moira.AuditEntityTag, tagName, tagName, nil)
 	return &dto.MessageResponse{Message: "tag deleted"}, nil
 }
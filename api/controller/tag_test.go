@@ -45,14 +45,19 @@ func TestDeleteTag(t *testing.T) {
 	Convey("Test no trigger ids by tag", t, func() {
 		database.EXPECT().GetTagTriggerIDs(tag).Return(nil, nil)
 		database.EXPECT().RemoveTag(tag).Return(nil)
-		resp, err := RemoveTag(database, tag)
+		database.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityTag)
+			So(record.EntityID, ShouldEqual, tag)
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
+		resp, err := RemoveTag(database, tag, "user")
 		So(err, ShouldBeNil)
 		So(resp, ShouldResemble, &dto.MessageResponse{Message: "tag deleted"})
 	})
 
 	Convey("Test has trigger ids by tag", t, func() {
 		database.EXPECT().GetTagTriggerIDs(tag).Return([]string{"123"}, nil)
-		resp, err := RemoveTag(database, tag)
+		resp, err := RemoveTag(database, tag, "user")
 		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("This tag is assigned to %v triggers. Remove tag from triggers first", 1)))
 		So(resp, ShouldBeNil)
 	})
@@ -60,7 +65,7 @@ func TestDeleteTag(t *testing.T) {
 	Convey("GetTagTriggerIDs error", t, func() {
 		expected := fmt.Errorf("Can not read trigger ids")
 		database.EXPECT().GetTagTriggerIDs(tag).Return(nil, expected)
-		resp, err := RemoveTag(database, tag)
+		resp, err := RemoveTag(database, tag, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(resp, ShouldBeNil)
 	})
@@ -69,7 +74,7 @@ func TestDeleteTag(t *testing.T) {
 		expected := fmt.Errorf("Can not delete tag")
 		database.EXPECT().GetTagTriggerIDs(tag).Return(nil, nil)
 		database.EXPECT().RemoveTag(tag).Return(expected)
-		resp, err := RemoveTag(database, tag)
+		resp, err := RemoveTag(database, tag, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(resp, ShouldBeNil)
 	})
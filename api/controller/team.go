@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetUserTeams gets teams where user is member
+func GetUserTeams(dataBase moira.Database, userLogin string) (*dto.TeamsList, *api.ErrorResponse) {
+	teams, err := getUserTeams(dataBase, userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TeamsList{List: teams}, nil
+}
+
+// CreateTeam creates new team, user creating team becomes its admin
+func CreateTeam(dataBase moira.Database, team *dto.Team, userLogin string) *api.ErrorResponse {
+	if userLogin == "" {
+		return api.ErrorForbidden("Teams can not be created by anonymous user")
+	}
+	if team.ID == "" {
+		team.ID = uuid.NewV4().String()
+	} else {
+		_, err := dataBase.GetTeam(team.ID)
+		if err == nil {
+			return api.ErrorInvalidRequest(fmt.Errorf("Team with this ID already exists"))
+		}
+		if err != database.ErrNil {
+			return api.ErrorInternalServer(err)
+		}
+	}
+	if team.Members == nil {
+		team.Members = make(map[string]string)
+	}
+	team.Members[userLogin] = moira.TeamRoleAdmin
+	if err := dataBase.SaveTeam(&team.Team); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// UpdateTeam updates team name and members, team can not be left without admins
+func UpdateTeam(dataBase moira.Database, team *dto.Team, teamID string) *api.ErrorResponse {
+	team.ID = teamID
+	hasAdmin := false
+	for _, role := range team.Members {
+		if role == moira.TeamRoleAdmin {
+			hasAdmin = true
+			break
+		}
+	}
+	if !hasAdmin {
+		return api.ErrorInvalidRequest(fmt.Errorf("Team must have at least one admin"))
+	}
+	if err := dataBase.SaveTeam(&team.Team); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// RemoveTeam deletes team, team owning triggers, contacts or subscriptions can not be removed
+func RemoveTeam(dataBase moira.Database, teamID string) *api.ErrorResponse {
+	triggerIDs, err := dataBase.GetTeamTriggerIDs(teamID)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	contactIDs, err := dataBase.GetTeamContactIDs(teamID)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	subscriptionIDs, err := dataBase.GetTeamSubscriptionIDs(teamID)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	if len(triggerIDs) > 0 || len(contactIDs) > 0 || len(subscriptionIDs) > 0 {
+		return api.ErrorInvalidRequest(fmt.Errorf("Team owns %d triggers, %d contacts and %d subscriptions, remove them or move to other team first",
+			len(triggerIDs), len(contactIDs), len(subscriptionIDs)))
+	}
+	if err := dataBase.RemoveTeam(teamID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// CheckUserPermissionsForTeam checks team for existence and that given user has at least given role in it
+func CheckUserPermissionsForTeam(dataBase moira.Database, teamID string, userLogin string, role string) (moira.Team, *api.ErrorResponse) {
+	team, err := dataBase.GetTeam(teamID)
+	if err != nil {
+		if err == database.ErrNil {
+			return team, api.ErrorNotFound(fmt.Sprintf("Team with ID '%s' does not exists", teamID))
+		}
+		return team, api.ErrorInternalServer(err)
+	}
+	if !team.HasRole(userLogin, role) {
+		return team, api.ErrorForbidden("You have not permissions")
+	}
+	return team, nil
+}
+
+// CheckUserPermissionsForTeamObject checks that user can create and change triggers, contacts and subscriptions
+// owned by given team. Objects without team are not restricted by team roles
+func CheckUserPermissionsForTeamObject(dataBase moira.Database, teamID string, userLogin string) *api.ErrorResponse {
+	return checkUserRoleForTeamObject(dataBase, teamID, userLogin, moira.TeamRoleEditor)
+}
+
+func checkUserRoleForTeamObject(dataBase moira.Database, teamID string, userLogin string, role string) *api.ErrorResponse {
+	if teamID == "" {
+		return nil
+	}
+	_, errorResponse := CheckUserPermissionsForTeam(dataBase, teamID, userLogin, role)
+	return errorResponse
+}
+
+// getUserTeamIDs returns set of IDs of teams where user is member
+func getUserTeamIDs(dataBase moira.Database, userLogin string) (map[string]bool, error) {
+	teamIDs, err := dataBase.GetUserTeamIDs(userLogin)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		result[teamID] = true
+	}
+	return result, nil
+}
+
+// getUserTeams returns teams where user is member, teams removed concurrently are skipped
+func getUserTeams(dataBase moira.Database, userLogin string) ([]moira.Team, error) {
+	teamIDs, err := dataBase.GetUserTeamIDs(userLogin)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]moira.Team, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		team, err := dataBase.GetTeam(teamID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
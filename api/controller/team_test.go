@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestCreateTeam(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+
+	Convey("Creator becomes team admin", t, func() {
+		team := &dto.Team{Team: moira.Team{Name: "Team", Members: map[string]string{"other": moira.TeamRoleViewer, login: moira.TeamRoleViewer}}}
+		dataBase.EXPECT().SaveTeam(gomock.Any()).Return(nil)
+		err := CreateTeam(dataBase, team, login)
+		So(err, ShouldBeNil)
+		So(team.ID, ShouldNotBeEmpty)
+		So(team.Members, ShouldResemble, map[string]string{"other": moira.TeamRoleViewer, login: moira.TeamRoleAdmin})
+	})
+
+	Convey("Team with given ID", t, func() {
+		team := &dto.Team{Team: moira.Team{ID: "team", Name: "Team"}}
+
+		Convey("Is created", func() {
+			dataBase.EXPECT().GetTeam(team.ID).Return(moira.Team{}, database.ErrNil)
+			dataBase.EXPECT().SaveTeam(&team.Team).Return(nil)
+			err := CreateTeam(dataBase, team, login)
+			So(err, ShouldBeNil)
+			So(team.Members, ShouldResemble, map[string]string{login: moira.TeamRoleAdmin})
+		})
+
+		Convey("Already exists", func() {
+			dataBase.EXPECT().GetTeam(team.ID).Return(moira.Team{ID: team.ID}, nil)
+			err := CreateTeam(dataBase, team, login)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Team with this ID already exists")))
+		})
+	})
+
+	Convey("Anonymous user can not create team", t, func() {
+		err := CreateTeam(dataBase, &dto.Team{Team: moira.Team{Name: "Team"}}, "")
+		So(err, ShouldResemble, api.ErrorForbidden("Teams can not be created by anonymous user"))
+	})
+}
+
+func TestUpdateTeam(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Success update", t, func() {
+		team := &dto.Team{Team: moira.Team{Name: "Team", Members: map[string]string{"user": moira.TeamRoleAdmin}}}
+		dataBase.EXPECT().SaveTeam(&moira.Team{ID: "team", Name: "Team", Members: map[string]string{"user": moira.TeamRoleAdmin}}).Return(nil)
+		err := UpdateTeam(dataBase, team, "team")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Team without admins", t, func() {
+		team := &dto.Team{Team: moira.Team{Name: "Team", Members: map[string]string{"user": moira.TeamRoleEditor}}}
+		err := UpdateTeam(dataBase, team, "team")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Team must have at least one admin")))
+	})
+}
+
+func TestRemoveTeam(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Team without objects is removed", t, func() {
+		dataBase.EXPECT().GetTeamTriggerIDs("team").Return(make([]string, 0), nil)
+		dataBase.EXPECT().GetTeamContactIDs("team").Return(make([]string, 0), nil)
+		dataBase.EXPECT().GetTeamSubscriptionIDs("team").Return(make([]string, 0), nil)
+		dataBase.EXPECT().RemoveTeam("team").Return(nil)
+		err := RemoveTeam(dataBase, "team")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Team owning objects is not removed", t, func() {
+		dataBase.EXPECT().GetTeamTriggerIDs("team").Return([]string{"trigger"}, nil)
+		dataBase.EXPECT().GetTeamContactIDs("team").Return(make([]string, 0), nil)
+		dataBase.EXPECT().GetTeamSubscriptionIDs("team").Return([]string{"subscription1", "subscription2"}, nil)
+		err := RemoveTeam(dataBase, "team")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Team owns 1 triggers, 0 contacts and 2 subscriptions, remove them or move to other team first")))
+	})
+}
+
+func TestCheckUserPermissionsForTeam(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	team := moira.Team{ID: "team", Members: map[string]string{"viewer": moira.TeamRoleViewer, "admin": moira.TeamRoleAdmin}}
+
+	Convey("No team", t, func() {
+		dataBase.EXPECT().GetTeam(team.ID).Return(moira.Team{}, database.ErrNil)
+		_, err := CheckUserPermissionsForTeam(dataBase, team.ID, "admin", moira.TeamRoleViewer)
+		So(err, ShouldResemble, api.ErrorNotFound("Team with ID 'team' does not exists"))
+	})
+
+	Convey("Member with required role", t, func() {
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		actual, err := CheckUserPermissionsForTeam(dataBase, team.ID, "admin", moira.TeamRoleEditor)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, team)
+	})
+
+	Convey("Member with lower role", t, func() {
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		_, err := CheckUserPermissionsForTeam(dataBase, team.ID, "viewer", moira.TeamRoleEditor)
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+
+	Convey("Not a member", t, func() {
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		_, err := CheckUserPermissionsForTeam(dataBase, team.ID, "other", moira.TeamRoleViewer)
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+}
+
+func TestCheckUserPermissionsForTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	team := moira.Team{ID: "team", Members: map[string]string{"viewer": moira.TeamRoleViewer, "editor": moira.TeamRoleEditor}}
+
+	Convey("Trigger without team can be changed by everyone", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger"}, nil)
+		So(CheckUserPermissionsForTrigger(dataBase, "trigger", "other"), ShouldBeNil)
+	})
+
+	Convey("Missing trigger is not checked", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{}, database.ErrNil)
+		So(CheckUserPermissionsForTrigger(dataBase, "trigger", "other"), ShouldBeNil)
+	})
+
+	Convey("Team trigger can be changed by team editors", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: team.ID}, nil)
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		So(CheckUserPermissionsForTrigger(dataBase, "trigger", "editor"), ShouldBeNil)
+
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: team.ID}, nil)
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		So(CheckUserPermissionsForTrigger(dataBase, "trigger", "viewer"), ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+}
+
+func TestCheckUserReadPermissionsForTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	team := moira.Team{ID: "team", Members: map[string]string{"viewer": moira.TeamRoleViewer}}
+
+	Convey("Trigger without team can be seen by everyone", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger"}, nil)
+		So(CheckUserReadPermissionsForTrigger(dataBase, "trigger", "other"), ShouldBeNil)
+	})
+
+	Convey("Team trigger can be seen by team members only", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: team.ID}, nil)
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		So(CheckUserReadPermissionsForTrigger(dataBase, "trigger", "viewer"), ShouldBeNil)
+
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: team.ID}, nil)
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		So(CheckUserReadPermissionsForTrigger(dataBase, "trigger", "other"), ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetAllTriggerTemplates gets all trigger templates
+func GetAllTriggerTemplates(dataBase moira.Database) (*dto.TriggerTemplatesList, *api.ErrorResponse) {
+	templates, err := dataBase.GetTriggerTemplates()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggerTemplatesList{List: templates}, nil
+}
+
+// GetTriggerTemplate gets trigger template by given id
+func GetTriggerTemplate(dataBase moira.Database, templateID string) (*dto.TriggerTemplate, *api.ErrorResponse) {
+	template, err := dataBase.GetTriggerTemplate(templateID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorNotFound(fmt.Sprintf("Template with ID = '%s' does not exists", templateID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggerTemplate{TriggerTemplate: template}, nil
+}
+
+// CreateTriggerTemplate creates new trigger template without instances
+func CreateTriggerTemplate(dataBase moira.Database, template *dto.TriggerTemplate, userLogin string) (*dto.SaveTriggerTemplateResponse, *api.ErrorResponse) {
+	if template.ID == "" {
+		template.ID = uuid.NewV4().String()
+	} else {
+		_, err := dataBase.GetTriggerTemplate(template.ID)
+		if err == nil {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Template with this ID already exists"))
+		}
+		if err != database.ErrNil {
+			return nil, api.ErrorInternalServer(err)
+		}
+	}
+	if err := dataBase.SaveTriggerTemplate(&template.TriggerTemplate); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityTemplate, template.ID, nil, template.TriggerTemplate)
+	return &dto.SaveTriggerTemplateResponse{ID: template.ID, Message: "template created"}, nil
+}
+
+// UpdateTriggerTemplate updates trigger template and re-renders all its instances with their parameters.
+// Nothing is saved if any instance can not be rendered with new template or user can not change it
+func UpdateTriggerTemplate(dataBase moira.Database, template *dto.TriggerTemplate, templateID string, userLogin string, renderTemplate dto.TemplateRenderer) (*dto.SaveTriggerTemplateResponse, *api.ErrorResponse) {
+	existing, errorResponse := GetTriggerTemplate(dataBase, templateID)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	template.ID = templateID
+	instances, errorResponse := getTemplateInstances(dataBase, templateID)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	triggerIDs := make([]string, 0, len(instances))
+	rendered := make([]*dto.TemplateInstance, 0, len(instances))
+	for _, instance := range instances {
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, instance.TeamID, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+		result, err := renderTemplate(&template.TriggerTemplate, instance.Template.Parameters)
+		if err != nil {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Failed to render trigger %s: %s", instance.ID, err.Error()))
+		}
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, result.Trigger.TeamID, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+		triggerIDs = append(triggerIDs, instance.ID)
+		rendered = append(rendered, result)
+	}
+	if err := dataBase.SaveTriggerTemplate(&template.TriggerTemplate); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityTemplate, templateID, existing.TriggerTemplate, template.TriggerTemplate)
+	for i, instance := range instances {
+		if errorResponse := saveTemplateInstance(dataBase, instance, rendered[i], triggerIDs[i], templateID, instance.Template.Parameters, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+	}
+	return &dto.SaveTriggerTemplateResponse{ID: templateID, Message: "template updated", TriggerIDs: triggerIDs}, nil
+}
+
+// RemoveTriggerTemplate deletes trigger template, template having instances can not be removed
+func RemoveTriggerTemplate(dataBase moira.Database, templateID string, userLogin string) *api.ErrorResponse {
+	existing, errorResponse := GetTriggerTemplate(dataBase, templateID)
+	if errorResponse != nil {
+		return errorResponse
+	}
+	instances, errorResponse := getTemplateInstances(dataBase, templateID)
+	if errorResponse != nil {
+		return errorResponse
+	}
+	for _, instance := range instances {
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, instance.TeamID, userLogin); errorResponse != nil {
+			return errorResponse
+		}
+	}
+	if len(instances) > 0 {
+		return api.ErrorInvalidRequest(fmt.Errorf("Template is used by %d triggers, remove them first", len(instances)))
+	}
+	if err := dataBase.RemoveTriggerTemplate(templateID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityTemplate, templateID, existing.TriggerTemplate, nil)
+	return nil
+}
+
+// InstantiateTriggerTemplate creates or updates trigger for each of given parameter sets.
+// Instance is identified by rendered trigger ID if template sets it, otherwise by equal parameters
+func InstantiateTriggerTemplate(dataBase moira.Database, templateID string, userLogin string, instances *dto.TemplateInstances, renderTemplate dto.TemplateRenderer) (*dto.SaveTriggerTemplateResponse, *api.ErrorResponse) {
+	template, errorResponse := GetTriggerTemplate(dataBase, templateID)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	existing, errorResponse := getTemplateInstances(dataBase, templateID)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	triggerIDs := make([]string, 0, len(instances.Parameters))
+	rendered := make([]*dto.TemplateInstance, 0, len(instances.Parameters))
+	seen := make(map[string]bool, len(instances.Parameters))
+	for _, parameters := range instances.Parameters {
+		result, err := renderTemplate(&template.TriggerTemplate, parameters)
+		if err != nil {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Failed to render trigger with parameters %v: %s", parameters, err.Error()))
+		}
+		triggerID, errorResponse := getInstanceTriggerID(dataBase, existing, result.Trigger.ID, templateID, parameters)
+		if errorResponse != nil {
+			return nil, errorResponse
+		}
+		if seen[triggerID] {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("Parameters render trigger %s more than once", triggerID))
+		}
+		seen[triggerID] = true
+		if errorResponse := checkUserPermissionsForInstance(dataBase, findTrigger(existing, triggerID), result, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+		triggerIDs = append(triggerIDs, triggerID)
+		rendered = append(rendered, result)
+	}
+	for i, parameters := range instances.Parameters {
+		if errorResponse := saveTemplateInstance(dataBase, findTrigger(existing, triggerIDs[i]), rendered[i], triggerIDs[i], templateID, parameters, userLogin); errorResponse != nil {
+			return nil, errorResponse
+		}
+	}
+	return &dto.SaveTriggerTemplateResponse{ID: templateID, Message: "template instantiated", TriggerIDs: triggerIDs}, nil
+}
+
+// getTemplateInstances returns triggers rendered from given template
+func getTemplateInstances(dataBase moira.Database, templateID string) ([]*moira.Trigger, *api.ErrorResponse) {
+	triggerIDs, err := dataBase.GetTemplateTriggerIDs(templateID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	triggers, err := dataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	instances := make([]*moira.Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		if trigger != nil && trigger.Template != nil {
+			instances = append(instances, trigger)
+		}
+	}
+	return instances, nil
+}
+
+// getInstanceTriggerID returns ID of trigger rendered with given parameters, existing triggers
+// not rendered from the same template are never overwritten
+func getInstanceTriggerID(dataBase moira.Database, existing []*moira.Trigger, renderedID, templateID string, parameters map[string]interface{}) (string, *api.ErrorResponse) {
+	if renderedID == "" {
+		for _, instance := range existing {
+			if reflect.DeepEqual(instance.Template.Parameters, parameters) {
+				return instance.ID, nil
+			}
+		}
+		return uuid.NewV4().String(), nil
+	}
+	trigger, err := dataBase.GetTrigger(renderedID)
+	if err != nil {
+		if err == database.ErrNil {
+			return renderedID, nil
+		}
+		return "", api.ErrorInternalServer(err)
+	}
+	if trigger.Template == nil || trigger.Template.ID != templateID {
+		return "", api.ErrorInvalidRequest(fmt.Errorf("Trigger with ID %s already exists and is not rendered from this template", renderedID))
+	}
+	return renderedID, nil
+}
+
+// checkUserPermissionsForInstance checks that user can change existing instance trigger and its team owner
+func checkUserPermissionsForInstance(dataBase moira.Database, existing *moira.Trigger, instance *dto.TemplateInstance, userLogin string) *api.ErrorResponse {
+	if existing != nil {
+		if errorResponse := CheckUserPermissionsForTeamObject(dataBase, existing.TeamID, userLogin); errorResponse != nil {
+			return errorResponse
+		}
+	}
+	return CheckUserPermissionsForTeamObject(dataBase, instance.Trigger.TeamID, userLogin)
+}
+
+func findTrigger(triggers []*moira.Trigger, triggerID string) *moira.Trigger {
+	for _, trigger := range triggers {
+		if trigger.ID == triggerID {
+			return trigger
+		}
+	}
+	return nil
+}
+
+func saveTemplateInstance(dataBase moira.Database, existing *moira.Trigger, instance *dto.TemplateInstance, triggerID, templateID string, parameters map[string]interface{}, userLogin string) *api.ErrorResponse {
+	trigger := instance.Trigger.ToMoiraTrigger()
+	trigger.ID = triggerID
+	trigger.Template = &moira.TemplateLink{ID: templateID, Parameters: parameters}
+	_, errorResponse := saveTrigger(dataBase, existing, trigger, triggerID, instance.TimeSeriesNames, userLogin)
+	return errorResponse
+}
@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+var testTemplate = moira.TriggerTemplate{
+	ID:      "template",
+	Name:    "Service errors",
+	Trigger: json.RawMessage(`{"name":"{{service}} errors","targets":["{{service}}.errors"],"warn_value":"{{warn}}","tags":["{{service}}"]}`),
+}
+
+// renderTemplate renders template without checking targets, which needs graphite storage
+func renderTemplate(template *moira.TriggerTemplate, parameters map[string]interface{}) (*dto.TemplateInstance, error) {
+	trigger, err := dto.RenderTemplate(template, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TemplateInstance{Trigger: trigger.TriggerModel, TimeSeriesNames: make(map[string]bool)}, nil
+}
+
+func expectSaveTrigger(dataBase *mock_moira_alert.MockDatabase, triggerID string, trigger *moira.Trigger) {
+	dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
+	dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
+	dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+	dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
+	dataBase.EXPECT().SaveTrigger(triggerID, trigger).Return(nil)
+	dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+	dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+}
+
+func TestCreateTriggerTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Success with empty ID", t, func() {
+		template := &dto.TriggerTemplate{TriggerTemplate: moira.TriggerTemplate{Name: testTemplate.Name, Trigger: testTemplate.Trigger}}
+		dataBase.EXPECT().SaveTriggerTemplate(&template.TriggerTemplate).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "user")
+			So(record.Entity, ShouldEqual, moira.AuditEntityTemplate)
+			So(record.Action, ShouldEqual, moira.AuditActionCreate)
+		}).Return(nil)
+		resp, err := CreateTriggerTemplate(dataBase, template, "user")
+		So(err, ShouldBeNil)
+		So(resp.ID, ShouldNotBeEmpty)
+		So(resp.ID, ShouldResemble, template.ID)
+		So(resp.Message, ShouldResemble, "template created")
+	})
+
+	Convey("Template already exists", t, func() {
+		template := &dto.TriggerTemplate{TriggerTemplate: testTemplate}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		resp, err := CreateTriggerTemplate(dataBase, template, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Template with this ID already exists")))
+		So(resp, ShouldBeNil)
+	})
+}
+
+func TestRemoveTriggerTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Template without instances is removed", t, func() {
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		dataBase.EXPECT().RemoveTriggerTemplate(testTemplate.ID).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityTemplate)
+			So(record.EntityID, ShouldEqual, testTemplate.ID)
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
+		err := RemoveTriggerTemplate(dataBase, testTemplate.ID, "user")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Template with instances is not removed", t, func() {
+		instance := &moira.Trigger{ID: "api", Template: &moira.TemplateLink{ID: testTemplate.ID}}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{"api"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"api"}).Return([]*moira.Trigger{instance}, nil)
+		err := RemoveTriggerTemplate(dataBase, testTemplate.ID, "user")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Template is used by 1 triggers, remove them first")))
+	})
+
+	Convey("User can not change team instance", t, func() {
+		instance := &moira.Trigger{ID: "api", TeamID: "team", Template: &moira.TemplateLink{ID: testTemplate.ID}}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{"api"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"api"}).Return([]*moira.Trigger{instance}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleAdmin}}, nil)
+		err := RemoveTriggerTemplate(dataBase, testTemplate.ID, "user")
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+}
+
+func TestInstantiateTriggerTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	warn := float64(10)
+	emptyExpression := ""
+	parameters := map[string]interface{}{"service": "api", "warn": warn}
+	existing := moira.Trigger{
+		ID:       "existing",
+		Name:     "api errors",
+		Template: &moira.TemplateLink{ID: testTemplate.ID, Parameters: parameters},
+	}
+
+	Convey("Template not found", t, func() {
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(moira.TriggerTemplate{}, database.ErrNil)
+		resp, err := InstantiateTriggerTemplate(dataBase, testTemplate.ID, "user", &dto.TemplateInstances{}, renderTemplate)
+		So(err, ShouldResemble, api.ErrorNotFound("Template with ID = 'template' does not exists"))
+		So(resp, ShouldBeNil)
+	})
+
+	Convey("Instances with equal parameters are updated, others are created", t, func() {
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{existing.ID}, nil)
+		dataBase.EXPECT().GetTriggers([]string{existing.ID}).Return([]*moira.Trigger{&existing}, nil)
+		expectSaveTrigger(dataBase, existing.ID, &moira.Trigger{
+			ID:         existing.ID,
+			Name:       "api errors",
+			Targets:    []string{"api.errors"},
+			WarnValue:  &warn,
+			Tags:       []string{"api"},
+			Expression: &emptyExpression,
+			Template:   existing.Template,
+		})
+		dataBase.EXPECT().AcquireTriggerCheckLock(gomock.Any(), 10)
+		dataBase.EXPECT().DeleteTriggerCheckLock(gomock.Any())
+		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any()).Return(nil)
+		dataBase.EXPECT().SaveTrigger(gomock.Any(), gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(gomock.Any(), gomock.Any()).Return(nil)
+
+		instances := &dto.TemplateInstances{Parameters: []map[string]interface{}{
+			parameters,
+			{"service": "web", "warn": warn},
+		}}
+		resp, err := InstantiateTriggerTemplate(dataBase, testTemplate.ID, "user", instances, renderTemplate)
+		So(err, ShouldBeNil)
+		So(resp.Message, ShouldResemble, "template instantiated")
+		So(resp.TriggerIDs, ShouldHaveLength, 2)
+		So(resp.TriggerIDs[0], ShouldResemble, existing.ID)
+	})
+
+	Convey("Nothing is saved if any instance can not be rendered", t, func() {
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		instances := &dto.TemplateInstances{Parameters: []map[string]interface{}{
+			parameters,
+			{"service": "web"},
+		}}
+		resp, err := InstantiateTriggerTemplate(dataBase, testTemplate.ID, "user", instances, renderTemplate)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Failed to render trigger with parameters map[service:web]: template parameter warn is not set")))
+		So(resp, ShouldBeNil)
+	})
+
+	Convey("Rendered trigger ID of trigger not rendered from template", t, func() {
+		template := testTemplate
+		template.Trigger = json.RawMessage(`{"id":"{{service}}-errors","name":"{{service}} errors","targets":["{{service}}.errors"],"warn_value":"{{warn}}","tags":["{{service}}"]}`)
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(template, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		dataBase.EXPECT().GetTrigger("api-errors").Return(moira.Trigger{ID: "api-errors"}, nil)
+		instances := &dto.TemplateInstances{Parameters: []map[string]interface{}{parameters}}
+		resp, err := InstantiateTriggerTemplate(dataBase, testTemplate.ID, "user", instances, renderTemplate)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger with ID api-errors already exists and is not rendered from this template")))
+		So(resp, ShouldBeNil)
+	})
+}
+
+func TestUpdateTriggerTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	warn := float64(10)
+	emptyExpression := ""
+	existing := moira.Trigger{
+		ID:       "existing",
+		Name:     "api errors",
+		Template: &moira.TemplateLink{ID: testTemplate.ID, Parameters: map[string]interface{}{"service": "api", "warn": warn}},
+	}
+
+	Convey("Template instances are re-rendered with their parameters", t, func() {
+		template := &dto.TriggerTemplate{TriggerTemplate: moira.TriggerTemplate{
+			Name:    testTemplate.Name,
+			Trigger: json.RawMessage(`{"name":"{{service}} 5xx","targets":["{{service}}.5xx"],"warn_value":"{{warn}}","tags":["{{service}}", "http"]}`),
+		}}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{existing.ID}, nil)
+		dataBase.EXPECT().GetTriggers([]string{existing.ID}).Return([]*moira.Trigger{&existing}, nil)
+		dataBase.EXPECT().SaveTriggerTemplate(&template.TriggerTemplate).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityTemplate)
+			So(record.Action, ShouldEqual, moira.AuditActionUpdate)
+		}).Return(nil)
+		expectSaveTrigger(dataBase, existing.ID, &moira.Trigger{
+			ID:         existing.ID,
+			Name:       "api 5xx",
+			Targets:    []string{"api.5xx"},
+			WarnValue:  &warn,
+			Tags:       []string{"api", "http"},
+			Expression: &emptyExpression,
+			Template:   existing.Template,
+		})
+		resp, err := UpdateTriggerTemplate(dataBase, template, testTemplate.ID, "user", renderTemplate)
+		So(err, ShouldBeNil)
+		So(resp, ShouldResemble, &dto.SaveTriggerTemplateResponse{ID: testTemplate.ID, Message: "template updated", TriggerIDs: []string{existing.ID}})
+		So(template.ID, ShouldResemble, testTemplate.ID)
+	})
+
+	Convey("Template is not saved if instance can not be rendered", t, func() {
+		template := &dto.TriggerTemplate{TriggerTemplate: moira.TriggerTemplate{
+			Name:    testTemplate.Name,
+			Trigger: json.RawMessage(`{"name":"{{service}} errors in {{dc}}"}`),
+		}}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{existing.ID}, nil)
+		dataBase.EXPECT().GetTriggers([]string{existing.ID}).Return([]*moira.Trigger{&existing}, nil)
+		resp, err := UpdateTriggerTemplate(dataBase, template, testTemplate.ID, "user", renderTemplate)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Failed to render trigger existing: template parameter dc is not set")))
+		So(resp, ShouldBeNil)
+	})
+
+	Convey("Template is not saved if user can not change team instance", t, func() {
+		teamTrigger := existing
+		teamTrigger.TeamID = "team"
+		template := &dto.TriggerTemplate{TriggerTemplate: testTemplate}
+		dataBase.EXPECT().GetTriggerTemplate(testTemplate.ID).Return(testTemplate, nil)
+		dataBase.EXPECT().GetTemplateTriggerIDs(testTemplate.ID).Return([]string{existing.ID}, nil)
+		dataBase.EXPECT().GetTriggers([]string{existing.ID}).Return([]*moira.Trigger{&teamTrigger}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"user": moira.TeamRoleViewer}}, nil)
+		resp, err := UpdateTriggerTemplate(dataBase, template, testTemplate.ID, "user", renderTemplate)
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+		So(resp, ShouldBeNil)
+	})
+}
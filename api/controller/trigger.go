@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/moira-alert/moira"
@@ -13,19 +14,28 @@ import (
 )
 
 // UpdateTrigger update trigger data and trigger metrics in last state
-func UpdateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, triggerID string, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
-	_, err := dataBase.GetTrigger(triggerID)
+func UpdateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, triggerID string, timeSeriesNames map[string]bool, userLogin string) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	existing, err := dataBase.GetTrigger(triggerID)
 	if err != nil {
 		if err == database.ErrNil {
 			return nil, api.ErrorNotFound(fmt.Sprintf("Trigger with ID = '%s' does not exists", triggerID))
 		}
 		return nil, api.ErrorInternalServer(err)
 	}
-	return saveTrigger(dataBase, trigger.ToMoiraTrigger(), triggerID, timeSeriesNames)
+	return saveTrigger(dataBase, &existing, trigger.ToMoiraTrigger(), triggerID, timeSeriesNames, userLogin)
 }
 
-// saveTrigger create or update trigger data and update trigger metrics in last state
-func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID string, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+// saveTrigger create or update trigger data, update trigger metrics in last state, record change to audit log
+// and save new trigger revision, existing is saved trigger state or nil for new trigger
+func saveTrigger(dataBase moira.Database, existing *moira.Trigger, trigger *moira.Trigger, triggerID string, timeSeriesNames map[string]bool, userLogin string) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	if err := checkTriggerDependencies(dataBase, trigger, triggerID); err != nil {
+		return nil, err
+	}
+	return saveCheckedTrigger(dataBase, existing, trigger, triggerID, timeSeriesNames, userLogin)
+}
+
+// saveCheckedTrigger saves trigger which dependencies are already checked
+func saveCheckedTrigger(dataBase moira.Database, existing *moira.Trigger, trigger *moira.Trigger, triggerID string, timeSeriesNames map[string]bool, userLogin string) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
 	if err := dataBase.AcquireTriggerCheckLock(triggerID, 10); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
@@ -36,9 +46,12 @@ func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID stri
 	}
 
 	if err != database.ErrNil {
-		for metric := range lastCheck.Metrics {
-			if _, ok := timeSeriesNames[metric]; !ok {
-				delete(lastCheck.Metrics, metric)
+		// nil timeSeriesNames means trigger timeseries are unknown, so metrics are kept
+		if timeSeriesNames != nil {
+			for metric := range lastCheck.Metrics {
+				if _, ok := timeSeriesNames[metric]; !ok {
+					delete(lastCheck.Metrics, metric)
+				}
 			}
 		}
 	} else {
@@ -56,6 +69,16 @@ func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID stri
 	if err = dataBase.SaveTrigger(triggerID, trigger); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityTrigger, triggerID, existing, trigger)
+	revision := &moira.TriggerRevision{
+		Timestamp: time.Now().Unix(),
+		User:      userLogin,
+		Trigger:   *trigger,
+	}
+	revision.Trigger.ID = triggerID
+	if err = dataBase.AddTriggerRevision(triggerID, revision); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
 
 	resp := dto.SaveTriggerResponse{
 		ID:      triggerID,
@@ -64,6 +87,113 @@ func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID stri
 	return &resp, nil
 }
 
+// checkTriggerDependencies checks that trigger does not form aggregate or inhibition cycle with saved triggers
+func checkTriggerDependencies(dataBase moira.Database, trigger *moira.Trigger, triggerID string) *api.ErrorResponse {
+	if err := checkAggregateCycles(dataBase, trigger, triggerID); err != nil {
+		return err
+	}
+	return checkInhibitionCycles(dataBase, trigger, triggerID)
+}
+
+// checkAggregateCycles checks that aggregate trigger does not depend on itself through other aggregate triggers
+func checkAggregateCycles(dataBase moira.Database, trigger *moira.Trigger, triggerID string) *api.ErrorResponse {
+	if trigger.Aggregate == nil {
+		return nil
+	}
+	saved := *trigger
+	saved.ID = triggerID
+	cycle, err := findAggregateCycle(dataBase, &saved, &saved, make(map[string]bool))
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	if cycle != nil {
+		return api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger dependencies contain cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return nil
+}
+
+// findAggregateCycle walks inputs of current aggregate trigger and returns path to saved trigger if any
+func findAggregateCycle(dataBase moira.Database, saved, current *moira.Trigger, visited map[string]bool) ([]string, error) {
+	inputIDs, err := checker.GetAggregateInputTriggerIDs(dataBase, current.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+	// Saved trigger tags are not stored yet, so it is matched to inputs by new tags
+	if current.Aggregate.IsDependentOn(saved) {
+		return []string{current.ID, saved.ID}, nil
+	}
+	for _, inputID := range inputIDs {
+		if inputID == saved.ID || visited[inputID] {
+			continue
+		}
+		visited[inputID] = true
+		input, err := dataBase.GetTrigger(inputID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		if input.Aggregate == nil {
+			continue
+		}
+		cycle, err := findAggregateCycle(dataBase, saved, &input, visited)
+		if err != nil {
+			return nil, err
+		}
+		if cycle != nil {
+			return append([]string{current.ID}, cycle...), nil
+		}
+	}
+	return nil, nil
+}
+
+// checkInhibitionCycles checks that parent trigger does not suppress itself through other parent triggers
+func checkInhibitionCycles(dataBase moira.Database, trigger *moira.Trigger, triggerID string) *api.ErrorResponse {
+	if trigger.Inhibits == nil {
+		return nil
+	}
+	parentIDs, err := dataBase.GetInhibitingTriggerIDs()
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	parents, err := dataBase.GetTriggers(parentIDs)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	// Saved trigger is taken with new settings and tags instead of stored ones
+	others := make([]*moira.Trigger, 0, len(parents))
+	for _, parent := range parents {
+		if parent != nil && parent.Inhibits != nil && parent.ID != triggerID {
+			others = append(others, parent)
+		}
+	}
+	saved := *trigger
+	saved.ID = triggerID
+	if cycle := findInhibitionCycle(&saved, &saved, others, make(map[string]bool)); cycle != nil {
+		return api.ErrorInvalidRequest(fmt.Errorf("Trigger inhibition contains cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return nil
+}
+
+// findInhibitionCycle walks parent triggers suppressed by current parent trigger and returns path to saved trigger if any.
+// Trigger suppressing itself is not a cycle, because checker never inhibits trigger by itself
+func findInhibitionCycle(saved, current *moira.Trigger, parents []*moira.Trigger, visited map[string]bool) []string {
+	if current != saved && current.Inhibits.IsSuppressing(saved) {
+		return []string{current.ID, saved.ID}
+	}
+	for _, parent := range parents {
+		if visited[parent.ID] || !current.Inhibits.IsSuppressing(parent) {
+			continue
+		}
+		visited[parent.ID] = true
+		if cycle := findInhibitionCycle(saved, parent, parents, visited); cycle != nil {
+			return append([]string{current.ID}, cycle...)
+		}
+	}
+	return nil
+}
+
 // GetTrigger gets trigger with his throttling - next allowed message time
 func GetTrigger(dataBase moira.Database, triggerID string) (*dto.Trigger, *api.ErrorResponse) {
 	trigger, err := dataBase.GetTrigger(triggerID)
@@ -89,13 +219,21 @@ func GetTrigger(dataBase moira.Database, triggerID string) (*dto.Trigger, *api.E
 }
 
 // RemoveTrigger deletes trigger by given triggerID
-func RemoveTrigger(database moira.Database, triggerID string) *api.ErrorResponse {
-	if err := database.RemoveTrigger(triggerID); err != nil {
+func RemoveTrigger(dataBase moira.Database, triggerID string, userLogin string) *api.ErrorResponse {
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil && err != database.ErrNil {
 		return api.ErrorInternalServer(err)
 	}
-	if err := database.RemoveTriggerLastCheck(triggerID); err != nil {
+	if err := dataBase.RemoveTrigger(triggerID); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	if err := dataBase.RemoveTriggerLastCheck(triggerID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	if err == database.ErrNil {
+		return nil
+	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityTrigger, triggerID, &trigger, nil)
 	return nil
 }
 
@@ -130,11 +268,98 @@ func GetTriggerLastCheck(dataBase moira.Database, triggerID string) (*dto.Trigge
 	return &triggerCheck, nil
 }
 
+// GetTriggerHistory gets trigger and its metrics state intervals from trigger history within given time range
+func GetTriggerHistory(dataBase moira.Database, triggerID string, from, to int64) (*dto.TriggerHistory, *api.ErrorResponse) {
+	if now := time.Now().Unix(); to > now {
+		to = now
+	}
+	if from > to {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to"))
+	}
+	snapshots, err := dataBase.GetTriggerStateHistory(triggerID, from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return getStateIntervals(triggerID, snapshots, from, to), nil
+}
+
+// getStateIntervals converts trigger state snapshots ordered by time to trigger and metrics state intervals,
+// interval lasts until snapshot with other state or without metric
+func getStateIntervals(triggerID string, snapshots []*moira.TriggerStateSnapshot, from, to int64) *dto.TriggerHistory {
+	history := &dto.TriggerHistory{
+		TriggerID: triggerID,
+		Trigger:   make([]dto.StateInterval, 0),
+		Metrics:   make(map[string][]dto.StateInterval),
+	}
+	var triggerInterval *dto.StateInterval
+	metricIntervals := make(map[string]*dto.StateInterval)
+	closeInterval := func(intervals []dto.StateInterval, interval *dto.StateInterval, end int64) []dto.StateInterval {
+		if interval == nil || end <= interval.Start {
+			return intervals
+		}
+		interval.End = end
+		return append(intervals, *interval)
+	}
+
+	for _, snapshot := range snapshots {
+		timestamp := snapshot.Timestamp
+		if timestamp < from {
+			timestamp = from
+		}
+		if triggerInterval == nil || triggerInterval.State != snapshot.State {
+			history.Trigger = closeInterval(history.Trigger, triggerInterval, timestamp)
+			triggerInterval = &dto.StateInterval{Start: timestamp, State: snapshot.State}
+		}
+		for metric, interval := range metricIntervals {
+			if state, ok := snapshot.Metrics[metric]; !ok || state != interval.State {
+				history.Metrics[metric] = closeInterval(history.Metrics[metric], interval, timestamp)
+				delete(metricIntervals, metric)
+			}
+		}
+		for metric, state := range snapshot.Metrics {
+			if _, ok := metricIntervals[metric]; !ok {
+				metricIntervals[metric] = &dto.StateInterval{Start: timestamp, State: state}
+			}
+		}
+	}
+	history.Trigger = closeInterval(history.Trigger, triggerInterval, to)
+	for metric, interval := range metricIntervals {
+		history.Metrics[metric] = closeInterval(history.Metrics[metric], interval, to)
+	}
+	for metric, intervals := range history.Metrics {
+		if len(intervals) == 0 {
+			delete(history.Metrics, metric)
+		}
+	}
+	return history
+}
+
+// BacktestTrigger simulates given trigger checks over metrics values stored within given time range,
+// returned metrics state transitions and events are not saved
+func BacktestTrigger(dataBase moira.Database, logger moira.Logger, trigger *dto.TriggerModel, from, to int64) (*dto.BacktestResult, *api.ErrorResponse) {
+	if now := time.Now().Unix(); to > now {
+		to = now
+	}
+	if from > to {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to"))
+	}
+	result, err := checker.Backtest(dataBase, logger, trigger.ToMoiraTrigger(), from, to)
+	if err != nil {
+		return nil, api.ErrorInvalidRequest(err)
+	}
+	return &dto.BacktestResult{BacktestResult: result}, nil
+}
+
 // DeleteTriggerThrottling deletes trigger throttling
-func DeleteTriggerThrottling(database moira.Database, triggerID string) *api.ErrorResponse {
+func DeleteTriggerThrottling(database moira.Database, triggerID string, userLogin string) *api.ErrorResponse {
+	throttling, errorResponse := GetTriggerThrottling(database, triggerID)
+	if errorResponse != nil {
+		return errorResponse
+	}
 	if err := database.DeleteTriggerThrottling(triggerID); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(database, userLogin, moira.AuditEntityThrottling, triggerID, throttling, nil)
 
 	now := time.Now().Unix()
 	notifications, _, err := database.GetNotifications(0, -1)
@@ -190,10 +415,43 @@ func DeleteTriggerMetric(dataBase moira.Database, metricName string, triggerID s
 }
 
 // SetMetricsMaintenance sets metrics maintenance for current trigger
-func SetMetricsMaintenance(database moira.Database, triggerID string, metricsMaintenance dto.MetricsMaintenance) *api.ErrorResponse {
-	if err := database.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64(metricsMaintenance)); err != nil {
+func SetMetricsMaintenance(dataBase moira.Database, triggerID string, metricsMaintenance dto.MetricsMaintenance, userLogin string) *api.ErrorResponse {
+	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
+	if err != nil && err != database.ErrNil {
+		return api.ErrorInternalServer(err)
+	}
+	before := make(map[string]int64, len(metricsMaintenance))
+	for metric := range metricsMaintenance {
+		before[metric] = lastCheck.Metrics[metric].Maintenance
+	}
+	if err := dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64(metricsMaintenance)); err != nil {
 		return api.ErrorInternalServer(err)
 	}
+	addAuditRecord(dataBase, userLogin, moira.AuditEntityMaintenance, triggerID, before, metricsMaintenance)
+	return nil
+}
+
+// AcknowledgeTrigger acknowledges current state of trigger or given metrics by user,
+// notifications about acknowledged states are not sent until state changes
+func AcknowledgeTrigger(database moira.Database, triggerID string, userLogin string, ack dto.Acknowledgement) *api.ErrorResponse {
+	acknowledgement := &moira.Acknowledgement{
+		User:      userLogin,
+		Comment:   ack.Comment,
+		Timestamp: time.Now().Unix(),
+	}
+	if err := database.SetTriggerCheckAcknowledgement(triggerID, ack.Metrics, acknowledgement); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	addAuditRecord(database, userLogin, moira.AuditEntityAcknowledgement, triggerID, nil, ack)
+	return nil
+}
+
+// RemoveTriggerAcknowledgement removes acknowledgement of trigger or given metrics
+func RemoveTriggerAcknowledgement(database moira.Database, triggerID string, metrics []string, userLogin string) *api.ErrorResponse {
+	if err := database.SetTriggerCheckAcknowledgement(triggerID, metrics, nil); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	addAuditRecord(database, userLogin, moira.AuditEntityAcknowledgement, triggerID, dto.Acknowledgement{Metrics: metrics}, nil)
 	return nil
 }
 
@@ -228,3 +486,26 @@ func GetTriggerMetrics(dataBase moira.Database, from, to int64, triggerID string
 	}
 	return triggerMetrics, nil
 }
+
+// CheckUserPermissionsForTrigger checks that user can change trigger, triggers owned by team can be changed
+// only by its editors and admins. Missing trigger is not an error here as handlers report it themselves
+func CheckUserPermissionsForTrigger(dataBase moira.Database, triggerID string, userLogin string) *api.ErrorResponse {
+	return checkUserRoleForTrigger(dataBase, triggerID, userLogin, moira.TeamRoleEditor)
+}
+
+// CheckUserReadPermissionsForTrigger checks that user can see trigger, its state and history,
+// triggers owned by team are visible only to its members
+func CheckUserReadPermissionsForTrigger(dataBase moira.Database, triggerID string, userLogin string) *api.ErrorResponse {
+	return checkUserRoleForTrigger(dataBase, triggerID, userLogin, moira.TeamRoleViewer)
+}
+
+func checkUserRoleForTrigger(dataBase moira.Database, triggerID string, userLogin string, role string) *api.ErrorResponse {
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return api.ErrorInternalServer(err)
+	}
+	return checkUserRoleForTeamObject(dataBase, trigger.TeamID, userLogin, role)
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerRevisions gets all saved revisions of trigger
+func GetTriggerRevisions(dataBase moira.Database, triggerID string) (*dto.TriggerRevisionsList, *api.ErrorResponse) {
+	revisions, err := dataBase.GetTriggerRevisions(triggerID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggerRevisionsList{List: revisions}, nil
+}
+
+// GetTriggerRevision gets trigger revision by number
+func GetTriggerRevision(dataBase moira.Database, triggerID string, number int64) (*dto.TriggerRevision, *api.ErrorResponse) {
+	revision, err := getTriggerRevision(dataBase, triggerID, number)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TriggerRevision{TriggerRevision: revision}, nil
+}
+
+// GetTriggerRevisionsDiff gets changes of trigger fields made between two revisions
+func GetTriggerRevisionsDiff(dataBase moira.Database, triggerID string, from, to int64) (*dto.TriggerRevisionsDiff, *api.ErrorResponse) {
+	fromRevision, errorResponse := getTriggerRevision(dataBase, triggerID, from)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	toRevision, errorResponse := getTriggerRevision(dataBase, triggerID, to)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	changes, err := getFieldChanges(fromRevision.Trigger, toRevision.Trigger)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggerRevisionsDiff{
+		TriggerID: triggerID,
+		From:      from,
+		To:        to,
+		Changes:   changes,
+	}, nil
+}
+
+// RestoreTriggerRevision saves trigger definition from revision as new trigger revision,
+// removed trigger can be restored too
+func RestoreTriggerRevision(dataBase moira.Database, trigger *dto.TriggerModel, triggerID string, timeSeriesNames map[string]bool, userLogin string) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	var existing *moira.Trigger
+	saved, err := dataBase.GetTrigger(triggerID)
+	if err != nil && err != database.ErrNil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	if err == nil {
+		existing = &saved
+	}
+	trigger.ID = triggerID
+	response, errorResponse := saveTrigger(dataBase, existing, trigger.ToMoiraTrigger(), triggerID, timeSeriesNames, userLogin)
+	if response != nil {
+		response.Message = "trigger restored"
+	}
+	return response, errorResponse
+}
+
+func getTriggerRevision(dataBase moira.Database, triggerID string, number int64) (moira.TriggerRevision, *api.ErrorResponse) {
+	revision, err := dataBase.GetTriggerRevision(triggerID, number)
+	if err != nil {
+		if err == database.ErrNil {
+			return revision, api.ErrorNotFound(fmt.Sprintf("Revision %d of trigger with ID = '%s' does not exists", number, triggerID))
+		}
+		return revision, api.ErrorInternalServer(err)
+	}
+	return revision, nil
+}
+
+// getFieldChanges compares json representations of two states and returns changed fields sorted by name.
+// Nested objects are compared field by field, arrays are compared as whole values
+func getFieldChanges(old, new interface{}) ([]dto.FieldChange, error) {
+	oldValue, err := toJSONValue(old)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toJSONValue(new)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]dto.FieldChange, 0)
+	collectFieldChanges("", oldValue, newValue, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+func collectFieldChanges(field string, oldValue, newValue interface{}, changes *[]dto.FieldChange) {
+	oldObject, oldIsObject := oldValue.(map[string]interface{})
+	newObject, newIsObject := newValue.(map[string]interface{})
+	if !oldIsObject || !newIsObject {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*changes = append(*changes, dto.FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+		return
+	}
+	for key, value := range oldObject {
+		collectFieldChanges(joinField(field, key), value, newObject[key], changes)
+	}
+	for key, value := range newObject {
+		if _, ok := oldObject[key]; !ok {
+			collectFieldChanges(joinField(field, key), nil, value, changes)
+		}
+	}
+}
+
+func joinField(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+func toJSONValue(state interface{}) (interface{}, error) {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal state: %s", err.Error())
+	}
+	var value interface{}
+	if err := json.Unmarshal(bytes, &value); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal state: %s", err.Error())
+	}
+	return value, nil
+}
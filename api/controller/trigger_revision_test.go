@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestGetTriggerRevisions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	revisions := []*moira.TriggerRevision{{Number: 1, User: "user", Trigger: moira.Trigger{ID: "triggerID"}}}
+
+	Convey("Has revisions", t, func() {
+		dataBase.EXPECT().GetTriggerRevisions("triggerID").Return(revisions, nil)
+		actual, err := GetTriggerRevisions(dataBase, "triggerID")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerRevisionsList{List: revisions})
+	})
+
+	Convey("Get revision", t, func() {
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(1)).Return(*revisions[0], nil)
+		actual, err := GetTriggerRevision(dataBase, "triggerID", 1)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerRevision{TriggerRevision: *revisions[0]})
+	})
+
+	Convey("Revision does not exists", t, func() {
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(2)).Return(moira.TriggerRevision{}, database.ErrNil)
+		actual, err := GetTriggerRevision(dataBase, "triggerID", 2)
+		So(err, ShouldResemble, api.ErrorNotFound("Revision 2 of trigger with ID = 'triggerID' does not exists"))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Errors", t, func() {
+		expected := fmt.Errorf("Oooops! Can not read revisions")
+		dataBase.EXPECT().GetTriggerRevisions("triggerID").Return(nil, expected)
+		actual, err := GetTriggerRevisions(dataBase, "triggerID")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(1)).Return(moira.TriggerRevision{}, expected)
+		revision, err := GetTriggerRevision(dataBase, "triggerID", 1)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(revision, ShouldBeNil)
+	})
+}
+
+func TestGetTriggerRevisionsDiff(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	warn, oldError, newError := 10.0, 20.0, 30.0
+	from := moira.TriggerRevision{Number: 1, Trigger: moira.Trigger{
+		ID:         "triggerID",
+		Name:       "trigger",
+		Targets:    []string{"my.metric"},
+		WarnValue:  &warn,
+		ErrorValue: &oldError,
+		Tags:       []string{"tag"},
+		Schedule:   &moira.ScheduleData{TimezoneOffset: 0, StartOffset: 0, EndOffset: 1439},
+	}}
+	to := moira.TriggerRevision{Number: 2, Trigger: moira.Trigger{
+		ID:         "triggerID",
+		Name:       "renamed trigger",
+		Targets:    []string{"my.metric"},
+		WarnValue:  &warn,
+		ErrorValue: &newError,
+		Tags:       []string{"tag", "other"},
+		Schedule:   &moira.ScheduleData{TimezoneOffset: -180, StartOffset: 0, EndOffset: 1439},
+		TTL:        600,
+	}}
+
+	Convey("Changed fields", t, func() {
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(1)).Return(from, nil)
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(2)).Return(to, nil)
+		actual, err := GetTriggerRevisionsDiff(dataBase, "triggerID", 1, 2)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerRevisionsDiff{
+			TriggerID: "triggerID",
+			From:      1,
+			To:        2,
+			Changes: []dto.FieldChange{
+				{Field: "error_value", Old: 20.0, New: 30.0},
+				{Field: "name", Old: "trigger", New: "renamed trigger"},
+				{Field: "sched.tzOffset", Old: 0.0, New: -180.0},
+				{Field: "tags", Old: []interface{}{"tag"}, New: []interface{}{"tag", "other"}},
+				{Field: "ttl", Old: nil, New: 600.0},
+			},
+		})
+	})
+
+	Convey("Same revision has no changes", t, func() {
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(1)).Return(from, nil).Times(2)
+		actual, err := GetTriggerRevisionsDiff(dataBase, "triggerID", 1, 1)
+		So(err, ShouldBeNil)
+		So(actual.Changes, ShouldBeEmpty)
+	})
+
+	Convey("Revision does not exists", t, func() {
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(1)).Return(from, nil)
+		dataBase.EXPECT().GetTriggerRevision("triggerID", int64(3)).Return(moira.TriggerRevision{}, database.ErrNil)
+		actual, err := GetTriggerRevisionsDiff(dataBase, "triggerID", 1, 3)
+		So(err, ShouldResemble, api.ErrorNotFound("Revision 3 of trigger with ID = 'triggerID' does not exists"))
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestRestoreTriggerRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerModel := dto.TriggerModel{Name: "restored", Tags: []string{"tag"}, Targets: []string{"my.metric"}}
+
+	expectRestore := func(action string) {
+		dataBase.EXPECT().AcquireTriggerCheckLock("triggerID", 10)
+		dataBase.EXPECT().DeleteTriggerCheckLock("triggerID")
+		dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().SetTriggerLastCheck("triggerID", gomock.Any()).Return(nil)
+		dataBase.EXPECT().SaveTrigger("triggerID", gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Action, ShouldEqual, action)
+		}).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision("triggerID", gomock.Any()).Do(func(triggerID string, revision *moira.TriggerRevision) {
+			So(revision.Trigger.ID, ShouldEqual, "triggerID")
+			So(revision.Trigger.Name, ShouldEqual, "restored")
+			So(revision.User, ShouldEqual, "user")
+		}).Return(nil)
+	}
+
+	Convey("Restore existing trigger", t, func() {
+		dataBase.EXPECT().GetTrigger("triggerID").Return(moira.Trigger{ID: "triggerID", Name: "current"}, nil)
+		expectRestore(moira.AuditActionUpdate)
+		actual, err := RestoreTriggerRevision(dataBase, &triggerModel, "triggerID", make(map[string]bool), "user")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.SaveTriggerResponse{ID: "triggerID", Message: "trigger restored"})
+	})
+
+	Convey("Restore removed trigger", t, func() {
+		dataBase.EXPECT().GetTrigger("triggerID").Return(moira.Trigger{}, database.ErrNil)
+		expectRestore(moira.AuditActionCreate)
+		actual, err := RestoreTriggerRevision(dataBase, &triggerModel, "triggerID", make(map[string]bool), "user")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.SaveTriggerResponse{ID: "triggerID", Message: "trigger restored"})
+	})
+
+	Convey("Get trigger error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not read trigger")
+		dataBase.EXPECT().GetTrigger("triggerID").Return(moira.Trigger{}, expected)
+		actual, err := RestoreTriggerRevision(dataBase, &triggerModel, "triggerID", make(map[string]bool), "user")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
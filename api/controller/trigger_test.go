@@ -8,6 +8,7 @@ import (
 	"github.com/moira-alert/moira/api/dto"
 	"github.com/moira-alert/moira/database"
 	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/op/go-logging"
 	"github.com/satori/go.uuid"
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
@@ -28,7 +29,17 @@ func TestUpdateTrigger(t *testing.T) {
 		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
 		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(gomock.Any(), trigger).Return(nil)
-		resp, err := UpdateTrigger(dataBase, &triggerModel, triggerModel.ID, make(map[string]bool))
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "user")
+			So(record.Entity, ShouldEqual, moira.AuditEntityTrigger)
+			So(record.EntityID, ShouldEqual, triggerModel.ID)
+			So(record.Action, ShouldEqual, moira.AuditActionUpdate)
+		}).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(triggerModel.ID, gomock.Any()).Do(func(triggerID string, revision *moira.TriggerRevision) {
+			So(revision.User, ShouldEqual, "user")
+			So(revision.Trigger.ID, ShouldEqual, triggerModel.ID)
+		}).Return(nil)
+		resp, err := UpdateTrigger(dataBase, &triggerModel, triggerModel.ID, make(map[string]bool), "user")
 		So(err, ShouldBeNil)
 		So(resp.Message, ShouldResemble, "trigger updated")
 	})
@@ -36,7 +47,7 @@ func TestUpdateTrigger(t *testing.T) {
 	Convey("Trigger does not exists", t, func() {
 		trigger := dto.TriggerModel{ID: uuid.NewV4().String()}
 		dataBase.EXPECT().GetTrigger(trigger.ID).Return(moira.Trigger{}, database.ErrNil)
-		resp, err := UpdateTrigger(dataBase, &trigger, trigger.ID, make(map[string]bool))
+		resp, err := UpdateTrigger(dataBase, &trigger, trigger.ID, make(map[string]bool), "user")
 		So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("Trigger with ID = '%s' does not exists", trigger.ID)))
 		So(resp, ShouldBeNil)
 	})
@@ -45,7 +56,7 @@ func TestUpdateTrigger(t *testing.T) {
 		trigger := dto.TriggerModel{ID: uuid.NewV4().String()}
 		expected := fmt.Errorf("Soo bad trigger")
 		dataBase.EXPECT().GetTrigger(trigger.ID).Return(moira.Trigger{}, expected)
-		resp, err := UpdateTrigger(dataBase, &trigger, trigger.ID, make(map[string]bool))
+		resp, err := UpdateTrigger(dataBase, &trigger, trigger.ID, make(map[string]bool), "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(resp, ShouldBeNil)
 	})
@@ -74,7 +85,9 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
 			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+			dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldBeNil)
 			So(resp, ShouldResemble, &dto.SaveTriggerResponse{ID: triggerID, Message: "trigger updated"})
 		})
@@ -85,7 +98,9 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(actualLastCheck, nil)
 			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &actualLastCheck).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+			dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldBeNil)
 			So(resp, ShouldResemble, &dto.SaveTriggerResponse{ID: triggerID, Message: "trigger updated"})
 			So(actualLastCheck, ShouldResemble, emptyLastCheck)
@@ -99,17 +114,36 @@ func TestSaveTrigger(t *testing.T) {
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
 		dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
-		resp, err := saveTrigger(dataBase, &trigger, triggerID, map[string]bool{"super.metric1": true, "super.metric2": true})
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+		resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, map[string]bool{"super.metric1": true, "super.metric2": true}, "user")
 		So(err, ShouldBeNil)
 		So(resp, ShouldResemble, &dto.SaveTriggerResponse{ID: triggerID, Message: "trigger updated"})
 		So(actualLastCheck, ShouldResemble, lastCheck)
 	})
 
+	Convey("Unknown timeSeries of remote trigger", t, func() {
+		actualLastCheck := moira.CheckData{
+			Metrics: map[string]moira.MetricState{"super.metric1": {}},
+		}
+		dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
+		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(actualLastCheck, nil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &actualLastCheck).Return(nil)
+		dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+		resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, nil, "user")
+		So(err, ShouldBeNil)
+		So(resp, ShouldResemble, &dto.SaveTriggerResponse{ID: triggerID, Message: "trigger updated"})
+		So(actualLastCheck.Metrics, ShouldContainKey, "super.metric1")
+	})
+
 	Convey("Errors", t, func() {
 		Convey("AcquireTriggerCheckLock error", func() {
 			expected := fmt.Errorf("AcquireTriggerCheckLock error")
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10).Return(expected)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
 			So(resp, ShouldBeNil)
 		})
@@ -119,7 +153,7 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, expected)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
 			So(resp, ShouldBeNil)
 		})
@@ -130,7 +164,34 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
 			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(expected)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(resp, ShouldBeNil)
+		})
+
+		Convey("AddAuditRecord error does not fail saved trigger", func() {
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
+			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
+			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
+			dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(fmt.Errorf("AddAuditRecord error"))
+			dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(nil)
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
+			So(err, ShouldBeNil)
+			So(resp, ShouldResemble, &dto.SaveTriggerResponse{ID: triggerID, Message: "trigger updated"})
+		})
+
+		Convey("AddTriggerRevision error", func() {
+			expected := fmt.Errorf("AddTriggerRevision error")
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
+			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
+			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
+			dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+			dataBase.EXPECT().AddTriggerRevision(triggerID, gomock.Any()).Return(expected)
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
 			So(resp, ShouldBeNil)
 		})
@@ -142,13 +203,100 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
 			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(expected)
-			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
+			resp, err := saveTrigger(dataBase, nil, &trigger, triggerID, make(map[string]bool), "user")
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
 			So(resp, ShouldBeNil)
 		})
 	})
 }
 
+func TestCheckAggregateCycles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Not aggregate trigger", t, func() {
+		So(checkAggregateCycles(dataBase, &moira.Trigger{}, "a"), ShouldBeNil)
+	})
+
+	Convey("No cycles", t, func() {
+		trigger := &moira.Trigger{Aggregate: &moira.AggregateSettings{TriggerIDs: []string{"b", "c"}}}
+		dataBase.EXPECT().GetTrigger("b").Return(moira.Trigger{ID: "b", Aggregate: &moira.AggregateSettings{TriggerIDs: []string{"c"}}}, nil)
+		dataBase.EXPECT().GetTrigger("c").Return(moira.Trigger{ID: "c"}, nil)
+		So(checkAggregateCycles(dataBase, trigger, "a"), ShouldBeNil)
+	})
+
+	Convey("Trigger selects itself by tags", t, func() {
+		trigger := &moira.Trigger{Tags: []string{"api"}, Aggregate: &moira.AggregateSettings{Tags: []string{"api"}}}
+		dataBase.EXPECT().GetTagTriggerIDs("api").Return([]string{}, nil)
+		err := checkAggregateCycles(dataBase, trigger, "a")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger dependencies contain cycle: a -> a")))
+	})
+
+	Convey("Cycle through other aggregate trigger", t, func() {
+		trigger := &moira.Trigger{Tags: []string{"api"}, Aggregate: &moira.AggregateSettings{TriggerIDs: []string{"b"}}}
+		dataBase.EXPECT().GetTrigger("b").Return(moira.Trigger{ID: "b", Aggregate: &moira.AggregateSettings{Tags: []string{"api"}}}, nil)
+		dataBase.EXPECT().GetTagTriggerIDs("api").Return([]string{"c"}, nil)
+		err := checkAggregateCycles(dataBase, trigger, "a")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger dependencies contain cycle: a -> b -> a")))
+	})
+
+	Convey("Get trigger error", t, func() {
+		expected := fmt.Errorf("oops")
+		trigger := &moira.Trigger{Aggregate: &moira.AggregateSettings{TriggerIDs: []string{"b"}}}
+		dataBase.EXPECT().GetTrigger("b").Return(moira.Trigger{}, expected)
+		So(checkAggregateCycles(dataBase, trigger, "a"), ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestCheckInhibitionCycles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	states := []string{"ERROR"}
+
+	Convey("Not parent trigger", t, func() {
+		So(checkInhibitionCycles(dataBase, &moira.Trigger{}, "a"), ShouldBeNil)
+	})
+
+	Convey("No cycles", t, func() {
+		trigger := &moira.Trigger{Tags: []string{"network"}, Inhibits: &moira.InhibitionSettings{Tags: []string{"api"}, States: states}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{"a", "b"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"a", "b"}).Return([]*moira.Trigger{
+			{ID: "a", Tags: []string{"network"}, Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"b"}, States: states}},
+			{ID: "b", Tags: []string{"api"}, Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"c"}, States: states}},
+		}, nil)
+		So(checkInhibitionCycles(dataBase, trigger, "a"), ShouldBeNil)
+	})
+
+	Convey("Trigger suppresses itself", t, func() {
+		trigger := &moira.Trigger{Tags: []string{"api"}, Inhibits: &moira.InhibitionSettings{Tags: []string{"api"}, States: states}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggers([]string{}).Return([]*moira.Trigger{}, nil)
+		So(checkInhibitionCycles(dataBase, trigger, "a"), ShouldBeNil)
+	})
+
+	Convey("Cycle through other parent triggers", t, func() {
+		trigger := &moira.Trigger{Tags: []string{"network"}, Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"b"}, States: states}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{"b", "c", "d"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"b", "c", "d"}).Return([]*moira.Trigger{
+			{ID: "b", Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"c"}, States: states}},
+			{ID: "c", Inhibits: &moira.InhibitionSettings{Tags: []string{"network"}, States: states}},
+			nil,
+		}, nil)
+		err := checkInhibitionCycles(dataBase, trigger, "a")
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger inhibition contains cycle: a -> b -> c -> a")))
+	})
+
+	Convey("Get triggers error", t, func() {
+		expected := fmt.Errorf("oops")
+		trigger := &moira.Trigger{Inhibits: &moira.InhibitionSettings{TriggerIDs: []string{"b"}, States: states}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{"b"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"b"}).Return(nil, expected)
+		So(checkInhibitionCycles(dataBase, trigger, "a"), ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
 func TestGetTrigger(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -206,88 +354,47 @@ func TestRemoveTrigger(t *testing.T) {
 	defer mockCtrl.Finish()
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
 	triggerID := uuid.NewV4().String()
+	trigger := moira.Trigger{ID: triggerID}
 
 	Convey("Success", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().RemoveTrigger(triggerID).Return(nil)
 		dataBase.EXPECT().RemoveTriggerLastCheck(triggerID).Return(nil)
-		err := RemoveTrigger(dataBase, triggerID)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "user")
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+			So(record.After, ShouldBeNil)
+		}).Return(nil)
+		err := RemoveTrigger(dataBase, triggerID, "user")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Trigger does not exists", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+		dataBase.EXPECT().RemoveTrigger(triggerID).Return(nil)
+		dataBase.EXPECT().RemoveTriggerLastCheck(triggerID).Return(nil)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldBeNil)
 	})
 
 	Convey("Error remove trigger", t, func() {
 		expected := fmt.Errorf("Oooops! Error delete")
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().RemoveTrigger(triggerID).Return(expected)
-		err := RemoveTrigger(dataBase, triggerID)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 
 	Convey("Error remove last check", t, func() {
 		expected := fmt.Errorf("Oooops! Error delete")
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().RemoveTrigger(triggerID).Return(nil)
 		dataBase.EXPECT().RemoveTriggerLastCheck(triggerID).Return(expected)
-		err := RemoveTrigger(dataBase, triggerID)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
 
-func TestGetTriggerThrottling(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
-	triggerID := uuid.NewV4().String()
-	begging := time.Unix(0, 0)
-	now := time.Now()
-	tomorrow := now.Add(time.Hour * 24)
-	yesterday := now.Add(-time.Hour * 24)
-
-	Convey("no throttling", t, func() {
-		dataBase.EXPECT().GetTriggerThrottling(triggerID).Return(begging, begging)
-		actual, err := GetTriggerThrottling(dataBase, triggerID)
-		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, &dto.ThrottlingResponse{Throttling: 0})
-	})
-
-	Convey("has throttling", t, func() {
-		dataBase.EXPECT().GetTriggerThrottling(triggerID).Return(tomorrow, begging)
-		actual, err := GetTriggerThrottling(dataBase, triggerID)
-		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, &dto.ThrottlingResponse{Throttling: tomorrow.Unix()})
-	})
-
-	Convey("has old throttling", t, func() {
-		dataBase.EXPECT().GetTriggerThrottling(triggerID).Return(yesterday, begging)
-		actual, err := GetTriggerThrottling(dataBase, triggerID)
-		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, &dto.ThrottlingResponse{Throttling: 0})
-	})
-}
-
-func TestGetTriggerLastCheck(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
-	triggerID := uuid.NewV4().String()
-	lastCheck := moira.CheckData{}
-
-	Convey("Success", t, func() {
-		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
-		check, err := GetTriggerLastCheck(dataBase, triggerID)
-		So(err, ShouldBeNil)
-		So(check, ShouldResemble, &dto.TriggerCheck{
-			TriggerID: triggerID,
-			CheckData: &lastCheck,
-		})
-	})
-
-	Convey("Error", t, func() {
-		expected := fmt.Errorf("Oooops! Error get")
-		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, expected)
-		check, err := GetTriggerLastCheck(dataBase, triggerID)
-		So(err, ShouldResemble, api.ErrorInternalServer(expected))
-		So(check, ShouldBeNil)
-	})
-}
-
 func TestDeleteTriggerThrottling(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -295,19 +402,25 @@ func TestDeleteTriggerThrottling(t *testing.T) {
 	triggerID := uuid.NewV4().String()
 
 	Convey("Success", t, func() {
+		dataBase.EXPECT().GetTriggerThrottling(triggerID).Return(time.Now().Add(time.Hour), time.Now())
 		dataBase.EXPECT().DeleteTriggerThrottling(triggerID).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityThrottling)
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
 		var total int64
 		var to int64 = -1
 		dataBase.EXPECT().GetNotifications(total, to).Return(make([]*moira.ScheduledNotification, 0), total, nil)
 		dataBase.EXPECT().AddNotifications(make([]*moira.ScheduledNotification, 0), gomock.Any()).Return(nil)
-		err := DeleteTriggerThrottling(dataBase, triggerID)
+		err := DeleteTriggerThrottling(dataBase, triggerID, "user")
 		So(err, ShouldBeNil)
 	})
 
 	Convey("Error", t, func() {
 		expected := fmt.Errorf("Oooops! Error delete")
+		dataBase.EXPECT().GetTriggerThrottling(triggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
 		dataBase.EXPECT().DeleteTriggerThrottling(triggerID).Return(expected)
-		err := DeleteTriggerThrottling(dataBase, triggerID)
+		err := DeleteTriggerThrottling(dataBase, triggerID, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
@@ -422,18 +535,67 @@ func TestSetMetricsMaintenance(t *testing.T) {
 	defer mockCtrl.Finish()
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
 	triggerID := uuid.NewV4().String()
-	maintenance := make(map[string]int64)
+	maintenance := dto.MetricsMaintenance{"metric1": 1000}
+	lastCheck := moira.CheckData{Metrics: map[string]moira.MetricState{"metric1": {Maintenance: 500}}}
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64(maintenance)).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityMaintenance)
+			So(record.Action, ShouldEqual, moira.AuditActionUpdate)
+			So(string(record.Before), ShouldEqual, `{"metric1":500}`)
+			So(string(record.After), ShouldEqual, `{"metric1":1000}`)
+		}).Return(nil)
+		err := SetMetricsMaintenance(dataBase, triggerID, maintenance, "user")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Error set")
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64(maintenance)).Return(expected)
+		err := SetMetricsMaintenance(dataBase, triggerID, maintenance, "user")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestAcknowledgeTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := uuid.NewV4().String()
+	metrics := []string{"super.puper.metric"}
 
 	Convey("Success", t, func() {
-		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance(triggerID, maintenance).Return(nil)
-		err := SetMetricsMaintenance(dataBase, triggerID, maintenance)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, metrics, gomock.Any()).Do(func(triggerID string, metrics []string, ack *moira.Acknowledgement) {
+			So(ack.User, ShouldEqual, "user")
+			So(ack.Comment, ShouldEqual, "investigating")
+			So(ack.Timestamp, ShouldBeGreaterThan, 0)
+		}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityAcknowledgement)
+			So(record.Action, ShouldEqual, moira.AuditActionCreate)
+			So(string(record.After), ShouldEqual, `{"metrics":["super.puper.metric"],"comment":"investigating"}`)
+		}).Return(nil)
+		err := AcknowledgeTrigger(dataBase, triggerID, "user", dto.Acknowledgement{Metrics: metrics, Comment: "investigating"})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Remove", t, func() {
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, metrics, (*moira.Acknowledgement)(nil)).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.User, ShouldEqual, "user")
+			So(record.Action, ShouldEqual, moira.AuditActionDelete)
+		}).Return(nil)
+		err := RemoveTriggerAcknowledgement(dataBase, triggerID, metrics, "user")
 		So(err, ShouldBeNil)
 	})
 
 	Convey("Error", t, func() {
 		expected := fmt.Errorf("Oooops! Error set")
-		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance(triggerID, maintenance).Return(expected)
-		err := SetMetricsMaintenance(dataBase, triggerID, maintenance)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, metrics, gomock.Any()).Return(expected)
+		err := AcknowledgeTrigger(dataBase, triggerID, "user", dto.Acknowledgement{Metrics: metrics})
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
@@ -516,3 +678,96 @@ func TestGetTriggerMetrics(t *testing.T) {
 	})
 
 }
+
+func TestGetTriggerHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := uuid.NewV4().String()
+	var from int64 = 1000
+	var to int64 = 2000
+
+	Convey("Trigger and metrics state intervals", t, func() {
+		snapshots := []*moira.TriggerStateSnapshot{
+			{Timestamp: 900, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+			{Timestamp: 1200, State: "OK", Metrics: map[string]string{"m1": "ERROR", "m2": "OK"}},
+			{Timestamp: 1500, State: "EXCEPTION", Metrics: map[string]string{"m1": "ERROR"}},
+			{Timestamp: 1800, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+		}
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, from, to).Return(snapshots, nil)
+		actual, err := GetTriggerHistory(dataBase, triggerID, from, to)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerHistory{
+			TriggerID: triggerID,
+			Trigger: []dto.StateInterval{
+				{Start: 1000, End: 1500, State: "OK"},
+				{Start: 1500, End: 1800, State: "EXCEPTION"},
+				{Start: 1800, End: 2000, State: "OK"},
+			},
+			Metrics: map[string][]dto.StateInterval{
+				"m1": {
+					{Start: 1000, End: 1200, State: "OK"},
+					{Start: 1200, End: 1800, State: "ERROR"},
+					{Start: 1800, End: 2000, State: "OK"},
+				},
+				"m2": {
+					{Start: 1200, End: 1500, State: "OK"},
+				},
+			},
+		})
+	})
+
+	Convey("Empty history", t, func() {
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, from, to).Return(make([]*moira.TriggerStateSnapshot, 0), nil)
+		actual, err := GetTriggerHistory(dataBase, triggerID, from, to)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerHistory{
+			TriggerID: triggerID,
+			Trigger:   make([]dto.StateInterval, 0),
+			Metrics:   make(map[string][]dto.StateInterval),
+		})
+	})
+
+	Convey("Invalid time range", t, func() {
+		actual, err := GetTriggerHistory(dataBase, triggerID, to, from)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to")))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Get history error", t, func() {
+		expected := fmt.Errorf("oops")
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, from, to).Return(nil, expected)
+		actual, err := GetTriggerHistory(dataBase, triggerID, from, to)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestBacktestTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+
+	Convey("Metrics without values", t, func() {
+		trigger := dto.TriggerModel{Targets: []string{"my.metric"}, Patterns: []string{"my.metric"}}
+		dataBase.EXPECT().GetPatternMetrics("my.metric").Return([]string{}, nil)
+		actual, err := BacktestTrigger(dataBase, logger, &trigger, 1000, 2000)
+		So(err, ShouldBeNil)
+		So(actual.Transitions, ShouldBeEmpty)
+		So(actual.Events, ShouldBeEmpty)
+	})
+
+	Convey("Aggregate trigger", t, func() {
+		trigger := dto.TriggerModel{Aggregate: &moira.AggregateSettings{Tags: []string{"api"}}}
+		actual, err := BacktestTrigger(dataBase, logger, &trigger, 1000, 2000)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Aggregate trigger can not be backtested")))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Invalid time range", t, func() {
+		actual, err := BacktestTrigger(dataBase, logger, &dto.TriggerModel{}, 2000, 1000)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("from can not be later than to")))
+		So(actual, ShouldBeNil)
+	})
+}
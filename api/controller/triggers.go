@@ -12,7 +12,7 @@ import (
 )
 
 // CreateTrigger creates new trigger
-func CreateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+func CreateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, timeSeriesNames map[string]bool, userLogin string) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
 	if trigger.ID == "" {
 		trigger.ID = uuid.NewV4().String()
 	} else {
@@ -24,7 +24,7 @@ func CreateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, timeSerie
 			return nil, api.ErrorInvalidRequest(fmt.Errorf("Trigger with this ID already exists"))
 		}
 	}
-	resp, err := saveTrigger(dataBase, trigger.ToMoiraTrigger(), trigger.ID, timeSeriesNames)
+	resp, err := saveTrigger(dataBase, nil, trigger.ToMoiraTrigger(), trigger.ID, timeSeriesNames, userLogin)
 	if resp != nil {
 		resp.Message = "trigger created"
 	}
@@ -42,8 +42,8 @@ func isTriggerExists(dataBase moira.Database, triggerID string) (bool, error) {
 	return true, nil
 }
 
-// GetAllTriggers gets all moira triggers
-func GetAllTriggers(database moira.Database) (*dto.TriggersList, *api.ErrorResponse) {
+// GetAllTriggers gets all moira triggers visible to user, triggers owned by team are visible only to its members
+func GetAllTriggers(database moira.Database, userLogin string) (*dto.TriggersList, *api.ErrorResponse) {
 	triggerIDs, err := database.GetTriggerIDs()
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
@@ -52,23 +52,31 @@ func GetAllTriggers(database moira.Database) (*dto.TriggersList, *api.ErrorRespo
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
+	teamIDs, err := getUserTeamIDs(database, userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
 	triggersList := dto.TriggersList{
 		List: make([]moira.TriggerCheck, 0),
 	}
 	for _, triggerCheck := range triggerChecks {
-		if triggerCheck != nil {
+		if triggerCheck != nil && isTriggerVisible(&triggerCheck.Trigger, teamIDs) {
 			triggersList.List = append(triggersList.List, *triggerCheck)
 		}
 	}
 	return &triggersList, nil
 }
 
-// GetTriggerPage gets trigger page and filter trigger by tags and errors
-func GetTriggerPage(database moira.Database, page int64, size int64, onlyErrors bool, filterTags []string) (*dto.TriggersList, *api.ErrorResponse) {
+// GetTriggerPage gets trigger page and filter trigger by tags and errors, triggers owned by team are visible only to its members
+func GetTriggerPage(database moira.Database, page int64, size int64, onlyErrors bool, filterTags []string, userLogin string) (*dto.TriggersList, *api.ErrorResponse) {
 	triggerIDs, err := database.GetTriggerCheckIDs(filterTags, onlyErrors)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
+	triggerIDs, err = getVisibleTriggerIDs(database, triggerIDs, userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
 	total := int64(len(triggerIDs))
 	triggerIDs = getTriggerIdsRange(triggerIDs, total, page, size)
 	triggerChecks, err := database.GetTriggerChecks(triggerIDs)
@@ -90,6 +98,29 @@ func GetTriggerPage(database moira.Database, page int64, size int64, onlyErrors
 	return &triggersList, nil
 }
 
+// getVisibleTriggerIDs filters out triggers owned by teams user is not member of, it is done before paging to keep pages full
+func getVisibleTriggerIDs(database moira.Database, triggerIDs []string, userLogin string) ([]string, error) {
+	teamIDs, err := getUserTeamIDs(database, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := database.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	visibleIDs := make([]string, 0, len(triggerIDs))
+	for i, trigger := range triggers {
+		if trigger == nil || isTriggerVisible(trigger, teamIDs) {
+			visibleIDs = append(visibleIDs, triggerIDs[i])
+		}
+	}
+	return visibleIDs, nil
+}
+
+func isTriggerVisible(trigger *moira.Trigger, userTeamIDs map[string]bool) bool {
+	return trigger.TeamID == "" || userTeamIDs[trigger.TeamID]
+}
+
 func getTriggerIdsRange(triggerIDs []string, total int64, page int64, size int64) []string {
 	from := page * size
 	to := (page + 1) * size
@@ -25,7 +25,9 @@ func TestCreateTrigger(t *testing.T) {
 		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
 		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(gomock.Any(), gomock.Any()).Return(nil)
-		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool))
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(gomock.Any(), gomock.Any()).Return(nil)
+		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool), "user")
 		So(err, ShouldBeNil)
 		So(resp.Message, ShouldResemble, "trigger created")
 	})
@@ -39,7 +41,12 @@ func TestCreateTrigger(t *testing.T) {
 		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
 		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(gomock.Any(), triggerModel.ToMoiraTrigger()).Return(nil)
-		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool))
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Action, ShouldEqual, moira.AuditActionCreate)
+			So(record.Before, ShouldBeNil)
+		}).Return(nil)
+		dataBase.EXPECT().AddTriggerRevision(gomock.Any(), gomock.Any()).Return(nil)
+		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool), "user")
 		So(err, ShouldBeNil)
 		So(resp.Message, ShouldResemble, "trigger created")
 		So(resp.ID, ShouldResemble, triggerID)
@@ -49,7 +56,7 @@ func TestCreateTrigger(t *testing.T) {
 		triggerModel := dto.TriggerModel{ID: uuid.NewV4().String()}
 		trigger := triggerModel.ToMoiraTrigger()
 		dataBase.EXPECT().GetTrigger(triggerModel.ID).Return(*trigger, nil)
-		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool))
+		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool), "user")
 		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Trigger with this ID already exists")))
 		So(resp, ShouldBeNil)
 	})
@@ -58,7 +65,7 @@ func TestCreateTrigger(t *testing.T) {
 		trigger := dto.TriggerModel{ID: uuid.NewV4().String()}
 		expected := fmt.Errorf("Soo bad trigger")
 		dataBase.EXPECT().GetTrigger(trigger.ID).Return(moira.Trigger{}, expected)
-		resp, err := CreateTrigger(dataBase, &trigger, make(map[string]bool))
+		resp, err := CreateTrigger(dataBase, &trigger, make(map[string]bool), "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(resp, ShouldBeNil)
 	})
@@ -72,7 +79,7 @@ func TestCreateTrigger(t *testing.T) {
 		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
 		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(gomock.Any(), triggerModel.ToMoiraTrigger()).Return(expected)
-		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool))
+		resp, err := CreateTrigger(dataBase, &triggerModel, make(map[string]bool), "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(resp, ShouldBeNil)
 	})
@@ -89,7 +96,8 @@ func TestGetAllTriggers(t *testing.T) {
 		triggersList := []moira.TriggerCheck{{Trigger: moira.Trigger{ID: triggerIDs[0]}}, {Trigger: moira.Trigger{ID: triggerIDs[1]}}}
 		database.EXPECT().GetTriggerIDs().Return(triggerIDs, nil)
 		database.EXPECT().GetTriggerChecks(triggerIDs).Return(triggers, nil)
-		list, err := GetAllTriggers(database)
+		database.EXPECT().GetUserTeamIDs("user").Return(nil, nil)
+		list, err := GetAllTriggers(database, "user")
 		So(err, ShouldBeNil)
 		So(list, ShouldResemble, &dto.TriggersList{List: triggersList})
 	})
@@ -97,15 +105,31 @@ func TestGetAllTriggers(t *testing.T) {
 	Convey("No triggers", t, func() {
 		database.EXPECT().GetTriggerIDs().Return(make([]string, 0), nil)
 		database.EXPECT().GetTriggerChecks(make([]string, 0)).Return(make([]*moira.TriggerCheck, 0), nil)
-		list, err := GetAllTriggers(database)
+		database.EXPECT().GetUserTeamIDs("user").Return(nil, nil)
+		list, err := GetAllTriggers(database, "user")
 		So(err, ShouldBeNil)
 		So(list, ShouldResemble, &dto.TriggersList{List: make([]moira.TriggerCheck, 0)})
 	})
 
+	Convey("Triggers of teams user is not member of are hidden", t, func() {
+		triggerIDs := []string{"t1", "t2", "t3"}
+		triggers := []*moira.TriggerCheck{
+			{Trigger: moira.Trigger{ID: "t1"}},
+			{Trigger: moira.Trigger{ID: "t2", TeamID: "team"}},
+			{Trigger: moira.Trigger{ID: "t3", TeamID: "other"}},
+		}
+		database.EXPECT().GetTriggerIDs().Return(triggerIDs, nil)
+		database.EXPECT().GetTriggerChecks(triggerIDs).Return(triggers, nil)
+		database.EXPECT().GetUserTeamIDs("user").Return([]string{"team"}, nil)
+		list, err := GetAllTriggers(database, "user")
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.TriggersList{List: []moira.TriggerCheck{*triggers[0], *triggers[1]}})
+	})
+
 	Convey("GetTriggerIDs error", t, func() {
 		expected := fmt.Errorf("GetTriggerIDs error")
 		database.EXPECT().GetTriggerIDs().Return(nil, expected)
-		list, err := GetAllTriggers(database)
+		list, err := GetAllTriggers(database, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(list, ShouldBeNil)
 	})
@@ -114,7 +138,7 @@ func TestGetAllTriggers(t *testing.T) {
 		expected := fmt.Errorf("GetTriggerChecks error")
 		database.EXPECT().GetTriggerIDs().Return(make([]string, 0), nil)
 		database.EXPECT().GetTriggerChecks(make([]string, 0)).Return(nil, expected)
-		list, err := GetAllTriggers(database)
+		list, err := GetAllTriggers(database, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(list, ShouldBeNil)
 	})
@@ -174,13 +198,19 @@ func TestGetTriggerPage(t *testing.T) {
 	for i := range triggerIDs {
 		triggersPointers[i] = &moira.TriggerCheck{Trigger: moira.Trigger{ID: triggerIDs[i]}}
 	}
+	allTriggers := make([]*moira.Trigger, 20)
+	for i := range triggerIDs {
+		allTriggers[i] = &moira.Trigger{ID: triggerIDs[i]}
+	}
 
 	Convey("Has tags and only errors", t, func() {
 		tags := []string{"tag1", "tag2"}
 		var exp int64 = 20
 		database.EXPECT().GetTriggerCheckIDs(tags, true).Return(triggerIDs, nil)
+		database.EXPECT().GetUserTeamIDs("user").Return(nil, nil)
+		database.EXPECT().GetTriggers(triggerIDs).Return(allTriggers, nil)
 		database.EXPECT().GetTriggerChecks(triggerIDs[0:10]).Return(triggersPointers[0:10], nil)
-		list, err := GetTriggerPage(database, page, size, true, tags)
+		list, err := GetTriggerPage(database, page, size, true, tags, "user")
 		So(err, ShouldBeNil)
 		So(list, ShouldResemble, &dto.TriggersList{
 			List:  triggers[0:10],
@@ -193,8 +223,10 @@ func TestGetTriggerPage(t *testing.T) {
 	Convey("All triggers", t, func() {
 		var exp int64 = 20
 		database.EXPECT().GetTriggerCheckIDs(make([]string, 0), false).Return(triggerIDs, nil)
+		database.EXPECT().GetUserTeamIDs("user").Return(nil, nil)
+		database.EXPECT().GetTriggers(triggerIDs).Return(allTriggers, nil)
 		database.EXPECT().GetTriggerChecks(triggerIDs[0:10]).Return(triggersPointers[0:10], nil)
-		list, err := GetTriggerPage(database, page, size, false, make([]string, 0))
+		list, err := GetTriggerPage(database, page, size, false, make([]string, 0), "user")
 		So(err, ShouldBeNil)
 		So(list, ShouldResemble, &dto.TriggersList{
 			List:  triggers[0:10],
@@ -204,10 +236,27 @@ func TestGetTriggerPage(t *testing.T) {
 		})
 	})
 
+	Convey("Triggers of teams user is not member of are not counted", t, func() {
+		var exp int64 = 1
+		teamTriggers := []*moira.Trigger{{ID: "t1", TeamID: "other"}, {ID: "t2"}}
+		database.EXPECT().GetTriggerCheckIDs(make([]string, 0), false).Return([]string{"t1", "t2"}, nil)
+		database.EXPECT().GetUserTeamIDs("user").Return([]string{"team"}, nil)
+		database.EXPECT().GetTriggers([]string{"t1", "t2"}).Return(teamTriggers, nil)
+		database.EXPECT().GetTriggerChecks([]string{"t2"}).Return([]*moira.TriggerCheck{{Trigger: *teamTriggers[1]}}, nil)
+		list, err := GetTriggerPage(database, page, size, false, make([]string, 0), "user")
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.TriggersList{
+			List:  []moira.TriggerCheck{{Trigger: *teamTriggers[1]}},
+			Total: &exp,
+			Page:  &page,
+			Size:  &size,
+		})
+	})
+
 	Convey("Error GetFilteredTriggerCheckIDs", t, func() {
 		expected := fmt.Errorf("GetFilteredTriggerCheckIDs error")
 		database.EXPECT().GetTriggerCheckIDs(make([]string, 0), true).Return(nil, expected)
-		list, err := GetTriggerPage(database, 0, 20, true, make([]string, 0))
+		list, err := GetTriggerPage(database, 0, 20, true, make([]string, 0), "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(list, ShouldBeNil)
 	})
@@ -215,8 +264,10 @@ func TestGetTriggerPage(t *testing.T) {
 	Convey("Error GetTriggerChecks", t, func() {
 		expected := fmt.Errorf("GetTriggerChecks error")
 		database.EXPECT().GetTriggerCheckIDs(make([]string, 0), false).Return(triggerIDs, nil)
+		database.EXPECT().GetUserTeamIDs("user").Return(nil, nil)
+		database.EXPECT().GetTriggers(triggerIDs).Return(allTriggers, nil)
 		database.EXPECT().GetTriggerChecks(triggerIDs[0:10]).Return(nil, expected)
-		list, err := GetTriggerPage(database, page, size, false, make([]string, 0))
+		list, err := GetTriggerPage(database, page, size, false, make([]string, 0), "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 		So(list, ShouldBeNil)
 	})
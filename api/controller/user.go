@@ -1,46 +1,99 @@
 package controller
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/database"
 )
 
-// GetUserSettings gets user contacts and subscriptions
-func GetUserSettings(database moira.Database, userLogin string) (*dto.UserSettings, *api.ErrorResponse) {
-	userSettings := &dto.UserSettings{
-		User:          dto.User{Login: userLogin},
-		Contacts:      make([]moira.ContactData, 0),
-		Subscriptions: make([]moira.SubscriptionData, 0),
-	}
+const userTokenSecretSize = 32
 
-	subscriptionIDs, err := database.GetUserSubscriptionIDs(userLogin)
+// GetUserSettings gets user teams with contacts and subscriptions of user and user teams
+func GetUserSettings(database moira.Database, userLogin string) (*dto.UserSettings, *api.ErrorResponse) {
+	teams, err := getUserTeams(database, userLogin)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
-
-	subscriptions, err := database.GetSubscriptions(subscriptionIDs)
+	subscriptions, err := getUserSubscriptions(database, userLogin, teams)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
-	for _, subscription := range subscriptions {
-		if subscription != nil {
-			userSettings.Subscriptions = append(userSettings.Subscriptions, *subscription)
-		}
-	}
-	contactIDs, err := database.GetUserContactIDs(userLogin)
+	contacts, err := getUserContacts(database, userLogin, teams)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
+	return &dto.UserSettings{
+		User:          dto.User{Login: userLogin},
+		Contacts:      contacts,
+		Subscriptions: subscriptions,
+		Teams:         teams,
+	}, nil
+}
 
-	contacts, err := database.GetContacts(contactIDs)
+// GetUserTokens gets API tokens issued to user, token secrets are never returned
+func GetUserTokens(dataBase moira.Database, userLogin string) (*dto.UserTokensList, *api.ErrorResponse) {
+	tokens, err := dataBase.GetUserTokens(userLogin)
 	if err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
-	for _, contact := range contacts {
-		if contact != nil {
-			userSettings.Contacts = append(userSettings.Contacts, *contact)
+	list := &dto.UserTokensList{List: make([]dto.UserToken, 0, len(tokens))}
+	for _, token := range tokens {
+		list.List = append(list.List, dto.UserToken{ID: token.ID, Name: token.Name, CreatedAt: token.CreatedAt})
+	}
+	return list, nil
+}
+
+// CreateUserToken issues new API token to user, token is returned only once as only its hash is stored
+func CreateUserToken(dataBase moira.Database, token *dto.UserToken, userLogin string) (*dto.UserToken, *api.ErrorResponse) {
+	if userLogin == "" {
+		return nil, api.ErrorForbidden("API tokens can not be issued to anonymous user")
+	}
+	secretBytes := make([]byte, userTokenSecretSize)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, api.ErrorInternalServer(fmt.Errorf("Failed to generate token secret: %s", err.Error()))
+	}
+	secret := hex.EncodeToString(secretBytes)
+	userToken := &moira.UserToken{
+		ID:        uuid.NewV4().String(),
+		User:      userLogin,
+		Name:      token.Name,
+		Hash:      moira.HashTokenSecret(secret),
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := dataBase.SaveUserToken(userToken); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.UserToken{
+		ID:        userToken.ID,
+		Name:      userToken.Name,
+		CreatedAt: userToken.CreatedAt,
+		Token:     middleware.FormatUserToken(userToken.ID, secret),
+	}, nil
+}
+
+// RemoveUserToken revokes API token of user
+func RemoveUserToken(dataBase moira.Database, tokenID string, userLogin string) *api.ErrorResponse {
+	token, err := dataBase.GetUserToken(tokenID)
+	if err != nil {
+		if err == database.ErrNil {
+			return api.ErrorNotFound(fmt.Sprintf("Token with ID '%s' does not exists", tokenID))
 		}
+		return api.ErrorInternalServer(err)
+	}
+	if token.User != userLogin {
+		return api.ErrorForbidden("You have not permissions")
+	}
+	if err := dataBase.RemoveUserToken(tokenID); err != nil {
+		return api.ErrorInternalServer(err)
 	}
-	return userSettings, nil
+	return nil
 }
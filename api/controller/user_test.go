@@ -6,6 +6,8 @@ import (
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/database"
 	"github.com/moira-alert/moira/mock/moira-alert"
 	"github.com/satori/go.uuid"
 	. "github.com/smartystreets/goconvey/convey"
@@ -17,6 +19,7 @@ func TestGetUserSettings(t *testing.T) {
 	defer mockCtrl.Finish()
 	database := mock_moira_alert.NewMockDatabase(mockCtrl)
 	login := "user"
+	database.EXPECT().GetUserTeamIDs(login).Return(make([]string, 0), nil).AnyTimes()
 
 	Convey("Success get user data", t, func() {
 		subscriptionIDs := []string{uuid.NewV4().String(), uuid.NewV4().String()}
@@ -33,6 +36,7 @@ func TestGetUserSettings(t *testing.T) {
 			User:          dto.User{Login: login},
 			Contacts:      []moira.ContactData{*contacts[0], *contacts[1]},
 			Subscriptions: []moira.SubscriptionData{*subscriptions[0], *subscriptions[1]},
+			Teams:         make([]moira.Team, 0),
 		})
 	})
 
@@ -47,6 +51,7 @@ func TestGetUserSettings(t *testing.T) {
 			User:          dto.User{Login: login},
 			Contacts:      make([]moira.ContactData, 0),
 			Subscriptions: make([]moira.SubscriptionData, 0),
+			Teams:         make([]moira.Team, 0),
 		})
 	})
 
@@ -90,3 +95,105 @@ func TestGetUserSettings(t *testing.T) {
 		})
 	})
 }
+
+func TestGetUserSettingsOfTeamMember(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+	team := moira.Team{ID: "team", Name: "Team", Members: map[string]string{login: moira.TeamRoleViewer}}
+
+	Convey("Team contacts and subscriptions are shared, objects of former teams are hidden", t, func() {
+		personalContact := moira.ContactData{ID: "contact1", User: login}
+		teamContact := moira.ContactData{ID: "contact2", User: "other", TeamID: team.ID}
+		formerTeamContact := moira.ContactData{ID: "contact3", User: login, TeamID: "former"}
+		teamSubscription := moira.SubscriptionData{ID: "subscription1", User: login, TeamID: team.ID}
+
+		dataBase.EXPECT().GetUserTeamIDs(login).Return([]string{team.ID, "removed"}, nil)
+		dataBase.EXPECT().GetTeam(team.ID).Return(team, nil)
+		dataBase.EXPECT().GetTeam("removed").Return(moira.Team{}, database.ErrNil)
+		dataBase.EXPECT().GetUserSubscriptionIDs(login).Return([]string{teamSubscription.ID}, nil)
+		dataBase.EXPECT().GetTeamSubscriptionIDs(team.ID).Return([]string{teamSubscription.ID}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{teamSubscription.ID, teamSubscription.ID}).Return([]*moira.SubscriptionData{&teamSubscription, &teamSubscription}, nil)
+		dataBase.EXPECT().GetUserContactIDs(login).Return([]string{personalContact.ID, formerTeamContact.ID}, nil)
+		dataBase.EXPECT().GetTeamContactIDs(team.ID).Return([]string{teamContact.ID}, nil)
+		dataBase.EXPECT().GetContacts([]string{personalContact.ID, formerTeamContact.ID, teamContact.ID}).Return([]*moira.ContactData{&personalContact, &formerTeamContact, &teamContact}, nil)
+		settings, err := GetUserSettings(dataBase, login)
+		So(err, ShouldBeNil)
+		So(settings, ShouldResemble, &dto.UserSettings{
+			User:          dto.User{Login: login},
+			Contacts:      []moira.ContactData{personalContact, teamContact},
+			Subscriptions: []moira.SubscriptionData{teamSubscription},
+			Teams:         []moira.Team{team},
+		})
+	})
+}
+
+func TestCreateUserToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+
+	Convey("Token is returned once, only its hash is stored", t, func() {
+		var saved *moira.UserToken
+		database.EXPECT().SaveUserToken(gomock.Any()).Do(func(token *moira.UserToken) { saved = token }).Return(nil)
+		token, err := CreateUserToken(database, &dto.UserToken{Name: "ci"}, login)
+		So(err, ShouldBeNil)
+		So(token.Name, ShouldEqual, "ci")
+		So(saved.ID, ShouldEqual, token.ID)
+		So(saved.User, ShouldEqual, login)
+		tokenID, secret, ok := middleware.ParseUserToken(token.Token)
+		So(ok, ShouldBeTrue)
+		So(tokenID, ShouldEqual, token.ID)
+		So(saved.Hash, ShouldNotContainSubstring, secret)
+		So(saved.IsSecretValid(secret), ShouldBeTrue)
+	})
+
+	Convey("Anonymous user can not get token", t, func() {
+		token, err := CreateUserToken(database, &dto.UserToken{Name: "ci"}, "")
+		So(err, ShouldResemble, api.ErrorForbidden("API tokens can not be issued to anonymous user"))
+		So(token, ShouldBeNil)
+	})
+}
+
+func TestGetUserTokens(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+
+	Convey("Token hashes are not returned", t, func() {
+		database.EXPECT().GetUserTokens(login).Return([]*moira.UserToken{{ID: "token", User: login, Name: "ci", Hash: "hash", CreatedAt: 1502719200}}, nil)
+		tokens, err := GetUserTokens(database, login)
+		So(err, ShouldBeNil)
+		So(tokens, ShouldResemble, &dto.UserTokensList{List: []dto.UserToken{{ID: "token", Name: "ci", CreatedAt: 1502719200}}})
+	})
+}
+
+func TestRemoveUserToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+	token := moira.UserToken{ID: "token", User: login}
+
+	Convey("Token is revoked", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(token, nil)
+		dataBase.EXPECT().RemoveUserToken(token.ID).Return(nil)
+		err := RemoveUserToken(dataBase, token.ID, login)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Token of other user can not be revoked", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(token, nil)
+		err := RemoveUserToken(dataBase, token.ID, "other")
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+
+	Convey("Token does not exist", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(moira.UserToken{}, database.ErrNil)
+		err := RemoveUserToken(dataBase, token.ID, login)
+		So(err, ShouldResemble, api.ErrorNotFound("Token with ID 'token' does not exists"))
+	})
+}
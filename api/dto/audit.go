@@ -0,0 +1,16 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type AuditRecordsList struct {
+	List []*moira.AuditRecord `json:"list"`
+}
+
+func (*AuditRecordsList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,172 @@
+// nolint
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/expression"
+	"github.com/moira-alert/moira/target"
+)
+
+// Config formats
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+)
+
+// Config is declarative configuration of triggers and subscriptions, which can be kept in version control system.
+// Entities are identified by their IDs, so applied configuration keeps IDs and state of existing triggers
+type Config struct {
+	Triggers      []TriggerModel           `json:"triggers"`
+	Subscriptions []moira.SubscriptionData `json:"subscriptions"`
+}
+
+func (*Config) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Validate checks configuration entities for required fields and unique IDs, trigger targets and expressions are not evaluated
+func (config *Config) Validate() error {
+	triggerIDs := make(map[string]bool, len(config.Triggers))
+	for i, trigger := range config.Triggers {
+		if trigger.ID == "" {
+			return fmt.Errorf("Trigger %d must have id", i+1)
+		}
+		if triggerIDs[trigger.ID] {
+			return fmt.Errorf("Trigger id %s is duplicated", trigger.ID)
+		}
+		triggerIDs[trigger.ID] = true
+		if trigger.Name == "" {
+			return fmt.Errorf("Trigger %s must have name", trigger.ID)
+		}
+		if len(trigger.Tags) == 0 {
+			return fmt.Errorf("Trigger %s must have tags", trigger.ID)
+		}
+		if len(trigger.Targets) == 0 && trigger.Aggregate == nil {
+			return fmt.Errorf("Trigger %s must have targets", trigger.ID)
+		}
+	}
+	subscriptionIDs := make(map[string]bool, len(config.Subscriptions))
+	for i, subscription := range config.Subscriptions {
+		if subscription.ID == "" {
+			return fmt.Errorf("Subscription %d must have id", i+1)
+		}
+		if subscriptionIDs[subscription.ID] {
+			return fmt.Errorf("Subscription id %s is duplicated", subscription.ID)
+		}
+		subscriptionIDs[subscription.ID] = true
+		data := Subscription(subscription)
+		if err := data.Bind(nil); err != nil {
+			return fmt.Errorf("Invalid subscription %s: %s", subscription.ID, err.Error())
+		}
+	}
+	return nil
+}
+
+// BindTriggers validates triggers like trigger update does and resolves their patterns by request database,
+// returns time series names of triggers by trigger ID
+func (config *Config) BindTriggers(request *http.Request) (map[string]map[string]bool, error) {
+	timeSeriesNames := make(map[string]map[string]bool, len(config.Triggers))
+	for i := range config.Triggers {
+		trigger := &Trigger{TriggerModel: config.Triggers[i]}
+		if err := trigger.Bind(request); err != nil {
+			switch err.(type) {
+			case target.ErrParseExpr, target.ErrEvalExpr, target.ErrUnknownFunction:
+				err = fmt.Errorf("Invalid graphite targets: %s", err.Error())
+			case expression.ErrInvalidExpression:
+				err = fmt.Errorf("Invalid expression: %s", err.Error())
+			}
+			return nil, fmt.Errorf("Invalid trigger %s: %s", trigger.ID, err.Error())
+		}
+		config.Triggers[i] = trigger.TriggerModel
+		timeSeriesNames[trigger.ID] = middleware.GetTimeSeriesNames(request)
+	}
+	return timeSeriesNames, nil
+}
+
+// ParseConfig parses configuration in given format
+func ParseConfig(data []byte, format string) (*Config, error) {
+	config := &Config{}
+	switch format {
+	case ConfigFormatJSON:
+	case ConfigFormatYAML:
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("Failed to parse yaml: %s", err.Error())
+		}
+		var err error
+		if data, err = json.Marshal(yamlToJSONValue(value)); err != nil {
+			return nil, fmt.Errorf("Failed to convert yaml to json: %s", err.Error())
+		}
+	default:
+		return nil, fmt.Errorf("Unknown config format: %s", format)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %s", err.Error())
+	}
+	return config, nil
+}
+
+// Marshal returns configuration in given format, yaml keys are the same as json ones
+func (config *Config) Marshal(format string) ([]byte, error) {
+	switch format {
+	case ConfigFormatJSON:
+		return json.MarshalIndent(config, "", "  ")
+	case ConfigFormatYAML:
+		data, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(value)
+	default:
+		return nil, fmt.Errorf("Unknown config format: %s", format)
+	}
+}
+
+// yamlToJSONValue converts yaml maps with interface keys to maps json can be marshaled from
+func yamlToJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[fmt.Sprint(key)] = yamlToJSONValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range value {
+			value[i] = yamlToJSONValue(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// ConfigChange is planned change of single configuration entity, create and delete changes list all entity fields
+type ConfigChange struct {
+	Entity  string        `json:"entity"`
+	ID      string        `json:"id"`
+	Action  string        `json:"action"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ConfigPlan is list of changes needed to apply configuration. Configuration is applied only with checksum
+// of reviewed plan, so changes made after review are not applied unnoticed
+type ConfigPlan struct {
+	Changes  []ConfigChange `json:"changes"`
+	Checksum string         `json:"checksum"`
+}
+
+func (*ConfigPlan) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
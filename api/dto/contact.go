@@ -16,10 +16,12 @@ func (*ContactList) Render(w http.ResponseWriter, r *http.Request) error {
 }
 
 type Contact struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
-	ID    string `json:"id,omitempty"`
-	User  string `json:"user,omitempty"`
+	Type           string `json:"type"`
+	Value          string `json:"value"`
+	ID             string `json:"id,omitempty"`
+	User           string `json:"user,omitempty"`
+	TeamID         string `json:"team_id,omitempty"`
+	DigestInterval int64  `json:"digest_interval,omitempty"`
 }
 
 func (*Contact) Render(w http.ResponseWriter, r *http.Request) error {
@@ -33,5 +35,8 @@ func (contact *Contact) Bind(r *http.Request) error {
 	if contact.Value == "" {
 		return fmt.Errorf("Contact value of type %s can not be empty", contact.Type)
 	}
+	if contact.DigestInterval < 0 {
+		return fmt.Errorf("Contact digest interval can not be negative")
+	}
 	return nil
 }
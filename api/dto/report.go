@@ -0,0 +1,16 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira/report"
+)
+
+type Report struct {
+	*report.Report
+}
+
+func (*Report) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
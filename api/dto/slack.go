@@ -0,0 +1,33 @@
+// nolint
+package dto
+
+import "net/http"
+
+// SlackInteraction is payload sent by slack when notification button is pressed
+type SlackInteraction struct {
+	Token      string              `json:"token"`
+	CallbackID string              `json:"callback_id"`
+	User       SlackUser           `json:"user"`
+	Actions    []SlackActionChoice `json:"actions"`
+}
+
+type SlackUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SlackActionChoice struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SlackInteractionResponse is message shown to user who pressed the button
+type SlackInteractionResponse struct {
+	ResponseType    string `json:"response_type"`
+	ReplaceOriginal bool   `json:"replace_original"`
+	Text            string `json:"text"`
+}
+
+func (*SlackInteractionResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -28,5 +28,25 @@ func (subscription *Subscription) Bind(r *http.Request) error {
 	if len(subscription.Contacts) == 0 {
 		return fmt.Errorf("Subscription must have contacts")
 	}
+	if subscription.ThrottlingPolicy != nil {
+		if err := checkThrottlingPolicy(subscription.ThrottlingPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkThrottlingPolicy(policy *moira.ThrottlingPolicy) error {
+	if len(policy.Levels) == 0 {
+		return fmt.Errorf("Throttling policy must have levels")
+	}
+	for i, level := range policy.Levels {
+		if level.Window <= 0 || level.Count <= 0 || level.Delay <= 0 {
+			return fmt.Errorf("Throttling level %d must have positive window, count and delay", i+1)
+		}
+	}
+	if policy.MaxDelay < 0 {
+		return fmt.Errorf("Throttling policy max delay can not be negative")
+	}
 	return nil
 }
@@ -0,0 +1,40 @@
+// nolint
+package dto
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type TeamsList struct {
+	List []moira.Team `json:"list"`
+}
+
+func (*TeamsList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type Team struct {
+	moira.Team
+}
+
+func (*Team) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (team *Team) Bind(r *http.Request) error {
+	if team.Name == "" {
+		return fmt.Errorf("team name is required")
+	}
+	for login, role := range team.Members {
+		if login == "" {
+			return fmt.Errorf("team member login can not be empty")
+		}
+		if !moira.IsValidTeamRole(role) {
+			return fmt.Errorf("unknown role %s of team member %s, use %s, %s or %s", role, login, moira.TeamRoleViewer, moira.TeamRoleEditor, moira.TeamRoleAdmin)
+		}
+	}
+	return nil
+}
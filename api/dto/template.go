@@ -0,0 +1,157 @@
+// nolint
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+// templatePlaceholder matches {{parameter}} placeholders in template trigger string fields
+var templatePlaceholder = regexp.MustCompile(`{{\s*([a-zA-Z0-9_\-]+)\s*}}`)
+
+type TriggerTemplatesList struct {
+	List []*moira.TriggerTemplate `json:"list"`
+}
+
+func (*TriggerTemplatesList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type TriggerTemplate struct {
+	moira.TriggerTemplate
+}
+
+func (*TriggerTemplate) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (template *TriggerTemplate) Bind(request *http.Request) error {
+	if template.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(template.Trigger, &fields); err != nil || fields == nil {
+		return fmt.Errorf("template trigger must be json object")
+	}
+	return nil
+}
+
+type SaveTriggerTemplateResponse struct {
+	ID         string   `json:"id"`
+	Message    string   `json:"message"`
+	TriggerIDs []string `json:"trigger_ids,omitempty"`
+}
+
+func (*SaveTriggerTemplateResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// TemplateInstances is list of parameter sets trigger template is instantiated with, one trigger per set
+type TemplateInstances struct {
+	Parameters []map[string]interface{} `json:"parameters"`
+}
+
+func (instances *TemplateInstances) Bind(request *http.Request) error {
+	if len(instances.Parameters) == 0 {
+		return fmt.Errorf("parameters is required")
+	}
+	return nil
+}
+
+// TemplateInstance is validated trigger rendered from template
+type TemplateInstance struct {
+	Trigger         TriggerModel
+	TimeSeriesNames map[string]bool
+}
+
+// TemplateRenderer renders trigger template with given parameters
+type TemplateRenderer func(template *moira.TriggerTemplate, parameters map[string]interface{}) (*TemplateInstance, error)
+
+// NewTemplateRenderer returns renderer validating rendered triggers the same way as triggers given to API
+func NewTemplateRenderer(request *http.Request) TemplateRenderer {
+	return func(template *moira.TriggerTemplate, parameters map[string]interface{}) (*TemplateInstance, error) {
+		trigger, err := RenderTemplate(template, parameters)
+		if err != nil {
+			return nil, err
+		}
+		if err := trigger.Bind(request); err != nil {
+			return nil, err
+		}
+		return &TemplateInstance{
+			Trigger:         trigger.TriggerModel,
+			TimeSeriesNames: middleware.GetTimeSeriesNames(request),
+		}, nil
+	}
+}
+
+// RenderTemplate substitutes parameters to template trigger placeholders. String which is a single placeholder
+// is replaced by parameter value as is, so numbers and lists can be passed to thresholds and tags
+func RenderTemplate(template *moira.TriggerTemplate, parameters map[string]interface{}) (*Trigger, error) {
+	var fields interface{}
+	if err := json.Unmarshal(template.Trigger, &fields); err != nil {
+		return nil, fmt.Errorf("Failed to parse template trigger: %s", err.Error())
+	}
+	fields, err := renderTemplateValue(fields, parameters)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal rendered trigger: %s", err.Error())
+	}
+	trigger := &Trigger{}
+	if err := json.Unmarshal(bytes, trigger); err != nil {
+		return nil, fmt.Errorf("rendered trigger is invalid: %s", err.Error())
+	}
+	return trigger, nil
+}
+
+func renderTemplateValue(value interface{}, parameters map[string]interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		return renderTemplateString(typed, parameters)
+	case []interface{}:
+		for i, item := range typed {
+			rendered, err := renderTemplateValue(item, parameters)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = rendered
+		}
+	case map[string]interface{}:
+		for key, item := range typed {
+			rendered, err := renderTemplateValue(item, parameters)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = rendered
+		}
+	}
+	return value, nil
+}
+
+func renderTemplateString(value string, parameters map[string]interface{}) (interface{}, error) {
+	if match := templatePlaceholder.FindStringSubmatch(value); match != nil && match[0] == value {
+		parameter, ok := parameters[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("template parameter %s is not set", match[1])
+		}
+		return parameter, nil
+	}
+	var err error
+	rendered := templatePlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		parameter, ok := parameters[name]
+		if !ok {
+			err = fmt.Errorf("template parameter %s is not set", name)
+			return placeholder
+		}
+		return fmt.Sprint(parameter)
+	})
+	return rendered, err
+}
@@ -0,0 +1,42 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type TriggerRevisionsList struct {
+	List []*moira.TriggerRevision `json:"list"`
+}
+
+func (*TriggerRevisionsList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type TriggerRevision struct {
+	moira.TriggerRevision
+}
+
+func (*TriggerRevision) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// FieldChange is change of single field value, nested fields are joined with dots
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type TriggerRevisionsDiff struct {
+	TriggerID string        `json:"trigger_id"`
+	From      int64         `json:"from"`
+	To        int64         `json:"to"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+func (*TriggerRevisionsDiff) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -4,8 +4,8 @@ package dto
 import (
 	"fmt"
 	"net/http"
-	"time"
 	"strings"
+	"time"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api/middleware"
@@ -14,6 +14,13 @@ import (
 	"github.com/moira-alert/moira/target"
 )
 
+// Limits of anomaly detection trigger baseline settings
+const (
+	minBaselinePeriod  = 60
+	maxBaselinePeriods = 10
+	maxBaselineHistory = 35 * 86400
+)
+
 type TriggersList struct {
 	Page  *int64               `json:"page,omitempty"`
 	Size  *int64               `json:"size,omitempty"`
@@ -32,58 +39,91 @@ type Trigger struct {
 
 // TriggerModel is moira.Trigger api representation
 type TriggerModel struct {
-	ID         string              `json:"id"`
-	Name       string              `json:"name"`
-	Desc       *string             `json:"desc,omitempty"`
-	Targets    []string            `json:"targets"`
-	WarnValue  *float64            `json:"warn_value"`
-	ErrorValue *float64            `json:"error_value"`
-	Tags       []string            `json:"tags"`
-	TTLState   *string             `json:"ttl_state,omitempty"`
-	TTL        int64               `json:"ttl,omitempty"`
-	Schedule   *moira.ScheduleData `json:"sched,omitempty"`
-	Expression string              `json:"expression"`
-	Patterns   []string            `json:"patterns"`
+	ID               string                    `json:"id"`
+	Name             string                    `json:"name"`
+	Desc             *string                   `json:"desc,omitempty"`
+	Targets          []string                  `json:"targets"`
+	WarnValue        *float64                  `json:"warn_value"`
+	ErrorValue       *float64                  `json:"error_value"`
+	Tags             []string                  `json:"tags"`
+	TTLState         *string                   `json:"ttl_state,omitempty"`
+	TTL              int64                     `json:"ttl,omitempty"`
+	Schedule         *moira.ScheduleData       `json:"sched,omitempty"`
+	Expression       string                    `json:"expression"`
+	Patterns         []string                  `json:"patterns"`
+	FiringPoints     int64                     `json:"firing_points,omitempty"`
+	FiringDuration   int64                     `json:"firing_duration,omitempty"`
+	RecoveryPoints   int64                     `json:"recovery_points,omitempty"`
+	RecoveryDuration int64                     `json:"recovery_duration,omitempty"`
+	RemindIntervals  map[string]int64          `json:"remind_intervals,omitempty"`
+	Baseline         *moira.BaselineSettings   `json:"baseline,omitempty"`
+	IsRemote         bool                      `json:"is_remote"`
+	Aggregate        *moira.AggregateSettings  `json:"aggregate,omitempty"`
+	Inhibits         *moira.InhibitionSettings `json:"inhibits,omitempty"`
+	Template         *moira.TemplateLink       `json:"template,omitempty"`
+	TeamID           string                    `json:"team_id,omitempty"`
 }
 
 // ToMoiraTrigger transforms TriggerModel to moira.Trigger
 func (model *TriggerModel) ToMoiraTrigger() *moira.Trigger {
 	return &moira.Trigger{
-		ID:         model.ID,
-		Name:       model.Name,
-		Desc:       model.Desc,
-		Targets:    model.Targets,
-		WarnValue:  model.WarnValue,
-		ErrorValue: model.ErrorValue,
-		Tags:       model.Tags,
-		TTLState:   model.TTLState,
-		TTL:        model.TTL,
-		Schedule:   model.Schedule,
-		Expression: &model.Expression,
-		Patterns:   model.Patterns,
+		ID:               model.ID,
+		Name:             model.Name,
+		Desc:             model.Desc,
+		Targets:          model.Targets,
+		WarnValue:        model.WarnValue,
+		ErrorValue:       model.ErrorValue,
+		Tags:             model.Tags,
+		TTLState:         model.TTLState,
+		TTL:              model.TTL,
+		Schedule:         model.Schedule,
+		Expression:       &model.Expression,
+		Patterns:         model.Patterns,
+		FiringPoints:     model.FiringPoints,
+		FiringDuration:   model.FiringDuration,
+		RecoveryPoints:   model.RecoveryPoints,
+		RecoveryDuration: model.RecoveryDuration,
+		RemindIntervals:  model.RemindIntervals,
+		Baseline:         model.Baseline,
+		IsRemote:         model.IsRemote,
+		Aggregate:        model.Aggregate,
+		Inhibits:         model.Inhibits,
+		Template:         model.Template,
+		TeamID:           model.TeamID,
 	}
 }
 
 // CreateTriggerModel transforms moira.Trigger to TriggerModel
 func CreateTriggerModel(trigger *moira.Trigger) TriggerModel {
 	return TriggerModel{
-		ID:         trigger.ID,
-		Name:       trigger.Name,
-		Desc:       trigger.Desc,
-		Targets:    trigger.Targets,
-		WarnValue:  trigger.WarnValue,
-		ErrorValue: trigger.ErrorValue,
-		Tags:       trigger.Tags,
-		TTLState:   trigger.TTLState,
-		TTL:        trigger.TTL,
-		Schedule:   trigger.Schedule,
-		Expression: moira.UseString(trigger.Expression),
-		Patterns:   trigger.Patterns,
+		ID:               trigger.ID,
+		Name:             trigger.Name,
+		Desc:             trigger.Desc,
+		Targets:          trigger.Targets,
+		WarnValue:        trigger.WarnValue,
+		ErrorValue:       trigger.ErrorValue,
+		Tags:             trigger.Tags,
+		TTLState:         trigger.TTLState,
+		TTL:              trigger.TTL,
+		Schedule:         trigger.Schedule,
+		Expression:       moira.UseString(trigger.Expression),
+		Patterns:         trigger.Patterns,
+		FiringPoints:     trigger.FiringPoints,
+		FiringDuration:   trigger.FiringDuration,
+		RecoveryPoints:   trigger.RecoveryPoints,
+		RecoveryDuration: trigger.RecoveryDuration,
+		RemindIntervals:  trigger.RemindIntervals,
+		Baseline:         trigger.Baseline,
+		IsRemote:         trigger.IsRemote,
+		Aggregate:        trigger.Aggregate,
+		Inhibits:         trigger.Inhibits,
+		Template:         trigger.Template,
+		TeamID:           trigger.TeamID,
 	}
 }
 
 func (trigger *Trigger) Bind(request *http.Request) error {
-	if len(trigger.Targets) == 0 {
+	if len(trigger.Targets) == 0 && trigger.Aggregate == nil {
 		return fmt.Errorf("targets is required")
 	}
 	if len(trigger.Tags) == 0 {
@@ -97,12 +137,27 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	if trigger.Name == "" {
 		return fmt.Errorf("trigger name is required")
 	}
+	if err := checkInhibition(trigger.Inhibits); err != nil {
+		return err
+	}
+	if trigger.Aggregate != nil {
+		return checkAggregate(request, trigger)
+	}
 	if trigger.WarnValue == nil && trigger.Expression == "" {
 		return fmt.Errorf("warn_value is required")
 	}
 	if trigger.ErrorValue == nil && trigger.Expression == "" {
 		return fmt.Errorf("error_value is required")
 	}
+	if err := checkTransitionConditions(trigger); err != nil {
+		return err
+	}
+	if err := checkRemindIntervals(trigger.RemindIntervals); err != nil {
+		return err
+	}
+	if err := checkBaseline(trigger.Baseline); err != nil {
+		return err
+	}
 
 	triggerExpression := expression.TriggerExpression{
 		AdditionalTargetsValues: make(map[string]float64),
@@ -111,6 +166,9 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		PreviousState:           checker.NODATA,
 		Expression:              &trigger.Expression,
 	}
+	if trigger.Baseline != nil {
+		triggerExpression.Baseline = &expression.Baseline{}
+	}
 
 	if err := resolvePatterns(request, trigger, &triggerExpression); err != nil {
 		return err
@@ -126,8 +184,20 @@ func resolvePatterns(request *http.Request, trigger *Trigger, expressionValues *
 	targetNum := 1
 	trigger.Patterns = make([]string, 0)
 	timeSeriesNames := make(map[string]bool)
+	if trigger.IsRemote {
+		// Remote timeseries are unknown to API, so metrics of saved trigger are kept as is
+		timeSeriesNames = nil
+	}
 
 	for _, tar := range trigger.Targets {
+		if trigger.IsRemote {
+			if err := target.ValidateRemoteTarget(tar); err != nil {
+				return err
+			}
+			setTargetValue(expressionValues, targetNum)
+			targetNum++
+			continue
+		}
 		database := middleware.GetDatabase(request)
 		result, err := target.EvaluateTarget(database, tar, now-600, now, false)
 		if err != nil {
@@ -137,20 +207,127 @@ func resolvePatterns(request *http.Request, trigger *Trigger, expressionValues *
 		trigger.Patterns = append(trigger.Patterns, result.Patterns...)
 
 		if targetNum == 1 {
-			expressionValues.MainTargetValue = 42
 			for _, timeSeries := range result.TimeSeries {
 				timeSeriesNames[timeSeries.Name] = true
 			}
-		} else {
-			targetName := fmt.Sprintf("t%v", targetNum)
-			expressionValues.AdditionalTargetsValues[targetName] = 42
 		}
+		setTargetValue(expressionValues, targetNum)
 		targetNum++
 	}
 	middleware.SetTimeSeriesNames(request, timeSeriesNames)
 	return nil
 }
 
+// setTargetValue sets test value of target used to check trigger expression
+func setTargetValue(expressionValues *expression.TriggerExpression, targetNum int) {
+	if targetNum == 1 {
+		expressionValues.MainTargetValue = 42
+	} else {
+		targetName := fmt.Sprintf("t%v", targetNum)
+		expressionValues.AdditionalTargetsValues[targetName] = 42
+	}
+}
+
+func checkTransitionConditions(trigger *Trigger) error {
+	if trigger.FiringPoints < 0 {
+		return fmt.Errorf("firing_points can not be negative")
+	}
+	if trigger.FiringDuration < 0 {
+		return fmt.Errorf("firing_duration can not be negative")
+	}
+	if trigger.RecoveryPoints < 0 {
+		return fmt.Errorf("recovery_points can not be negative")
+	}
+	if trigger.RecoveryDuration < 0 {
+		return fmt.Errorf("recovery_duration can not be negative")
+	}
+	return nil
+}
+
+func checkRemindIntervals(remindIntervals map[string]int64) error {
+	for state, interval := range remindIntervals {
+		if !checker.IsRemindableState(state) {
+			return fmt.Errorf("remind interval can not be set for state %s", state)
+		}
+		if interval < 0 {
+			return fmt.Errorf("remind interval for state %s can not be negative", state)
+		}
+	}
+	return nil
+}
+
+func checkBaseline(baseline *moira.BaselineSettings) error {
+	if baseline == nil {
+		return nil
+	}
+	if baseline.Method != moira.BaselineMeanStdDev && baseline.Method != moira.BaselineMedianMAD {
+		return fmt.Errorf("baseline method must be %s or %s", moira.BaselineMeanStdDev, moira.BaselineMedianMAD)
+	}
+	if baseline.Period < minBaselinePeriod {
+		return fmt.Errorf("baseline period can not be less than %d seconds", minBaselinePeriod)
+	}
+	if baseline.Periods < 2 || baseline.Periods > maxBaselinePeriods {
+		return fmt.Errorf("baseline periods must be between 2 and %d", maxBaselinePeriods)
+	}
+	if baseline.GetHistoryDuration() > maxBaselineHistory {
+		return fmt.Errorf("baseline can not look back more than %d days", maxBaselineHistory/86400)
+	}
+	return nil
+}
+
+// checkAggregate validates aggregate trigger, it has no targets and therefore no patterns and metrics
+func checkAggregate(request *http.Request, trigger *Trigger) error {
+	if len(trigger.Targets) > 0 {
+		return fmt.Errorf("aggregate trigger can not have targets")
+	}
+	if trigger.IsRemote || trigger.Baseline != nil {
+		return fmt.Errorf("aggregate trigger can not be remote or anomaly detection one")
+	}
+	if len(trigger.Aggregate.TriggerIDs) == 0 && len(trigger.Aggregate.Tags) == 0 {
+		return fmt.Errorf("aggregate trigger_ids or tags is required")
+	}
+	if trigger.Aggregate.Expression == "" {
+		return fmt.Errorf("aggregate expression is required")
+	}
+	if err := checkTransitionConditions(trigger); err != nil {
+		return err
+	}
+	if err := checkRemindIntervals(trigger.RemindIntervals); err != nil {
+		return err
+	}
+	aggregateExpression := expression.AggregateExpression{
+		Expression:    trigger.Aggregate.Expression,
+		StatesCount:   make(map[string]int64),
+		PreviousState: checker.NODATA,
+	}
+	if _, err := aggregateExpression.Evaluate(); err != nil {
+		return err
+	}
+	trigger.Patterns = make([]string, 0)
+	middleware.SetTimeSeriesNames(request, make(map[string]bool))
+	return nil
+}
+
+func checkInhibition(inhibition *moira.InhibitionSettings) error {
+	if inhibition == nil {
+		return nil
+	}
+	if len(inhibition.TriggerIDs) == 0 && len(inhibition.Tags) == 0 {
+		return fmt.Errorf("inhibits trigger_ids or tags is required")
+	}
+	if len(inhibition.States) == 0 {
+		return fmt.Errorf("inhibits states is required")
+	}
+	for _, state := range inhibition.States {
+		switch state {
+		case checker.WARN, checker.ERROR, checker.NODATA, checker.EXCEPTION:
+		default:
+			return fmt.Errorf("trigger can not inhibit other triggers in state %s", state)
+		}
+	}
+	return nil
+}
+
 func checkTriggerTags(tags []string) []string {
 	reservedTagsFound := make([]string, 0)
 	for _, tag := range tags {
@@ -181,6 +358,16 @@ func (*MetricsMaintenance) Bind(r *http.Request) error {
 	return nil
 }
 
+// Acknowledgement is request to acknowledge current state of trigger or given trigger metrics
+type Acknowledgement struct {
+	Metrics []string `json:"metrics,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+func (*Acknowledgement) Bind(r *http.Request) error {
+	return nil
+}
+
 type ThrottlingResponse struct {
 	Throttling int64 `json:"throttling"`
 }
@@ -203,3 +390,29 @@ type TriggerMetrics map[string][]moira.MetricValue
 func (*TriggerMetrics) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
+
+// StateInterval is time interval trigger or metric stayed in given state
+type StateInterval struct {
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	State string `json:"state"`
+}
+
+// TriggerHistory contains trigger and its metrics state intervals within requested time range, intervals are cut at range bounds
+type TriggerHistory struct {
+	TriggerID string                     `json:"trigger_id"`
+	Trigger   []StateInterval            `json:"trigger"`
+	Metrics   map[string][]StateInterval `json:"metrics"`
+}
+
+func (*TriggerHistory) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type BacktestResult struct {
+	*checker.BacktestResult
+}
+
+func (*BacktestResult) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -2,6 +2,7 @@
 package dto
 
 import (
+	"fmt"
 	"github.com/moira-alert/moira"
 	"net/http"
 )
@@ -10,6 +11,7 @@ type UserSettings struct {
 	User
 	Contacts      []moira.ContactData      `json:"contacts"`
 	Subscriptions []moira.SubscriptionData `json:"subscriptions"`
+	Teams         []moira.Team             `json:"teams"`
 }
 
 func (*UserSettings) Render(w http.ResponseWriter, r *http.Request) error {
@@ -23,3 +25,30 @@ type User struct {
 func (*User) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
+
+// UserToken is personal API token of user, token itself is returned only on creation
+type UserToken struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+	Token     string `json:"token,omitempty"`
+}
+
+func (*UserToken) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (token *UserToken) Bind(r *http.Request) error {
+	if token.Name == "" {
+		return fmt.Errorf("token name is required")
+	}
+	return nil
+}
+
+type UserTokensList struct {
+	List []UserToken `json:"list"`
+}
+
+func (*UserTokensList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func audit(router chi.Router) {
+	router.With(middleware.DateRange("-1day", "now")).Get("/", getAuditRecords)
+}
+
+func getAuditRecords(writer http.ResponseWriter, request *http.Request) {
+	fromStr := middleware.GetFromStr(request)
+	toStr := middleware.GetToStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr)))
+		return
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %s", toStr)))
+		return
+	}
+	entity := request.URL.Query().Get("entity")
+	user := request.URL.Query().Get("user")
+	userLogin := middleware.GetLogin(request)
+	records, err := controller.GetAuditRecords(database, entity, user, int64(from), int64(to), userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, records); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+// configuration routes are registered one by one, because /api/config is taken by web config
+func configuration(router chi.Router) {
+	router.Get("/config/export", exportConfig)
+	router.Post("/config/plan", planConfig)
+	router.Post("/config/apply", applyConfig)
+}
+
+func exportConfig(writer http.ResponseWriter, request *http.Request) {
+	format := getConfigFormat(request)
+	config, errorResponse := controller.ExportConfig(database, getRequestTags(request), middleware.GetLogin(request))
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if format == dto.ConfigFormatJSON {
+		if err := render.Render(writer, request, config); err != nil {
+			render.Render(writer, request, api.ErrorRender(err))
+		}
+		return
+	}
+	bytes, err := config.Marshal(format)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/x-yaml")
+	writer.Write(bytes)
+}
+
+func planConfig(writer http.ResponseWriter, request *http.Request) {
+	config, _, errorResponse := bindConfig(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	plan, errorResponse := controller.PlanConfig(database, config, getRequestTags(request), middleware.GetLogin(request))
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, plan); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func applyConfig(writer http.ResponseWriter, request *http.Request) {
+	checksum := request.URL.Query().Get("checksum")
+	if checksum == "" {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Checksum of reviewed plan must be set")))
+		return
+	}
+	config, timeSeriesNames, errorResponse := bindConfig(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	plan, errorResponse := controller.ApplyConfig(database, config, getRequestTags(request), checksum, timeSeriesNames, middleware.GetLogin(request))
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, plan); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+// bindConfig parses configuration from request body and validates its triggers like trigger update does,
+// returns time series names of triggers by trigger ID
+func bindConfig(request *http.Request) (*dto.Config, map[string]map[string]bool, *api.ErrorResponse) {
+	bytes, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, nil, api.ErrorInvalidRequest(err)
+	}
+	config, err := dto.ParseConfig(bytes, getConfigFormat(request))
+	if err != nil {
+		return nil, nil, api.ErrorInvalidRequest(err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, api.ErrorInvalidRequest(err)
+	}
+	timeSeriesNames, err := config.BindTriggers(request)
+	if err != nil {
+		return nil, nil, api.ErrorInvalidRequest(err)
+	}
+	return config, timeSeriesNames, nil
+}
+
+// getConfigFormat gets configuration format from format query parameter or from request content type
+func getConfigFormat(request *http.Request) string {
+	if format := request.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	if strings.Contains(request.Header.Get("Content-Type"), "yaml") {
+		return dto.ConfigFormatYAML
+	}
+	return dto.ConfigFormatJSON
+}
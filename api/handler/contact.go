@@ -27,7 +27,8 @@ func contact(router chi.Router) {
 }
 
 func getAllContacts(writer http.ResponseWriter, request *http.Request) {
-	contacts, err := controller.GetAllContacts(database)
+	userLogin := middleware.GetLogin(request)
+	contacts, err := controller.GetAllContacts(database, userLogin)
 	if err != nil {
 		render.Render(writer, request, err)
 		return
@@ -80,8 +81,9 @@ func updateContact(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 	contactData := request.Context().Value(contactKey).(moira.ContactData)
+	userLogin := middleware.GetLogin(request)
 
-	contactDTO, err := controller.UpdateContact(database, contactDTO, contactData)
+	contactDTO, err := controller.UpdateContact(database, contactDTO, contactData, userLogin)
 	if err != nil {
 		render.Render(writer, request, err)
 		return
@@ -93,7 +95,7 @@ func updateContact(writer http.ResponseWriter, request *http.Request) {
 
 func removeContact(writer http.ResponseWriter, request *http.Request) {
 	contactData := request.Context().Value(contactKey).(moira.ContactData)
-	err := controller.RemoveContact(database, contactData.ID, contactData.User)
+	err := controller.RemoveContact(database, contactData, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err)
 	}
@@ -10,7 +10,7 @@ import (
 )
 
 func event(router chi.Router) {
-	router.With(middleware.TriggerContext, middleware.Paginate(0, 100)).Get("/{triggerId}", func(writer http.ResponseWriter, request *http.Request) {
+	router.With(middleware.TriggerContext, triggerReaderFilter, middleware.Paginate(0, 100)).Get("/{triggerId}", func(writer http.ResponseWriter, request *http.Request) {
 		triggerID := middleware.GetTriggerID(request)
 		size := middleware.GetSize(request)
 		page := middleware.GetPage(request)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
 	moira_middle "github.com/moira-alert/moira/api/middleware"
 )
 
@@ -18,13 +19,15 @@ var database moira.Database
 
 const contactKey moira_middle.ContextKey = "contact"
 const subscriptionKey moira_middle.ContextKey = "subscription"
+const teamKey moira_middle.ContextKey = "team"
 
 // NewHandler creates new api handler request uris based on github.com/go-chi/chi
-func NewHandler(db moira.Database, log moira.Logger, config *api.Config, configFile []byte) http.Handler {
+func NewHandler(db moira.Database, log moira.Logger, config *api.Config, configFile []byte, authenticator moira_middle.Authenticator) http.Handler {
 	database = db
+	controller.SetAuditLogger(log)
 	router := chi.NewRouter()
 	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Use(moira_middle.UserContext)
+	router.Use(moira_middle.UserContext(authenticator))
 	router.Use(moira_middle.RequestLogger(log))
 	router.Use(middleware.NoCache)
 
@@ -33,15 +36,30 @@ func NewHandler(db moira.Database, log moira.Logger, config *api.Config, configF
 
 	router.Route("/api", func(router chi.Router) {
 		router.Use(moira_middle.DatabaseContext(database))
+		router.Use(moira_middle.AdminContext(config.Auth.Admins))
 		router.Get("/config", webConfig(configFile))
-		router.Route("/user", user)
-		router.Route("/trigger", triggers)
-		router.Route("/tag", tag)
-		router.Route("/pattern", pattern)
-		router.Route("/event", event)
-		router.Route("/contact", contact)
-		router.Route("/subscription", subscription)
-		router.Route("/notification", notification)
+		// Slack requests are authenticated by verification token
+		if config.SlackVerificationToken != "" {
+			router.Route("/slack", slack(config.SlackVerificationToken))
+		}
+		router.Group(func(router chi.Router) {
+			if !config.Auth.AllowAnonymous {
+				router.Use(moira_middle.RequireUser)
+			}
+			router.Route("/user", user)
+			router.Route("/team", team)
+			router.Route("/trigger", triggers)
+			router.Route("/template", template)
+			router.Route("/tag", tag)
+			router.Route("/pattern", pattern)
+			router.Route("/event", event)
+			router.Route("/contact", contact)
+			router.Route("/subscription", subscription)
+			router.Route("/notification", notification)
+			router.Route("/report", report)
+			router.Route("/audit", audit)
+			router.Group(configuration)
+		})
 	})
 	if config.EnableCORS {
 		return cors.AllowAll().Handler(router)
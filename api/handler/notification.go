@@ -6,11 +6,13 @@ import (
 	"github.com/go-chi/render"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
 	"net/http"
 	"strconv"
 )
 
 func notification(router chi.Router) {
+	router.Use(middleware.RequireAdmin)
 	router.Get("/", getNotification)
 	router.Delete("/", deleteNotification)
 }
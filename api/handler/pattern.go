@@ -12,7 +12,7 @@ import (
 
 func pattern(router chi.Router) {
 	router.Get("/", getAllPatterns)
-	router.Delete("/{pattern}", deletePattern)
+	router.With(middleware.RequireAdmin).Delete("/{pattern}", deletePattern)
 }
 
 func getAllPatterns(writer http.ResponseWriter, request *http.Request) {
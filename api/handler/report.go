@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func report(router chi.Router) {
+	router.With(middleware.DateRange("-30days", "now")).Get("/", getReport)
+}
+
+func getReport(writer http.ResponseWriter, request *http.Request) {
+	fromStr := middleware.GetFromStr(request)
+	toStr := middleware.GetToStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr)))
+		return
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %s", toStr)))
+		return
+	}
+	format := request.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Unknown report format: %s", format)))
+		return
+	}
+	availabilityReport, err := controller.GetReport(database, getRequestTags(request), int64(from), int64(to))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if format == "csv" {
+		writer.Header().Set("Content-Type", "text/csv")
+		if err := availabilityReport.WriteCSV(writer); err != nil {
+			render.Render(writer, request, api.ErrorRender(err))
+		}
+		return
+	}
+	if err := render.Render(writer, request, availabilityReport); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func slack(verificationToken string) func(chi.Router) {
+	return func(router chi.Router) {
+		router.Post("/interactions", handleSlackInteraction(verificationToken))
+	}
+}
+
+func handleSlackInteraction(verificationToken string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		interaction := dto.SlackInteraction{}
+		if err := json.Unmarshal([]byte(request.FormValue("payload")), &interaction); err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Failed to parse slack payload: %s", err.Error())))
+			return
+		}
+		logger := middleware.GetLoggerEntry(request)
+		response, err := controller.HandleSlackInteraction(database, logger, verificationToken, interaction)
+		if err != nil {
+			render.Render(writer, request, err)
+			return
+		}
+		if err := render.Render(writer, request, response); err != nil {
+			render.Render(writer, request, api.ErrorRender(err))
+		}
+	}
+}
@@ -79,8 +79,9 @@ func updateSubscription(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 	subscriptionData := request.Context().Value(subscriptionKey).(moira.SubscriptionData)
+	userLogin := middleware.GetLogin(request)
 
-	if err := controller.UpdateSubscription(database, subscriptionData.ID, subscriptionData.User, subscription); err != nil {
+	if err := controller.UpdateSubscription(database, subscriptionData, userLogin, subscription); err != nil {
 		render.Render(writer, request, err)
 		return
 	}
@@ -91,8 +92,9 @@ func updateSubscription(writer http.ResponseWriter, request *http.Request) {
 }
 
 func removeSubscription(writer http.ResponseWriter, request *http.Request) {
-	subscriptionID := middleware.GetSubscriptionID(request)
-	if err := controller.RemoveSubscription(database, subscriptionID); err != nil {
+	subscriptionData := request.Context().Value(subscriptionKey).(moira.SubscriptionData)
+	userLogin := middleware.GetLogin(request)
+	if err := controller.RemoveSubscription(database, subscriptionData, userLogin); err != nil {
 		render.Render(writer, request, err)
 	}
 }
@@ -14,7 +14,7 @@ func tag(router chi.Router) {
 	router.Get("/stats", getAllTagsAndSubscriptions)
 	router.Route("/{tag}", func(router chi.Router) {
 		router.Use(middleware.TagContext)
-		router.Delete("/", removeTag)
+		router.With(middleware.RequireAdmin).Delete("/", removeTag)
 	})
 }
 
@@ -46,7 +46,7 @@ func getAllTagsAndSubscriptions(writer http.ResponseWriter, request *http.Reques
 
 func removeTag(writer http.ResponseWriter, request *http.Request) {
 	tagName := middleware.GetTag(request)
-	response, err := controller.RemoveTag(database, tagName)
+	response, err := controller.RemoveTag(database, tagName, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err)
 		return
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func team(router chi.Router) {
+	router.Get("/", getUserTeams)
+	router.Put("/", createTeam)
+	router.Route("/{teamId}", func(router chi.Router) {
+		router.Use(middleware.TeamContext)
+		router.With(teamFilter(moira.TeamRoleViewer)).Get("/", getTeam)
+		router.With(teamFilter(moira.TeamRoleAdmin)).Put("/", updateTeam)
+		router.With(teamFilter(moira.TeamRoleAdmin)).Delete("/", removeTeam)
+	})
+}
+
+func getUserTeams(writer http.ResponseWriter, request *http.Request) {
+	userLogin := middleware.GetLogin(request)
+	teams, err := controller.GetUserTeams(database, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, teams); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func createTeam(writer http.ResponseWriter, request *http.Request) {
+	team := &dto.Team{}
+	if err := render.Bind(request, team); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	if err := controller.CreateTeam(database, team, userLogin); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, team); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+// teamFilter is middleware for check team existence and that user has at least given role in it
+func teamFilter(role string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			teamID := middleware.GetTeamID(request)
+			userLogin := middleware.GetLogin(request)
+			team, err := controller.CheckUserPermissionsForTeam(database, teamID, userLogin, role)
+			if err != nil {
+				render.Render(writer, request, err)
+				return
+			}
+			ctx := context.WithValue(request.Context(), teamKey, team)
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
+
+func getTeam(writer http.ResponseWriter, request *http.Request) {
+	team := request.Context().Value(teamKey).(moira.Team)
+	if err := render.Render(writer, request, &dto.Team{Team: team}); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func updateTeam(writer http.ResponseWriter, request *http.Request) {
+	team := &dto.Team{}
+	if err := render.Bind(request, team); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	teamID := middleware.GetTeamID(request)
+	if err := controller.UpdateTeam(database, team, teamID); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, team); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeTeam(writer http.ResponseWriter, request *http.Request) {
+	teamID := middleware.GetTeamID(request)
+	if err := controller.RemoveTeam(database, teamID); err != nil {
+		render.Render(writer, request, err)
+	}
+}
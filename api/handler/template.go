@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func template(router chi.Router) {
+	router.Get("/", getAllTriggerTemplates)
+	router.Put("/", createTriggerTemplate)
+	router.Route("/{templateId}", func(router chi.Router) {
+		router.Use(middleware.TemplateContext)
+		router.Get("/", getTriggerTemplate)
+		router.Put("/", updateTriggerTemplate)
+		router.Delete("/", removeTriggerTemplate)
+		router.Put("/instances", instantiateTriggerTemplate)
+	})
+}
+
+func getAllTriggerTemplates(writer http.ResponseWriter, request *http.Request) {
+	templates, err := controller.GetAllTriggerTemplates(database)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, templates); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func createTriggerTemplate(writer http.ResponseWriter, request *http.Request) {
+	template := &dto.TriggerTemplate{}
+	if err := render.Bind(request, template); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	response, err := controller.CreateTriggerTemplate(database, template, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getTriggerTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateID := middleware.GetTemplateID(request)
+	template, err := controller.GetTriggerTemplate(database, templateID)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, template); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func updateTriggerTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateID := middleware.GetTemplateID(request)
+	template := &dto.TriggerTemplate{}
+	if err := render.Bind(request, template); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	response, err := controller.UpdateTriggerTemplate(database, template, templateID, userLogin, dto.NewTemplateRenderer(request))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeTriggerTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateID := middleware.GetTemplateID(request)
+	userLogin := middleware.GetLogin(request)
+	if err := controller.RemoveTriggerTemplate(database, templateID, userLogin); err != nil {
+		render.Render(writer, request, err)
+	}
+}
+
+func instantiateTriggerTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateID := middleware.GetTemplateID(request)
+	instances := &dto.TemplateInstances{}
+	if err := render.Bind(request, instances); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	response, err := controller.InstantiateTriggerTemplate(database, templateID, userLogin, instances, dto.NewTemplateRenderer(request))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
@@ -19,19 +19,53 @@ import (
 
 func trigger(router chi.Router) {
 	router.Use(middleware.TriggerContext)
-	router.Put("/", updateTrigger)
+	router.Use(triggerReaderFilter)
+	router.With(triggerFilter).Put("/", updateTrigger)
 	router.Get("/", getTrigger)
-	router.Delete("/", removeTrigger)
+	router.With(triggerFilter).Delete("/", removeTrigger)
 	router.Get("/state", getTriggerState)
 	router.Route("/throttling", func(router chi.Router) {
 		router.Get("/", getTriggerThrottling)
-		router.Delete("/", deleteThrottling)
+		router.With(triggerFilter).Delete("/", deleteThrottling)
 	})
 	router.Route("/metrics", func(router chi.Router) {
 		router.With(middleware.DateRange("-10minutes", "now")).Get("/", getTriggerMetrics)
-		router.Delete("/", deleteTriggerMetric)
+		router.With(triggerFilter).Delete("/", deleteTriggerMetric)
+	})
+	router.With(middleware.DateRange("-1day", "now")).Get("/history", getTriggerHistory)
+	router.With(triggerFilter).Put("/maintenance", setMetricsMaintenance)
+	router.Route("/ack", func(router chi.Router) {
+		router.Use(triggerFilter)
+		router.Put("/", acknowledgeTrigger)
+		router.Delete("/", removeTriggerAcknowledgement)
+	})
+	router.Route("/revisions", triggerRevisions)
+}
+
+// triggerReaderFilter is middleware for check user permissions to see trigger owned by team
+func triggerReaderFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		triggerID := middleware.GetTriggerID(request)
+		userLogin := middleware.GetLogin(request)
+		if err := controller.CheckUserReadPermissionsForTrigger(database, triggerID, userLogin); err != nil {
+			render.Render(writer, request, err)
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// triggerFilter is middleware for check user permissions to change trigger owned by team
+func triggerFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		triggerID := middleware.GetTriggerID(request)
+		userLogin := middleware.GetLogin(request)
+		if err := controller.CheckUserPermissionsForTrigger(database, triggerID, userLogin); err != nil {
+			render.Render(writer, request, err)
+			return
+		}
+		next.ServeHTTP(writer, request)
 	})
-	router.Put("/maintenance", setMetricsMaintenance)
 }
 
 func updateTrigger(writer http.ResponseWriter, request *http.Request) {
@@ -49,8 +83,14 @@ func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	userLogin := middleware.GetLogin(request)
+	if err := controller.CheckUserPermissionsForTeamObject(database, trigger.TeamID, userLogin); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+
 	timeSeriesNames := middleware.GetTimeSeriesNames(request)
-	response, err := controller.UpdateTrigger(database, &trigger.TriggerModel, triggerID, timeSeriesNames)
+	response, err := controller.UpdateTrigger(database, &trigger.TriggerModel, triggerID, timeSeriesNames, userLogin)
 	if err != nil {
 		render.Render(writer, request, err)
 		return
@@ -64,7 +104,7 @@ func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 
 func removeTrigger(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
-	err := controller.RemoveTrigger(database, triggerID)
+	err := controller.RemoveTrigger(database, triggerID, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err)
 	}
@@ -111,7 +151,7 @@ func getTriggerThrottling(writer http.ResponseWriter, request *http.Request) {
 
 func deleteThrottling(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
-	err := controller.DeleteTriggerThrottling(database, triggerID)
+	err := controller.DeleteTriggerThrottling(database, triggerID, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err)
 	}
@@ -141,6 +181,30 @@ func getTriggerMetrics(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func getTriggerHistory(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	fromStr := middleware.GetFromStr(request)
+	toStr := middleware.GetToStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr)))
+		return
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %s", toStr)))
+		return
+	}
+	triggerHistory, err := controller.GetTriggerHistory(database, triggerID, int64(from), int64(to))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, triggerHistory); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
 func deleteTriggerMetric(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
 	metricName := request.URL.Query().Get("name")
@@ -160,8 +224,29 @@ func setMetricsMaintenance(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, api.ErrorInvalidRequest(err))
 		return
 	}
-	err := controller.SetMetricsMaintenance(database, triggerID, metricsMaintenance)
+	err := controller.SetMetricsMaintenance(database, triggerID, metricsMaintenance, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err)
 	}
 }
+
+func acknowledgeTrigger(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	ack := dto.Acknowledgement{}
+	if err := render.Bind(request, &ack); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	if err := controller.AcknowledgeTrigger(database, triggerID, userLogin, ack); err != nil {
+		render.Render(writer, request, err)
+	}
+}
+
+func removeTriggerAcknowledgement(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	metrics := request.URL.Query()["metric"]
+	if err := controller.RemoveTriggerAcknowledgement(database, triggerID, metrics, middleware.GetLogin(request)); err != nil {
+		render.Render(writer, request, err)
+	}
+}
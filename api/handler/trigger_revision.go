@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/expression"
+	"github.com/moira-alert/moira/target"
+)
+
+func triggerRevisions(router chi.Router) {
+	router.Get("/", getTriggerRevisions)
+	router.Get("/diff", getTriggerRevisionsDiff)
+	router.Route("/{revision}", func(router chi.Router) {
+		router.Use(middleware.TriggerRevisionContext)
+		router.Get("/", getTriggerRevision)
+		router.With(triggerFilter).Put("/restore", restoreTriggerRevision)
+	})
+}
+
+func getTriggerRevisions(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	revisions, err := controller.GetTriggerRevisions(database, triggerID)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, revisions); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getTriggerRevision(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	revision, err := controller.GetTriggerRevision(database, triggerID, middleware.GetTriggerRevision(request))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, revision); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getTriggerRevisionsDiff(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	from, err := strconv.ParseInt(request.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from revision: %s", request.URL.Query().Get("from"))))
+		return
+	}
+	to, err := strconv.ParseInt(request.URL.Query().Get("to"), 10, 64)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to revision: %s", request.URL.Query().Get("to"))))
+		return
+	}
+	diff, errorResponse := controller.GetTriggerRevisionsDiff(database, triggerID, from, to)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, diff); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func restoreTriggerRevision(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	number := middleware.GetTriggerRevision(request)
+	revision, errorResponse := controller.GetTriggerRevision(database, triggerID, number)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+
+	// restored definition is validated again, because its targets may be not valid anymore
+	trigger := &dto.Trigger{TriggerModel: dto.CreateTriggerModel(&revision.Trigger)}
+	if err := trigger.Bind(request); err != nil {
+		switch err.(type) {
+		case target.ErrParseExpr, target.ErrEvalExpr, target.ErrUnknownFunction:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid graphite targets: %s", err.Error())))
+		case expression.ErrInvalidExpression:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid expression: %s", err.Error())))
+		default:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Revision %d can not be restored: %s", number, err.Error())))
+		}
+		return
+	}
+
+	userLogin := middleware.GetLogin(request)
+	if err := controller.CheckUserPermissionsForTeamObject(database, trigger.TeamID, userLogin); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+
+	timeSeriesNames := middleware.GetTimeSeriesNames(request)
+	response, err := controller.RestoreTriggerRevision(database, &trigger.TriggerModel, triggerID, timeSeriesNames, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
 
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
@@ -20,11 +22,13 @@ func triggers(router chi.Router) {
 	router.Get("/", getAllTriggers)
 	router.Put("/", createTrigger)
 	router.With(middleware.Paginate(0, 10)).Get("/page", getTriggersPage)
+	router.With(middleware.DateRange("-1day", "now")).Post("/backtest", backtestTrigger)
 	router.Route("/{triggerId}", trigger)
 }
 
 func getAllTriggers(writer http.ResponseWriter, request *http.Request) {
-	triggersList, errorResponse := controller.GetAllTriggers(database)
+	userLogin := middleware.GetLogin(request)
+	triggersList, errorResponse := controller.GetAllTriggers(database, userLogin)
 	if errorResponse != nil {
 		render.Render(writer, request, errorResponse)
 		return
@@ -49,8 +53,13 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 		}
 		return
 	}
+	userLogin := middleware.GetLogin(request)
+	if err := controller.CheckUserPermissionsForTeamObject(database, trigger.TeamID, userLogin); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
 	timeSeriesNames := middleware.GetTimeSeriesNames(request)
-	response, err := controller.CreateTrigger(database, &trigger.TriggerModel, timeSeriesNames)
+	response, err := controller.CreateTrigger(database, &trigger.TriggerModel, timeSeriesNames, userLogin)
 	if err != nil {
 		render.Render(writer, request, err)
 		return
@@ -70,7 +79,8 @@ func getTriggersPage(writer http.ResponseWriter, request *http.Request) {
 	page := middleware.GetPage(request)
 	size := middleware.GetSize(request)
 
-	triggersList, errorResponse := controller.GetTriggerPage(database, page, size, onlyErrors, filterTags)
+	userLogin := middleware.GetLogin(request)
+	triggersList, errorResponse := controller.GetTriggerPage(database, page, size, onlyErrors, filterTags, userLogin)
 	if errorResponse != nil {
 		render.Render(writer, request, errorResponse)
 		return
@@ -82,6 +92,42 @@ func getTriggersPage(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func backtestTrigger(writer http.ResponseWriter, request *http.Request) {
+	fromStr := middleware.GetFromStr(request)
+	toStr := middleware.GetToStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr)))
+		return
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %s", toStr)))
+		return
+	}
+	trigger := &dto.Trigger{}
+	if err := render.Bind(request, trigger); err != nil {
+		switch err.(type) {
+		case target.ErrParseExpr, target.ErrEvalExpr, target.ErrUnknownFunction:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid graphite targets: %s", err.Error())))
+		case expression.ErrInvalidExpression:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid expression: %s", err.Error())))
+		default:
+			render.Render(writer, request, api.ErrorInternalServer(err))
+		}
+		return
+	}
+	logger := middleware.GetLoggerEntry(request)
+	result, err := controller.BacktestTrigger(database, logger, &trigger.TriggerModel, int64(from), int64(to))
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, result); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
 func getRequestTags(request *http.Request) []string {
 	var filterTags []string
 	i := 0
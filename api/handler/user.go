@@ -13,6 +13,11 @@ import (
 func user(router chi.Router) {
 	router.Get("/", getUserName)
 	router.Get("/settings", getUserSettings)
+	router.Route("/tokens", func(router chi.Router) {
+		router.Get("/", getUserTokens)
+		router.Put("/", createUserToken)
+		router.Delete("/{tokenId}", removeUserToken)
+	})
 }
 
 func getUserName(writer http.ResponseWriter, request *http.Request) {
@@ -36,3 +41,40 @@ func getUserSettings(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 }
+
+func getUserTokens(writer http.ResponseWriter, request *http.Request) {
+	userLogin := middleware.GetLogin(request)
+	tokens, err := controller.GetUserTokens(database, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, tokens); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func createUserToken(writer http.ResponseWriter, request *http.Request) {
+	token := &dto.UserToken{}
+	if err := render.Bind(request, token); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	response, err := controller.CreateUserToken(database, token, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeUserToken(writer http.ResponseWriter, request *http.Request) {
+	tokenID := chi.URLParam(request, "tokenId")
+	userLogin := middleware.GetLogin(request)
+	if err := controller.RemoveUserToken(database, tokenID, userLogin); err != nil {
+		render.Render(writer, request, err)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/database"
+)
+
+// Authenticator gets login of user making request. Empty login without error means
+// that request has no credentials of kind checked by authenticator
+type Authenticator interface {
+	Authenticate(request *http.Request) (string, error)
+}
+
+// ErrInvalidCredentials is returned by Authenticator if request credentials are given, but are not valid
+type ErrInvalidCredentials struct {
+	message string
+}
+
+// Error is implementation of golang error interface for ErrInvalidCredentials struct
+func (err ErrInvalidCredentials) Error() string {
+	return err.message
+}
+
+// NewAuthenticator creates authenticator trying configured providers in given order
+func NewAuthenticator(config *api.AuthConfig, dataBase moira.Database) (Authenticator, error) {
+	authenticators := make(authenticatorsChain, 0, len(config.Providers))
+	for _, provider := range config.Providers {
+		switch provider {
+		case api.AuthProviderHeader:
+			if config.Header == "" {
+				return nil, fmt.Errorf("header of %s auth provider is not set", provider)
+			}
+			authenticators = append(authenticators, &HeaderAuthenticator{Header: config.Header})
+		case api.AuthProviderJWT:
+			authenticator, err := NewJWTAuthenticator(&config.JWT)
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, authenticator)
+		case api.AuthProviderToken:
+			authenticators = append(authenticators, &TokenAuthenticator{Database: dataBase})
+		default:
+			return nil, fmt.Errorf("unknown auth provider %s", provider)
+		}
+	}
+	return authenticators, nil
+}
+
+// authenticatorsChain returns login given by first authenticator finding credentials in request
+type authenticatorsChain []Authenticator
+
+func (authenticators authenticatorsChain) Authenticate(request *http.Request) (string, error) {
+	for _, authenticator := range authenticators {
+		login, err := authenticator.Authenticate(request)
+		if err != nil || login != "" {
+			return login, err
+		}
+	}
+	return "", nil
+}
+
+// HeaderAuthenticator trusts user login given in request header, so API must be reachable only through authenticating proxy
+type HeaderAuthenticator struct {
+	Header string
+}
+
+// Authenticate returns login given in configured header
+func (authenticator *HeaderAuthenticator) Authenticate(request *http.Request) (string, error) {
+	return request.Header.Get(authenticator.Header), nil
+}
+
+// TokenAuthenticator checks personal API tokens of users given as bearer tokens
+type TokenAuthenticator struct {
+	Database moira.Database
+}
+
+// Authenticate returns login of user token was issued to
+func (authenticator *TokenAuthenticator) Authenticate(request *http.Request) (string, error) {
+	tokenID, secret, ok := ParseUserToken(getBearerToken(request))
+	if !ok {
+		return "", nil
+	}
+	token, err := authenticator.Database.GetUserToken(tokenID)
+	if err != nil {
+		if err == database.ErrNil {
+			return "", ErrInvalidCredentials{message: "Invalid API token"}
+		}
+		return "", err
+	}
+	if !token.IsSecretValid(secret) {
+		return "", ErrInvalidCredentials{message: "Invalid API token"}
+	}
+	return token.User, nil
+}
+
+// FormatUserToken returns API token given to user, it consists of token ID and secret
+func FormatUserToken(tokenID, secret string) string {
+	return tokenID + "." + secret
+}
+
+// ParseUserToken splits API token to token ID and secret
+func ParseUserToken(token string) (string, string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func getBearerToken(request *http.Request) string {
+	header := request.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "moira-jwt")
+	defer os.RemoveAll(dir)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	jwksFile := filepath.Join(dir, "jwks.json")
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y)},
+	}})
+	ioutil.WriteFile(jwksFile, jwks, 0600)
+
+	pemFile := filepath.Join(dir, "key.pem")
+	pemBytes, _ := x509.MarshalPKIXPublicKey(&otherKey.PublicKey)
+	ioutil.WriteFile(pemFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pemBytes}), 0600)
+
+	now := time.Unix(1502719200, 0)
+	claims := map[string]interface{}{"sub": "user", "iss": "issuer", "aud": []string{"moira"}, "exp": now.Unix() + 60}
+
+	Convey("Authenticator without keys can not be created", t, func() {
+		_, err := NewJWTAuthenticator(&api.JWTConfig{})
+		So(err, ShouldNotBeNil)
+	})
+
+	authenticator, err := NewJWTAuthenticator(&api.JWTConfig{JWKSFile: jwksFile, KeyFiles: []string{pemFile}, Issuer: "issuer", Audience: "moira"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.now = func() time.Time { return now }
+
+	Convey("Tokens signed by configured keys are accepted", t, func() {
+		login, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", claims)))
+		So(err, ShouldBeNil)
+		So(login, ShouldEqual, "user")
+
+		login, err = authenticator.Authenticate(bearerRequest(signES256(ecKey, "ec", claims)))
+		So(err, ShouldBeNil)
+		So(login, ShouldEqual, "user")
+
+		login, err = authenticator.Authenticate(bearerRequest(signRS256(otherKey, "", claims)))
+		So(err, ShouldBeNil)
+		So(login, ShouldEqual, "user")
+	})
+
+	Convey("Request without JWT has no credentials", t, func() {
+		login, err := authenticator.Authenticate(bearerRequest(""))
+		So(err, ShouldBeNil)
+		So(login, ShouldBeEmpty)
+
+		login, err = authenticator.Authenticate(bearerRequest("token.secret"))
+		So(err, ShouldBeNil)
+		So(login, ShouldBeEmpty)
+	})
+
+	Convey("Invalid tokens are rejected", t, func() {
+		Convey("Signed by unknown key", func() {
+			unknownKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(unknownKey, "rsa", claims)))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: invalid signature"})
+		})
+
+		Convey("Signed by key with other ID", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signES256(ecKey, "rsa", claims)))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: invalid signature"})
+		})
+
+		Convey("Not signed", func() {
+			header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+			payload, _ := json.Marshal(claims)
+			token := header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+			_, err := authenticator.Authenticate(bearerRequest(token))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: unsupported algorithm none"})
+		})
+
+		Convey("Expired", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", withClaim(claims, "exp", now.Unix()))))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: token is expired"})
+		})
+
+		Convey("Not valid yet", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", withClaim(claims, "nbf", now.Unix()+10))))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: token is not valid yet"})
+		})
+
+		Convey("Issued by other issuer", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", withClaim(claims, "iss", "other"))))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: unexpected issuer"})
+		})
+
+		Convey("Issued for other audience", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", withClaim(claims, "aud", "other"))))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: unexpected audience"})
+		})
+
+		Convey("Without login", func() {
+			_, err := authenticator.Authenticate(bearerRequest(signRS256(rsaKey, "rsa", withClaim(claims, "sub", ""))))
+			So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid JWT: sub claim is not set"})
+		})
+	})
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	authenticator := &TokenAuthenticator{Database: dataBase}
+	token := moira.UserToken{ID: "token", User: "user", Hash: moira.HashTokenSecret("secret")}
+
+	Convey("Valid token", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(token, nil)
+		login, err := authenticator.Authenticate(bearerRequest(FormatUserToken(token.ID, "secret")))
+		So(err, ShouldBeNil)
+		So(login, ShouldEqual, "user")
+	})
+
+	Convey("Invalid secret", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(token, nil)
+		_, err := authenticator.Authenticate(bearerRequest(FormatUserToken(token.ID, "other")))
+		So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid API token"})
+	})
+
+	Convey("Revoked token", t, func() {
+		dataBase.EXPECT().GetUserToken(token.ID).Return(moira.UserToken{}, database.ErrNil)
+		_, err := authenticator.Authenticate(bearerRequest(FormatUserToken(token.ID, "secret")))
+		So(err, ShouldResemble, ErrInvalidCredentials{message: "Invalid API token"})
+	})
+
+	Convey("Request without token has no credentials", t, func() {
+		login, err := authenticator.Authenticate(&http.Request{Header: http.Header{}})
+		So(err, ShouldBeNil)
+		So(login, ShouldBeEmpty)
+	})
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	Convey("Providers are tried in given order", t, func() {
+		authenticator, err := NewAuthenticator(&api.AuthConfig{Providers: []string{api.AuthProviderToken, api.AuthProviderHeader}, Header: "x-webauth-user"}, nil)
+		So(err, ShouldBeNil)
+		request := &http.Request{Header: http.Header{}}
+		request.Header.Set("x-webauth-user", "user")
+		login, err := authenticator.Authenticate(request)
+		So(err, ShouldBeNil)
+		So(login, ShouldEqual, "user")
+	})
+
+	Convey("Unknown provider", t, func() {
+		_, err := NewAuthenticator(&api.AuthConfig{Providers: []string{"ldap"}}, nil)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRequireAdmin(t *testing.T) {
+	handler := UserContext(&HeaderAuthenticator{Header: "x-webauth-user"})(AdminContext([]string{"admin"})(RequireAdmin(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))))
+	serve := func(login string) int {
+		request := httptest.NewRequest("DELETE", "/api/notification", nil)
+		request.Header.Set("x-webauth-user", login)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	Convey("Admin is permitted", t, func() {
+		So(serve("admin"), ShouldEqual, http.StatusOK)
+	})
+
+	Convey("Other users and anonymous are rejected", t, func() {
+		So(serve("user"), ShouldEqual, http.StatusForbidden)
+		So(serve(""), ShouldEqual, http.StatusForbidden)
+	})
+}
+
+func bearerRequest(token string) *http.Request {
+	request := &http.Request{Header: http.Header{}}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return request
+}
+
+func withClaim(claims map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(claims))
+	for key, claim := range claims {
+		result[key] = claim
+	}
+	result[name] = value
+	return result
+}
+
+func signingInput(algorithm, keyID string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": algorithm, "kid": keyID})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func signRS256(key *rsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	input := signingInput("RS256", keyID, claims)
+	hasher := crypto.SHA256.New()
+	hasher.Write([]byte(input))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hasher.Sum(nil))
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(key *ecdsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	input := signingInput("ES256", keyID, claims)
+	hasher := crypto.SHA256.New()
+	hasher.Write([]byte(input))
+	r, s, _ := ecdsa.Sign(rand.Reader, key, hasher.Sum(nil))
+	signature := make([]byte, 64)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(signature[32-len(rBytes):32], rBytes)
+	copy(signature[64-len(sBytes):], sBytes)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
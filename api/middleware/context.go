@@ -21,12 +21,60 @@ func DatabaseContext(database moira.Database) func(next http.Handler) http.Handl
 	}
 }
 
-// UserContext get x-webauth-user header and sets it in request context, if header is empty sets empty string
-func UserContext(next http.Handler) http.Handler {
+// UserContext authenticates request and sets user login to request context, login of request without credentials is empty string
+func UserContext(authenticator Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			userLogin, err := authenticator.Authenticate(request)
+			if err != nil {
+				switch err.(type) {
+				case ErrInvalidCredentials:
+					render.Render(writer, request, api.ErrorUnauthorized(err.Error()))
+				default:
+					render.Render(writer, request, api.ErrorInternalServer(err))
+				}
+				return
+			}
+			ctx := context.WithValue(request.Context(), loginKey, userLogin)
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
+
+// AdminContext sets to request context if authenticated user is one of given moira admins
+func AdminContext(admins []string) func(next http.Handler) http.Handler {
+	adminsSet := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		adminsSet[admin] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			userLogin := GetLogin(request)
+			ctx := context.WithValue(request.Context(), adminKey, userLogin != "" && adminsSet[userLogin])
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin rejects requests of users who are not moira admins
+func RequireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		userLogin := request.Header.Get("x-webauth-user")
-		ctx := context.WithValue(request.Context(), loginKey, userLogin)
-		next.ServeHTTP(writer, request.WithContext(ctx))
+		if !IsAdmin(request) {
+			render.Render(writer, request, api.ErrorForbidden("Only admins are permitted"))
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// RequireUser rejects requests of anonymous users
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if GetLogin(request) == "" {
+			render.Render(writer, request, api.ErrorUnauthorized("Authentication required"))
+			return
+		}
+		next.ServeHTTP(writer, request)
 	})
 }
 
@@ -43,6 +91,45 @@ func TriggerContext(next http.Handler) http.Handler {
 	})
 }
 
+// TriggerRevisionContext gets revision number from parsed URI corresponding to trigger revision routes and set it to request context
+func TriggerRevisionContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		revision, err := strconv.ParseInt(chi.URLParam(request, "revision"), 10, 64)
+		if err != nil || revision < 1 {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Revision must be positive number")))
+			return
+		}
+		ctx := context.WithValue(request.Context(), revisionKey, revision)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// TemplateContext gets templateId from parsed URI corresponding to trigger template routes and set it to request context
+func TemplateContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		templateID := chi.URLParam(request, "templateId")
+		if templateID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("TemplateID must be set")))
+			return
+		}
+		ctx := context.WithValue(request.Context(), templateIDKey, templateID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// TeamContext gets teamId from parsed URI corresponding to team routes and set it to request context
+func TeamContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		teamID := chi.URLParam(request, "teamId")
+		if teamID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("TeamID must be set")))
+			return
+		}
+		ctx := context.WithValue(request.Context(), teamIDKey, teamID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
 // ContactContext gets contactID from parsed URI corresponding to trigger routes and set it to request context
 func ContactContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -0,0 +1,310 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	// Hash functions used by supported signature algorithms are registered by their packages
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/moira-alert/moira/api"
+)
+
+const defaultLoginClaim = "sub"
+
+// jwtAlgorithms are supported JWT signature algorithms, symmetric ones are not supported
+// as API would have to keep secret able to issue tokens
+var jwtAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// JWTAuthenticator checks JWT bearer tokens signed by one of configured RSA or ECDSA keys
+type JWTAuthenticator struct {
+	keys       []jwtKey
+	issuer     string
+	audience   string
+	loginClaim string
+	now        func() time.Time
+}
+
+// jwtKey is public key, keys without ID are tried for all tokens
+type jwtKey struct {
+	id  string
+	key crypto.PublicKey
+}
+
+// NewJWTAuthenticator creates JWT authenticator with keys read from JWKS file and PEM key files
+func NewJWTAuthenticator(config *api.JWTConfig) (*JWTAuthenticator, error) {
+	authenticator := &JWTAuthenticator{
+		issuer:     config.Issuer,
+		audience:   config.Audience,
+		loginClaim: config.LoginClaim,
+		now:        time.Now,
+	}
+	if authenticator.loginClaim == "" {
+		authenticator.loginClaim = defaultLoginClaim
+	}
+	if config.JWKSFile != "" {
+		keys, err := readJWKSFile(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.keys = append(authenticator.keys, keys...)
+	}
+	for _, path := range config.KeyFiles {
+		key, err := readPEMKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.keys = append(authenticator.keys, jwtKey{key: key})
+	}
+	if len(authenticator.keys) == 0 {
+		return nil, fmt.Errorf("jwt auth provider has no keys, set JWKS file or key files")
+	}
+	return authenticator, nil
+}
+
+// Authenticate returns login given in configured claim of valid JWT
+func (authenticator *JWTAuthenticator) Authenticate(request *http.Request) (string, error) {
+	token := getBearerToken(request)
+	if strings.Count(token, ".") != 2 {
+		return "", nil
+	}
+	login, err := authenticator.getLogin(token)
+	if err != nil {
+		return "", ErrInvalidCredentials{message: fmt.Sprintf("Invalid JWT: %s", err.Error())}
+	}
+	return login, nil
+}
+
+func (authenticator *JWTAuthenticator) getLogin(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return "", fmt.Errorf("failed to parse header: %s", err.Error())
+	}
+	hash, ok := jwtAlgorithms[header.Algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported algorithm %s", header.Algorithm)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode signature: %s", err.Error())
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if !authenticator.verify(header.Algorithm, header.KeyID, hash, hasher.Sum(nil), signature) {
+		return "", fmt.Errorf("invalid signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("failed to parse claims: %s", err.Error())
+	}
+	now := authenticator.now().Unix()
+	expiration, ok := claims["exp"].(float64)
+	if !ok {
+		return "", fmt.Errorf("exp claim is required")
+	}
+	if int64(expiration) <= now {
+		return "", fmt.Errorf("token is expired")
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && int64(notBefore) > now {
+		return "", fmt.Errorf("token is not valid yet")
+	}
+	if authenticator.issuer != "" && claims["iss"] != authenticator.issuer {
+		return "", fmt.Errorf("unexpected issuer")
+	}
+	if authenticator.audience != "" && !hasAudience(claims["aud"], authenticator.audience) {
+		return "", fmt.Errorf("unexpected audience")
+	}
+	login, _ := claims[authenticator.loginClaim].(string)
+	if login == "" {
+		return "", fmt.Errorf("%s claim is not set", authenticator.loginClaim)
+	}
+	return login, nil
+}
+
+// verify checks signature by keys with given ID and keys without ID
+func (authenticator *JWTAuthenticator) verify(algorithm, keyID string, hash crypto.Hash, digest, signature []byte) bool {
+	for _, key := range authenticator.keys {
+		if key.id != "" && key.id != keyID {
+			continue
+		}
+		switch publicKey := key.key.(type) {
+		case *rsa.PublicKey:
+			if strings.HasPrefix(algorithm, "RS") && rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) == nil {
+				return true
+			}
+		case *ecdsa.PublicKey:
+			if strings.HasPrefix(algorithm, "ES") && verifyECDSA(publicKey, digest, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyECDSA checks JWS ECDSA signature, which is concatenation of fixed size R and S
+func verifyECDSA(key *ecdsa.PublicKey, digest, signature []byte) bool {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	return ecdsa.Verify(key, digest, r, s)
+}
+
+func hasAudience(claim interface{}, audience string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == audience
+	case []interface{}:
+		for _, item := range value {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeJWTPart(part string, value interface{}) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, value)
+}
+
+// jsonWebKey is RSA or EC public key of JWKS
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func readJWKSFile(path string) ([]jwtKey, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read JWKS file %s: %s", path, err.Error())
+	}
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(bytes, &keySet); err != nil {
+		return nil, fmt.Errorf("Failed to parse JWKS file %s: %s", path, err.Error())
+	}
+	keys := make([]jwtKey, 0, len(keySet.Keys))
+	for _, webKey := range keySet.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+		key, err := webKey.getPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse key %s of JWKS file %s: %s", webKey.KeyID, path, err.Error())
+		}
+		keys = append(keys, jwtKey{id: webKey.KeyID, key: key})
+	}
+	return keys, nil
+}
+
+func (webKey *jsonWebKey) getPublicKey() (crypto.PublicKey, error) {
+	switch webKey.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch webKey.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", webKey.Curve)
+		}
+		x, err := decodeBigInt(webKey.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(webKey.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", webKey.Curve)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", webKey.KeyType)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// readPEMKeyFile reads public key or certificate in PEM format
+func readPEMKeyFile(path string) (crypto.PublicKey, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read key file %s: %s", path, err.Error())
+	}
+	block, _ := pem.Decode(bytes)
+	if block == nil {
+		return nil, fmt.Errorf("Failed to parse key file %s: no PEM data found", path)
+	}
+	if block.Type == "CERTIFICATE" {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse certificate file %s: %s", path, err.Error())
+		}
+		return certificate.PublicKey, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse key file %s: %s", path, err.Error())
+	}
+	return key, nil
+}
@@ -16,6 +16,9 @@ func (key ContextKey) String() string {
 var (
 	databaseKey        ContextKey = "database"
 	triggerIDKey       ContextKey = "triggerID"
+	revisionKey        ContextKey = "revision"
+	templateIDKey      ContextKey = "templateID"
+	teamIDKey          ContextKey = "teamID"
 	contactIDKey       ContextKey = "contactID"
 	tagKey             ContextKey = "tag"
 	subscriptionIDKey  ContextKey = "subscriptionID"
@@ -24,6 +27,7 @@ var (
 	fromKey            ContextKey = "from"
 	toKey              ContextKey = "to"
 	loginKey           ContextKey = "login"
+	adminKey           ContextKey = "admin"
 	timeSeriesNamesKey ContextKey = "timeSeriesNames"
 )
 
@@ -37,11 +41,32 @@ func GetLogin(request *http.Request) string {
 	return request.Context().Value(loginKey).(string)
 }
 
+// IsAdmin tells if user making request is moira admin, which is set in AdminContext middleware
+func IsAdmin(request *http.Request) bool {
+	isAdmin, _ := request.Context().Value(adminKey).(bool)
+	return isAdmin
+}
+
 // GetTriggerID gets TriggerID string from request context, which was sets in TriggerContext middleware
 func GetTriggerID(request *http.Request) string {
 	return request.Context().Value(triggerIDKey).(string)
 }
 
+// GetTriggerRevision gets trigger revision number from request context, which was sets in TriggerRevisionContext middleware
+func GetTriggerRevision(request *http.Request) int64 {
+	return request.Context().Value(revisionKey).(int64)
+}
+
+// GetTemplateID gets TemplateID string from request context, which was sets in TemplateContext middleware
+func GetTemplateID(request *http.Request) string {
+	return request.Context().Value(templateIDKey).(string)
+}
+
+// GetTeamID gets TeamID string from request context, which was sets in TeamContext middleware
+func GetTeamID(request *http.Request) string {
+	return request.Context().Value(teamIDKey).(string)
+}
+
 // GetTag gets tag string from request context, which was sets in TagContext middleware
 func GetTag(request *http.Request) string {
 	return request.Context().Value(tagKey).(string)
@@ -77,6 +102,12 @@ func GetToStr(request *http.Request) string {
 	return request.Context().Value(toKey).(string)
 }
 
+// SetDatabase sets to requests context given database, it is used to bind entities outside of API router
+func SetDatabase(request *http.Request, database moira.Database) {
+	ctx := context.WithValue(request.Context(), databaseKey, database)
+	*request = *request.WithContext(ctx)
+}
+
 // SetTimeSeriesNames sets to requests context timeSeriesNames from saved trigger
 func SetTimeSeriesNames(request *http.Request, timeSeriesNames map[string]bool) {
 	ctx := context.WithValue(request.Context(), timeSeriesNamesKey, timeSeriesNames)
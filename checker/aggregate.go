@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/expression"
+)
+
+// aggregateStates are input triggers states listed in aggregate trigger check message
+var aggregateStates = []string{ERROR, WARN, NODATA, EXCEPTION, OK}
+
+// GetAggregateInputTriggerIDs returns IDs of triggers aggregate trigger is evaluated over:
+// triggers given by ID and triggers having all selector tags
+func GetAggregateInputTriggerIDs(dataBase moira.Database, aggregate *moira.AggregateSettings) ([]string, error) {
+	inputIDs := make(map[string]bool)
+	for _, triggerID := range aggregate.TriggerIDs {
+		inputIDs[triggerID] = true
+	}
+	var tagged map[string]bool
+	for _, tag := range aggregate.Tags {
+		tagTriggerIDs, err := dataBase.GetTagTriggerIDs(tag)
+		if err != nil {
+			return nil, err
+		}
+		current := make(map[string]bool, len(tagTriggerIDs))
+		for _, triggerID := range tagTriggerIDs {
+			if tagged == nil || tagged[triggerID] {
+				current[triggerID] = true
+			}
+		}
+		tagged = current
+	}
+	for triggerID := range tagged {
+		inputIDs[triggerID] = true
+	}
+	result := make([]string, 0, len(inputIDs))
+	for triggerID := range inputIDs {
+		result = append(result, triggerID)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetDependentTriggerIDs returns aggregate triggers using checked trigger as input if its state or score was changed by Check
+func (triggerChecker *TriggerChecker) GetDependentTriggerIDs() ([]string, error) {
+	if !triggerChecker.stateChanged {
+		return nil, nil
+	}
+	aggregateIDs, err := triggerChecker.Database.GetAggregateTriggerIDs()
+	if err != nil {
+		return nil, err
+	}
+	aggregateTriggers, err := triggerChecker.Database.GetTriggers(aggregateIDs)
+	if err != nil {
+		return nil, err
+	}
+	dependentIDs := make([]string, 0)
+	for _, aggregateTrigger := range aggregateTriggers {
+		if aggregateTrigger == nil || aggregateTrigger.Aggregate == nil || aggregateTrigger.ID == triggerChecker.TriggerID {
+			continue
+		}
+		if aggregateTrigger.Aggregate.IsDependentOn(triggerChecker.trigger) {
+			dependentIDs = append(dependentIDs, aggregateTrigger.ID)
+		}
+	}
+	return dependentIDs, nil
+}
+
+// handleAggregateTrigger evaluates aggregate trigger expression over last checks of input triggers,
+// input trigger state is the worst of its trigger and metrics states
+func (triggerChecker *TriggerChecker) handleAggregateTrigger(checkData moira.CheckData) (moira.CheckData, error) {
+	inputIDs, err := GetAggregateInputTriggerIDs(triggerChecker.Database, triggerChecker.trigger.Aggregate)
+	if err != nil {
+		return checkData, err
+	}
+	aggregateExpression := expression.AggregateExpression{
+		Expression:    triggerChecker.trigger.Aggregate.Expression,
+		StatesCount:   make(map[string]int64),
+		PreviousState: triggerChecker.lastCheck.State,
+	}
+	for _, triggerID := range inputIDs {
+		if triggerID == triggerChecker.TriggerID {
+			continue
+		}
+		lastCheck, err := triggerChecker.Database.GetTriggerLastCheck(triggerID)
+		if err != nil {
+			if err != database.ErrNil {
+				return checkData, err
+			}
+			lastCheck.State = NODATA
+		}
+		aggregateExpression.StatesCount[lastCheck.GetWorstState()]++
+		aggregateExpression.Total++
+		aggregateExpression.Score += lastCheck.Score
+	}
+
+	if aggregateExpression.Total == 0 {
+		checkData.State = NODATA
+		checkData.Message = "Aggregate trigger has no input triggers"
+		return triggerChecker.compareChecks(checkData)
+	}
+	checkData.State, err = aggregateExpression.Evaluate()
+	if err != nil {
+		return checkData, err
+	}
+	checkData.Message = getAggregateMessage(aggregateExpression.StatesCount)
+	return triggerChecker.compareChecks(checkData)
+}
+
+func getAggregateMessage(statesCount map[string]int64) string {
+	parts := make([]string, 0, len(aggregateStates))
+	for _, state := range aggregateStates {
+		if count := statesCount[state]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", state, count))
+		}
+	}
+	return fmt.Sprintf("Input triggers states: %s", strings.Join(parts, ", "))
+}
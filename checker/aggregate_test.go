@@ -0,0 +1,143 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAggregateInputTriggerIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Only trigger IDs", t, func() {
+		actual, err := GetAggregateInputTriggerIDs(dataBase, &moira.AggregateSettings{TriggerIDs: []string{"t2", "t1"}})
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, []string{"t1", "t2"})
+	})
+
+	Convey("Triggers selected by all tags", t, func() {
+		dataBase.EXPECT().GetTagTriggerIDs("api").Return([]string{"t1", "t2", "t3"}, nil)
+		dataBase.EXPECT().GetTagTriggerIDs("prod").Return([]string{"t2", "t3", "t4"}, nil)
+		actual, err := GetAggregateInputTriggerIDs(dataBase, &moira.AggregateSettings{TriggerIDs: []string{"t5"}, Tags: []string{"api", "prod"}})
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, []string{"t2", "t3", "t5"})
+	})
+
+	Convey("Get tag triggers error", t, func() {
+		expected := fmt.Errorf("oops")
+		dataBase.EXPECT().GetTagTriggerIDs("api").Return(nil, expected)
+		actual, err := GetAggregateInputTriggerIDs(dataBase, &moira.AggregateSettings{Tags: []string{"api"}})
+		So(err, ShouldResemble, expected)
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestHandleAggregateTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
+
+	var now int64 = 1502719200
+	triggerChecker := TriggerChecker{
+		TriggerID: "aggregate",
+		Database:  dataBase,
+		Logger:    logger,
+		Until:     now,
+		trigger: &moira.Trigger{
+			ID:   "aggregate",
+			Name: "Both are broken",
+			Aggregate: &moira.AggregateSettings{
+				TriggerIDs: []string{"api-errors", "db-latency", "aggregate"},
+				Expression: "count(ERROR) >= 2",
+			},
+		},
+		lastCheck: &moira.CheckData{
+			Metrics:   make(map[string]moira.MetricState),
+			State:     OK,
+			Timestamp: now - 60,
+		},
+	}
+
+	Convey("Inputs in ERROR state", t, func() {
+		dataBase.EXPECT().GetTriggerLastCheck("api-errors").Return(moira.CheckData{State: ERROR, Score: 100}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("db-latency").Return(moira.CheckData{State: OK, Score: 100, Metrics: map[string]moira.MetricState{"db.latency": {State: ERROR}}}, nil)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			IsTriggerEvent: true,
+			TriggerID:      "aggregate",
+			State:          ERROR,
+			OldState:       OK,
+			Timestamp:      now,
+			Metric:         "Both are broken",
+			Message:        &[]string{"Input triggers states: ERROR: 2"}[0],
+		}, true).Return(nil)
+
+		actual, err := triggerChecker.handleTrigger()
+		So(err, ShouldBeNil)
+		So(actual.State, ShouldEqual, ERROR)
+		So(actual.Message, ShouldEqual, "Input triggers states: ERROR: 2")
+		So(actual.EventTimestamp, ShouldEqual, now)
+	})
+
+	Convey("Input without last check is NODATA", t, func() {
+		dataBase.EXPECT().GetTriggerLastCheck("api-errors").Return(moira.CheckData{State: ERROR}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("db-latency").Return(moira.CheckData{}, database.ErrNil)
+
+		actual, err := triggerChecker.handleTrigger()
+		So(err, ShouldBeNil)
+		So(actual.State, ShouldEqual, OK)
+		So(actual.Message, ShouldEqual, "Input triggers states: ERROR: 1, NODATA: 1")
+	})
+
+	Convey("Invalid expression", t, func() {
+		triggerChecker.trigger.Aggregate.Expression = "count(ERROR) >= t1"
+		dataBase.EXPECT().GetTriggerLastCheck("api-errors").Return(moira.CheckData{State: ERROR}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("db-latency").Return(moira.CheckData{State: OK}, nil)
+
+		_, err := triggerChecker.handleTrigger()
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "no value with name t1")
+	})
+}
+
+func TestGetDependentTriggerIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "api-errors",
+		Database:  dataBase,
+		trigger:   &moira.Trigger{ID: "api-errors", Tags: []string{"api"}},
+	}
+
+	Convey("State is not changed", t, func() {
+		actual, err := triggerChecker.GetDependentTriggerIDs()
+		So(err, ShouldBeNil)
+		So(actual, ShouldBeEmpty)
+	})
+
+	Convey("State is changed", t, func() {
+		triggerChecker.stateChanged = true
+		aggregateTriggers := []*moira.Trigger{
+			{ID: "by-id", Aggregate: &moira.AggregateSettings{TriggerIDs: []string{"api-errors"}}},
+			{ID: "by-tag", Aggregate: &moira.AggregateSettings{Tags: []string{"api"}}},
+			{ID: "other", Aggregate: &moira.AggregateSettings{Tags: []string{"db"}}},
+			nil,
+		}
+		dataBase.EXPECT().GetAggregateTriggerIDs().Return([]string{"by-id", "by-tag", "other", "removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"by-id", "by-tag", "other", "removed"}).Return(aggregateTriggers, nil)
+		actual, err := triggerChecker.GetDependentTriggerIDs()
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, []string{"by-id", "by-tag"})
+	})
+}
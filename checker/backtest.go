@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moira-alert/moira"
+)
+
+// BacktestResult contains metrics state transitions and events trigger would have produced over stored metrics values
+type BacktestResult struct {
+	Transitions map[string][]moira.MetricState `json:"transitions"`
+	Events      []moira.NotificationEvent      `json:"events"`
+}
+
+// Backtest runs trigger checking state machine over metrics values stored within given time range.
+// Nothing is written to database: metrics states are kept in memory and events are collected to result.
+// Trigger schedule is applied, while maintenance and inhibition by other triggers are not
+func Backtest(dataBase moira.Database, logger moira.Logger, trigger *moira.Trigger, from, until int64) (*BacktestResult, error) {
+	if trigger.Aggregate != nil {
+		return nil, fmt.Errorf("Aggregate trigger can not be backtested")
+	}
+	result := &BacktestResult{
+		Transitions: make(map[string][]moira.MetricState),
+		Events:      make([]moira.NotificationEvent, 0),
+	}
+	triggerChecker := &TriggerChecker{
+		TriggerID: trigger.ID,
+		Database:  dataBase,
+		Logger:    logger,
+		Config:    &Config{},
+		From:      from,
+		Until:     until,
+		trigger:   trigger,
+		lastCheck: &moira.CheckData{
+			Metrics:   make(map[string]moira.MetricState),
+			State:     NODATA,
+			Timestamp: from,
+		},
+		inhibitedBy: new(string),
+		backtest:    result,
+	}
+	triggerTimeSeries, _, err := triggerChecker.getTimeSeries(from, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, timeSeries := range triggerTimeSeries.Main {
+		if _, ok := result.Transitions[timeSeries.Name]; ok || timeSeries.Wildcard {
+			continue
+		}
+		transitions := make([]moira.MetricState, 0)
+		lastState := triggerChecker.lastCheck.GetOrCreateMetricState(timeSeries.Name, int64(timeSeries.StartTime-3600))
+		metricStates, err := triggerChecker.getTimeSeriesStepsStates(triggerTimeSeries, timeSeries, lastState)
+		if err != nil {
+			return nil, err
+		}
+		for _, currentState := range metricStates {
+			previousState := lastState.State
+			lastState, err = triggerChecker.compareStates(timeSeries.Name, currentState, lastState)
+			if err != nil {
+				return nil, err
+			}
+			if lastState.State != previousState {
+				transitions = append(transitions, lastState)
+			}
+		}
+		result.Transitions[timeSeries.Name] = transitions
+	}
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].Timestamp < result.Events[j].Timestamp
+	})
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBacktest(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+
+	var retention int64 = 10
+	var warnValue float64 = 1
+	var errValue float64 = 3
+	pattern := "super.puper.pattern"
+	metric := "super.puper.metric"
+	var from int64 = 3617
+	var until int64 = 3667
+	dataList := map[string][]*moira.MetricValue{
+		metric: {
+			{RetentionTimestamp: 3620, Timestamp: 3623, Value: 0},
+			{RetentionTimestamp: 3630, Timestamp: 3633, Value: 1},
+			{RetentionTimestamp: 3640, Timestamp: 3643, Value: 2},
+			{RetentionTimestamp: 3650, Timestamp: 3653, Value: 3},
+			{RetentionTimestamp: 3660, Timestamp: 3663, Value: 4},
+		},
+	}
+	trigger := &moira.Trigger{
+		ID:         "SuperId",
+		ErrorValue: &errValue,
+		WarnValue:  &warnValue,
+		Targets:    []string{pattern},
+		Patterns:   []string{pattern},
+	}
+
+	Convey("Transitions and events are returned instead of being written", t, func() {
+		dataBase.EXPECT().GetPatternMetrics(pattern).Return([]string{metric}, nil)
+		dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
+		dataBase.EXPECT().GetMetricsValues([]string{metric}, from, until).Return(dataList, nil)
+
+		result, err := Backtest(dataBase, logger, trigger, from, until)
+		So(err, ShouldBeNil)
+		values := []float64{0, 1, 3}
+		So(result.Transitions, ShouldResemble, map[string][]moira.MetricState{
+			metric: {
+				{State: OK, Timestamp: 3617, EventTimestamp: 3617, Value: &values[0]},
+				{State: WARN, Timestamp: 3627, EventTimestamp: 3627, Value: &values[1]},
+				{State: ERROR, Timestamp: 3647, EventTimestamp: 3647, Value: &values[2]},
+			},
+		})
+		So(result.Events, ShouldResemble, []moira.NotificationEvent{
+			{TriggerID: "SuperId", Metric: metric, State: OK, OldState: NODATA, Timestamp: 3617, Value: &values[0]},
+			{TriggerID: "SuperId", Metric: metric, State: WARN, OldState: OK, Timestamp: 3627, Value: &values[1]},
+			{TriggerID: "SuperId", Metric: metric, State: ERROR, OldState: WARN, Timestamp: 3647, Value: &values[2]},
+		})
+	})
+
+	Convey("Aggregate trigger can not be backtested", t, func() {
+		result, err := Backtest(dataBase, logger, &moira.Trigger{Aggregate: &moira.AggregateSettings{}}, from, until)
+		So(err, ShouldNotBeNil)
+		So(result, ShouldBeNil)
+	})
+}
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"math"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/expression"
+	"github.com/moira-alert/moira/target"
+)
+
+// minBaselineValues is minimal number of history values baseline can be computed from
+const minBaselineValues = 2
+
+// madScale makes median absolute deviation comparable with standard deviation of normally distributed values
+const madScale = 1.4826
+
+// getBaselineTimeSeries evaluates main target over previous baseline periods. Result is indexed by timeseries name,
+// i-th element of timeseries history is shifted by i+1 periods back and is nil if timeseries was absent then
+func (triggerChecker *TriggerChecker) getBaselineTimeSeries(from, until int64) (map[string][]*target.TimeSeries, error) {
+	settings := triggerChecker.trigger.Baseline
+	isSimpleTrigger := triggerChecker.trigger.IsSimple()
+	history := make(map[string][]*target.TimeSeries)
+	for period := int64(1); period <= settings.Periods; period++ {
+		shift := period * settings.Period
+		result, err := triggerChecker.evaluateTarget(triggerChecker.trigger.Targets[0], from-shift, until-shift, isSimpleTrigger)
+		if err != nil {
+			return nil, err
+		}
+		for _, timeSeries := range result.TimeSeries {
+			if _, ok := history[timeSeries.Name]; !ok {
+				history[timeSeries.Name] = make([]*target.TimeSeries, settings.Periods)
+			}
+			history[timeSeries.Name][period-1] = timeSeries
+		}
+	}
+	return history, nil
+}
+
+// getBaseline computes baseline of value at given timestamp from values of the same timeseries taken whole periods ago.
+// Returns false if there are not enough history values
+func getBaseline(settings *moira.BaselineSettings, history []*target.TimeSeries, valueTimestamp int64, value float64) (*expression.Baseline, bool) {
+	values := make([]float64, 0, len(history))
+	for i, timeSeries := range history {
+		if timeSeries == nil {
+			continue
+		}
+		historyValue := timeSeries.GetTimestampValue(valueTimestamp - int64(i+1)*settings.Period)
+		if !IsInvalidValue(historyValue) {
+			values = append(values, historyValue)
+		}
+	}
+	if len(values) < minBaselineValues {
+		return nil, false
+	}
+
+	var center, deviation float64
+	if settings.Method == moira.BaselineMedianMAD {
+		center, deviation = getMedianMAD(values)
+	} else {
+		center, deviation = getMeanStdDev(values)
+	}
+	return &expression.Baseline{Value: center, Deviation: deviation, ZScore: getZScore(value, center, deviation)}, true
+}
+
+func getMeanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
+
+func getMedianMAD(values []float64) (float64, float64) {
+	median := getMedian(values)
+	deviations := make([]float64, 0, len(values))
+	for _, value := range values {
+		deviations = append(deviations, math.Abs(value-median))
+	}
+	return median, getMedian(deviations) * madScale
+}
+
+func getMedian(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// getZScore returns number of deviations value is away from center. Any difference is infinitely large if history has no deviation
+func getZScore(value, center, deviation float64) float64 {
+	if deviation == 0 {
+		switch {
+		case value > center:
+			return math.Inf(1)
+		case value < center:
+			return math.Inf(-1)
+		}
+		return 0
+	}
+	return (value - center) / deviation
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-graphite/carbonapi/expr"
+	pb "github.com/go-graphite/carbonzipper/carbonzipperpb3"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/expression"
+	"github.com/moira-alert/moira/target"
+)
+
+func TestGetBaseline(t *testing.T) {
+	var period int64 = 86400
+	newHistoryTimeSeries := func(periods int64, values ...float64) *target.TimeSeries {
+		return &target.TimeSeries{
+			MetricData: expr.MetricData{FetchResponse: pb.FetchResponse{
+				Name:      "metric",
+				StartTime: int32(1000 - periods*period),
+				StopTime:  int32(1000 - periods*period + int64(len(values))*60),
+				StepTime:  60,
+				Values:    values,
+				IsAbsent:  make([]bool, len(values)),
+			}},
+		}
+	}
+	history := []*target.TimeSeries{
+		newHistoryTimeSeries(1, 10),
+		newHistoryTimeSeries(2, 12),
+		nil,
+		newHistoryTimeSeries(4, 14),
+		newHistoryTimeSeries(5, math.NaN()),
+	}
+
+	Convey("Mean and standard deviation", t, func() {
+		baseline, ok := getBaseline(&moira.BaselineSettings{Method: moira.BaselineMeanStdDev, Period: period, Periods: 5}, history, 1000, 18)
+		So(ok, ShouldBeTrue)
+		So(baseline, ShouldResemble, &expression.Baseline{Value: 12, Deviation: 2, ZScore: 3})
+	})
+
+	Convey("Median and median absolute deviation", t, func() {
+		baseline, ok := getBaseline(&moira.BaselineSettings{Method: moira.BaselineMedianMAD, Period: period, Periods: 5}, history, 1000, 12)
+		So(ok, ShouldBeTrue)
+		So(baseline, ShouldResemble, &expression.Baseline{Value: 12, Deviation: 2 * madScale, ZScore: 0})
+	})
+
+	Convey("Not enough history values", t, func() {
+		_, ok := getBaseline(&moira.BaselineSettings{Period: period, Periods: 5}, history[2:], 1000, 12)
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("Any deviation from constant history is infinite", t, func() {
+		constantHistory := []*target.TimeSeries{newHistoryTimeSeries(1, 5), newHistoryTimeSeries(2, 5)}
+		baseline, ok := getBaseline(&moira.BaselineSettings{Period: period, Periods: 2}, constantHistory, 1000, 6)
+		So(ok, ShouldBeTrue)
+		So(math.IsInf(baseline.ZScore, 1), ShouldBeTrue)
+		baseline, _ = getBaseline(&moira.BaselineSettings{Period: period, Periods: 2}, constantHistory, 1000, 5)
+		So(baseline.ZScore, ShouldEqual, 0)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/expression"
+	"github.com/moira-alert/moira/remote"
 	"github.com/moira-alert/moira/target"
 )
 
@@ -36,7 +38,16 @@ func (err ErrTriggerHasSameTimeSeriesNames) Error() string {
 	return fmt.Sprintf("Trigger has same timeseries names: %s", strings.Join(err.names, ", "))
 }
 
+// ErrRemoteTriggersDisabled used if remote trigger is checked while remote graphite is not configured
+type ErrRemoteTriggersDisabled struct{}
+
+// ErrRemoteTriggersDisabled implementation with constant error message
+func (err ErrRemoteTriggersDisabled) Error() string {
+	return fmt.Sprintf("Remote graphite is not configured, remote triggers can not be checked")
+}
+
 // Check handle trigger and last check and write new state of trigger, if state were change then write new NotificationEvent
+// and save trigger state snapshot to trigger history
 func (triggerChecker *TriggerChecker) Check() error {
 	triggerChecker.Logger.Debugf("Checking trigger %s", triggerChecker.TriggerID)
 	checkData, err := triggerChecker.handleTrigger()
@@ -47,6 +58,13 @@ func (triggerChecker *TriggerChecker) Check() error {
 		}
 	}
 	checkData.UpdateScore()
+	triggerChecker.stateChanged = checkData.State != triggerChecker.lastCheck.State || checkData.Score != triggerChecker.lastCheck.Score
+	if triggerChecker.lastCheck.GetStateSnapshot().IsStateChanged(&checkData) {
+		// Trigger history is informational, so failing to save it must not prevent saving the check itself
+		if err := triggerChecker.Database.AddTriggerStateSnapshot(triggerChecker.TriggerID, checkData.GetStateSnapshot()); err != nil {
+			triggerChecker.Logger.Errorf("Trigger %s: failed to save state snapshot: %s", triggerChecker.TriggerID, err.Error())
+		}
+	}
 	return triggerChecker.Database.SetTriggerLastCheck(triggerChecker.TriggerID, &checkData)
 }
 
@@ -62,6 +80,9 @@ func (triggerChecker *TriggerChecker) handleTrigger() (moira.CheckData, error) {
 		EventTimestamp: triggerChecker.lastCheck.EventTimestamp,
 		Score:          triggerChecker.lastCheck.Score,
 	}
+	if triggerChecker.trigger.Aggregate != nil {
+		return triggerChecker.handleAggregateTrigger(checkData)
+	}
 
 	triggerTimeSeries, metrics, err := triggerChecker.getTimeSeries(triggerChecker.From, triggerChecker.Until)
 	if err != nil {
@@ -158,7 +179,11 @@ func (triggerChecker *TriggerChecker) handleErrorCheck(checkData moira.CheckData
 		triggerChecker.Logger.Warningf("Trigger %s: %s", triggerChecker.TriggerID, checkingError.Error())
 		checkData.State = EXCEPTION
 		checkData.Message = checkingError.Error()
-	case ErrWrongTriggerTarget, ErrTriggerHasSameTimeSeriesNames:
+	case remote.ErrRemoteUnavailable:
+		triggerChecker.Logger.Warningf("Trigger %s: %s", triggerChecker.TriggerID, checkingError.Error())
+		checkData.State = EXCEPTION
+		checkData.Message = checkingError.Error()
+	case ErrWrongTriggerTarget, ErrTriggerHasSameTimeSeriesNames, ErrRemoteTriggersDisabled, expression.ErrInvalidExpression:
 		checkData.State = EXCEPTION
 		checkData.Message = checkingError.Error()
 	default:
@@ -208,6 +233,7 @@ func (triggerChecker *TriggerChecker) getTimeSeriesStepsStates(triggerTimeSeries
 		if metricNewState == nil {
 			continue
 		}
+		triggerChecker.applyHysteresis(metricNewState, metricLastState, stepTime)
 		metricLastState = *metricNewState
 		metricStates = append(metricStates, *metricNewState)
 	}
@@ -243,9 +269,18 @@ func (triggerChecker *TriggerChecker) getTimeSeriesState(triggerTimeSeries *trig
 	}, nil
 }
 
+// cleanupMetricsValues removes old metrics values. Anomaly detection triggers keep values for whole baseline history,
+// so they are not removed by other triggers using the same metrics
 func (triggerChecker *TriggerChecker) cleanupMetricsValues(metrics []string, until int64) {
 	if len(metrics) > 0 {
-		if err := triggerChecker.Database.RemoveMetricsValues(metrics, until-triggerChecker.Config.MetricsTTLSeconds); err != nil {
+		metricsTTL := triggerChecker.Config.MetricsTTLSeconds
+		if triggerChecker.trigger.Baseline != nil {
+			metricsTTL += triggerChecker.trigger.Baseline.GetHistoryDuration()
+			if err := triggerChecker.Database.KeepMetricsValues(metrics, metricsTTL); err != nil {
+				triggerChecker.Logger.Error(err.Error())
+			}
+		}
+		if err := triggerChecker.Database.RemoveMetricsValues(metrics, until-metricsTTL); err != nil {
 			triggerChecker.Logger.Error(err.Error())
 		}
 	}
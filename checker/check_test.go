@@ -364,6 +364,7 @@ func TestCheckForNODATA(t *testing.T) {
 func TestCheckErrors(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
 	logger, _ := logging.GetLogger("Test")
 	defer mockCtrl.Finish()
 
@@ -417,11 +418,72 @@ func TestCheckErrors(t *testing.T) {
 		err := triggerChecker.Check()
 		So(err, ShouldBeNil)
 	})
+
+	Convey("State change is saved to trigger history", t, func() {
+		triggerChecker.lastCheck.State = OK
+		dataBase.EXPECT().GetPatternMetrics(pattern).Return([]string{metric}, nil)
+		dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
+		dataBase.EXPECT().GetMetricsValues([]string{metric}, triggerChecker.From, triggerChecker.Until).Return(nil, metricErr)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			IsTriggerEvent: true,
+			TriggerID:      triggerChecker.TriggerID,
+			State:          EXCEPTION,
+			OldState:       OK,
+			Timestamp:      triggerChecker.Until,
+			Message:        &[]string{""}[0],
+		}, true).Return(nil)
+		dataBase.EXPECT().AddTriggerStateSnapshot(triggerChecker.TriggerID, &moira.TriggerStateSnapshot{
+			Timestamp: triggerChecker.Until,
+			State:     EXCEPTION,
+			Score:     100000,
+			Metrics:   map[string]string{metric: OK},
+		}).Return(nil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &moira.CheckData{
+			Metrics:        triggerChecker.lastCheck.Metrics,
+			State:          EXCEPTION,
+			Timestamp:      triggerChecker.Until,
+			EventTimestamp: triggerChecker.Until,
+			Score:          100000,
+		}).Return(nil)
+		err := triggerChecker.Check()
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Failure to save trigger history should not prevent saving check", t, func() {
+		triggerChecker.lastCheck.State = OK
+		dataBase.EXPECT().GetPatternMetrics(pattern).Return([]string{metric}, nil)
+		dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
+		dataBase.EXPECT().GetMetricsValues([]string{metric}, triggerChecker.From, triggerChecker.Until).Return(nil, metricErr)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			IsTriggerEvent: true,
+			TriggerID:      triggerChecker.TriggerID,
+			State:          EXCEPTION,
+			OldState:       OK,
+			Timestamp:      triggerChecker.Until,
+			Message:        &[]string{""}[0],
+		}, true).Return(nil)
+		dataBase.EXPECT().AddTriggerStateSnapshot(triggerChecker.TriggerID, &moira.TriggerStateSnapshot{
+			Timestamp: triggerChecker.Until,
+			State:     EXCEPTION,
+			Score:     100000,
+			Metrics:   map[string]string{metric: OK},
+		}).Return(fmt.Errorf("Failed to save snapshot"))
+		dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &moira.CheckData{
+			Metrics:        triggerChecker.lastCheck.Metrics,
+			State:          EXCEPTION,
+			Timestamp:      triggerChecker.Until,
+			EventTimestamp: triggerChecker.Until,
+			Score:          100000,
+		}).Return(nil)
+		err := triggerChecker.Check()
+		So(err, ShouldBeNil)
+	})
 }
 
 func TestHandleTrigger(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
 	logger, _ := logging.GetLogger("Test")
 	logging.SetLevel(logging.INFO, "Test")
 	defer mockCtrl.Finish()
@@ -694,6 +756,7 @@ func TestHandleErrorCheck(t *testing.T) {
 	defer mockCtrl.Finish()
 	logger, _ := logging.GetLogger("Test")
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
 
 	Convey("Handle error no metrics", t, func() {
 		Convey("TTL is 0", func() {
@@ -731,7 +794,7 @@ func TestHandleErrorCheck(t *testing.T) {
 					State:     NODATA,
 				},
 			}
-			err1 := "This metric has been in bad state for more than 24 hours - please, fix."
+			err1 := "This metric has been in NODATA state for more than 24 hours - please, fix."
 			checkData := moira.CheckData{
 				State:     OK,
 				Timestamp: time.Now().Unix(),
@@ -1,6 +1,10 @@
 package checker
 
-import "time"
+import (
+	"time"
+
+	"github.com/moira-alert/moira/remote"
+)
 
 // Config represent checker config
 type Config struct {
@@ -12,4 +16,5 @@ type Config struct {
 	MaxParallelChecks           int
 	LogFile                     string
 	LogLevel                    string
+	Remote                      *remote.Config
 }
@@ -6,11 +6,25 @@ import (
 	"time"
 )
 
+// badStateReminder contains default remind intervals used if trigger has no own interval for state
 var badStateReminder = map[string]int64{
 	ERROR:  86400,
 	NODATA: 86400,
 }
 
+// remindableStates are states trigger remind intervals can be set for
+var remindableStates = map[string]bool{
+	WARN:      true,
+	ERROR:     true,
+	NODATA:    true,
+	EXCEPTION: true,
+}
+
+// IsRemindableState returns true if remind interval can be set for given state
+func IsRemindableState(state string) bool {
+	return remindableStates[state]
+}
+
 func (triggerChecker *TriggerChecker) compareChecks(currentCheck moira.CheckData) (moira.CheckData, error) {
 	currentStateValue := currentCheck.State
 	lastStateValue := triggerChecker.lastCheck.State
@@ -21,8 +35,13 @@ func (triggerChecker *TriggerChecker) compareChecks(currentCheck moira.CheckData
 	} else {
 		currentCheck.EventTimestamp = timestamp
 	}
+	if isAcknowledged(triggerChecker.lastCheck.Acknowledgement, currentStateValue) {
+		currentCheck.Acknowledgement = triggerChecker.lastCheck.Acknowledgement
+		return currentCheck, nil
+	}
 
-	needSend, message := needSendEvent(currentStateValue, lastStateValue, timestamp, triggerChecker.lastCheck.GetEventTimestamp(), triggerChecker.lastCheck.Suppressed)
+	remindInterval := triggerChecker.getRemindInterval(currentStateValue)
+	needSend, message := needSendEvent(currentStateValue, lastStateValue, timestamp, triggerChecker.lastCheck.GetEventTimestamp(), triggerChecker.lastCheck.Suppressed, remindInterval)
 	if !needSend {
 		return currentCheck, nil
 	}
@@ -46,8 +65,14 @@ func (triggerChecker *TriggerChecker) compareChecks(currentCheck moira.CheckData
 		currentCheck.Suppressed = true
 		return currentCheck, nil
 	}
-	triggerChecker.Logger.Infof("Writing new event: %v", event)
-	err := triggerChecker.Database.PushNotificationEvent(&event, true)
+	inhibited, err := triggerChecker.isTriggerInhibited(&event)
+	if inhibited {
+		currentCheck.Suppressed = true
+	}
+	if inhibited || err != nil {
+		return currentCheck, err
+	}
+	err = triggerChecker.pushEvent(&event)
 	return currentCheck, err
 }
 
@@ -57,8 +82,13 @@ func (triggerChecker *TriggerChecker) compareStates(metric string, currentState
 	} else {
 		currentState.EventTimestamp = currentState.Timestamp
 	}
+	if isAcknowledged(lastState.Acknowledgement, currentState.State) {
+		currentState.Acknowledgement = lastState.Acknowledgement
+		return currentState, nil
+	}
 
-	needSend, message := needSendEvent(currentState.State, lastState.State, currentState.Timestamp, lastState.GetEventTimestamp(), lastState.Suppressed)
+	remindInterval := triggerChecker.getRemindInterval(currentState.State)
+	needSend, message := needSendEvent(currentState.State, lastState.State, currentState.Timestamp, lastState.GetEventTimestamp(), lastState.Suppressed, remindInterval)
 	if !needSend {
 		return currentState, nil
 	}
@@ -80,11 +110,27 @@ func (triggerChecker *TriggerChecker) compareStates(metric string, currentState
 		currentState.Suppressed = true
 		return currentState, nil
 	}
-	triggerChecker.Logger.Infof("Writing new event: %v", event)
-	err := triggerChecker.Database.PushNotificationEvent(&event, true)
+	inhibited, err := triggerChecker.isTriggerInhibited(&event)
+	if inhibited {
+		currentState.Suppressed = true
+	}
+	if inhibited || err != nil {
+		return currentState, err
+	}
+	err = triggerChecker.pushEvent(&event)
 	return currentState, err
 }
 
+// pushEvent writes event to database, events of backtested trigger are collected to backtest result instead
+func (triggerChecker *TriggerChecker) pushEvent(event *moira.NotificationEvent) error {
+	if triggerChecker.backtest != nil {
+		triggerChecker.backtest.Events = append(triggerChecker.backtest.Events, *event)
+		return nil
+	}
+	triggerChecker.Logger.Infof("Writing new event: %v", event)
+	return triggerChecker.Database.PushNotificationEvent(event, true)
+}
+
 func (triggerChecker *TriggerChecker) isTriggerSuppressed(event *moira.NotificationEvent, timestamp int64, stateMaintenance int64, metric string) bool {
 	if !triggerChecker.trigger.Schedule.IsScheduleAllows(timestamp) {
 		triggerChecker.Logger.Debugf("Event %v suppressed due to trigger schedule", event)
@@ -97,13 +143,26 @@ func (triggerChecker *TriggerChecker) isTriggerSuppressed(event *moira.Notificat
 	return false
 }
 
-func needSendEvent(currentStateValue string, lastStateValue string, currentStateTimestamp int64, lastStateEventTimestamp int64, isLastStateSuppressed bool) (needSend bool, message *string) {
+// isAcknowledged returns true if acknowledgement was made for given state. Acknowledgement of other state is stale
+// and is dropped, so any state change re-arms notifications
+func isAcknowledged(ack *moira.Acknowledgement, state string) bool {
+	return ack != nil && ack.State == state
+}
+
+// getRemindInterval returns interval in seconds between reminders about metric staying in given state, zero means never remind
+func (triggerChecker *TriggerChecker) getRemindInterval(state string) int64 {
+	if interval, ok := triggerChecker.trigger.RemindIntervals[state]; ok {
+		return interval
+	}
+	return badStateReminder[state]
+}
+
+func needSendEvent(currentStateValue string, lastStateValue string, currentStateTimestamp int64, lastStateEventTimestamp int64, isLastStateSuppressed bool, remindInterval int64) (needSend bool, message *string) {
 	if currentStateValue != lastStateValue {
 		return true, nil
 	}
-	remindInterval, ok := badStateReminder[currentStateValue]
-	if ok && needRemindAgain(currentStateTimestamp, lastStateEventTimestamp, remindInterval) {
-		message := fmt.Sprintf("This metric has been in bad state for more than %v hours - please, fix.", remindInterval/3600)
+	if remindInterval > 0 && needRemindAgain(currentStateTimestamp, lastStateEventTimestamp, remindInterval) {
+		message := fmt.Sprintf("This metric has been in %s state for more than %s - please, fix.", currentStateValue, formatRemindInterval(remindInterval))
 		return true, &message
 	}
 	if !isLastStateSuppressed || currentStateValue == OK {
@@ -115,3 +174,22 @@ func needSendEvent(currentStateValue string, lastStateValue string, currentState
 func needRemindAgain(currentStateTimestamp, lastStateEventTimestamp, remindInterval int64) bool {
 	return currentStateTimestamp-lastStateEventTimestamp >= remindInterval
 }
+
+// formatRemindInterval formats interval in the largest whole units: hours, minutes or seconds
+func formatRemindInterval(interval int64) string {
+	switch {
+	case interval%3600 == 0:
+		return pluralize(interval/3600, "hour")
+	case interval%60 == 0:
+		return pluralize(interval/60, "minute")
+	default:
+		return pluralize(interval, "second")
+	}
+}
+
+func pluralize(count int64, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, unit)
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}
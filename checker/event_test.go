@@ -15,6 +15,7 @@ func TestCompareStates(t *testing.T) {
 	defer mockCtrl.Finish()
 	logger, _ := logging.GetLogger("Test")
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
 
 	triggerChecker := TriggerChecker{
 		TriggerID: "SuperId",
@@ -81,7 +82,7 @@ func TestCompareStates(t *testing.T) {
 			currentState.State = NODATA
 			currentState.Timestamp = 1502809200
 
-			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix.")
+			message := fmt.Sprintf("This metric has been in NODATA state for more than 24 hours - please, fix.")
 			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
 				TriggerID: triggerChecker.TriggerID,
 				Timestamp: currentState.Timestamp,
@@ -105,7 +106,7 @@ func TestCompareStates(t *testing.T) {
 			currentState.State = ERROR
 			currentState.Timestamp = 1502809200
 
-			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix.")
+			message := fmt.Sprintf("This metric has been in ERROR state for more than 24 hours - please, fix.")
 			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
 				TriggerID: triggerChecker.TriggerID,
 				Timestamp: currentState.Timestamp,
@@ -160,6 +161,46 @@ func TestCompareStates(t *testing.T) {
 		})
 	})
 
+	Convey("Trigger remind intervals", t, func() {
+		triggerChecker.trigger = &moira.Trigger{RemindIntervals: map[string]int64{WARN: 1800, ERROR: 0}}
+		defer func() { triggerChecker.trigger = &moira.Trigger{} }()
+
+		Convey("Status WARN and trigger remind interval, need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = WARN
+			currentState.State = WARN
+
+			message := "This metric has been in WARN state for more than 30 minutes - please, fix."
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: currentState.Timestamp,
+				State:     WARN,
+				OldState:  WARN,
+				Metric:    "m1",
+				Value:     currentState.Value,
+				Message:   &message,
+			}, true).Return(nil)
+			actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = currentState.Timestamp
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("Status ERROR and zero trigger remind interval, no need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = ERROR
+			currentState.State = ERROR
+			currentState.Timestamp = 1502809200
+
+			actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = lastState.EventTimestamp
+			So(actual, ShouldResemble, currentState)
+		})
+	})
+
 	Convey("Test different states", t, func() {
 		Convey("Trigger maintenance", func() {
 			lastState := lastStateExample
@@ -175,12 +216,53 @@ func TestCompareStates(t *testing.T) {
 			So(actual, ShouldResemble, currentState)
 		})
 	})
+
+	Convey("Acknowledged state", t, func() {
+		ack := &moira.Acknowledgement{User: "user", Comment: "on it", Timestamp: 1502712100, State: ERROR}
+
+		Convey("Reminder should not be sent while state is the same", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = ERROR
+			lastState.Acknowledgement = ack
+			currentState.State = ERROR
+			currentState.Timestamp = 1502809200
+
+			actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = lastState.EventTimestamp
+			currentState.Acknowledgement = ack
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("State change should send event and clear acknowledgement", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = ERROR
+			lastState.Acknowledgement = ack
+			currentState.State = OK
+
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: currentState.Timestamp,
+				State:     OK,
+				OldState:  ERROR,
+				Metric:    "m1",
+				Value:     currentState.Value,
+			}, true).Return(nil)
+			actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = currentState.Timestamp
+			So(actual, ShouldResemble, currentState)
+		})
+	})
 }
 func TestCompareChecks(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	logger, _ := logging.GetLogger("Test")
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	dataBase.EXPECT().GetInhibitingTriggerIDs().Return(nil, nil).AnyTimes()
 
 	triggerChecker := TriggerChecker{
 		TriggerID: "SuperId",
@@ -290,4 +372,30 @@ func TestCompareChecks(t *testing.T) {
 			So(actual, ShouldResemble, currentCheck)
 		})
 	})
+
+	Convey("Acknowledged trigger state should not be resent", t, func() {
+		ack := &moira.Acknowledgement{User: "user", Timestamp: 1502712100, State: EXCEPTION}
+		lastCheck := lastCheckExample
+		currentCheck := currentCheckExample
+		triggerChecker.lastCheck = &lastCheck
+		lastCheck.State = EXCEPTION
+		lastCheck.Suppressed = true
+		lastCheck.Acknowledgement = ack
+		currentCheck.State = EXCEPTION
+		actual, err := triggerChecker.compareChecks(currentCheck)
+
+		So(err, ShouldBeNil)
+		currentCheck.EventTimestamp = lastCheck.EventTimestamp
+		currentCheck.Acknowledgement = ack
+		So(actual, ShouldResemble, currentCheck)
+	})
+}
+
+func TestFormatRemindInterval(t *testing.T) {
+	Convey("Remind interval should be formatted in the largest whole units", t, func() {
+		So(formatRemindInterval(86400), ShouldEqual, "24 hours")
+		So(formatRemindInterval(3600), ShouldEqual, "1 hour")
+		So(formatRemindInterval(1800), ShouldEqual, "30 minutes")
+		So(formatRemindInterval(90), ShouldEqual, "90 seconds")
+	})
 }
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+)
+
+// applyHysteresis holds metric in its last state until new state is confirmed by trigger firing or recovery conditions.
+// Not confirmed state is remembered as pending state with timestamp of the first point it was observed at.
+// Points must be consecutive, so pending state is started again after gap in time series
+func (triggerChecker *TriggerChecker) applyHysteresis(newState *moira.MetricState, lastState moira.MetricState, stepTime int64) {
+	newState.PendingState = ""
+	newState.PendingSince = 0
+	if newState.State == lastState.State {
+		return
+	}
+
+	requiredPoints, requiredDuration := triggerChecker.getTransitionConditions(newState.State, lastState.State)
+	if requiredPoints <= 1 && requiredDuration <= 0 {
+		return
+	}
+
+	pendingSince := newState.Timestamp
+	isConsecutive := stepTime <= 0 || newState.Timestamp-lastState.Timestamp <= stepTime
+	if isConsecutive && isSamePendingState(lastState.PendingState, newState.State) && lastState.PendingSince != 0 && lastState.PendingSince <= newState.Timestamp {
+		pendingSince = lastState.PendingSince
+	}
+	var points int64 = 1
+	if stepTime > 0 {
+		points = (newState.Timestamp-pendingSince)/stepTime + 1
+	}
+	if points >= requiredPoints && newState.Timestamp-pendingSince >= requiredDuration {
+		return
+	}
+
+	newState.PendingState = newState.State
+	newState.PendingSince = pendingSince
+	newState.State = lastState.State
+}
+
+// getTransitionConditions returns number of consecutive points and duration in seconds required to move metric from last state to new one
+func (triggerChecker *TriggerChecker) getTransitionConditions(newState, lastState string) (points int64, duration int64) {
+	switch {
+	case isBadState(newState):
+		return triggerChecker.trigger.FiringPoints, triggerChecker.trigger.FiringDuration
+	case newState == OK && isBadState(lastState):
+		return triggerChecker.trigger.RecoveryPoints, triggerChecker.trigger.RecoveryDuration
+	}
+	return 0, 0
+}
+
+// isSamePendingState returns true if new state continues pending state. WARN and ERROR points are counted together,
+// so metric jumping between them still enters bad state
+func isSamePendingState(pendingState, newState string) bool {
+	return pendingState == newState || (isBadState(pendingState) && isBadState(newState))
+}
+
+func isBadState(state string) bool {
+	return state == WARN || state == ERROR
+}
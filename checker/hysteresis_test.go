@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestApplyHysteresis(t *testing.T) {
+	triggerChecker := TriggerChecker{trigger: &moira.Trigger{}}
+	var stepTime int64 = 60
+
+	Convey("Without transition conditions state should change on the first point", t, func() {
+		newState := moira.MetricState{State: ERROR, Timestamp: 120}
+		triggerChecker.applyHysteresis(&newState, moira.MetricState{State: OK, Timestamp: 60}, stepTime)
+		So(newState, ShouldResemble, moira.MetricState{State: ERROR, Timestamp: 120})
+	})
+
+	Convey("Firing points", t, func() {
+		triggerChecker.trigger = &moira.Trigger{FiringPoints: 3}
+		lastState := moira.MetricState{State: OK, Timestamp: 60}
+
+		Convey("Not enough points should keep last state", func() {
+			newState := moira.MetricState{State: WARN, Timestamp: 120}
+			triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+			So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 120, PendingState: WARN, PendingSince: 120})
+
+			lastState = newState
+			newState = moira.MetricState{State: ERROR, Timestamp: 180}
+			triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+			So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 180, PendingState: ERROR, PendingSince: 120})
+
+			Convey("Enough consecutive bad points should change state", func() {
+				lastState = newState
+				newState = moira.MetricState{State: ERROR, Timestamp: 240}
+				triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+				So(newState, ShouldResemble, moira.MetricState{State: ERROR, Timestamp: 240})
+			})
+
+			Convey("OK point should reset pending state", func() {
+				lastState = newState
+				newState = moira.MetricState{State: OK, Timestamp: 240}
+				triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+				So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 240})
+
+				lastState = newState
+				newState = moira.MetricState{State: ERROR, Timestamp: 300}
+				triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+				So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 300, PendingState: ERROR, PendingSince: 300})
+			})
+		})
+	})
+
+	Convey("Gap in time series should restart pending state", t, func() {
+		triggerChecker.trigger = &moira.Trigger{FiringPoints: 3}
+		lastState := moira.MetricState{State: OK, Timestamp: 180, PendingState: ERROR, PendingSince: 120}
+
+		newState := moira.MetricState{State: ERROR, Timestamp: 300}
+		triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+		So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 300, PendingState: ERROR, PendingSince: 300})
+	})
+
+	Convey("Firing duration", t, func() {
+		triggerChecker.trigger = &moira.Trigger{FiringDuration: 300}
+		lastState := moira.MetricState{State: OK, PendingState: ERROR, PendingSince: 60, Timestamp: 300}
+
+		newState := moira.MetricState{State: ERROR, Timestamp: 330}
+		triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+		So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 330, PendingState: ERROR, PendingSince: 60})
+
+		newState = moira.MetricState{State: ERROR, Timestamp: 360}
+		triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+		So(newState, ShouldResemble, moira.MetricState{State: ERROR, Timestamp: 360})
+	})
+
+	Convey("Recovery conditions", t, func() {
+		triggerChecker.trigger = &moira.Trigger{FiringPoints: 5, RecoveryPoints: 2}
+
+		Convey("Should be applied when returning to OK from bad state", func() {
+			newState := moira.MetricState{State: OK, Timestamp: 120}
+			triggerChecker.applyHysteresis(&newState, moira.MetricState{State: ERROR, Timestamp: 60}, stepTime)
+			So(newState, ShouldResemble, moira.MetricState{State: ERROR, Timestamp: 120, PendingState: OK, PendingSince: 120})
+
+			lastState := newState
+			newState = moira.MetricState{State: OK, Timestamp: 180}
+			triggerChecker.applyHysteresis(&newState, lastState, stepTime)
+			So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 180})
+		})
+
+		Convey("Should not be applied when leaving NODATA", func() {
+			newState := moira.MetricState{State: OK, Timestamp: 120}
+			triggerChecker.applyHysteresis(&newState, moira.MetricState{State: NODATA, Timestamp: 60}, stepTime)
+			So(newState, ShouldResemble, moira.MetricState{State: OK, Timestamp: 120})
+		})
+	})
+}
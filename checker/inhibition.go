@@ -0,0 +1,57 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// getInhibitingTriggerID returns ID of parent trigger which state inhibits checked trigger events,
+// empty string means trigger is not inhibited. Parent is looked up once per check
+func (triggerChecker *TriggerChecker) getInhibitingTriggerID() (string, error) {
+	if triggerChecker.inhibitedBy != nil {
+		return *triggerChecker.inhibitedBy, nil
+	}
+	inhibitingIDs, err := triggerChecker.Database.GetInhibitingTriggerIDs()
+	if err != nil || len(inhibitingIDs) == 0 {
+		triggerChecker.inhibitedBy = new(string)
+		return "", err
+	}
+	parents, err := triggerChecker.Database.GetTriggers(inhibitingIDs)
+	if err != nil {
+		return "", err
+	}
+	inhibitedBy := ""
+	for _, parent := range parents {
+		if parent == nil || parent.Inhibits == nil || parent.ID == triggerChecker.TriggerID {
+			continue
+		}
+		lastCheck, err := triggerChecker.Database.GetTriggerLastCheck(parent.ID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return "", err
+		}
+		if parent.Inhibits.IsInhibiting(triggerChecker.trigger, lastCheck.GetWorstState()) {
+			inhibitedBy = parent.ID
+			break
+		}
+	}
+	triggerChecker.inhibitedBy = &inhibitedBy
+	return inhibitedBy, nil
+}
+
+// isTriggerInhibited returns true if event is suppressed because of failing parent trigger.
+// State changes of inhibited trigger are saved to events history marked with parent trigger ID, reminders are dropped
+func (triggerChecker *TriggerChecker) isTriggerInhibited(event *moira.NotificationEvent) (bool, error) {
+	parentID, err := triggerChecker.getInhibitingTriggerID()
+	if err != nil || parentID == "" {
+		return false, err
+	}
+	triggerChecker.Logger.Debugf("Event %v suppressed due to parent trigger %s state", event, parentID)
+	if event.State == event.OldState {
+		return true, nil
+	}
+	event.InhibitedBy = parentID
+	return true, triggerChecker.pushEvent(event)
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetInhibitingTriggerID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	parents := []*moira.Trigger{
+		{ID: "child", Inhibits: &moira.InhibitionSettings{Tags: []string{"api"}, States: []string{ERROR}}},
+		{ID: "no-check", Inhibits: &moira.InhibitionSettings{Tags: []string{"api"}, States: []string{ERROR}}},
+		{ID: "other", Inhibits: &moira.InhibitionSettings{Tags: []string{"db"}, States: []string{ERROR}}},
+		{ID: "network", Inhibits: &moira.InhibitionSettings{Tags: []string{"api"}, States: []string{ERROR, NODATA}}},
+		nil,
+	}
+	parentIDs := []string{"child", "no-check", "other", "network", "removed"}
+
+	Convey("No inhibiting triggers", t, func() {
+		triggerChecker := TriggerChecker{TriggerID: "child", Database: dataBase, trigger: &moira.Trigger{ID: "child", Tags: []string{"api"}}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{}, nil)
+		parentID, err := triggerChecker.getInhibitingTriggerID()
+		So(err, ShouldBeNil)
+		So(parentID, ShouldBeEmpty)
+	})
+
+	Convey("Parent trigger is failing", t, func() {
+		triggerChecker := TriggerChecker{TriggerID: "child", Database: dataBase, trigger: &moira.Trigger{ID: "child", Tags: []string{"api"}}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return(parentIDs, nil)
+		dataBase.EXPECT().GetTriggers(parentIDs).Return(parents, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("no-check").Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().GetTriggerLastCheck("other").Return(moira.CheckData{State: ERROR}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("network").Return(moira.CheckData{State: OK, Metrics: map[string]moira.MetricState{"ping": {State: NODATA}}}, nil)
+		parentID, err := triggerChecker.getInhibitingTriggerID()
+		So(err, ShouldBeNil)
+		So(parentID, ShouldEqual, "network")
+
+		Convey("Parent is looked up once", func() {
+			parentID, err := triggerChecker.getInhibitingTriggerID()
+			So(err, ShouldBeNil)
+			So(parentID, ShouldEqual, "network")
+		})
+	})
+
+	Convey("Parent trigger is not in inhibiting state", t, func() {
+		triggerChecker := TriggerChecker{TriggerID: "child", Database: dataBase, trigger: &moira.Trigger{ID: "child", Tags: []string{"api"}}}
+		dataBase.EXPECT().GetInhibitingTriggerIDs().Return([]string{"network"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"network"}).Return([]*moira.Trigger{parents[3]}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("network").Return(moira.CheckData{State: WARN}, nil)
+		parentID, err := triggerChecker.getInhibitingTriggerID()
+		So(err, ShouldBeNil)
+		So(parentID, ShouldBeEmpty)
+	})
+}
+
+func TestCompareStatesInhibited(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	parentID := "network"
+	triggerChecker := TriggerChecker{
+		TriggerID:   "child",
+		Database:    dataBase,
+		Logger:      logger,
+		trigger:     &moira.Trigger{ID: "child", RemindIntervals: map[string]int64{ERROR: 600}},
+		inhibitedBy: &parentID,
+	}
+	lastState := moira.MetricState{State: OK, Timestamp: 1502712000, EventTimestamp: 1502708400}
+
+	Convey("State change is saved marked with parent trigger", t, func() {
+		currentState := moira.MetricState{State: ERROR, Timestamp: 1502719200}
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID:   "child",
+			State:       ERROR,
+			OldState:    OK,
+			Timestamp:   1502719200,
+			Metric:      "m1",
+			InhibitedBy: parentID,
+		}, true).Return(nil)
+		actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+		So(err, ShouldBeNil)
+		So(actual.Suppressed, ShouldBeTrue)
+		So(actual.EventTimestamp, ShouldEqual, 1502719200)
+	})
+
+	Convey("Reminder is dropped", t, func() {
+		lastState := moira.MetricState{State: ERROR, Timestamp: 1502712000, EventTimestamp: 1502708400}
+		currentState := moira.MetricState{State: ERROR, Timestamp: 1502719200}
+		actual, err := triggerChecker.compareStates("m1", currentState, lastState)
+		So(err, ShouldBeNil)
+		So(actual.Suppressed, ShouldBeTrue)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/expression"
 	"github.com/moira-alert/moira/target"
 )
@@ -11,6 +12,10 @@ import (
 type triggerTimeSeries struct {
 	Main       []*target.TimeSeries
 	Additional []*target.TimeSeries
+
+	// Baseline contains history of main target timeseries used by anomaly detection triggers
+	Baseline         map[string][]*target.TimeSeries
+	BaselineSettings *moira.BaselineSettings
 }
 
 // ErrWrongTriggerTarget represents inconsistent number of timeseries
@@ -30,7 +35,7 @@ func (triggerChecker *TriggerChecker) getTimeSeries(from, until int64) (*trigger
 
 	isSimpleTrigger := triggerChecker.trigger.IsSimple()
 	for targetIndex, tar := range triggerChecker.trigger.Targets {
-		result, err := target.EvaluateTarget(triggerChecker.Database, tar, from, until, isSimpleTrigger)
+		result, err := triggerChecker.evaluateTarget(tar, from, until, isSimpleTrigger)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -54,9 +59,29 @@ func (triggerChecker *TriggerChecker) getTimeSeries(from, until int64) (*trigger
 		}
 		metricsArr = append(metricsArr, result.Metrics...)
 	}
+
+	if triggerChecker.trigger.Baseline != nil {
+		history, err := triggerChecker.getBaselineTimeSeries(from, until)
+		if err != nil {
+			return nil, nil, err
+		}
+		triggerTimeSeries.Baseline = history
+		triggerTimeSeries.BaselineSettings = triggerChecker.trigger.Baseline
+	}
 	return triggerTimeSeries, metricsArr, nil
 }
 
+// evaluateTarget evaluates target using metrics stored in DB or using remote graphite for remote triggers
+func (triggerChecker *TriggerChecker) evaluateTarget(tar string, from, until int64, allowRealTimeAlerting bool) (*target.EvaluationResult, error) {
+	if !triggerChecker.trigger.IsRemote {
+		return target.EvaluateTarget(triggerChecker.Database, tar, from, until, allowRealTimeAlerting)
+	}
+	if !triggerChecker.Config.Remote.IsEnabled() {
+		return nil, ErrRemoteTriggersDisabled{}
+	}
+	return target.EvaluateRemoteTarget(triggerChecker.Config.Remote, tar, from, until)
+}
+
 func (*triggerTimeSeries) getMainTargetName() string {
 	return "t1"
 }
@@ -74,6 +99,14 @@ func (triggerTimeSeries *triggerTimeSeries) getExpressionValues(firstTargetTimeS
 		return expressionValues, false
 	}
 	expressionValues.MainTargetValue = firstTargetValue
+	if triggerTimeSeries.BaselineSettings != nil {
+		baseline, ok := getBaseline(triggerTimeSeries.BaselineSettings, triggerTimeSeries.Baseline[firstTargetTimeSeries.Name], valueTimestamp, firstTargetValue)
+		if !ok {
+			// history is still warming up, so current value is treated as expected one instead of turning metric into NODATA
+			baseline = &expression.Baseline{Value: firstTargetValue}
+		}
+		expressionValues.Baseline = baseline
+	}
 
 	for targetNumber := 0; targetNumber < len(triggerTimeSeries.Additional); targetNumber++ {
 		additionalTimeSeries := triggerTimeSeries.Additional[targetNumber]
@@ -3,7 +3,10 @@ package checker
 import (
 	"fmt"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-graphite/carbonapi/expr"
 	pb "github.com/go-graphite/carbonzipper/carbonzipperpb3"
@@ -11,6 +14,7 @@ import (
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/expression"
 	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/remote"
 	"github.com/moira-alert/moira/target"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -303,6 +307,37 @@ func TestGetExpressionValues(t *testing.T) {
 		So(noEmptyValues, ShouldBeTrue)
 		So(values, ShouldResemble, expectedExpressionValues)
 	})
+
+	Convey("Anomaly detection trigger without enough history", t, func() {
+		fetchResponse := pb.FetchResponse{
+			Name:      "main",
+			StartTime: int32(17),
+			StopTime:  int32(37),
+			StepTime:  int32(10),
+			Values:    []float64{5.0, 7.0},
+			IsAbsent:  []bool{false, false},
+		}
+		timeSeries := target.TimeSeries{
+			MetricData: expr.MetricData{FetchResponse: fetchResponse},
+		}
+		tts := &triggerTimeSeries{
+			Main:             []*target.TimeSeries{&timeSeries},
+			Additional:       make([]*target.TimeSeries, 0),
+			Baseline:         map[string][]*target.TimeSeries{"main": {nil, nil}},
+			BaselineSettings: &moira.BaselineSettings{Period: 86400, Periods: 2},
+		}
+
+		values, noEmptyValues := tts.getExpressionValues(&timeSeries, 27)
+		So(noEmptyValues, ShouldBeTrue)
+		So(values.MainTargetValue, ShouldEqual, 7)
+		So(values.Baseline, ShouldResemble, &expression.Baseline{Value: 7})
+
+		warnValue, errorValue := 2.0, 3.0
+		values.WarnValue, values.ErrorValue = &warnValue, &errorValue
+		state, err := values.Evaluate()
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, OK)
+	})
 }
 
 func TestTriggerTimeSeriesHasOnlyWildcards(t *testing.T) {
@@ -343,3 +378,43 @@ func TestTriggerTimeSeriesHasOnlyWildcards(t *testing.T) {
 		So(tts.hasOnlyWildcards(), ShouldBeFalse)
 	})
 }
+
+func TestGetRemoteTimeSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(`[{"target": "remote.metric", "datapoints": [[1, 20], [null, 30], [3, 40]]}]`))
+	}))
+	defer server.Close()
+
+	triggerChecker := &TriggerChecker{
+		Config: &Config{
+			Remote: &remote.Config{Enabled: true, URL: server.URL, Timeout: time.Second},
+		},
+		trigger: &moira.Trigger{
+			Targets:  []string{"remote.metric"},
+			IsRemote: true,
+		},
+	}
+
+	Convey("Remote trigger timeseries are fetched from graphite", t, func() {
+		actual, metrics, err := triggerChecker.getTimeSeries(17, 67)
+		So(err, ShouldBeNil)
+		So(metrics, ShouldBeEmpty)
+		So(actual.Main, ShouldHaveLength, 1)
+		So(actual.Main[0].Name, ShouldEqual, "remote.metric")
+		So(actual.Main[0].StartTime, ShouldEqual, 20)
+		So(actual.Main[0].StopTime, ShouldEqual, 50)
+		So(actual.Main[0].StepTime, ShouldEqual, 10)
+		So(actual.Main[0].Wildcard, ShouldBeFalse)
+		So(actual.Main[0].GetTimestampValue(20), ShouldEqual, 1)
+		So(math.IsNaN(actual.Main[0].GetTimestampValue(30)), ShouldBeTrue)
+		So(actual.Main[0].GetTimestampValue(40), ShouldEqual, 3)
+	})
+
+	Convey("Remote trigger is not checked if remote is disabled", t, func() {
+		triggerChecker.Config.Remote.Enabled = false
+		actual, metrics, err := triggerChecker.getTimeSeries(17, 67)
+		So(err, ShouldResemble, ErrRemoteTriggersDisabled{})
+		So(actual, ShouldBeNil)
+		So(metrics, ShouldBeNil)
+	})
+}
@@ -24,6 +24,13 @@ type TriggerChecker struct {
 
 	ttl      int64
 	ttlState string
+
+	// stateChanged is set by Check if trigger state or score differs from last check one
+	stateChanged bool
+	// inhibitedBy caches ID of parent trigger inhibiting checked trigger events, see getInhibitingTriggerID
+	inhibitedBy *string
+	// backtest collects events of trigger checked by Backtest instead of writing them to database
+	backtest *BacktestResult
 }
 
 // ErrTriggerNotExists used if trigger to check does not exists
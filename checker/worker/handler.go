@@ -66,5 +66,13 @@ func (worker *Checker) checkTrigger(triggerID string) error {
 		}
 		return err
 	}
-	return triggerChecker.Check()
+	if err := triggerChecker.Check(); err != nil {
+		return err
+	}
+	dependentTriggerIDs, err := triggerChecker.GetDependentTriggerIDs()
+	if err != nil {
+		return err
+	}
+	worker.addTriggerIDsIfNeeded(dependentTriggerIDs)
+	return nil
 }
@@ -26,7 +26,7 @@ func (worker *Checker) checkNoData() error {
 		worker.Logger.Infof("Checking NODATA disabled. No metrics for %v seconds", now-worker.lastData)
 	} else {
 		worker.Logger.Info("Checking NODATA")
-		triggerIds, err := worker.Database.GetTriggerIDs()
+		triggerIds, err := worker.getLocalTriggerIDs()
 		if err != nil {
 			return err
 		}
@@ -34,3 +34,29 @@ func (worker *Checker) checkNoData() error {
 	}
 	return nil
 }
+
+// getLocalTriggerIDs returns all triggers except remote ones, which are checked by remote checker if it is enabled
+func (worker *Checker) getLocalTriggerIDs() ([]string, error) {
+	triggerIds, err := worker.Database.GetTriggerIDs()
+	if err != nil {
+		return nil, err
+	}
+	if !worker.Config.Remote.IsEnabled() {
+		return triggerIds, nil
+	}
+	remoteTriggerIds, err := worker.Database.GetRemoteTriggerIDs()
+	if err != nil {
+		return nil, err
+	}
+	remoteTriggers := make(map[string]bool, len(remoteTriggerIds))
+	for _, triggerID := range remoteTriggerIds {
+		remoteTriggers[triggerID] = true
+	}
+	localTriggerIds := make([]string, 0, len(triggerIds))
+	for _, triggerID := range triggerIds {
+		if !remoteTriggers[triggerID] {
+			localTriggerIds = append(localTriggerIds, triggerID)
+		}
+	}
+	return localTriggerIds, nil
+}
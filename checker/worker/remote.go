@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"time"
+)
+
+func (worker *Checker) remoteTriggersChecker() error {
+	checkTicker := time.NewTicker(worker.Config.Remote.CheckInterval)
+	for {
+		select {
+		case <-worker.tomb.Dying():
+			checkTicker.Stop()
+			worker.Logger.Info("Remote checker stopped")
+			return nil
+		case <-checkTicker.C:
+			if err := worker.checkRemote(); err != nil {
+				worker.Logger.Errorf("Remote triggers check failed: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (worker *Checker) checkRemote() error {
+	worker.Logger.Info("Checking remote triggers")
+	triggerIds, err := worker.Database.GetRemoteTriggerIDs()
+	if err != nil {
+		return err
+	}
+	worker.addTriggerIDsIfNeeded(triggerIds)
+	return nil
+}
@@ -25,11 +25,14 @@ type Checker struct {
 	triggersToCheck chan string
 }
 
-// Start start schedule new MetricEvents and check for NODATA triggers
+// Start start schedule new MetricEvents and check for NODATA triggers, remote triggers are scheduled on interval
 func (worker *Checker) Start() error {
 	if worker.Config.MaxParallelChecks == 0 {
 		return fmt.Errorf("MaxParallelChecks does not configure, checker does not started")
 	}
+	if worker.Config.Remote.IsEnabled() && worker.Config.Remote.CheckInterval <= 0 {
+		return fmt.Errorf("Remote CheckInterval does not configure, checker does not started")
+	}
 
 	worker.lastData = time.Now().UTC().Unix()
 	worker.triggersToCheck = make(chan string, 16384)
@@ -42,6 +45,11 @@ func (worker *Checker) Start() error {
 	worker.tomb.Go(worker.noDataChecker)
 	worker.Logger.Info("NODATA checker started")
 
+	if worker.Config.Remote.IsEnabled() {
+		worker.tomb.Go(worker.remoteTriggersChecker)
+		worker.Logger.Info("Remote checker started")
+	}
+
 	worker.Logger.Infof("Start %v parallel checkers", worker.Config.MaxParallelChecks)
 	for i := 0; i < worker.Config.MaxParallelChecks; i++ {
 		worker.tomb.Go(func() error { return worker.metricsChecker(metricEventsChannel) })
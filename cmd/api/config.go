@@ -16,12 +16,50 @@ type apiConfig struct {
 	Listen        string `yaml:"listen"`
 	EnableCORS    bool   `yaml:"enable_cors"`
 	WebConfigPath string `yaml:"web_config_path"`
+	// SlackVerificationToken enables endpoint for slack notification buttons, requests with other token are rejected
+	SlackVerificationToken string     `yaml:"slack_verification_token"`
+	Auth                   authConfig `yaml:"auth"`
+}
+
+type authConfig struct {
+	// Providers authenticate requests in given order, supported ones are header, jwt and token.
+	// Header provider trusts any request, so API must be reachable only through authenticating proxy
+	Providers []string  `yaml:"providers"`
+	Header    string    `yaml:"header"`
+	JWT       jwtConfig `yaml:"jwt"`
+	// AllowAnonymous lets requests without credentials in as anonymous user
+	AllowAnonymous bool `yaml:"allow_anonymous"`
+	// Admins are logins of users permitted to manage notifications, patterns and tags
+	Admins []string `yaml:"admins"`
+}
+
+type jwtConfig struct {
+	JWKSFile string   `yaml:"jwks_file"`
+	KeyFiles []string `yaml:"key_files"`
+	Issuer   string   `yaml:"issuer"`
+	Audience string   `yaml:"audience"`
+	// LoginClaim is claim with user login, sub by default
+	LoginClaim string `yaml:"login_claim"`
 }
 
 func (config *apiConfig) getSettings() *api.Config {
 	return &api.Config{
-		Listen:     config.Listen,
-		EnableCORS: config.EnableCORS,
+		Listen:                 config.Listen,
+		EnableCORS:             config.EnableCORS,
+		SlackVerificationToken: config.SlackVerificationToken,
+		Auth: api.AuthConfig{
+			Providers:      config.Auth.Providers,
+			Header:         config.Auth.Header,
+			AllowAnonymous: config.Auth.AllowAnonymous,
+			Admins:         config.Auth.Admins,
+			JWT: api.JWTConfig{
+				JWKSFile:   config.Auth.JWT.JWKSFile,
+				KeyFiles:   config.Auth.JWT.KeyFiles,
+				Issuer:     config.Auth.JWT.Issuer,
+				Audience:   config.Auth.JWT.Audience,
+				LoginClaim: config.Auth.JWT.LoginClaim,
+			},
+		},
 	}
 }
 
@@ -39,6 +77,11 @@ func getDefault() config {
 			Listen:        ":8081",
 			WebConfigPath: "/etc/moira/web.json",
 			EnableCORS:    false,
+			Auth: authConfig{
+				Providers:      []string{api.AuthProviderHeader},
+				Header:         "x-webauth-user",
+				AllowAnonymous: true,
+			},
 		},
 		Pprof: cmd.ProfilerConfig{
 			Listen: "",
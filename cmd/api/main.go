@@ -14,6 +14,7 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api/handler"
+	"github.com/moira-alert/moira/api/middleware"
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/logging/go-logging"
@@ -77,6 +78,11 @@ func main() {
 	databaseSettings := config.Redis.GetSettings()
 	database := redis.NewDatabase(logger, databaseSettings)
 
+	authenticator, err := middleware.NewAuthenticator(&apiConfig.Auth, database)
+	if err != nil {
+		logger.Fatalf("Can not configure authentication: %s", err.Error())
+	}
+
 	listener, err := net.Listen("tcp", apiConfig.Listen)
 	if err != nil {
 		logger.Fatal(err)
@@ -84,7 +90,7 @@ func main() {
 
 	logger.Infof("Start listening by address: [%s]", apiConfig.Listen)
 
-	httpHandler := handler.NewHandler(database, logger, apiConfig, configFile)
+	httpHandler := handler.NewHandler(database, logger, apiConfig, configFile, authenticator)
 	server := &http.Server{
 		Handler: httpHandler,
 	}
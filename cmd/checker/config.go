@@ -3,17 +3,19 @@ package main
 import (
 	"runtime"
 
+	"github.com/gosexy/to"
 	"github.com/moira-alert/moira/checker"
 	"github.com/moira-alert/moira/cmd"
-	"github.com/gosexy/to"
 )
 
 type config struct {
-	Redis    cmd.RedisConfig    `yaml:"redis"`
-	Graphite cmd.GraphiteConfig `yaml:"graphite"`
-	Logger   cmd.LoggerConfig   `yaml:"log"`
-	Checker  checkerConfig      `yaml:"checker"`
-	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
+	Redis      cmd.RedisConfig      `yaml:"redis"`
+	Graphite   cmd.GraphiteConfig   `yaml:"graphite"`
+	Prometheus cmd.PrometheusConfig `yaml:"prometheus"`
+	Logger     cmd.LoggerConfig     `yaml:"log"`
+	Checker    checkerConfig        `yaml:"checker"`
+	Remote     cmd.RemoteConfig     `yaml:"remote"`
+	Pprof      cmd.ProfilerConfig   `yaml:"pprof"`
 }
 
 type checkerConfig struct {
@@ -24,7 +26,7 @@ type checkerConfig struct {
 	MaxParallelChecks    int    `yaml:"max_parallel_checks"`
 }
 
-func (config *checkerConfig) getSettings() *checker.Config {
+func (config *checkerConfig) getSettings(remoteConfig cmd.RemoteConfig) *checker.Config {
 	if config.MaxParallelChecks == 0 {
 		config.MaxParallelChecks = runtime.NumCPU()
 	}
@@ -34,6 +36,7 @@ func (config *checkerConfig) getSettings() *checker.Config {
 		NoDataCheckInterval:         to.Duration(config.NoDataCheckInterval),
 		StopCheckingIntervalSeconds: int64(to.Duration(config.StopCheckingInterval).Seconds()),
 		MaxParallelChecks:           config.MaxParallelChecks,
+		Remote:                      remoteConfig.GetSettings(),
 	}
 }
 
@@ -54,11 +57,18 @@ func getDefault() config {
 			StopCheckingInterval: "30s",
 			MaxParallelChecks:    0,
 		},
+		Remote: cmd.RemoteConfig{
+			Timeout:       "60s",
+			CheckInterval: "60s",
+		},
 		Graphite: cmd.GraphiteConfig{
 			URI:      "localhost:2003",
 			Prefix:   "DevOps.Moira",
 			Interval: "60s",
 		},
+		Prometheus: cmd.PrometheusConfig{
+			Listen: ":8092",
+		},
 		Pprof: cmd.ProfilerConfig{
 			Listen: "",
 		},
@@ -18,6 +18,7 @@ import (
 	"github.com/moira-alert/moira/logging/go-logging"
 	"github.com/moira-alert/moira/metrics/graphite"
 	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/metrics/prometheus"
 )
 
 const serviceName = "checker"
@@ -74,12 +75,21 @@ func main() {
 	databaseSettings := config.Redis.GetSettings()
 	database := redis.NewDatabase(logger, databaseSettings)
 
-	checkerMetrics := metrics.ConfigureCheckerMetrics(serviceName)
-	if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
-		logger.Error(err)
+	var checkerMetrics *graphite.CheckerMetrics
+	if prometheusSettings := config.Prometheus.GetSettings(); prometheusSettings.Enabled {
+		registry := prometheus.NewRegistry()
+		checkerMetrics = prometheus.ConfigureCheckerMetrics(registry, serviceName, prometheusSettings.TriggerIDLabel)
+		if err = prometheus.Init(prometheusSettings, registry, logger); err != nil {
+			logger.Error(err)
+		}
+	} else {
+		checkerMetrics = metrics.ConfigureCheckerMetrics(serviceName)
+		if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
+			logger.Error(err)
+		}
 	}
 
-	checkerSettings := config.Checker.getSettings()
+	checkerSettings := config.Checker.getSettings(config.Remote)
 	if triggerID != nil && *triggerID != "" {
 		checkSingleTrigger(database, checkerMetrics, checkerSettings)
 	}
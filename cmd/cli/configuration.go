@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+// ExportConfig prints triggers having all given comma separated tags and subscriptions of user having them
+func ExportConfig(dataBase moira.Database, tags string, userLogin string, format string) error {
+	config, errorResponse := controller.ExportConfig(dataBase, splitTags(tags), userLogin)
+	if errorResponse != nil {
+		return responseError(errorResponse)
+	}
+	bytes, err := config.Marshal(format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bytes))
+	return nil
+}
+
+// PlanConfig prints changes needed to apply configuration file to triggers and subscriptions having all given tags
+func PlanConfig(dataBase moira.Database, fileName string, tags string, userLogin string, format string) error {
+	config, _, err := bindConfig(dataBase, fileName, format)
+	if err != nil {
+		return err
+	}
+	plan, errorResponse := controller.PlanConfig(dataBase, config, splitTags(tags), userLogin)
+	if errorResponse != nil {
+		return responseError(errorResponse)
+	}
+	return printPlan(plan)
+}
+
+// ApplyConfig prints plan of configuration file and applies it after confirmation. Triggers are validated
+// and their targets are evaluated like in API, so saved trigger metrics are cleaned up the same way
+func ApplyConfig(dataBase moira.Database, fileName string, tags string, userLogin string, format string) error {
+	config, timeSeriesNames, err := bindConfig(dataBase, fileName, format)
+	if err != nil {
+		return err
+	}
+	plan, errorResponse := controller.PlanConfig(dataBase, config, splitTags(tags), userLogin)
+	if errorResponse != nil {
+		return responseError(errorResponse)
+	}
+	if err := printPlan(plan); err != nil {
+		return err
+	}
+	if len(plan.Changes) == 0 {
+		fmt.Println("Nothing to apply")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Apply plan? [y/N]: ")
+	answer, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Println("Plan is not applied")
+		return nil
+	}
+	if _, errorResponse := controller.ApplyConfig(dataBase, config, splitTags(tags), plan.Checksum, timeSeriesNames, userLogin); errorResponse != nil {
+		return responseError(errorResponse)
+	}
+	fmt.Println(fmt.Sprintf("Applied %d changes", len(plan.Changes)))
+	return nil
+}
+
+// bindConfig reads configuration file and validates it like API does, returns time series names of triggers by trigger ID
+func bindConfig(dataBase moira.Database, fileName string, format string) (*dto.Config, map[string]map[string]bool, error) {
+	config, err := readConfig(fileName, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, "/api/config/apply", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	middleware.SetDatabase(request, dataBase)
+	timeSeriesNames, err := config.BindTriggers(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, timeSeriesNames, nil
+}
+
+func readConfig(fileName string, format string) (*dto.Config, error) {
+	bytes, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ParseConfig(bytes, format)
+}
+
+func printPlan(plan *dto.ConfigPlan) error {
+	bytes, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bytes))
+	return nil
+}
+
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+func responseError(errorResponse *api.ErrorResponse) error {
+	return fmt.Errorf("%s: %s", errorResponse.StatusText, errorResponse.ErrorText)
+}
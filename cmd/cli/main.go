@@ -20,6 +20,17 @@ var (
 	convertPythonExpression         = flag.String("convert-expression", "", "Convert python expression used in moira 1.x to govaluate expressions in moira 2.x for concrete trigger")
 	getTriggerWithPythonExpressions = flag.Bool("python-expressions-triggers", false, "Get count of triggers with python expression and count of triggers, that has python expression and has not govaluate expression")
 	removeBotInstanceLock           = flag.String("delete-bot-host-lock", "", "Delete bot host lock for launching bots with new distributed lock strategy. Must use for upgrade from Moira 1.x to 2.x")
+	printReport                     = flag.Bool("report", false, "Print availability report of triggers: time share of each state, incidents count and mean time to recover")
+	reportFrom                      = flag.String("report-from", "-30days", "Start of availability report time range")
+	reportTo                        = flag.String("report-to", "now", "End of availability report time range")
+	reportTags                      = flag.String("report-tags", "", "Comma separated tags, availability report includes triggers having all of them")
+	reportFormat                    = flag.String("report-format", "json", "Availability report format: json or csv")
+	exportConfig                    = flag.Bool("config-export", false, "Print configuration of triggers and subscriptions of config user, which can be kept in version control system")
+	planConfig                      = flag.String("config-plan", "", "Print changes needed to apply given configuration file")
+	applyConfig                     = flag.String("config-apply", "", "Print changes needed to apply given configuration file and apply them after confirmation")
+	configTags                      = flag.String("config-tags", "", "Comma separated tags, configuration includes triggers and subscriptions having all of them")
+	configUser                      = flag.String("config-user", "", "Login of user, whose subscriptions are managed by configuration and who is recorded as author of changes")
+	configFormat                    = flag.String("config-format", "yaml", "Configuration format: yaml or json")
 )
 
 // Moira version
@@ -81,6 +92,20 @@ func main() {
 		}
 	}
 
+	if *printReport {
+		if err := PrintReport(dataBase, *reportTags, *reportFrom, *reportTo, *reportFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build report: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	if *exportConfig || *planConfig != "" || *applyConfig != "" {
+		if err := manageConfig(dataBase); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to manage config: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	if *convertPythonExpression != "" {
 		if err := ConvertPythonExpression(dataBase, *convertPythonExpression); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to convert: %v", err)
@@ -89,6 +114,20 @@ func main() {
 	}
 }
 
+func manageConfig(dataBase moira.Database) error {
+	if *configUser == "" {
+		return fmt.Errorf("Config user must be set")
+	}
+	switch {
+	case *exportConfig:
+		return ExportConfig(dataBase, *configTags, *configUser, *configFormat)
+	case *planConfig != "":
+		return PlanConfig(dataBase, *planConfig, *configTags, *configUser, *configFormat)
+	default:
+		return ApplyConfig(dataBase, *applyConfig, *configTags, *configUser, *configFormat)
+	}
+}
+
 // RemoveBotInstanceLock - in Moira 2.0 we switch from host-based single instance telegram-bot run lock
 // to distributed lock, it allowed us to run moira in docker containers without fear that the bot will tied to the host name
 func RemoveBotInstanceLock(dataBase moira.Database, botName string) error {
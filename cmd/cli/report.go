@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/report"
+)
+
+// PrintReport prints availability report of triggers having all given comma separated tags in JSON or CSV format
+func PrintReport(dataBase moira.Database, tags string, fromStr string, toStr string, format string) error {
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return fmt.Errorf("Can not parse from: %s", fromStr)
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return fmt.Errorf("Can not parse to: %s", toStr)
+	}
+	var filterTags []string
+	if tags != "" {
+		filterTags = strings.Split(tags, ",")
+	}
+	availabilityReport, err := report.Build(dataBase, filterTags, int64(from), int64(to))
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "csv":
+		return availabilityReport.WriteCSV(os.Stdout)
+	case "json":
+		bytes, err := json.MarshalIndent(availabilityReport, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+		return nil
+	default:
+		return fmt.Errorf("Unknown report format: %s", format)
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/metrics/graphite"
+	"github.com/moira-alert/moira/metrics/prometheus"
+	"github.com/moira-alert/moira/remote"
 )
 
 // RedisConfig is redis config structure, which are taken on the start of moira
@@ -50,6 +52,45 @@ func (graphiteConfig *GraphiteConfig) GetSettings() graphite.Config {
 	}
 }
 
+// RemoteConfig is remote graphite render API config, which are taken on the start of moira
+type RemoteConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	URL           string `yaml:"url"`
+	User          string `yaml:"user"`
+	Password      string `yaml:"password"`
+	Timeout       string `yaml:"timeout"`
+	CheckInterval string `yaml:"check_interval"`
+}
+
+// GetSettings return remote graphite config parsed from moira config files
+func (remoteConfig *RemoteConfig) GetSettings() *remote.Config {
+	return &remote.Config{
+		Enabled:       remoteConfig.Enabled,
+		URL:           remoteConfig.URL,
+		User:          remoteConfig.User,
+		Password:      remoteConfig.Password,
+		Timeout:       to.Duration(remoteConfig.Timeout),
+		CheckInterval: to.Duration(remoteConfig.CheckInterval),
+	}
+}
+
+// PrometheusConfig is prometheus metrics endpoint config, which are taken on the start of moira.
+// If enabled it is used instead of graphite metrics
+type PrometheusConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Listen         string `yaml:"listen"`
+	TriggerIDLabel bool   `yaml:"trigger_id_label"`
+}
+
+// GetSettings return prometheus metrics config parsed from moira config files
+func (prometheusConfig *PrometheusConfig) GetSettings() prometheus.Config {
+	return prometheus.Config{
+		Enabled:        prometheusConfig.Enabled,
+		Listen:         prometheusConfig.Listen,
+		TriggerIDLabel: prometheusConfig.TriggerIDLabel,
+	}
+}
+
 // LoggerConfig is logger settings, which are taken on the start of moira
 type LoggerConfig struct {
 	LogFile  string `yaml:"log_file"`
@@ -2,19 +2,33 @@ package main
 
 import (
 	"github.com/moira-alert/moira/cmd"
+	"github.com/moira-alert/moira/filter/connection"
 )
 
 type config struct {
-	Redis    cmd.RedisConfig    `yaml:"redis"`
-	Graphite cmd.GraphiteConfig `yaml:"graphite"`
-	Logger   cmd.LoggerConfig   `yaml:"log"`
-	Filter   filterConfig       `yaml:"filter"`
-	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
+	Redis      cmd.RedisConfig      `yaml:"redis"`
+	Graphite   cmd.GraphiteConfig   `yaml:"graphite"`
+	Prometheus cmd.PrometheusConfig `yaml:"prometheus"`
+	Logger     cmd.LoggerConfig     `yaml:"log"`
+	Filter     filterConfig         `yaml:"filter"`
+	Pprof      cmd.ProfilerConfig   `yaml:"pprof"`
 }
 
 type filterConfig struct {
-	Listen          string `yaml:"listen"`
-	RetentionConfig string `yaml:"retention-config"`
+	Listen string `yaml:"listen"`
+	// Address to accept graphite pickle protocol on, empty value disables listener
+	PickleListen string `yaml:"pickle-listen"`
+	// Address to accept graphite plaintext protocol over UDP on, empty value disables listener
+	UDPListen             string            `yaml:"udp-listen"`
+	RetentionConfig       string            `yaml:"retention-config"`
+	PrometheusRemoteWrite remoteWriteConfig `yaml:"prometheus-remote-write"`
+}
+
+type remoteWriteConfig struct {
+	// Address to accept Prometheus remote write requests on, empty value disables listener
+	Listen string `yaml:"listen"`
+	// text/template used to build graphite metric name from sample labels
+	MetricNameTemplate string `yaml:"metric-name-template"`
 }
 
 func getDefault() config {
@@ -30,13 +44,22 @@ func getDefault() config {
 		},
 		Filter: filterConfig{
 			Listen:          ":2003",
+			PickleListen:    "",
+			UDPListen:       "",
 			RetentionConfig: "/etc/moira/storage-schemas.conf",
+			PrometheusRemoteWrite: remoteWriteConfig{
+				Listen:             "",
+				MetricNameTemplate: connection.DefaultRemoteWriteMetricNameTemplate,
+			},
 		},
 		Graphite: cmd.GraphiteConfig{
 			URI:      "localhost:2003",
 			Prefix:   "DevOps.Moira",
 			Interval: "60s",
 		},
+		Prometheus: cmd.PrometheusConfig{
+			Listen: ":8091",
+		},
 		Pprof: cmd.ProfilerConfig{
 			Listen: "",
 		},
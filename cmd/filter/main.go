@@ -16,7 +16,9 @@ import (
 	"github.com/moira-alert/moira/filter/matched_metrics"
 	"github.com/moira-alert/moira/filter/patterns"
 	"github.com/moira-alert/moira/logging/go-logging"
+	"github.com/moira-alert/moira/metrics/graphite"
 	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/metrics/prometheus"
 )
 
 const serviceName = "filter"
@@ -69,9 +71,18 @@ func main() {
 		cmd.StartProfiling(logger, config.Pprof)
 	}
 
-	cacheMetrics := metrics.ConfigureFilterMetrics(serviceName)
-	if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
-		logger.Error(err)
+	var cacheMetrics *graphite.FilterMetrics
+	if prometheusSettings := config.Prometheus.GetSettings(); prometheusSettings.Enabled {
+		registry := prometheus.NewRegistry()
+		cacheMetrics = prometheus.ConfigureFilterMetrics(registry, serviceName)
+		if err = prometheus.Init(prometheusSettings, registry, logger); err != nil {
+			logger.Error(err)
+		}
+	} else {
+		cacheMetrics = metrics.ConfigureFilterMetrics(serviceName)
+		if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
+			logger.Error(err)
+		}
 	}
 
 	database := redis.NewDatabase(logger, config.Redis.GetSettings())
@@ -106,18 +117,48 @@ func main() {
 	heartbeatWorker.Start()
 	defer stopHeartbeatWorker(heartbeatWorker)
 
-	// Start metrics listener
+	// Start metrics listeners
 	listener, err := connection.NewListener(config.Filter.Listen, logger, patternStorage)
 	if err != nil {
 		logger.Fatalf("Failed to start listen: %s", err.Error())
 	}
-	metricsChan := listener.Listen()
+	listeners := []metricsListener{listener}
+
+	if config.Filter.PickleListen != "" {
+		pickleListener, err := connection.NewPickleListener(config.Filter.PickleListen, logger, patternStorage)
+		if err != nil {
+			logger.Fatalf("Failed to start pickle listener: %s", err.Error())
+		}
+		listeners = append(listeners, pickleListener)
+	}
+
+	if config.Filter.UDPListen != "" {
+		udpListener, err := connection.NewUDPListener(config.Filter.UDPListen, logger, patternStorage)
+		if err != nil {
+			logger.Fatalf("Failed to start udp listener: %s", err.Error())
+		}
+		listeners = append(listeners, udpListener)
+	}
+
+	if config.Filter.PrometheusRemoteWrite.Listen != "" {
+		remoteWriteConfig := config.Filter.PrometheusRemoteWrite
+		remoteWriteListener, err := connection.NewRemoteWriteListener(remoteWriteConfig.Listen, remoteWriteConfig.MetricNameTemplate, logger, patternStorage)
+		if err != nil {
+			logger.Fatalf("Failed to start Prometheus remote write listener: %s", err.Error())
+		}
+		listeners = append(listeners, remoteWriteListener)
+	}
+
+	metricsChannels := make([]chan *moira.MatchedMetric, 0, len(listeners))
+	for _, listener := range listeners {
+		metricsChannels = append(metricsChannels, listener.Listen())
+	}
 
 	// Start metrics matcher
 	metricsMatcher := matchedmetrics.NewMetricsMatcher(cacheMetrics, logger, database, cacheStorage)
-	metricsMatcher.Start(metricsChan)
-	defer metricsMatcher.Wait()  // First stop listener
-	defer stopListener(listener) // Then waiting for metrics matcher handle all received events
+	metricsMatcher.Start(connection.MergeMetricsChannels(metricsChannels...))
+	defer metricsMatcher.Wait()    // First stop listeners
+	defer stopListeners(listeners) // Then waiting for metrics matcher handle all received events
 
 	logger.Infof("Moira Filter started. Version: %s", MoiraVersion)
 	ch := make(chan os.Signal, 1)
@@ -126,9 +167,17 @@ func main() {
 	logger.Infof("Moira Filter shutting down.")
 }
 
-func stopListener(listener *connection.MetricsListener) {
-	if err := listener.Stop(); err != nil {
-		logger.Errorf("Failed to stop listener: %v", err)
+// metricsListener is common interface of all filter listeners
+type metricsListener interface {
+	Listen() chan *moira.MatchedMetric
+	Stop() error
+}
+
+func stopListeners(listeners []metricsListener) {
+	for _, listener := range listeners {
+		if err := listener.Stop(); err != nil {
+			logger.Errorf("Failed to stop listener: %v", err)
+		}
 	}
 }
 
@@ -12,11 +12,12 @@ import (
 )
 
 type config struct {
-	Redis    cmd.RedisConfig    `yaml:"redis"`
-	Graphite cmd.GraphiteConfig `yaml:"graphite"`
-	Logger   cmd.LoggerConfig   `yaml:"log"`
-	Notifier notifierConfig     `yaml:"notifier"`
-	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
+	Redis      cmd.RedisConfig      `yaml:"redis"`
+	Graphite   cmd.GraphiteConfig   `yaml:"graphite"`
+	Prometheus cmd.PrometheusConfig `yaml:"prometheus"`
+	Logger     cmd.LoggerConfig     `yaml:"log"`
+	Notifier   notifierConfig       `yaml:"notifier"`
+	Pprof      cmd.ProfilerConfig   `yaml:"pprof"`
 }
 
 type notifierConfig struct {
@@ -49,6 +50,9 @@ func getDefault() config {
 			Prefix:   "DevOps.Moira",
 			Interval: "60s",
 		},
+		Prometheus: cmd.PrometheusConfig{
+			Listen: ":8093",
+		},
 		Logger: cmd.LoggerConfig{
 			LogFile:  "stdout",
 			LogLevel: "debug",
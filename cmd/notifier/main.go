@@ -11,7 +11,9 @@ import (
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/logging/go-logging"
+	"github.com/moira-alert/moira/metrics/graphite"
 	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/metrics/prometheus"
 	"github.com/moira-alert/moira/notifier"
 	"github.com/moira-alert/moira/notifier/events"
 	"github.com/moira-alert/moira/notifier/notifications"
@@ -68,9 +70,18 @@ func main() {
 		cmd.StartProfiling(logger, config.Pprof)
 	}
 
-	notifierMetrics := metrics.ConfigureNotifierMetrics(serviceName)
-	if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
-		logger.Error(err)
+	var notifierMetrics *graphite.NotifierMetrics
+	if prometheusSettings := config.Prometheus.GetSettings(); prometheusSettings.Enabled {
+		registry := prometheus.NewRegistry()
+		notifierMetrics = prometheus.ConfigureNotifierMetrics(registry, serviceName)
+		if err = prometheus.Init(prometheusSettings, registry, logger); err != nil {
+			logger.Error(err)
+		}
+	} else {
+		notifierMetrics = metrics.ConfigureNotifierMetrics(serviceName)
+		if err = metrics.Init(config.Graphite.GetSettings()); err != nil {
+			logger.Error(err)
+		}
 	}
 
 	databaseSettings := config.Redis.GetSettings()
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+)
+
+// auditMaxSize is size of audit log, the oldest records are removed when it is exceeded
+var auditMaxSize = 100000
+
+// AddAuditRecord appends configuration change record to audit log, which keeps 100000 latest records
+func (connector *DbConnector) AddAuditRecord(record *moira.AuditRecord) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	connector.auditSequence++
+	stored := *record
+	stored.ID = fmt.Sprintf("%d-%d", record.Timestamp*1000, connector.auditSequence)
+	bytes, err := json.Marshal(&stored)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal audit record: %s", err.Error())
+	}
+	connector.auditRecords = append(connector.auditRecords, bytes)
+	if len(connector.auditRecords) > auditMaxSize {
+		connector.auditRecords = connector.auditRecords[len(connector.auditRecords)-auditMaxSize:]
+	}
+	return nil
+}
+
+// GetAuditRecords gets audit records saved in given interval ordered by time
+func (connector *DbConnector) GetAuditRecords(from int64, to int64) ([]*moira.AuditRecord, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	records := make([]*moira.AuditRecord, 0)
+	for _, bytes := range connector.auditRecords {
+		record := &moira.AuditRecord{}
+		if err := json.Unmarshal(bytes, record); err != nil {
+			return nil, fmt.Errorf("Failed to parse audit record json %s: %s", string(bytes), err.Error())
+		}
+		if record.Timestamp >= from && record.Timestamp <= to {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
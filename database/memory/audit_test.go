@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestAuditRecords(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+	records := []*moira.AuditRecord{
+		{Timestamp: now - 200, User: user1, Entity: moira.AuditEntityTrigger, EntityID: "trigger", Action: moira.AuditActionCreate, After: json.RawMessage(`{"id":"trigger"}`)},
+		{Timestamp: now - 100, User: user2, Entity: moira.AuditEntityTrigger, EntityID: "trigger", Action: moira.AuditActionDelete, Before: json.RawMessage(`{"id":"trigger"}`)},
+	}
+
+	Convey("Audit records manipulation", t, func() {
+		dataBase.flush()
+		for _, record := range records {
+			err := dataBase.AddAuditRecord(record)
+			So(err, ShouldBeNil)
+		}
+
+		actual, err := dataBase.GetAuditRecords(now-300, now)
+		So(err, ShouldBeNil)
+		So(actual, ShouldHaveLength, 2)
+		So(actual[0].ID, ShouldNotBeEmpty)
+		So(actual[0].ID, ShouldNotEqual, actual[1].ID)
+		for i, record := range actual {
+			record.ID = ""
+			So(record, ShouldResemble, records[i])
+		}
+
+		actual, err = dataBase.GetAuditRecords(now-150, now)
+		So(err, ShouldBeNil)
+		So(actual, ShouldHaveLength, 1)
+		So(actual[0].User, ShouldEqual, user2)
+
+		Convey("The oldest records are removed", func() {
+			defer func(size int) { auditMaxSize = size }(auditMaxSize)
+			auditMaxSize = 2
+			err := dataBase.AddAuditRecord(&moira.AuditRecord{Timestamp: now, User: user1, Entity: moira.AuditEntityTag, EntityID: "tag", Action: moira.AuditActionDelete})
+			So(err, ShouldBeNil)
+
+			actual, err := dataBase.GetAuditRecords(now-300, now)
+			So(err, ShouldBeNil)
+			So(actual, ShouldHaveLength, 2)
+			So(actual[0].User, ShouldEqual, user2)
+			So(actual[1].Entity, ShouldEqual, moira.AuditEntityTag)
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/moira-alert/moira/database"
+)
+
+const (
+	botUsername = "moira-bot-host"
+)
+
+type botRegistration struct {
+	id         string
+	ttl        time.Duration
+	expiration time.Time
+}
+
+// GetIDByUsername read ID of user by messenger username
+func (connector *DbConnector) GetIDByUsername(messenger, username string) (string, error) {
+	if strings.HasPrefix(username, "#") {
+		result := "@" + username[1:]
+		return result, nil
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	if username == botUsername {
+		registration, ok := connector.getBotRegistration(messenger)
+		if !ok {
+			return "", database.ErrNil
+		}
+		return registration.id, nil
+	}
+	result, ok := connector.usernames[usernameKey(messenger, username)]
+	if !ok {
+		return "", database.ErrNil
+	}
+	return result, nil
+}
+
+// SetUsernameID store id of username
+func (connector *DbConnector) SetUsernameID(messenger, username, id string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.usernames[usernameKey(messenger, username)] = id
+	return nil
+}
+
+// RemoveUser removes username from messenger data
+func (connector *DbConnector) RemoveUser(messenger, username string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	delete(connector.usernames, usernameKey(messenger, username))
+	return nil
+}
+
+// RegisterBotIfAlreadyNot creates registration of bot instance with given ttl
+func (connector *DbConnector) RegisterBotIfAlreadyNot(messenger string, ttl time.Duration) bool {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	if _, ok := connector.getBotRegistration(messenger); ok {
+		return false
+	}
+	connector.bots[messenger] = &botRegistration{
+		id:         uuid.NewV4().String(),
+		ttl:        ttl,
+		expiration: time.Now().Add(ttl),
+	}
+	return true
+}
+
+// RenewBotRegistration extends bot registration for its ttl
+func (connector *DbConnector) RenewBotRegistration(messenger string) bool {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	registration, ok := connector.getBotRegistration(messenger)
+	if !ok {
+		return false
+	}
+	registration.expiration = time.Now().Add(registration.ttl)
+	return true
+}
+
+// DeregisterBots cancels registration for all registered messengers
+func (connector *DbConnector) DeregisterBots() {
+	connector.lock.Lock()
+	messengers := make([]string, 0, len(connector.bots))
+	for messenger := range connector.bots {
+		messengers = append(messengers, messenger)
+	}
+	connector.lock.Unlock()
+	for _, messenger := range messengers {
+		connector.DeregisterBot(messenger)
+	}
+}
+
+// DeregisterBot removes registration of bot instance
+func (connector *DbConnector) DeregisterBot(messenger string) bool {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	_, ok := connector.getBotRegistration(messenger)
+	delete(connector.bots, messenger)
+	return ok
+}
+
+func (connector *DbConnector) getBotRegistration(messenger string) (*botRegistration, bool) {
+	registration, ok := connector.bots[messenger]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(registration.expiration) {
+		delete(connector.bots, messenger)
+		return nil, false
+	}
+	return registration, true
+}
+
+func usernameKey(messenger, username string) string {
+	return fmt.Sprintf("%s:%s", messenger, username)
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetContact returns contact data by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetContact(id string) (moira.ContactData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getContact(id)
+}
+
+// GetContacts returns contacts data by given ids, len of contactIDs is equal to len of returned values array.
+// If there is no object by current ID, then nil is returned
+func (connector *DbConnector) GetContacts(contactIDs []string) ([]*moira.ContactData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getContacts(contactIDs)
+}
+
+// GetAllContacts returns full contact list
+func (connector *DbConnector) GetAllContacts() ([]*moira.ContactData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	contactIDs := make([]string, 0, len(connector.contacts))
+	for id := range connector.contacts {
+		contactIDs = append(contactIDs, id)
+	}
+	sort.Strings(contactIDs)
+	return connector.getContacts(contactIDs)
+}
+
+// SaveContact writes contact data and updates user and team contacts
+func (connector *DbConnector) SaveContact(contact *moira.ContactData) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, getContactErr := connector.getContact(contact.ID)
+	if getContactErr != nil && getContactErr != database.ErrNil {
+		return getContactErr
+	}
+	contactString, err := json.Marshal(contact)
+	if err != nil {
+		return err
+	}
+	connector.contacts[contact.ID] = contactString
+	if getContactErr != database.ErrNil && contact.User != existing.User {
+		removeFromSet(connector.userContacts, existing.User, contact.ID)
+	}
+	getSet(connector.userContacts, contact.User).add(contact.ID)
+	if getContactErr != database.ErrNil && existing.TeamID != "" && contact.TeamID != existing.TeamID {
+		removeFromSet(connector.teamContacts, existing.TeamID, contact.ID)
+	}
+	if contact.TeamID != "" {
+		getSet(connector.teamContacts, contact.TeamID).add(contact.ID)
+	}
+	return nil
+}
+
+// RemoveContact deletes contact data and contactID from user and team contacts
+func (connector *DbConnector) RemoveContact(contactID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, err := connector.getContact(contactID)
+	if err != nil && err != database.ErrNil {
+		return err
+	}
+	delete(connector.contacts, contactID)
+	removeFromSet(connector.userContacts, existing.User, contactID)
+	if existing.TeamID != "" {
+		removeFromSet(connector.teamContacts, existing.TeamID, contactID)
+	}
+	return nil
+}
+
+// GetUserContactIDs returns contacts ids by given login
+func (connector *DbConnector) GetUserContactIDs(login string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.userContacts, login), nil
+}
+
+func (connector *DbConnector) getContact(id string) (moira.ContactData, error) {
+	contact := moira.ContactData{}
+	bytes, ok := connector.contacts[id]
+	if !ok {
+		return contact, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &contact); err != nil {
+		return contact, fmt.Errorf("Failed to parse contact json %s: %s", string(bytes), err.Error())
+	}
+	contact.ID = id
+	return contact, nil
+}
+
+func (connector *DbConnector) getContacts(contactIDs []string) ([]*moira.ContactData, error) {
+	contacts := make([]*moira.ContactData, len(contactIDs))
+	for i, id := range contactIDs {
+		contact, err := connector.getContact(id)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		contacts[i] = &contact
+	}
+	return contacts, nil
+}
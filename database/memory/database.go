@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+const pubSubWorkerChannelSize = 16384
+
+const (
+	cacheCleanupInterval         = time.Minute * 60
+	cacheValueExpirationDuration = time.Minute
+)
+
+const (
+	fetchEventWaitDuration = time.Second
+	eventsUIListSize       = 100
+)
+
+// DbConnector implements moira.Database keeping all data in process memory. Moira services run as separate
+// processes sharing Redis, so it is not selectable in their configuration: it is a test double for tests
+// running the whole pipeline in one process without Redis, like notifier integration test
+type DbConnector struct {
+	lock           sync.Mutex
+	logger         moira.Logger
+	retentionCache *cache.Cache
+	metricsCache   *cache.Cache
+
+	metricsHeartbeat int64
+	checksCounter    int64
+
+	tags        stringSet
+	tagTriggers map[string]stringSet
+
+	triggersList           stringSet
+	remoteTriggersList     stringSet
+	aggregateTriggersList  stringSet
+	inhibitingTriggersList stringSet
+	triggers               map[string][]byte
+	triggerTags            map[string]stringSet
+	patternTriggers        map[string]stringSet
+
+	triggerTemplates map[string][]byte
+	templateTriggers map[string]stringSet
+
+	lastChecks       map[string][]byte
+	triggersChecks   *sortedSet
+	badStateTriggers stringSet
+
+	throttlingNext              map[string]int64
+	throttlingBeginning         map[string]int64
+	subscriptionsThrottlingNext map[string]map[string]int64
+
+	events         [][]byte
+	eventsUI       [][]byte
+	eventsNotify   chan struct{}
+	triggersEvents map[string]*sortedSet
+
+	triggersHistory  map[string]*sortedSet
+	triggerRevisions map[string][][]byte
+
+	auditRecords  [][]byte
+	auditSequence int64
+
+	contacts     map[string][]byte
+	userContacts map[string]stringSet
+
+	teams             map[string][]byte
+	userTeams         map[string]stringSet
+	teamTriggers      map[string]stringSet
+	teamContacts      map[string]stringSet
+	teamSubscriptions map[string]stringSet
+
+	userTokens      map[string][]byte
+	userTokensLists map[string]stringSet
+
+	subscriptions     map[string][]byte
+	userSubscriptions map[string]stringSet
+	tagSubscriptions  map[string]stringSet
+
+	notifications *sortedSet
+
+	patterns         stringSet
+	patternMetrics   map[string]stringSet
+	metricsData      map[string]*sortedSet
+	metricRetentions map[string]int64
+	metricsKeep      map[string]metricKeep
+
+	checkLocks map[string]time.Time
+
+	usernames map[string]string
+	bots      map[string]*botRegistration
+
+	subscribersLock   sync.Mutex
+	metricSubscribers map[chan *moira.MetricEvent]bool
+}
+
+// NewDatabase creates empty in-memory database
+func NewDatabase(logger moira.Logger) *DbConnector {
+	connector := &DbConnector{
+		logger:            logger,
+		retentionCache:    cache.New(cacheValueExpirationDuration, cacheCleanupInterval),
+		metricsCache:      cache.New(cacheValueExpirationDuration, cacheCleanupInterval),
+		eventsNotify:      make(chan struct{}, 1),
+		metricSubscribers: make(map[chan *moira.MetricEvent]bool),
+	}
+	connector.flush()
+	return connector
+}
+
+func (connector *DbConnector) unsubscribe(channel chan *moira.MetricEvent) {
+	connector.subscribersLock.Lock()
+	defer connector.subscribersLock.Unlock()
+	if _, ok := connector.metricSubscribers[channel]; ok {
+		delete(connector.metricSubscribers, channel)
+		close(channel)
+	}
+}
+
+func (connector *DbConnector) publish(event *moira.MetricEvent) {
+	connector.subscribersLock.Lock()
+	defer connector.subscribersLock.Unlock()
+	for channel := range connector.metricSubscribers {
+		select {
+		case channel <- event:
+		default:
+			connector.logger.Errorf("Metric events channel is full, drop event for metric %s", event.Metric)
+		}
+	}
+}
+
+func (connector *DbConnector) subscribe(tomb *tomb.Tomb) <-chan *moira.MetricEvent {
+	channel := make(chan *moira.MetricEvent, pubSubWorkerChannelSize)
+	connector.subscribersLock.Lock()
+	connector.metricSubscribers[channel] = true
+	connector.subscribersLock.Unlock()
+
+	go func() {
+		<-tomb.Dying()
+		connector.logger.Infof("Calling shutdown, unsubscribe from metric events...")
+		connector.unsubscribe(channel)
+	}()
+	return channel
+}
+
+// CLEAN DATABASE! USE IT ONLY FOR TESTING!!!
+func (connector *DbConnector) flush() {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	connector.retentionCache.Flush()
+	connector.metricsCache.Flush()
+
+	connector.metricsHeartbeat = 0
+	connector.checksCounter = 0
+
+	connector.tags = make(stringSet)
+	connector.tagTriggers = make(map[string]stringSet)
+
+	connector.triggersList = make(stringSet)
+	connector.remoteTriggersList = make(stringSet)
+	connector.aggregateTriggersList = make(stringSet)
+	connector.inhibitingTriggersList = make(stringSet)
+	connector.triggers = make(map[string][]byte)
+	connector.triggerTags = make(map[string]stringSet)
+	connector.patternTriggers = make(map[string]stringSet)
+
+	connector.triggerTemplates = make(map[string][]byte)
+	connector.templateTriggers = make(map[string]stringSet)
+
+	connector.lastChecks = make(map[string][]byte)
+	connector.triggersChecks = newSortedSet()
+	connector.badStateTriggers = make(stringSet)
+
+	connector.throttlingNext = make(map[string]int64)
+	connector.throttlingBeginning = make(map[string]int64)
+	connector.subscriptionsThrottlingNext = make(map[string]map[string]int64)
+
+	connector.events = make([][]byte, 0)
+	connector.eventsUI = make([][]byte, 0)
+	connector.triggersEvents = make(map[string]*sortedSet)
+	connector.triggersHistory = make(map[string]*sortedSet)
+	connector.triggerRevisions = make(map[string][][]byte)
+	connector.auditRecords = make([][]byte, 0)
+	connector.auditSequence = 0
+
+	connector.contacts = make(map[string][]byte)
+	connector.userContacts = make(map[string]stringSet)
+
+	connector.teams = make(map[string][]byte)
+	connector.userTeams = make(map[string]stringSet)
+	connector.teamTriggers = make(map[string]stringSet)
+	connector.teamContacts = make(map[string]stringSet)
+	connector.teamSubscriptions = make(map[string]stringSet)
+
+	connector.userTokens = make(map[string][]byte)
+	connector.userTokensLists = make(map[string]stringSet)
+
+	connector.subscriptions = make(map[string][]byte)
+	connector.userSubscriptions = make(map[string]stringSet)
+	connector.tagSubscriptions = make(map[string]stringSet)
+
+	connector.notifications = newSortedSet()
+
+	connector.patterns = make(stringSet)
+	connector.patternMetrics = make(map[string]stringSet)
+	connector.metricsData = make(map[string]*sortedSet)
+	connector.metricRetentions = make(map[string]int64)
+	connector.metricsKeep = make(map[string]metricKeep)
+
+	connector.checkLocks = make(map[string]time.Time)
+
+	connector.usernames = make(map[string]string)
+	connector.bots = make(map[string]*botRegistration)
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerLastCheck gets trigger last check data by given triggerID, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTriggerLastCheck(triggerID string) (moira.CheckData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getTriggerLastCheck(triggerID)
+}
+
+// SetTriggerLastCheck sets trigger last check data
+func (connector *DbConnector) SetTriggerLastCheck(triggerID string, checkData *moira.CheckData) error {
+	bytes, err := json.Marshal(checkData)
+	if err != nil {
+		return err
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	connector.lastChecks[triggerID] = bytes
+	connector.triggersChecks.add(checkData.Score, triggerID)
+	connector.checksCounter++
+	if checkData.Score > 0 {
+		connector.badStateTriggers.add(triggerID)
+	} else {
+		connector.badStateTriggers.remove(triggerID)
+	}
+	return nil
+}
+
+// RemoveTriggerLastCheck removes trigger last check data and trigger state history
+func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	delete(connector.lastChecks, triggerID)
+	connector.triggersChecks.remove(triggerID)
+	connector.badStateTriggers.remove(triggerID)
+	delete(connector.triggersHistory, triggerID)
+	return nil
+}
+
+// SetTriggerCheckMetricsMaintenance sets to given metrics throttling timestamps
+// If CheckData does not contain one of given metrics it will ignore this metric
+func (connector *DbConnector) SetTriggerCheckMetricsMaintenance(triggerID string, metrics map[string]int64) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	lastCheck, err := connector.getTriggerLastCheck(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	for metric, value := range metrics {
+		data, ok := lastCheck.Metrics[metric]
+		if !ok {
+			continue
+		}
+		data.Maintenance = value
+		lastCheck.Metrics[metric] = data
+	}
+	bytes, err := json.Marshal(lastCheck)
+	if err != nil {
+		return err
+	}
+	connector.lastChecks[triggerID] = bytes
+	return nil
+}
+
+// SetTriggerCheckAcknowledgement sets acknowledgement to given metrics or to whole trigger if metrics are empty,
+// nil acknowledgement removes existing ones
+func (connector *DbConnector) SetTriggerCheckAcknowledgement(triggerID string, metrics []string, ack *moira.Acknowledgement) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	lastCheck, err := connector.getTriggerLastCheck(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	lastCheck.Acknowledge(metrics, ack)
+	bytes, err := json.Marshal(lastCheck)
+	if err != nil {
+		return err
+	}
+	connector.lastChecks[triggerID] = bytes
+	return nil
+}
+
+// GetTriggerCheckIDs gets checked triggerIDs, sorted from max to min check score and filtered by given tags
+// If onlyErrors return only triggerIDs with score > 0
+func (connector *DbConnector) GetTriggerCheckIDs(tagNames []string, onlyErrors bool) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	filters := make([]stringSet, 0, len(tagNames)+1)
+	for _, tagName := range tagNames {
+		filters = append(filters, connector.tagTriggers[tagName])
+	}
+	if onlyErrors {
+		filters = append(filters, connector.badStateTriggers)
+	}
+
+	total := make([]string, 0)
+	for _, triggerID := range connector.triggersChecks.revRangeByIndex(0, -1) {
+		valid := true
+		for _, filter := range filters {
+			if _, ok := filter[triggerID]; !ok {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			total = append(total, triggerID)
+		}
+	}
+	return total, nil
+}
+
+func (connector *DbConnector) getTriggerLastCheck(triggerID string) (moira.CheckData, error) {
+	check := moira.CheckData{}
+	bytes, ok := connector.lastChecks[triggerID]
+	if !ok {
+		return check, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &check); err != nil {
+		return check, fmt.Errorf("Failed to parse lastCheck json %s: %s", string(bytes), err.Error())
+	}
+	return check, nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+)
+
+const checkLockTTL = time.Second * 30
+
+// AcquireTriggerCheckLock sets trigger lock by given id. If lock does not take, try again and repeat it for given attempts
+func (connector *DbConnector) AcquireTriggerCheckLock(triggerID string, timeout int) error {
+	acquired, err := connector.SetTriggerCheckLock(triggerID)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for !acquired && count < timeout {
+		count++
+		<-time.After(time.Millisecond * 500)
+		acquired, err = connector.SetTriggerCheckLock(triggerID)
+		if err != nil {
+			return err
+		}
+	}
+	if !acquired {
+		return fmt.Errorf("Can not acquire trigger lock in %v seconds", timeout)
+	}
+	return nil
+}
+
+// SetTriggerCheckLock create lock object with 30sec TTL and return true if object successfully created, or false if object already exists
+func (connector *DbConnector) SetTriggerCheckLock(triggerID string) (bool, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	now := time.Now()
+	if expiration, ok := connector.checkLocks[triggerID]; ok && now.Before(expiration) {
+		return false, nil
+	}
+	connector.checkLocks[triggerID] = now.Add(checkLockTTL)
+	return true, nil
+}
+
+// DeleteTriggerCheckLock deletes trigger check lock for given triggerID
+func (connector *DbConnector) DeleteTriggerCheckLock(triggerID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	delete(connector.checkLocks, triggerID)
+	return nil
+}
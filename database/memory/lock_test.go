@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLock(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Test lock manipulation", t, func() {
+		triggerID1 := "id"
+
+		isSet, err := dataBase.SetTriggerCheckLock(triggerID1)
+		So(err, ShouldBeNil)
+		So(isSet, ShouldBeTrue)
+
+		isSet, err = dataBase.SetTriggerCheckLock(triggerID1)
+		So(err, ShouldBeNil)
+		So(isSet, ShouldBeFalse)
+
+		err = dataBase.AcquireTriggerCheckLock(triggerID1, 1)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.DeleteTriggerCheckLock(triggerID1)
+		So(err, ShouldBeNil)
+
+		err = dataBase.AcquireTriggerCheckLock(triggerID1, 1)
+		So(err, ShouldBeNil)
+
+		isSet, err = dataBase.SetTriggerCheckLock(triggerID1)
+		So(err, ShouldBeNil)
+		So(isSet, ShouldBeFalse)
+
+		err = dataBase.DeleteTriggerCheckLock(triggerID1)
+		So(err, ShouldBeNil)
+	})
+}
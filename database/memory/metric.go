@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+const defaultRetention = 60
+
+// metricsKeepExpiration is time keeping of metrics values set by KeepMetricsValues lasts if it is not renewed
+const metricsKeepExpiration = 24 * time.Hour
+
+// GetPatterns gets updated patterns array
+func (connector *DbConnector) GetPatterns() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.patterns.members(), nil
+}
+
+// GetMetricsValues gets metrics values for given interval
+func (connector *DbConnector) GetMetricsValues(metrics []string, from int64, until int64) (map[string][]*moira.MetricValue, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	res := make(map[string][]*moira.MetricValue, len(metrics))
+	for _, metric := range metrics {
+		metricsValues := make([]*moira.MetricValue, 0)
+		if data, ok := connector.metricsData[metric]; ok {
+			for _, member := range data.rangeByScore(from, until) {
+				metricValue, err := parseMetricValue(member, data.scores[member])
+				if err != nil {
+					return nil, err
+				}
+				metricsValues = append(metricsValues, metricValue)
+			}
+		}
+		res[metric] = metricsValues
+	}
+	return res, nil
+}
+
+// GetMetricRetention gets given metric retention, if retention is empty then return default retention value(60)
+func (connector *DbConnector) GetMetricRetention(metric string) (int64, error) {
+	if value, ok := connector.retentionCache.Get(metric); ok {
+		if retention, ok := value.(int64); ok {
+			return retention, nil
+		}
+	}
+	connector.lock.Lock()
+	retention, ok := connector.metricRetentions[metric]
+	connector.lock.Unlock()
+	if !ok {
+		return defaultRetention, nil
+	}
+	connector.retentionCache.Set(metric, retention, 0)
+	return retention, nil
+}
+
+// SaveMetrics saves new metrics and publishes metric event for every matched pattern
+func (connector *DbConnector) SaveMetrics(metrics map[string]*moira.MatchedMetric) error {
+	events := make([]*moira.MetricEvent, 0, len(metrics))
+
+	connector.lock.Lock()
+	for _, metric := range metrics {
+		metricValue := fmt.Sprintf("%v %v", metric.Timestamp, metric.Value)
+		getSortedSet(connector.metricsData, metric.Metric).add(metric.RetentionTimestamp, metricValue)
+		connector.metricRetentions[metric.Metric] = int64(metric.Retention)
+
+		for _, pattern := range metric.Patterns {
+			getSet(connector.patternMetrics, pattern).add(metric.Metric)
+			events = append(events, &moira.MetricEvent{
+				Metric:  metric.Metric,
+				Pattern: pattern,
+			})
+		}
+	}
+	connector.lock.Unlock()
+
+	for _, event := range events {
+		connector.publish(event)
+	}
+	return nil
+}
+
+// SubscribeMetricEvents creates subscription for new metrics and return channel for this events
+// Channel is closed when given tomb is dying
+func (connector *DbConnector) SubscribeMetricEvents(tomb *tomb.Tomb) (<-chan *moira.MetricEvent, error) {
+	return connector.subscribe(tomb), nil
+}
+
+// AddPatternMetric adds new metrics by given pattern
+func (connector *DbConnector) AddPatternMetric(pattern, metric string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	getSet(connector.patternMetrics, pattern).add(metric)
+	return nil
+}
+
+// GetPatternMetrics gets all metrics by given pattern
+func (connector *DbConnector) GetPatternMetrics(pattern string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.patternMetrics, pattern), nil
+}
+
+// RemovePattern removes pattern from patterns list
+func (connector *DbConnector) RemovePattern(pattern string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.patterns.remove(pattern)
+	return nil
+}
+
+// RemovePatternsMetrics removes metrics by given patterns
+func (connector *DbConnector) RemovePatternsMetrics(patterns []string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	for _, pattern := range patterns {
+		delete(connector.patternMetrics, pattern)
+	}
+	return nil
+}
+
+// RemovePatternWithMetrics removes pattern metrics with data and given pattern
+func (connector *DbConnector) RemovePatternWithMetrics(pattern string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.removePatternWithMetrics(pattern)
+	return nil
+}
+
+// RemoveMetricValues remove metric timestamps values from 0 to given time
+func (connector *DbConnector) RemoveMetricValues(metric string, toTime int64) error {
+	if !connector.needRemoveMetrics(metric) {
+		return nil
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.removeMetricValues(metric, toTime)
+	return nil
+}
+
+// RemoveMetricsValues remove metrics timestamps values from 0 to given time
+// Values of metrics kept by KeepMetricsValues are removed only if they are older than keep period
+func (connector *DbConnector) RemoveMetricsValues(metrics []string, toTime int64) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	now := time.Now().Unix()
+	for _, metric := range metrics {
+		if connector.needRemoveMetrics(metric) {
+			removeToTime := toTime
+			if keep, ok := connector.metricsKeep[metric]; ok && keep.expires > now && now-keep.period < removeToTime {
+				removeToTime = now - keep.period
+			}
+			connector.removeMetricValues(metric, removeToTime)
+		}
+	}
+	return nil
+}
+
+// KeepMetricsValues prevents removal of metrics values newer than given period in seconds, used by triggers looking far in metrics history.
+// Keeping expires if it is not renewed, the longest period kept for metric wins
+func (connector *DbConnector) KeepMetricsValues(metrics []string, period int64) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	now := time.Now()
+	expires := now.Add(metricsKeepExpiration).Unix()
+	for _, metric := range metrics {
+		if keep, ok := connector.metricsKeep[metric]; ok && keep.expires > now.Unix() && keep.period > period {
+			continue
+		}
+		connector.metricsKeep[metric] = metricKeep{period: period, expires: expires}
+	}
+	return nil
+}
+
+// metricKeep holds metric values keep period set by KeepMetricsValues
+type metricKeep struct {
+	period  int64
+	expires int64
+}
+
+func (connector *DbConnector) needRemoveMetrics(metric string) bool {
+	err := connector.metricsCache.Add(metric, true, 0)
+	return err == nil
+}
+
+func (connector *DbConnector) removeMetricValues(metric string, toTime int64) {
+	data, ok := connector.metricsData[metric]
+	if !ok {
+		return
+	}
+	data.removeRangeByScore(toTime)
+	if data.len() == 0 {
+		delete(connector.metricsData, metric)
+	}
+}
+
+func (connector *DbConnector) removePatternWithMetrics(pattern string) {
+	connector.patterns.remove(pattern)
+	for metric := range connector.patternMetrics[pattern] {
+		delete(connector.metricsData, metric)
+	}
+	delete(connector.patternMetrics, pattern)
+}
+
+func parseMetricValue(value string, retentionTimestamp int64) (*moira.MetricValue, error) {
+	valuesArr := strings.Split(value, " ")
+	if len(valuesArr) != 2 {
+		return nil, fmt.Errorf("Value format is not valid: %s", value)
+	}
+	timestamp, err := strconv.ParseInt(valuesArr[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Metric timestamp format is not valid: %s", err.Error())
+	}
+	metricValue, err := strconv.ParseFloat(valuesArr[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("Metric value format is not valid: %s", err.Error())
+	}
+	return &moira.MetricValue{
+		RetentionTimestamp: retentionTimestamp,
+		Timestamp:          timestamp,
+		Value:              metricValue,
+	}, nil
+}
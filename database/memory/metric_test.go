@@ -0,0 +1,439 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/patrickmn/go-cache"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestMetricsStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	metric1 := "my.test.super.metric"
+	metric2 := "my.test.super.metric2"
+	pattern := "my.test.*.metric*"
+	Convey("GetPatterns works only if you add new trigger with this pattern", t, func() {
+		trigger := moira.Trigger{
+			ID:       "id",
+			Patterns: []string{pattern},
+		}
+		actual, err := dataBase.GetPatterns()
+		So(err, ShouldBeNil)
+		So(actual, ShouldBeEmpty)
+
+		//But you still can add new metrics by this pattern
+		err = dataBase.AddPatternMetric(pattern, metric1)
+		So(err, ShouldBeNil)
+
+		actualMetric, err := dataBase.GetPatternMetrics(pattern)
+		So(err, ShouldBeNil)
+		So(actualMetric, ShouldHaveLength, 1)
+
+		err = dataBase.AddPatternMetric(pattern, metric2)
+		So(err, ShouldBeNil)
+
+		actualMetric, err = dataBase.GetPatternMetrics(pattern)
+		So(err, ShouldBeNil)
+		So(actualMetric, ShouldHaveLength, 2)
+
+		//And nothing to remove
+		err = dataBase.RemovePattern(pattern)
+		So(err, ShouldBeNil)
+
+		actual, err = dataBase.GetPatterns()
+		So(err, ShouldBeNil)
+		So(actual, ShouldBeEmpty)
+
+		//Now save trigger with this pattern
+		dataBase.SaveTrigger(trigger.ID, &trigger)
+
+		actual, err = dataBase.GetPatterns()
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, trigger.Patterns)
+
+		//And you still can get metrics by this pattern
+		actualMetric, err = dataBase.GetPatternMetrics(pattern)
+		So(err, ShouldBeNil)
+		So(actualMetric, ShouldHaveLength, 2)
+
+		Convey("You can remove pattern and metric separately", func() {
+			err = dataBase.RemovePattern(pattern)
+			So(err, ShouldBeNil)
+
+			//But you still can get metrics by this pattern
+			actualMetric, err = dataBase.GetPatternMetrics(pattern)
+			So(err, ShouldBeNil)
+			So(actualMetric, ShouldHaveLength, 2)
+
+			err = dataBase.RemovePatternsMetrics([]string{pattern})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("You can remove remove pattern with metrics in one request", func() {
+			err = dataBase.RemovePatternWithMetrics(pattern)
+			So(err, ShouldBeNil)
+		})
+
+		//Now it have not patterns and metrics for this
+		actual, err = dataBase.GetPatterns()
+		So(err, ShouldBeNil)
+		So(actual, ShouldBeEmpty)
+
+		//And you still can get metrics by this pattern
+		actualMetric, err = dataBase.GetPatternMetrics(pattern)
+		So(err, ShouldBeNil)
+		So(actualMetric, ShouldBeEmpty)
+	})
+
+	Convey("Metrics values and retentions manipulation", t, func() {
+		val1 := &moira.MatchedMetric{
+			Patterns:           []string{pattern},
+			Metric:             metric1,
+			Retention:          10,
+			RetentionTimestamp: 10,
+			Timestamp:          15,
+			Value:              1,
+		}
+		val2 := &moira.MatchedMetric{
+			Patterns:           []string{pattern},
+			Metric:             metric1,
+			Retention:          10,
+			RetentionTimestamp: 20,
+			Timestamp:          22,
+			Value:              2,
+		}
+		val3 := &moira.MatchedMetric{
+			Patterns:           []string{pattern},
+			Metric:             metric1,
+			Retention:          60,
+			RetentionTimestamp: 60,
+			Timestamp:          66,
+			Value:              3,
+		}
+
+		actualRet, err := dataBase.GetMetricRetention(metric1)
+		So(err, ShouldBeNil)
+		So(actualRet, ShouldEqual, 60)
+
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: val1})
+		So(err, ShouldBeNil)
+
+		actualRet, err = dataBase.GetMetricRetention(metric1)
+		So(err, ShouldBeNil)
+		So(actualRet, ShouldEqual, 10)
+
+		actualValues, err := dataBase.GetMetricsValues([]string{metric1}, 0, 9)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 0, 10)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 10, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 11, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {}})
+
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: val2})
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 0, 9)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 0, 10)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 0, 19)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 10, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1}, &moira.MetricValue{Timestamp: 22, RetentionTimestamp: 20, Value: 2}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 11, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {&moira.MetricValue{Timestamp: 22, RetentionTimestamp: 20, Value: 2}}})
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 21, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {}})
+
+		//Save metric with changed retention
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: val3})
+		So(err, ShouldBeNil)
+
+		//But retention still old, because cache
+		actualRet, err = dataBase.GetMetricRetention(metric1)
+		So(err, ShouldBeNil)
+		So(actualRet, ShouldEqual, 10)
+	})
+}
+
+func TestRemoveMetricValues(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.metricsCache = cache.New(time.Second*2, time.Minute*60)
+	dataBase.flush()
+	defer dataBase.flush()
+	metric1 := "my.test.super.metric"
+	pattern := "my.test.*.metric*"
+	met1 := &moira.MatchedMetric{
+		Patterns:           []string{pattern},
+		Metric:             metric1,
+		Retention:          10,
+		RetentionTimestamp: 10,
+		Timestamp:          15,
+		Value:              1,
+	}
+	met2 := &moira.MatchedMetric{
+		Patterns:           []string{pattern},
+		Metric:             metric1,
+		Retention:          10,
+		RetentionTimestamp: 20,
+		Timestamp:          24,
+		Value:              2,
+	}
+	met3 := &moira.MatchedMetric{
+		Patterns:           []string{pattern},
+		Metric:             metric1,
+		Retention:          10,
+		RetentionTimestamp: 30,
+		Timestamp:          34,
+		Value:              3,
+	}
+	met4 := &moira.MatchedMetric{
+		Patterns:           []string{pattern},
+		Metric:             metric1,
+		Retention:          10,
+		RetentionTimestamp: 40,
+		Timestamp:          46,
+		Value:              4,
+	}
+
+	Convey("Test", t, func() {
+		err := dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: met1})
+		So(err, ShouldBeNil) //Save metric with changed retention
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: met2})
+		So(err, ShouldBeNil) //Save metric with changed retention
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: met3})
+		So(err, ShouldBeNil) //Save metric with changed retention
+		err = dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: met4})
+		So(err, ShouldBeNil)
+
+		actualValues, err := dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 15, RetentionTimestamp: 10, Value: 1},
+				&moira.MetricValue{Timestamp: 24, RetentionTimestamp: 20, Value: 2},
+				&moira.MetricValue{Timestamp: 34, RetentionTimestamp: 30, Value: 3},
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		err = dataBase.RemoveMetricValues(metric1, 11)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 24, RetentionTimestamp: 20, Value: 2},
+				&moira.MetricValue{Timestamp: 34, RetentionTimestamp: 30, Value: 3},
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		err = dataBase.RemoveMetricValues(metric1, 22)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 24, RetentionTimestamp: 20, Value: 2},
+				&moira.MetricValue{Timestamp: 34, RetentionTimestamp: 30, Value: 3},
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		err = dataBase.RemoveMetricsValues([]string{metric1}, 22)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 24, RetentionTimestamp: 20, Value: 2},
+				&moira.MetricValue{Timestamp: 34, RetentionTimestamp: 30, Value: 3},
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		time.Sleep(time.Second * 2)
+
+		err = dataBase.RemoveMetricsValues([]string{metric1}, 22)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 34, RetentionTimestamp: 30, Value: 3},
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		time.Sleep(time.Second * 2)
+
+		err = dataBase.RemoveMetricValues(metric1, 30)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		time.Sleep(time.Second * 2)
+
+		err = dataBase.RemoveMetricValues(metric1, 39)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+			metric1: {
+				&moira.MetricValue{Timestamp: 46, RetentionTimestamp: 40, Value: 4},
+			},
+		})
+
+		time.Sleep(time.Second * 2)
+
+		err = dataBase.RemoveMetricValues(metric1, 49)
+		So(err, ShouldBeNil)
+
+		actualValues, err = dataBase.GetMetricsValues([]string{metric1}, 1, 99)
+		So(err, ShouldBeNil)
+		So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{metric1: {}})
+	})
+}
+
+func TestMetricSubscription(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+	metric1 := "my.test.super.metric"
+	metric2 := "my.test.super.metric2"
+	pattern := "my.test.*.metric*"
+	Convey("Subscription manipulation", t, func() {
+		var tomb1 tomb.Tomb
+		ch, err := dataBase.SubscribeMetricEvents(&tomb1)
+		So(err, ShouldBeNil)
+		So(ch, ShouldNotBeNil)
+
+		met1 := &moira.MatchedMetric{
+			Patterns:           []string{pattern},
+			Metric:             metric1,
+			Retention:          10,
+			RetentionTimestamp: 10,
+			Timestamp:          15,
+			Value:              1,
+		}
+
+		met2 := &moira.MatchedMetric{
+			Patterns:           []string{pattern},
+			Metric:             metric2,
+			Retention:          20,
+			RetentionTimestamp: 20,
+			Timestamp:          25,
+			Value:              2,
+		}
+		numberOfChecks := 0
+
+		tomb1.Go(func() error {
+			for {
+				metricEvent, ok := <-ch
+				if !ok {
+					numberOfChecks++
+					logger.Info("Channel closed, end test")
+					return nil
+				}
+				if metricEvent.Metric == metric1 {
+					Convey("Test", t, func() {
+						numberOfChecks++
+						So(metricEvent, ShouldResemble, &moira.MetricEvent{Pattern: pattern, Metric: metric1})
+					})
+				}
+				if metricEvent.Metric == metric2 {
+					Convey("Test", t, func() {
+						numberOfChecks++
+						So(metricEvent, ShouldResemble, &moira.MetricEvent{Pattern: pattern, Metric: metric2})
+					})
+				}
+			}
+		})
+
+		dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric1: met1})
+		dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric2: met2})
+		tomb1.Kill(nil)
+		tomb1.Wait()
+
+		So(numberOfChecks, ShouldEqual, 3)
+	})
+}
+
+func TestKeepMetricsValues(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+	now := time.Now().Unix()
+	metric := "my.test.kept.metric"
+	pattern := "my.test.*.metric"
+
+	Convey("Kept metrics values should not be removed within keep period", t, func() {
+		for _, timestamp := range []int64{now - 7200, now - 1800, now - 60} {
+			err := dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric: {
+				Patterns:           []string{pattern},
+				Metric:             metric,
+				Retention:          60,
+				RetentionTimestamp: timestamp,
+				Timestamp:          timestamp,
+				Value:              1,
+			}})
+			So(err, ShouldBeNil)
+		}
+		err := dataBase.KeepMetricsValues([]string{metric}, 3600)
+		So(err, ShouldBeNil)
+		err = dataBase.KeepMetricsValues([]string{metric}, 600)
+		So(err, ShouldBeNil)
+
+		err = dataBase.RemoveMetricsValues([]string{metric}, now)
+		So(err, ShouldBeNil)
+
+		actualValues, err := dataBase.GetMetricsValues([]string{metric}, 0, now)
+		So(err, ShouldBeNil)
+		So(actualValues[metric], ShouldResemble, []*moira.MetricValue{
+			{Timestamp: now - 1800, RetentionTimestamp: now - 1800, Value: 1},
+			{Timestamp: now - 60, RetentionTimestamp: now - 60, Value: 1},
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moira-alert/moira"
+)
+
+// GetNotifications gets ScheduledNotifications in given range and full range
+func (connector *DbConnector) GetNotifications(start, end int64) ([]*moira.ScheduledNotification, int64, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	notifications, err := parseNotifications(connector.notifications.rangeByIndex(start, end))
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, connector.notifications.len(), nil
+}
+
+// RemoveNotification delete notifications by key = timestamp + contactID + subID
+func (connector *DbConnector) RemoveNotification(notificationKey string) (int64, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	var total int64
+	for _, member := range connector.notifications.sorted() {
+		notification := moira.ScheduledNotification{}
+		if err := json.Unmarshal([]byte(member), &notification); err != nil {
+			return 0, fmt.Errorf("Failed to parse notification json %s: %s", member, err.Error())
+		}
+		timestamp := strconv.FormatInt(notification.Timestamp, 10)
+		contactID := notification.Contact.ID
+		subID := moira.UseString(notification.Event.SubscriptionID)
+		idstr := strings.Join([]string{timestamp, contactID, subID}, "")
+		if idstr == notificationKey && connector.notifications.remove(member) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// FetchNotifications fetch notifications by given timestamp and delete it
+func (connector *DbConnector) FetchNotifications(to int64) ([]*moira.ScheduledNotification, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return parseNotifications(connector.notifications.removeRangeByScore(to))
+}
+
+// AddNotification store notification at given timestamp
+func (connector *DbConnector) AddNotification(notification *moira.ScheduledNotification) error {
+	bytes, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.notifications.add(notification.Timestamp, string(bytes))
+	return nil
+}
+
+// AddNotifications store notification at given timestamp
+func (connector *DbConnector) AddNotifications(notifications []*moira.ScheduledNotification, timestamp int64) error {
+	members := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		bytes, err := json.Marshal(notification)
+		if err != nil {
+			return err
+		}
+		members = append(members, string(bytes))
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	for _, member := range members {
+		connector.notifications.add(timestamp, member)
+	}
+	return nil
+}
+
+func parseNotifications(members []string) ([]*moira.ScheduledNotification, error) {
+	notifications := make([]*moira.ScheduledNotification, 0, len(members))
+	for _, member := range members {
+		notification := &moira.ScheduledNotification{}
+		if err := json.Unmarshal([]byte(member), notification); err != nil {
+			return nil, fmt.Errorf("Failed to parse notification json %s: %s", member, err.Error())
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
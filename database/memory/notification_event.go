@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+var eventsTTL int64 = 3600 * 24 * 30
+
+// GetNotificationEvents gets NotificationEvents by given triggerID and interval
+func (connector *DbConnector) GetNotificationEvents(triggerID string, start int64, size int64) ([]*moira.NotificationEvent, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	eventsData := make([]*moira.NotificationEvent, 0)
+	events, ok := connector.triggersEvents[triggerID]
+	if !ok {
+		return eventsData, nil
+	}
+	for _, member := range events.revRangeByIndex(start, start+size) {
+		event := &moira.NotificationEvent{}
+		if err := json.Unmarshal([]byte(member), event); err != nil {
+			return nil, fmt.Errorf("Failed to parse event json %s: %s", member, err.Error())
+		}
+		eventsData = append(eventsData, event)
+	}
+	return eventsData, nil
+}
+
+// PushNotificationEvent adds new NotificationEvent to events list and to given triggerID events list and deletes events who are older than 30 days
+// If ui=true, then add to ui events list
+func (connector *DbConnector) PushNotificationEvent(event *moira.NotificationEvent, ui bool) error {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	connector.lock.Lock()
+	connector.events = append(connector.events, eventBytes)
+	if event.TriggerID != "" {
+		events := getSortedSet(connector.triggersEvents, event.TriggerID)
+		events.add(event.Timestamp, string(eventBytes))
+		events.removeRangeByScore(time.Now().Unix() - eventsTTL)
+	}
+	if ui {
+		connector.eventsUI = append([][]byte{eventBytes}, connector.eventsUI...)
+		if len(connector.eventsUI) > eventsUIListSize+1 {
+			connector.eventsUI = connector.eventsUI[:eventsUIListSize+1]
+		}
+	}
+	connector.lock.Unlock()
+
+	select {
+	case connector.eventsNotify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// GetNotificationEventCount returns planned notifications count from given timestamp
+func (connector *DbConnector) GetNotificationEventCount(triggerID string, from int64) int64 {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	events, ok := connector.triggersEvents[triggerID]
+	if !ok {
+		return 0
+	}
+	return events.countFrom(from)
+}
+
+// FetchNotificationEvent waiting for event in events list for one second, if no events, return database.ErrNil error
+func (connector *DbConnector) FetchNotificationEvent() (moira.NotificationEvent, error) {
+	var event moira.NotificationEvent
+	timeout := time.After(fetchEventWaitDuration)
+	for {
+		eventBytes, ok := connector.popNotificationEvent()
+		if ok {
+			if err := json.Unmarshal(eventBytes, &event); err != nil {
+				return event, fmt.Errorf("Failed to parse event json %s: %s", eventBytes, err.Error())
+			}
+			return event, nil
+		}
+		select {
+		case <-connector.eventsNotify:
+		case <-timeout:
+			return event, database.ErrNil
+		}
+	}
+}
+
+func (connector *DbConnector) popNotificationEvent() ([]byte, bool) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	if len(connector.events) == 0 {
+		return nil, false
+	}
+	eventBytes := connector.events[0]
+	connector.events = connector.events[1:]
+	return eventBytes, true
+}
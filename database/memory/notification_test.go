@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"fmt"
+	"github.com/moira-alert/moira"
+	"strings"
+)
+
+func TestScheduledNotification(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("ScheduledNotification manipulation", t, func() {
+		now := time.Now().Unix()
+		notificationNew := moira.ScheduledNotification{
+			SendFail:  1,
+			Timestamp: now + 3600,
+		}
+		notification := moira.ScheduledNotification{
+			SendFail:  2,
+			Timestamp: now,
+		}
+		notificationOld := moira.ScheduledNotification{
+			SendFail:  3,
+			Timestamp: now - 3600,
+		}
+
+		Convey("Test add and get by pages", func() {
+			addNotifications(dataBase, []moira.ScheduledNotification{notification, notificationNew, notificationOld})
+			actual, total, err := dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notificationOld, &notification, &notificationNew})
+
+			actual, total, err = dataBase.GetNotifications(0, 0)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notificationOld})
+
+			actual, total, err = dataBase.GetNotifications(1, 2)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification, &notificationNew})
+		})
+
+		Convey("Test fetch notifications", func() {
+			actual, err := dataBase.FetchNotifications(now - 3600)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notificationOld})
+
+			actual, total, err := dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification, &notificationNew})
+
+			actual, err = dataBase.FetchNotifications(now + 3600)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification, &notificationNew})
+
+			actual, total, err = dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 0)
+			So(actual, ShouldResemble, make([]*moira.ScheduledNotification, 0))
+		})
+
+		Convey("Test remove notifications by key", func() {
+			now := time.Now().Unix()
+			id1 := "id1"
+			notification1 := moira.ScheduledNotification{
+				Contact:   moira.ContactData{ID: id1},
+				Event:     moira.NotificationEvent{SubscriptionID: &id1},
+				SendFail:  1,
+				Timestamp: now,
+			}
+			notification2 := moira.ScheduledNotification{
+				Contact:   moira.ContactData{ID: id1},
+				Event:     moira.NotificationEvent{SubscriptionID: &id1},
+				SendFail:  2,
+				Timestamp: now,
+			}
+			notification3 := moira.ScheduledNotification{
+				Contact:   moira.ContactData{ID: id1},
+				Event:     moira.NotificationEvent{SubscriptionID: &id1},
+				SendFail:  3,
+				Timestamp: now + 3600,
+			}
+			addNotifications(dataBase, []moira.ScheduledNotification{notification1, notification2, notification3})
+			actual, total, err := dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification1, &notification2, &notification3})
+
+			total, err = dataBase.RemoveNotification(strings.Join([]string{fmt.Sprintf("%v", now), id1, id1}, ""))
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2)
+
+			actual, total, err = dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 1)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification3})
+
+			total, err = dataBase.RemoveNotification(strings.Join([]string{fmt.Sprintf("%v", now+3600), id1, id1}, ""))
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 1)
+
+			actual, total, err = dataBase.GetNotifications(0, -1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 0)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{})
+
+			actual, err = dataBase.FetchNotifications(now + 3600)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{})
+		})
+	})
+}
+
+func addNotifications(dataBase moira.Database, notifications []moira.ScheduledNotification) {
+	for _, notification := range notifications {
+		err := dataBase.AddNotification(&notification)
+		So(err, ShouldBeNil)
+	}
+}
@@ -0,0 +1,23 @@
+package memory
+
+// UpdateMetricsHeartbeat increments metrics heartbeat counter
+func (connector *DbConnector) UpdateMetricsHeartbeat() error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.metricsHeartbeat++
+	return nil
+}
+
+// GetMetricsUpdatesCount return metrics count received by Moira-Filter
+func (connector *DbConnector) GetMetricsUpdatesCount() (int64, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.metricsHeartbeat, nil
+}
+
+// GetChecksUpdatesCount return checks count by Moira-Checker
+func (connector *DbConnector) GetChecksUpdatesCount() (int64, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.checksCounter, nil
+}
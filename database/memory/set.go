@@ -0,0 +1,181 @@
+package memory
+
+import "sort"
+
+// stringSet is an unordered set of strings, members are returned in lexicographical order
+type stringSet map[string]bool
+
+func (set stringSet) add(values ...string) {
+	for _, value := range values {
+		set[value] = true
+	}
+}
+
+func (set stringSet) remove(values ...string) {
+	for _, value := range values {
+		delete(set, value)
+	}
+}
+
+func (set stringSet) members() []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
+func getSet(sets map[string]stringSet, key string) stringSet {
+	set, ok := sets[key]
+	if !ok {
+		set = make(stringSet)
+		sets[key] = set
+	}
+	return set
+}
+
+func setMembers(sets map[string]stringSet, key string) []string {
+	set, ok := sets[key]
+	if !ok {
+		return make([]string, 0)
+	}
+	return set.members()
+}
+
+func removeFromSet(sets map[string]stringSet, key string, values ...string) {
+	set, ok := sets[key]
+	if !ok {
+		return
+	}
+	set.remove(values...)
+	if len(set) == 0 {
+		delete(sets, key)
+	}
+}
+
+// sortedSet keeps unique members ordered by score and then lexicographically, like redis sorted sets do
+type sortedSet struct {
+	scores map[string]int64
+}
+
+func newSortedSet() *sortedSet {
+	return &sortedSet{scores: make(map[string]int64)}
+}
+
+func getSortedSet(sets map[string]*sortedSet, key string) *sortedSet {
+	set, ok := sets[key]
+	if !ok {
+		set = newSortedSet()
+		sets[key] = set
+	}
+	return set
+}
+
+func (set *sortedSet) add(score int64, member string) {
+	set.scores[member] = score
+}
+
+func (set *sortedSet) remove(member string) bool {
+	if _, ok := set.scores[member]; !ok {
+		return false
+	}
+	delete(set.scores, member)
+	return true
+}
+
+func (set *sortedSet) len() int64 {
+	return int64(len(set.scores))
+}
+
+func (set *sortedSet) sorted() []string {
+	members := make([]string, 0, len(set.scores))
+	for member := range set.scores {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		left, right := set.scores[members[i]], set.scores[members[j]]
+		if left != right {
+			return left < right
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+// rangeByIndex returns members from start to stop index inclusive, negative indexes count from the end
+func (set *sortedSet) rangeByIndex(start, stop int64) []string {
+	return sliceByIndex(set.sorted(), start, stop)
+}
+
+// revRangeByIndex works as rangeByIndex, but members are ordered from the highest to the lowest score
+func (set *sortedSet) revRangeByIndex(start, stop int64) []string {
+	members := set.sorted()
+	for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+		members[i], members[j] = members[j], members[i]
+	}
+	return sliceByIndex(members, start, stop)
+}
+
+// rangeByScore returns members with score between from and to inclusive
+func (set *sortedSet) rangeByScore(from, to int64) []string {
+	members := make([]string, 0)
+	for _, member := range set.sorted() {
+		score := set.scores[member]
+		if score >= from && score <= to {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// removeRangeByScore removes members with score less or equal to given and returns removed members
+func (set *sortedSet) removeRangeByScore(to int64) []string {
+	removed := make([]string, 0)
+	for _, member := range set.sorted() {
+		if set.scores[member] > to {
+			break
+		}
+		delete(set.scores, member)
+		removed = append(removed, member)
+	}
+	return removed
+}
+
+// removeRangeByIndex removes members from start to stop index inclusive, negative indexes count from the end
+func (set *sortedSet) removeRangeByIndex(start, stop int64) {
+	for _, member := range set.rangeByIndex(start, stop) {
+		delete(set.scores, member)
+	}
+}
+
+// countFrom returns number of members with score greater or equal to given
+func (set *sortedSet) countFrom(from int64) int64 {
+	var count int64
+	for _, score := range set.scores {
+		if score >= from {
+			count++
+		}
+	}
+	return count
+}
+
+func sliceByIndex(members []string, start, stop int64) []string {
+	length := int64(len(members))
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop || start >= length {
+		return make([]string, 0)
+	}
+	return members[start : stop+1]
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetSubscription returns subscription data by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetSubscription(id string) (moira.SubscriptionData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getSubscription(id)
+}
+
+// GetSubscriptions returns subscriptions data by given ids, len of subscriptionIDs is equal to len of returned values array.
+// If there is no object by current ID, then nil is returned
+func (connector *DbConnector) GetSubscriptions(subscriptionIDs []string) ([]*moira.SubscriptionData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getSubscriptions(subscriptionIDs)
+}
+
+// SaveSubscription writes subscription data, updates tags subscriptions and user subscriptions
+func (connector *DbConnector) SaveSubscription(subscription *moira.SubscriptionData) error {
+	return connector.SaveSubscriptions([]*moira.SubscriptionData{subscription})
+}
+
+// SaveSubscriptions writes subscriptions, updates tags subscriptions and user subscriptions
+func (connector *DbConnector) SaveSubscriptions(subscriptions []*moira.SubscriptionData) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	ids := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ID
+	}
+	oldSubscriptions, err := connector.getSubscriptions(ids)
+	if err != nil {
+		return err
+	}
+	for i, subscription := range subscriptions {
+		if err := connector.saveSubscription(subscription, oldSubscriptions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveSubscription deletes subscription data and removes subscriptionID from users and tags subscriptions
+func (connector *DbConnector) RemoveSubscription(subscriptionID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	subscription, err := connector.getSubscription(subscriptionID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	removeFromSet(connector.userSubscriptions, subscription.User, subscriptionID)
+	if subscription.TeamID != "" {
+		removeFromSet(connector.teamSubscriptions, subscription.TeamID, subscriptionID)
+	}
+	for _, tag := range subscription.Tags {
+		removeFromSet(connector.tagSubscriptions, tag, subscriptionID)
+	}
+	delete(connector.subscriptions, subscriptionID)
+	return nil
+}
+
+// GetUserSubscriptionIDs returns subscriptions ids by given login
+func (connector *DbConnector) GetUserSubscriptionIDs(login string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.userSubscriptions, login), nil
+}
+
+// GetTagsSubscriptions gets all subscriptionsIDs by given tag list and read subscriptions.
+// Len of subscriptionIDs is equal to len of returned values array. If there is no object by current ID, then nil is returned
+func (connector *DbConnector) GetTagsSubscriptions(tags []string) ([]*moira.SubscriptionData, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	subscriptionIDs := make(stringSet)
+	for _, tag := range tags {
+		subscriptionIDs.add(setMembers(connector.tagSubscriptions, tag)...)
+	}
+	if len(subscriptionIDs) == 0 {
+		return make([]*moira.SubscriptionData, 0), nil
+	}
+	return connector.getSubscriptions(subscriptionIDs.members())
+}
+
+func (connector *DbConnector) saveSubscription(subscription *moira.SubscriptionData, oldSubscription *moira.SubscriptionData) error {
+	bytes, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+	if oldSubscription != nil {
+		for _, tag := range oldSubscription.Tags {
+			removeFromSet(connector.tagSubscriptions, tag, subscription.ID)
+		}
+		if oldSubscription.User != subscription.User {
+			removeFromSet(connector.userSubscriptions, oldSubscription.User, subscription.ID)
+		}
+		if oldSubscription.TeamID != "" && oldSubscription.TeamID != subscription.TeamID {
+			removeFromSet(connector.teamSubscriptions, oldSubscription.TeamID, subscription.ID)
+		}
+	}
+	for _, tag := range subscription.Tags {
+		getSet(connector.tagSubscriptions, tag).add(subscription.ID)
+	}
+	getSet(connector.userSubscriptions, subscription.User).add(subscription.ID)
+	if subscription.TeamID != "" {
+		getSet(connector.teamSubscriptions, subscription.TeamID).add(subscription.ID)
+	}
+	connector.subscriptions[subscription.ID] = bytes
+	return nil
+}
+
+func (connector *DbConnector) getSubscription(id string) (moira.SubscriptionData, error) {
+	subscription := moira.SubscriptionData{
+		ThrottlingEnabled: true,
+	}
+	bytes, ok := connector.subscriptions[id]
+	if !ok {
+		return subscription, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &subscription); err != nil {
+		return subscription, fmt.Errorf("Failed to parse subscription json %s: %s", string(bytes), err.Error())
+	}
+	subscription.ID = id
+	return subscription, nil
+}
+
+func (connector *DbConnector) getSubscriptions(subscriptionIDs []string) ([]*moira.SubscriptionData, error) {
+	subscriptions := make([]*moira.SubscriptionData, len(subscriptionIDs))
+	for i, id := range subscriptionIDs {
+		subscription, err := connector.getSubscription(id)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		subscriptions[i] = &subscription
+	}
+	return subscriptions, nil
+}
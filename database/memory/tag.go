@@ -0,0 +1,25 @@
+package memory
+
+// GetTagNames returns all tags from set with tag data
+func (connector *DbConnector) GetTagNames() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.tags.members(), nil
+}
+
+// RemoveTag deletes tag from tags list, deletes triggerIDs and subscriptionsIDs lists by given tag
+func (connector *DbConnector) RemoveTag(tagName string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.tags.remove(tagName)
+	delete(connector.tagSubscriptions, tagName)
+	delete(connector.tagTriggers, tagName)
+	return nil
+}
+
+// GetTagTriggerIDs gets all triggersIDs by given tagName
+func (connector *DbConnector) GetTagTriggerIDs(tagName string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.tagTriggers, tagName), nil
+}
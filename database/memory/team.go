@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTeam returns team by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTeam(teamID string) (moira.Team, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getTeam(teamID)
+}
+
+// SaveTeam writes team data and updates teams lists of its current and former members
+func (connector *DbConnector) SaveTeam(team *moira.Team) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, err := connector.getTeam(team.ID)
+	if err != nil && err != database.ErrNil {
+		return err
+	}
+	bytes, err := json.Marshal(team)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal team: %s", err.Error())
+	}
+	connector.teams[team.ID] = bytes
+	for login := range existing.Members {
+		if _, ok := team.Members[login]; !ok {
+			removeFromSet(connector.userTeams, login, team.ID)
+		}
+	}
+	for login := range team.Members {
+		getSet(connector.userTeams, login).add(team.ID)
+	}
+	return nil
+}
+
+// RemoveTeam deletes team data and removes team from teams lists of its members
+func (connector *DbConnector) RemoveTeam(teamID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, err := connector.getTeam(teamID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	delete(connector.teams, teamID)
+	for login := range existing.Members {
+		removeFromSet(connector.userTeams, login, teamID)
+	}
+	delete(connector.teamTriggers, teamID)
+	delete(connector.teamContacts, teamID)
+	delete(connector.teamSubscriptions, teamID)
+	return nil
+}
+
+// GetUserTeamIDs returns ids of teams where given user is member
+func (connector *DbConnector) GetUserTeamIDs(userLogin string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.userTeams, userLogin), nil
+}
+
+// GetTeamTriggerIDs returns ids of triggers owned by given team
+func (connector *DbConnector) GetTeamTriggerIDs(teamID string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.teamTriggers, teamID), nil
+}
+
+// GetTeamContactIDs returns ids of contacts owned by given team
+func (connector *DbConnector) GetTeamContactIDs(teamID string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.teamContacts, teamID), nil
+}
+
+// GetTeamSubscriptionIDs returns ids of subscriptions owned by given team
+func (connector *DbConnector) GetTeamSubscriptionIDs(teamID string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.teamSubscriptions, teamID), nil
+}
+
+func (connector *DbConnector) getTeam(teamID string) (moira.Team, error) {
+	team := moira.Team{}
+	bytes, ok := connector.teams[teamID]
+	if !ok {
+		return team, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &team); err != nil {
+		return team, fmt.Errorf("Failed to parse team json %s: %s", string(bytes), err.Error())
+	}
+	team.ID = teamID
+	return team, nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerTemplate returns trigger template by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTriggerTemplate(templateID string) (moira.TriggerTemplate, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getTriggerTemplate(templateID)
+}
+
+// GetTriggerTemplates returns all trigger templates
+func (connector *DbConnector) GetTriggerTemplates() ([]*moira.TriggerTemplate, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	templateIDs := make([]string, 0, len(connector.triggerTemplates))
+	for templateID := range connector.triggerTemplates {
+		templateIDs = append(templateIDs, templateID)
+	}
+	sort.Strings(templateIDs)
+	templates := make([]*moira.TriggerTemplate, 0, len(templateIDs))
+	for _, templateID := range templateIDs {
+		template, err := connector.getTriggerTemplate(templateID)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, &template)
+	}
+	return templates, nil
+}
+
+// SaveTriggerTemplate writes trigger template data and adds it to templates list
+func (connector *DbConnector) SaveTriggerTemplate(template *moira.TriggerTemplate) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	bytes, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal trigger template: %s", err.Error())
+	}
+	connector.triggerTemplates[template.ID] = bytes
+	return nil
+}
+
+// RemoveTriggerTemplate deletes trigger template data and removes it from templates list
+func (connector *DbConnector) RemoveTriggerTemplate(templateID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	delete(connector.triggerTemplates, templateID)
+	return nil
+}
+
+// GetTemplateTriggerIDs returns IDs of triggers rendered from given template
+func (connector *DbConnector) GetTemplateTriggerIDs(templateID string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.templateTriggers, templateID), nil
+}
+
+func (connector *DbConnector) getTriggerTemplate(templateID string) (moira.TriggerTemplate, error) {
+	template := moira.TriggerTemplate{}
+	bytes, ok := connector.triggerTemplates[templateID]
+	if !ok {
+		return template, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &template); err != nil {
+		return template, fmt.Errorf("Failed to parse trigger template json %s: %s", string(bytes), err.Error())
+	}
+	template.ID = templateID
+	return template, nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import "time"
+
+// GetTriggerThrottling get throttling or scheduled notifications delay for given triggerID
+func (connector *DbConnector) GetTriggerThrottling(triggerID string) (time.Time, time.Time) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return time.Unix(connector.throttlingNext[triggerID], 0), time.Unix(connector.throttlingBeginning[triggerID], 0)
+}
+
+// SetTriggerThrottling store throttling or scheduled notifications delay for given triggerID
+func (connector *DbConnector) SetTriggerThrottling(triggerID string, next time.Time) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.throttlingNext[triggerID] = next.Unix()
+	return nil
+}
+
+// GetSubscriptionThrottling get throttling delay of given subscription notifications for given triggerID
+func (connector *DbConnector) GetSubscriptionThrottling(triggerID, subscriptionID string) (time.Time, time.Time) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return time.Unix(connector.subscriptionsThrottlingNext[triggerID][subscriptionID], 0), time.Unix(connector.throttlingBeginning[triggerID], 0)
+}
+
+// SetSubscriptionThrottling store throttling delay of given subscription notifications for given triggerID.
+// Trigger throttling is moved forward if it is earlier than subscription one
+func (connector *DbConnector) SetSubscriptionThrottling(triggerID, subscriptionID string, next time.Time) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	subscriptionsNext, ok := connector.subscriptionsThrottlingNext[triggerID]
+	if !ok {
+		subscriptionsNext = make(map[string]int64)
+		connector.subscriptionsThrottlingNext[triggerID] = subscriptionsNext
+	}
+	subscriptionsNext[subscriptionID] = next.Unix()
+	if next.Unix() > connector.throttlingNext[triggerID] {
+		connector.throttlingNext[triggerID] = next.Unix()
+	}
+	return nil
+}
+
+// DeleteTriggerThrottling deletes throttling and scheduled notifications delay for given triggerID and all its subscriptions
+func (connector *DbConnector) DeleteTriggerThrottling(triggerID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	connector.throttlingBeginning[triggerID] = time.Now().Unix()
+	delete(connector.throttlingNext, triggerID)
+	delete(connector.subscriptionsThrottlingNext, triggerID)
+	return nil
+}
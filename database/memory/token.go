@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetUserToken returns user token by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetUserToken(tokenID string) (moira.UserToken, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getUserToken(tokenID)
+}
+
+// GetUserTokens returns all tokens issued to given user
+func (connector *DbConnector) GetUserTokens(userLogin string) ([]*moira.UserToken, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	tokenIDs := setMembers(connector.userTokensLists, userLogin)
+	tokens := make([]*moira.UserToken, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		token, err := connector.getUserToken(tokenID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+	return tokens, nil
+}
+
+// SaveUserToken writes user token data and adds it to user tokens list
+func (connector *DbConnector) SaveUserToken(token *moira.UserToken) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	bytes, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user token: %s", err.Error())
+	}
+	connector.userTokens[token.ID] = bytes
+	getSet(connector.userTokensLists, token.User).add(token.ID)
+	return nil
+}
+
+// RemoveUserToken deletes user token data and removes it from user tokens list
+func (connector *DbConnector) RemoveUserToken(tokenID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, err := connector.getUserToken(tokenID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	delete(connector.userTokens, tokenID)
+	removeFromSet(connector.userTokensLists, existing.User, tokenID)
+	return nil
+}
+
+func (connector *DbConnector) getUserToken(tokenID string) (moira.UserToken, error) {
+	token := moira.UserToken{}
+	bytes, ok := connector.userTokens[tokenID]
+	if !ok {
+		return token, database.ErrNil
+	}
+	if err := json.Unmarshal(bytes, &token); err != nil {
+		return token, fmt.Errorf("Failed to parse user token json %s: %s", string(bytes), err.Error())
+	}
+	token.ID = tokenID
+	return token, nil
+}
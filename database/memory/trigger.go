@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerIDs gets all moira triggerIDs
+func (connector *DbConnector) GetTriggerIDs() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.triggersList.members(), nil
+}
+
+// GetRemoteTriggerIDs gets all IDs of triggers checked against remote graphite
+func (connector *DbConnector) GetRemoteTriggerIDs() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.remoteTriggersList.members(), nil
+}
+
+// GetAggregateTriggerIDs gets all IDs of triggers evaluated over states of other triggers
+func (connector *DbConnector) GetAggregateTriggerIDs() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.aggregateTriggersList.members(), nil
+}
+
+// GetInhibitingTriggerIDs gets all IDs of triggers suppressing events of other triggers
+func (connector *DbConnector) GetInhibitingTriggerIDs() ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.inhibitingTriggersList.members(), nil
+}
+
+// GetTrigger gets trigger and trigger tags by given ID and return it in merged object
+func (connector *DbConnector) GetTrigger(triggerID string) (moira.Trigger, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getTrigger(triggerID)
+}
+
+// GetTriggers returns triggers data by given ids, len of triggerIDs is equal to len of returned values array.
+// If there is no object by current ID, then nil is returned
+func (connector *DbConnector) GetTriggers(triggerIDs []string) ([]*moira.Trigger, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	triggers := make([]*moira.Trigger, len(triggerIDs))
+	for i, triggerID := range triggerIDs {
+		trigger, err := connector.getTrigger(triggerID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		triggers[i] = &trigger
+	}
+	return triggers, nil
+}
+
+// GetPatternTriggerIDs gets trigger list by given pattern
+func (connector *DbConnector) GetPatternTriggerIDs(pattern string) ([]string, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return setMembers(connector.patternTriggers, pattern), nil
+}
+
+// RemovePatternTriggerIDs removes all triggerIDs list accepted to given pattern
+func (connector *DbConnector) RemovePatternTriggerIDs(pattern string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	delete(connector.patternTriggers, pattern)
+	return nil
+}
+
+// SaveTrigger sets trigger data by given trigger and triggerID
+// If trigger already exists, then merge old and new trigger patterns and tags list
+// and cleanup not used tags and patterns from lists
+// If given trigger contains new tags then create it
+func (connector *DbConnector) SaveTrigger(triggerID string, trigger *moira.Trigger) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	existing, errGetTrigger := connector.getTrigger(triggerID)
+	if errGetTrigger != nil && errGetTrigger != database.ErrNil {
+		return errGetTrigger
+	}
+	stored := *trigger
+	stored.ID = triggerID
+	bytes, err := json.Marshal(&stored)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal trigger: %s", err.Error())
+	}
+
+	cleanupPatterns := make([]string, 0)
+	if errGetTrigger != database.ErrNil {
+		for _, pattern := range leftJoin(existing.Patterns, trigger.Patterns) {
+			removeFromSet(connector.patternTriggers, pattern, triggerID)
+			cleanupPatterns = append(cleanupPatterns, pattern)
+		}
+		for _, tag := range leftJoin(existing.Tags, trigger.Tags) {
+			removeFromSet(connector.triggerTags, triggerID, tag)
+			removeFromSet(connector.tagTriggers, tag, triggerID)
+		}
+		if existing.Template != nil && (trigger.Template == nil || trigger.Template.ID != existing.Template.ID) {
+			removeFromSet(connector.templateTriggers, existing.Template.ID, triggerID)
+		}
+		if existing.TeamID != "" && existing.TeamID != trigger.TeamID {
+			removeFromSet(connector.teamTriggers, existing.TeamID, triggerID)
+		}
+	}
+	connector.triggers[triggerID] = bytes
+	connector.triggersList.add(triggerID)
+	if trigger.IsRemote {
+		connector.remoteTriggersList.add(triggerID)
+	} else {
+		connector.remoteTriggersList.remove(triggerID)
+	}
+	if trigger.Aggregate != nil {
+		connector.aggregateTriggersList.add(triggerID)
+	} else {
+		connector.aggregateTriggersList.remove(triggerID)
+	}
+	if trigger.Inhibits != nil {
+		connector.inhibitingTriggersList.add(triggerID)
+	} else {
+		connector.inhibitingTriggersList.remove(triggerID)
+	}
+	if trigger.Template != nil {
+		getSet(connector.templateTriggers, trigger.Template.ID).add(triggerID)
+	}
+	if trigger.TeamID != "" {
+		getSet(connector.teamTriggers, trigger.TeamID).add(triggerID)
+	}
+	for _, pattern := range trigger.Patterns {
+		connector.patterns.add(pattern)
+		getSet(connector.patternTriggers, pattern).add(triggerID)
+	}
+	for _, tag := range trigger.Tags {
+		getSet(connector.triggerTags, triggerID).add(tag)
+		getSet(connector.tagTriggers, tag).add(triggerID)
+		connector.tags.add(tag)
+	}
+	for _, pattern := range cleanupPatterns {
+		if len(connector.patternTriggers[pattern]) == 0 {
+			delete(connector.patternTriggers, pattern)
+			connector.patterns.remove(pattern)
+			delete(connector.patternMetrics, pattern)
+		}
+	}
+	return nil
+}
+
+// RemoveTrigger deletes trigger data by given triggerID, delete trigger tag list,
+// Deletes triggerID from containing tags triggers list and from containing patterns triggers list
+// If containing patterns doesn't used in another triggers, then delete this patterns with metrics data
+func (connector *DbConnector) RemoveTrigger(triggerID string) error {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	trigger, err := connector.getTrigger(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+
+	delete(connector.triggers, triggerID)
+	delete(connector.triggerTags, triggerID)
+	connector.triggersList.remove(triggerID)
+	connector.remoteTriggersList.remove(triggerID)
+	connector.aggregateTriggersList.remove(triggerID)
+	connector.inhibitingTriggersList.remove(triggerID)
+	if trigger.Template != nil {
+		removeFromSet(connector.templateTriggers, trigger.Template.ID, triggerID)
+	}
+	if trigger.TeamID != "" {
+		removeFromSet(connector.teamTriggers, trigger.TeamID, triggerID)
+	}
+	for _, tag := range trigger.Tags {
+		removeFromSet(connector.tagTriggers, tag, triggerID)
+	}
+	for _, pattern := range trigger.Patterns {
+		removeFromSet(connector.patternTriggers, pattern, triggerID)
+	}
+	for _, pattern := range trigger.Patterns {
+		if len(connector.patternTriggers[pattern]) == 0 {
+			connector.removePatternWithMetrics(pattern)
+		}
+	}
+	return nil
+}
+
+// GetTriggerChecks gets triggers data with tags, lastCheck data and throttling by given triggersIDs
+// Len of triggerIDs is equal to len of returned values array.
+// If there is no object by current ID, then nil is returned
+func (connector *DbConnector) GetTriggerChecks(triggerIDs []string) ([]*moira.TriggerCheck, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	triggerChecks := make([]*moira.TriggerCheck, len(triggerIDs))
+	for i, triggerID := range triggerIDs {
+		trigger, err := connector.getTrigger(triggerID)
+		if err != nil {
+			if err == database.ErrNil {
+				continue
+			}
+			return nil, err
+		}
+		lastCheck, err := connector.getTriggerLastCheck(triggerID)
+		if err != nil && err != database.ErrNil {
+			return nil, err
+		}
+		throttling := connector.throttlingNext[triggerID]
+		if time.Now().Unix() >= throttling {
+			throttling = 0
+		}
+		triggerChecks[i] = &moira.TriggerCheck{
+			Trigger:    trigger,
+			LastCheck:  lastCheck,
+			Throttling: throttling,
+		}
+	}
+	return triggerChecks, nil
+}
+
+func (connector *DbConnector) getTrigger(triggerID string) (moira.Trigger, error) {
+	bytes, ok := connector.triggers[triggerID]
+	if !ok {
+		return moira.Trigger{}, database.ErrNil
+	}
+	trigger := moira.Trigger{}
+	if err := json.Unmarshal(bytes, &trigger); err != nil {
+		return moira.Trigger{}, fmt.Errorf("Failed to parse trigger json %s: %s", string(bytes), err.Error())
+	}
+	if triggerTags := setMembers(connector.triggerTags, triggerID); len(triggerTags) > 0 {
+		trigger.Tags = triggerTags
+	}
+	trigger.ID = triggerID
+	return trigger, nil
+}
+
+func leftJoin(left, right []string) []string {
+	rightValues := make(map[string]bool)
+	for _, value := range right {
+		rightValues[value] = true
+	}
+	arr := make([]string, 0)
+	for _, leftValue := range left {
+		if _, ok := rightValues[leftValue]; !ok {
+			arr = append(arr, leftValue)
+		}
+	}
+	return arr
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+var triggerHistoryTTL int64 = 3600 * 24 * 30
+var triggerHistoryMaxSize int64 = 10000
+
+// AddTriggerStateSnapshot adds trigger state snapshot to trigger history and deletes snapshots who are older than 30 days,
+// history of one trigger keeps no more than 10000 latest snapshots
+func (connector *DbConnector) AddTriggerStateSnapshot(triggerID string, snapshot *moira.TriggerStateSnapshot) error {
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	history := getSortedSet(connector.triggersHistory, triggerID)
+	history.add(snapshot.Timestamp, string(bytes))
+	history.removeRangeByScore(time.Now().Unix() - triggerHistoryTTL)
+	history.removeRangeByIndex(0, -triggerHistoryMaxSize-1)
+	return nil
+}
+
+// GetTriggerStateHistory gets trigger state snapshots saved in given interval ordered by time.
+// The latest snapshot saved before interval goes first, so trigger and metrics states at interval start are known
+func (connector *DbConnector) GetTriggerStateHistory(triggerID string, from int64, to int64) ([]*moira.TriggerStateSnapshot, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	snapshots := make([]*moira.TriggerStateSnapshot, 0)
+	history, ok := connector.triggersHistory[triggerID]
+	if !ok {
+		return snapshots, nil
+	}
+	members := history.rangeByScore(from, to)
+	if previous := history.rangeByScore(math.MinInt64, from-1); len(previous) > 0 {
+		members = append(previous[len(previous)-1:], members...)
+	}
+	for _, member := range members {
+		snapshot := &moira.TriggerStateSnapshot{}
+		if err := json.Unmarshal([]byte(member), snapshot); err != nil {
+			return nil, fmt.Errorf("Failed to parse trigger state snapshot json %s: %s", member, err.Error())
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// AddTriggerRevision appends revision to trigger revisions list and sets its number. Revisions are kept
+// after trigger removal, so removed trigger can be restored
+func (connector *DbConnector) AddTriggerRevision(triggerID string, revision *moira.TriggerRevision) error {
+	bytes, err := json.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal trigger revision: %s", err.Error())
+	}
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	connector.triggerRevisions[triggerID] = append(connector.triggerRevisions[triggerID], bytes)
+	revision.Number = int64(len(connector.triggerRevisions[triggerID]))
+	return nil
+}
+
+// GetTriggerRevisions gets all trigger revisions ordered by number
+func (connector *DbConnector) GetTriggerRevisions(triggerID string) ([]*moira.TriggerRevision, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+
+	revisions := make([]*moira.TriggerRevision, 0, len(connector.triggerRevisions[triggerID]))
+	for i := range connector.triggerRevisions[triggerID] {
+		revision, err := connector.getTriggerRevision(triggerID, int64(i+1))
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, nil
+}
+
+// GetTriggerRevision gets trigger revision by number, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTriggerRevision(triggerID string, number int64) (moira.TriggerRevision, error) {
+	connector.lock.Lock()
+	defer connector.lock.Unlock()
+	return connector.getTriggerRevision(triggerID, number)
+}
+
+func (connector *DbConnector) getTriggerRevision(triggerID string, number int64) (moira.TriggerRevision, error) {
+	revision := moira.TriggerRevision{}
+	revisions := connector.triggerRevisions[triggerID]
+	if number < 1 || number > int64(len(revisions)) {
+		return revision, database.ErrNil
+	}
+	bytes := revisions[number-1]
+	if err := json.Unmarshal(bytes, &revision); err != nil {
+		return revision, fmt.Errorf("Failed to parse trigger revision json %s: %s", string(bytes), err.Error())
+	}
+	revision.Number = number
+	return revision, nil
+}
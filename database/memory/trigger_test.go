@@ -0,0 +1,393 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestTriggerStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Trigger manipulation", t, func() {
+		Convey("Test save-get-remove", func() {
+			trigger := &triggers[0]
+
+			//Check for not existing not writen trigger
+			actual, err := dataBase.GetTrigger(trigger.ID)
+			So(err, ShouldResemble, database.ErrNil)
+			So(actual, ShouldResemble, moira.Trigger{})
+
+			err = dataBase.RemoveTrigger(trigger.ID)
+			So(err, ShouldBeNil)
+
+			//Now write it
+			err = dataBase.SaveTrigger(trigger.ID, trigger)
+			So(err, ShouldBeNil)
+
+			//And check for existing by several pointers like id or tag
+			actual, err = dataBase.GetTrigger(trigger.ID)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, *trigger)
+
+			ids, err := dataBase.GetTriggerIDs()
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+
+			ids, err = dataBase.GetTagTriggerIDs(trigger.Tags[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+
+			ids, err = dataBase.GetPatternTriggerIDs(trigger.Patterns[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+
+			actualTriggers, err := dataBase.GetTriggers(ids)
+			So(err, ShouldBeNil)
+			So(actualTriggers, ShouldResemble, []*moira.Trigger{trigger})
+
+			//Also we write new patterns
+			actualPatterns, err := dataBase.GetPatterns()
+			So(err, ShouldBeNil)
+			So(actualPatterns, ShouldResemble, trigger.Patterns)
+
+			//And tags
+			actualTags, err := dataBase.GetTagNames()
+			So(err, ShouldBeNil)
+			So(actualTags, ShouldResemble, trigger.Tags)
+
+			//Now just add tag and pattern in trigger and save it
+			trigger = nil
+			changedTrigger := &triggers[1]
+			err = dataBase.SaveTrigger(changedTrigger.ID, changedTrigger)
+			So(err, ShouldBeNil)
+
+			actual, err = dataBase.GetTrigger(changedTrigger.ID)
+			So(err, ShouldBeNil)
+			So(actual.Name, ShouldResemble, changedTrigger.Name)
+
+			//Now we can get this trigger by two tags
+			ids, err = dataBase.GetTagTriggerIDs(changedTrigger.Tags[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedTrigger.ID})
+
+			ids, err = dataBase.GetTagTriggerIDs(changedTrigger.Tags[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedTrigger.ID})
+
+			//And we have new tag in tags list
+			actualTags, err = dataBase.GetTagNames()
+			So(err, ShouldBeNil)
+			So(actualTags, ShouldHaveLength, 2)
+
+			//Also we can get this trigger by new pattern
+			ids, err = dataBase.GetPatternTriggerIDs(changedTrigger.Patterns[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedTrigger.ID})
+
+			ids, err = dataBase.GetPatternTriggerIDs(changedTrigger.Patterns[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedTrigger.ID})
+
+			//And we have new pattern in patterns list
+			actualPatterns, err = dataBase.GetPatterns()
+			So(err, ShouldBeNil)
+			So(actualPatterns, ShouldHaveLength, 2)
+
+			//Now remove old tag and pattern in trigger and save it
+			oldTag := changedTrigger.Tags[1]
+			oldPattern := changedTrigger.Patterns[1]
+			changedTrigger = nil
+			changedAgainTrigger := &triggers[2]
+			err = dataBase.SaveTrigger(changedAgainTrigger.ID, changedAgainTrigger)
+			So(err, ShouldBeNil)
+
+			actual, err = dataBase.GetTrigger(changedAgainTrigger.ID)
+			So(err, ShouldBeNil)
+			So(actual.Name, ShouldResemble, changedAgainTrigger.Name)
+
+			//Now we can't find trigger by old tag but can get it by new one tag
+			ids, err = dataBase.GetTagTriggerIDs(oldTag)
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetTagTriggerIDs(changedAgainTrigger.Tags[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedAgainTrigger.ID})
+
+			ids, err = dataBase.GetTagTriggerIDs(changedAgainTrigger.Tags[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedAgainTrigger.ID})
+
+			//But we still has this tag in tags list with new one
+			actualTags, err = dataBase.GetTagNames()
+			So(err, ShouldBeNil)
+			So(actualTags, ShouldHaveLength, 3)
+
+			//Same story like tags and trigger with pattern and trigger
+			ids, err = dataBase.GetPatternTriggerIDs(oldPattern)
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetPatternTriggerIDs(changedAgainTrigger.Patterns[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedAgainTrigger.ID})
+
+			ids, err = dataBase.GetPatternTriggerIDs(changedAgainTrigger.Patterns[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{changedAgainTrigger.ID})
+
+			//But this pattern no more in pattern list, it is not needed
+			actualTags, err = dataBase.GetPatterns()
+			So(err, ShouldBeNil)
+			So(actualTags, ShouldHaveLength, 2)
+
+			//Stop it!! Remove trigger and check for no existing it by pointers
+			err = dataBase.RemoveTrigger(changedAgainTrigger.ID)
+			So(err, ShouldBeNil)
+
+			//And check for existing by several pointers like id or tag
+			actual, err = dataBase.GetTrigger(changedAgainTrigger.ID)
+			So(err, ShouldResemble, database.ErrNil)
+			So(actual, ShouldResemble, moira.Trigger{})
+
+			ids, err = dataBase.GetTriggerIDs()
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetTagTriggerIDs(changedAgainTrigger.Tags[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetTagTriggerIDs(changedAgainTrigger.Tags[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetPatternTriggerIDs(changedAgainTrigger.Patterns[0])
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			ids, err = dataBase.GetPatternTriggerIDs(changedAgainTrigger.Patterns[1])
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			actualTriggers, err = dataBase.GetTriggers([]string{changedAgainTrigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggers, ShouldResemble, []*moira.Trigger{nil})
+
+			//Also we delete all patterns
+			actualPatterns, err = dataBase.GetPatterns()
+			So(err, ShouldBeNil)
+			So(actualPatterns, ShouldBeEmpty)
+
+			//But has all tags
+			actualTags, err = dataBase.GetTagNames()
+			So(err, ShouldBeNil)
+			So(actualTags, ShouldHaveLength, 3)
+		})
+
+		Convey("Save trigger with lastCheck and throttling and GetTriggerChecks", func() {
+			trigger := triggers[5]
+			triggerCheck := &moira.TriggerCheck{
+				Trigger: trigger,
+			}
+
+			err := dataBase.SaveTrigger(trigger.ID, &trigger)
+			So(err, ShouldBeNil)
+
+			actual, err := dataBase.GetTrigger(trigger.ID)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, trigger)
+
+			actualTriggerChecks, err := dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
+
+			//Add check data
+			err = dataBase.SetTriggerLastCheck(trigger.ID, &lastCheckTest)
+			So(err, ShouldBeNil)
+
+			triggerCheck.LastCheck = lastCheckTest
+			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
+
+			//And throttling
+			err = dataBase.SetTriggerThrottling(trigger.ID, time.Now().Add(-time.Minute))
+			So(err, ShouldBeNil)
+
+			//But it is foul
+			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
+
+			//Now good throttling
+			th := time.Now().Add(time.Minute)
+			err = dataBase.SetTriggerThrottling(trigger.ID, th)
+			So(err, ShouldBeNil)
+
+			triggerCheck.Throttling = th.Unix()
+			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
+
+			//Remove throttling
+			err = dataBase.DeleteTriggerThrottling(trigger.ID)
+			So(err, ShouldBeNil)
+
+			triggerCheck.Throttling = 0
+			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
+
+			//Can not remove check data, but can remove trigger!
+			err = dataBase.RemoveTrigger(trigger.ID)
+			So(err, ShouldBeNil)
+
+			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
+			So(err, ShouldBeNil)
+			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{nil})
+		})
+
+		Convey("Save remote, aggregate and inhibiting triggers", func() {
+			kinds := []struct {
+				name   string
+				set    func(trigger *moira.Trigger, enabled bool)
+				getIDs func() ([]string, error)
+			}{
+				{
+					name: "remote",
+					set: func(trigger *moira.Trigger, enabled bool) {
+						trigger.IsRemote = enabled
+					},
+					getIDs: dataBase.GetRemoteTriggerIDs,
+				},
+				{
+					name: "aggregate",
+					set: func(trigger *moira.Trigger, enabled bool) {
+						trigger.Aggregate = nil
+						if enabled {
+							trigger.Aggregate = &moira.AggregateSettings{TriggerIDs: []string{triggers[0].ID}, Expression: "count(ERROR) > 0"}
+						}
+					},
+					getIDs: dataBase.GetAggregateTriggerIDs,
+				},
+				{
+					name: "inhibiting",
+					set: func(trigger *moira.Trigger, enabled bool) {
+						trigger.Inhibits = nil
+						if enabled {
+							trigger.Inhibits = &moira.InhibitionSettings{Tags: []string{"dc-1"}, States: []string{"ERROR"}}
+						}
+					},
+					getIDs: dataBase.GetInhibitingTriggerIDs,
+				},
+			}
+			for i, kind := range kinds {
+				trigger := triggers[i+1]
+				kind.set(&trigger, true)
+				err := dataBase.SaveTrigger(trigger.ID, &trigger)
+				So(err, ShouldBeNil)
+
+				actual, err := dataBase.GetTrigger(trigger.ID)
+				So(err, ShouldBeNil)
+				So(actual.IsRemote, ShouldEqual, trigger.IsRemote)
+				So(actual.Aggregate, ShouldResemble, trigger.Aggregate)
+				So(actual.Inhibits, ShouldResemble, trigger.Inhibits)
+
+				ids, err := kind.getIDs()
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{trigger.ID})
+
+				//Trigger is switched to ordinary one
+				kind.set(&trigger, false)
+				err = dataBase.SaveTrigger(trigger.ID, &trigger)
+				So(err, ShouldBeNil)
+
+				ids, err = kind.getIDs()
+				So(err, ShouldBeNil)
+				So(ids, ShouldBeEmpty)
+
+				//And back, then removed
+				kind.set(&trigger, true)
+				err = dataBase.SaveTrigger(trigger.ID, &trigger)
+				So(err, ShouldBeNil)
+
+				err = dataBase.RemoveTrigger(trigger.ID)
+				So(err, ShouldBeNil)
+
+				ids, err = kind.getIDs()
+				So(err, ShouldBeNil)
+				So(ids, ShouldBeEmpty)
+			}
+		})
+	})
+}
+
+var triggers = []moira.Trigger{
+	{
+		ID:       "triggerID-0000000000001",
+		Name:     "test trigger 1 v1.0",
+		Targets:  []string{"test.target.1"},
+		Tags:     []string{"test-tag-1"},
+		Patterns: []string{"test.pattern.1"},
+	},
+	{
+		ID:       "triggerID-0000000000001",
+		Name:     "test trigger 1 v2.0",
+		Targets:  []string{"test.target.1", "test.target.2"},
+		Tags:     []string{"test-tag-2", "test-tag-1"},
+		Patterns: []string{"test.pattern.2", "test.pattern.1"},
+	},
+	{
+		ID:       "triggerID-0000000000001",
+		Name:     "test trigger 1 v3.0",
+		Targets:  []string{"test.target.3"},
+		Tags:     []string{"test-tag-2", "test-tag-3"},
+		Patterns: []string{"test.pattern.3", "test.pattern.2"},
+	},
+	{
+		ID:      "triggerID-0000000000004",
+		Name:    "test trigger 4",
+		Targets: []string{"test.target.4"},
+		Tags:    []string{"test-tag-4"},
+	},
+	{
+		ID:      "triggerID-0000000000005",
+		Name:    "test trigger 5 (nobody is subscribed)",
+		Targets: []string{"test.target.5"},
+		Tags:    []string{"test-tag-nosub"},
+	},
+	{
+		ID:      "triggerID-0000000000006",
+		Name:    "test trigger 6 (throttling disabled)",
+		Targets: []string{"test.target.6"},
+		Tags:    []string{"test-tag-throttling-disabled"},
+	},
+	{
+		ID:      "triggerID-0000000000007",
+		Name:    "test trigger 7 (multiple subscribers)",
+		Targets: []string{"test.target.7"},
+		Tags:    []string{"test-tag-multiple-subs"},
+	},
+	{
+		ID:      "triggerID-0000000000008",
+		Name:    "test trigger 8 (duplicated contacts)",
+		Targets: []string{"test.target.8"},
+		Tags:    []string{"test-tag-dup-contacts"},
+	},
+	{
+		ID:      "triggerID-0000000000009",
+		Name:    "test trigger 9 (pseudo tag)",
+		Targets: []string{"test.target.9"},
+		Tags:    []string{"test-degradation"},
+	},
+}
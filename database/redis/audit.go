@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// auditMaxSize is approximate size of audit stream, redis trims the oldest records when it is exceeded
+var auditMaxSize int64 = 100000
+
+// AddAuditRecord appends configuration change record to audit stream, which keeps about 100000 latest records.
+// Redis streams are available since redis 5.0
+func (connector *DbConnector) AddAuditRecord(record *moira.AuditRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal audit record: %s", err.Error())
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	if _, err = c.Do("XADD", auditKey, "MAXLEN", "~", auditMaxSize, "*", "record", bytes); err != nil {
+		return fmt.Errorf("Failed to XADD: %s", err.Error())
+	}
+	return nil
+}
+
+// GetAuditRecords gets audit records saved in given interval ordered by time
+func (connector *DbConnector) GetAuditRecords(from int64, to int64) ([]*moira.AuditRecord, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	return reply.AuditRecords(c.Do("XRANGE", auditKey, from*1000, to*1000+999))
+}
+
+var auditKey = "moira-audit"
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestAuditRecords(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+	records := []*moira.AuditRecord{
+		{Timestamp: now - 200, User: user1, Entity: moira.AuditEntityTrigger, EntityID: "trigger", Action: moira.AuditActionCreate, After: json.RawMessage(`{"id":"trigger"}`)},
+		{Timestamp: now - 100, User: user2, Entity: moira.AuditEntityTrigger, EntityID: "trigger", Action: moira.AuditActionDelete, Before: json.RawMessage(`{"id":"trigger"}`)},
+	}
+
+	Convey("Audit records manipulation", t, func() {
+		dataBase.flush()
+		for _, record := range records {
+			err := dataBase.AddAuditRecord(record)
+			So(err, ShouldBeNil)
+		}
+
+		actual, err := dataBase.GetAuditRecords(now-300, now)
+		So(err, ShouldBeNil)
+		So(actual, ShouldHaveLength, 2)
+		So(actual[0].ID, ShouldNotBeEmpty)
+		So(actual[0].ID, ShouldNotEqual, actual[1].ID)
+		for i, record := range actual {
+			record.ID = ""
+			So(record, ShouldResemble, records[i])
+		}
+
+		// Records are ordered by time they were added at
+		actual, err = dataBase.GetAuditRecords(now-3600, now-300)
+		So(err, ShouldBeNil)
+		So(actual, ShouldBeEmpty)
+	})
+}
+
+func TestAuditRecordsErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		err := dataBase.AddAuditRecord(&moira.AuditRecord{User: user1})
+		So(err, ShouldNotBeNil)
+
+		actual, err := dataBase.GetAuditRecords(0, 100)
+		So(err, ShouldNotBeNil)
+		So(actual, ShouldBeNil)
+	})
+}
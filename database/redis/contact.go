@@ -65,7 +65,7 @@ func (connector *DbConnector) GetAllContacts() ([]*moira.ContactData, error) {
 	return connector.GetContacts(contactIDs)
 }
 
-// SaveContact writes contact data and updates user contacts
+// SaveContact writes contact data and updates user and team contacts
 func (connector *DbConnector) SaveContact(contact *moira.ContactData) error {
 	existing, getContactErr := connector.GetContact(contact.ID)
 	if getContactErr != nil && getContactErr != database.ErrNil {
@@ -85,6 +85,12 @@ func (connector *DbConnector) SaveContact(contact *moira.ContactData) error {
 		c.Send("SREM", userContactsKey(existing.User), contact.ID)
 	}
 	c.Send("SADD", userContactsKey(contact.User), contact.ID)
+	if getContactErr != database.ErrNil && existing.TeamID != "" && contact.TeamID != existing.TeamID {
+		c.Send("SREM", teamContactsKey(existing.TeamID), contact.ID)
+	}
+	if contact.TeamID != "" {
+		c.Send("SADD", teamContactsKey(contact.TeamID), contact.ID)
+	}
 	_, err = c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("Failed to EXEC: %s", err.Error())
@@ -92,7 +98,7 @@ func (connector *DbConnector) SaveContact(contact *moira.ContactData) error {
 	return nil
 }
 
-// RemoveContact deletes contact data and contactID from user contacts
+// RemoveContact deletes contact data and contactID from user and team contacts
 func (connector *DbConnector) RemoveContact(contactID string) error {
 	existing, err := connector.GetContact(contactID)
 	if err != nil && err != database.ErrNil {
@@ -104,6 +110,9 @@ func (connector *DbConnector) RemoveContact(contactID string) error {
 	c.Send("MULTI")
 	c.Send("DEL", contactKey(contactID))
 	c.Send("SREM", userContactsKey(existing.User), contactID)
+	if existing.TeamID != "" {
+		c.Send("SREM", teamContactsKey(existing.TeamID), contactID)
+	}
 	_, err = c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("Failed to EXEC: %s", err.Error())
@@ -42,7 +42,7 @@ func (connector *DbConnector) SetTriggerLastCheck(triggerID string, checkData *m
 	return nil
 }
 
-// RemoveTriggerLastCheck removes trigger last check data
+// RemoveTriggerLastCheck removes trigger last check data and trigger state history
 func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
 	c := connector.pool.Get()
 	defer c.Close()
@@ -50,6 +50,7 @@ func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
 	c.Send("DEL", metricLastCheckKey(triggerID))
 	c.Send("ZREM", triggersChecksKey, triggerID)
 	c.Send("SREM", badStateTriggersKey, triggerID)
+	c.Send("DEL", triggerHistoryKey(triggerID))
 	_, err := c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("Failed to EXEC: %s", err.Error())
@@ -61,6 +62,31 @@ func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
 // If during the update lastCheck was updated from another place, try update again
 // If CheckData does not contain one of given metrics it will ignore this metric
 func (connector *DbConnector) SetTriggerCheckMetricsMaintenance(triggerID string, metrics map[string]int64) error {
+	return connector.updateTriggerLastCheck(triggerID, func(lastCheck *moira.CheckData) {
+		metricsCheck := lastCheck.Metrics
+		if len(metricsCheck) > 0 {
+			for metric, value := range metrics {
+				data, ok := metricsCheck[metric]
+				if !ok {
+					continue
+				}
+				data.Maintenance = value
+				metricsCheck[metric] = data
+			}
+		}
+	})
+}
+
+// SetTriggerCheckAcknowledgement sets acknowledgement to given metrics or to whole trigger if metrics are empty,
+// nil acknowledgement removes existing ones. If during the update lastCheck was updated from another place, try update again
+func (connector *DbConnector) SetTriggerCheckAcknowledgement(triggerID string, metrics []string, ack *moira.Acknowledgement) error {
+	return connector.updateTriggerLastCheck(triggerID, func(lastCheck *moira.CheckData) {
+		lastCheck.Acknowledge(metrics, ack)
+	})
+}
+
+// updateTriggerLastCheck applies update to stored trigger last check, if it was changed during update then update is applied again
+func (connector *DbConnector) updateTriggerLastCheck(triggerID string, update func(lastCheck *moira.CheckData)) error {
 	c := connector.pool.Get()
 	defer c.Close()
 	var readingErr error
@@ -75,17 +101,7 @@ func (connector *DbConnector) SetTriggerCheckMetricsMaintenance(triggerID string
 		if err != nil {
 			return fmt.Errorf("Failed to parse lastCheck json %s: %s", lastCheckString, err.Error())
 		}
-		metricsCheck := lastCheck.Metrics
-		if len(metricsCheck) > 0 {
-			for metric, value := range metrics {
-				data, ok := metricsCheck[metric]
-				if !ok {
-					continue
-				}
-				data.Maintenance = value
-				metricsCheck[metric] = data
-			}
-		}
+		update(&lastCheck)
 		newLastCheck, err := json.Marshal(lastCheck)
 		if err != nil {
 			return err
@@ -3,6 +3,7 @@ package redis
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/garyburd/redigo/redis"
 	"gopkg.in/tomb.v2"
@@ -12,6 +13,9 @@ import (
 	"github.com/moira-alert/moira/database/redis/reply"
 )
 
+// metricsKeepExpiration is time keeping of metrics values set by KeepMetricsValues lasts if it is not renewed
+const metricsKeepExpiration = 24 * time.Hour
+
 // GetPatterns gets updated patterns array
 func (connector *DbConnector) GetPatterns() ([]string, error) {
 	c := connector.pool.Get()
@@ -224,15 +228,32 @@ func (connector *DbConnector) RemoveMetricValues(metric string, toTime int64) er
 }
 
 // RemoveMetricsValues remove metrics timestamps values from 0 to given time
+// Values of metrics kept by KeepMetricsValues are removed only if they are older than keep period
 func (connector *DbConnector) RemoveMetricsValues(metrics []string, toTime int64) error {
+	metricsToRemove := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		if connector.needRemoveMetrics(metric) {
+			metricsToRemove = append(metricsToRemove, metric)
+		}
+	}
+	if len(metricsToRemove) == 0 {
+		return nil
+	}
+	keepPeriods, err := connector.getMetricsKeepPeriods(metricsToRemove)
+	if err != nil {
+		return err
+	}
+
 	c := connector.pool.Get()
 	defer c.Close()
-
+	now := time.Now().Unix()
 	c.Send("MULTI")
-	for _, metric := range metrics {
-		if connector.needRemoveMetrics(metric) {
-			c.Send("ZREMRANGEBYSCORE", metricDataKey(metric), "-inf", toTime)
+	for i, metric := range metricsToRemove {
+		removeToTime := toTime
+		if keepPeriods[i] > 0 && now-keepPeriods[i] < removeToTime {
+			removeToTime = now - keepPeriods[i]
 		}
+		c.Send("ZREMRANGEBYSCORE", metricDataKey(metric), "-inf", removeToTime)
 	}
 	if _, err := c.Do("EXEC"); err != nil {
 		return fmt.Errorf("Failed to EXEC remove metrics: %v", err)
@@ -240,6 +261,58 @@ func (connector *DbConnector) RemoveMetricsValues(metrics []string, toTime int64
 	return nil
 }
 
+// keepMetricsValuesScript sets keep period of each metric unless longer one is kept already,
+// so triggers with shorter periods do not cut history needed by others.
+// Longer keeping is not renewed here and expires if its trigger does not renew it
+var keepMetricsValuesScript = redis.NewScript(-1, `
+for _, key in ipairs(KEYS) do
+	if tonumber(ARGV[1]) >= tonumber(redis.call("GET", key) or "0") then
+		redis.call("SET", key, ARGV[1], "EX", ARGV[2])
+	end
+end
+return 1
+`)
+
+// KeepMetricsValues prevents removal of metrics values newer than given period in seconds, used by triggers looking far in metrics history.
+// Keeping expires if it is not renewed, the longest period kept for metric wins
+func (connector *DbConnector) KeepMetricsValues(metrics []string, period int64) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	args := make([]interface{}, 0, len(metrics)+3)
+	args = append(args, len(metrics))
+	for _, metric := range metrics {
+		args = append(args, metricKeepKey(metric))
+	}
+	args = append(args, period, int64(metricsKeepExpiration.Seconds()))
+	if _, err := keepMetricsValuesScript.Do(c, args...); err != nil {
+		return fmt.Errorf("Failed to keep metrics values: %v", err)
+	}
+	return nil
+}
+
+func (connector *DbConnector) getMetricsKeepPeriods(metrics []string) ([]int64, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	keys := make([]interface{}, 0, len(metrics))
+	for _, metric := range metrics {
+		keys = append(keys, metricKeepKey(metric))
+	}
+	values, err := redis.Values(c.Do("MGET", keys...))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get metrics keep periods: %v", err)
+	}
+	periods := make([]int64, len(metrics))
+	for i, value := range values {
+		if value != nil {
+			periods[i], _ = redis.Int64(value, nil)
+		}
+	}
+	return periods, nil
+}
+
 func (connector *DbConnector) needRemoveMetrics(metric string) bool {
 	err := connector.metricsCache.Add(metric, true, 0)
 	return err == nil
@@ -252,6 +325,10 @@ func patternMetricsKey(pattern string) string {
 	return fmt.Sprintf("moira-pattern-metrics:%s", pattern)
 }
 
+func metricKeepKey(metric string) string {
+	return fmt.Sprintf("moira-metric-keep:%s", metric)
+}
+
 func metricDataKey(metric string) string {
 	return fmt.Sprintf("moira-metric-data:%s", metric)
 }
@@ -400,6 +400,44 @@ func TestMetricSubscription(t *testing.T) {
 	})
 }
 
+func TestKeepMetricsValues(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+	now := time.Now().Unix()
+	metric := "my.test.kept.metric"
+	pattern := "my.test.*.metric"
+
+	Convey("Longest keep period should not be shortened by other triggers", t, func() {
+		for _, timestamp := range []int64{now - 7200, now - 1800, now - 60} {
+			err := dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric: {
+				Patterns:           []string{pattern},
+				Metric:             metric,
+				Retention:          60,
+				RetentionTimestamp: timestamp,
+				Timestamp:          timestamp,
+				Value:              1,
+			}})
+			So(err, ShouldBeNil)
+		}
+		err := dataBase.KeepMetricsValues([]string{metric}, 3600)
+		So(err, ShouldBeNil)
+		err = dataBase.KeepMetricsValues([]string{metric}, 600)
+		So(err, ShouldBeNil)
+
+		err = dataBase.RemoveMetricsValues([]string{metric}, now)
+		So(err, ShouldBeNil)
+
+		actualValues, err := dataBase.GetMetricsValues([]string{metric}, 0, now)
+		So(err, ShouldBeNil)
+		So(actualValues[metric], ShouldResemble, []*moira.MetricValue{
+			{Timestamp: now - 1800, RetentionTimestamp: now - 1800, Value: 1},
+			{Timestamp: now - 60, RetentionTimestamp: now - 60, Value: 1},
+		})
+	})
+}
+
 func TestMetricsStoringErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewDatabase(logger, emptyConfig)
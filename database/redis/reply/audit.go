@@ -0,0 +1,43 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+)
+
+// AuditRecords converts redis DB stream entries reply to moira.AuditRecord objects array,
+// record ID is set to stream entry ID
+func AuditRecords(rep interface{}, err error) ([]*moira.AuditRecord, error) {
+	entries, err := redis.Values(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return make([]*moira.AuditRecord, 0), nil
+		}
+		return nil, fmt.Errorf("Failed to read audit records: %s", err.Error())
+	}
+	records := make([]*moira.AuditRecord, 0, len(entries))
+	for _, entry := range entries {
+		values, err := redis.Values(entry, nil)
+		if err != nil || len(values) != 2 {
+			return nil, fmt.Errorf("Failed to read audit stream entry: %v", entry)
+		}
+		id, err := redis.String(values[0], nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read audit stream entry ID: %s", err.Error())
+		}
+		fields, err := redis.StringMap(values[1], nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read audit stream entry %s fields: %s", id, err.Error())
+		}
+		record := &moira.AuditRecord{}
+		if err := json.Unmarshal([]byte(fields["record"]), record); err != nil {
+			return nil, fmt.Errorf("Failed to parse audit record json %s: %s", fields["record"], err.Error())
+		}
+		record.ID = id
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,29 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+)
+
+// StateSnapshots converts redis DB reply to moira.TriggerStateSnapshot objects array
+func StateSnapshots(rep interface{}, err error) ([]*moira.TriggerStateSnapshot, error) {
+	values, err := redis.ByteSlices(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return make([]*moira.TriggerStateSnapshot, 0), nil
+		}
+		return nil, fmt.Errorf("Failed to read trigger history: %s", err.Error())
+	}
+	snapshots := make([]*moira.TriggerStateSnapshot, 0, len(values))
+	for _, bytes := range values {
+		snapshot := &moira.TriggerStateSnapshot{}
+		if err := json.Unmarshal(bytes, snapshot); err != nil {
+			return nil, fmt.Errorf("Failed to parse trigger state snapshot json %s: %s", string(bytes), err.Error())
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,26 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// Team converts redis DB reply to moira.Team object
+func Team(rep interface{}, err error) (moira.Team, error) {
+	team := moira.Team{}
+	bytes, err := redis.Bytes(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return team, database.ErrNil
+		}
+		return team, fmt.Errorf("Failed to read team: %s", err.Error())
+	}
+	err = json.Unmarshal(bytes, &team)
+	if err != nil {
+		return team, fmt.Errorf("Failed to parse team json %s: %s", string(bytes), err.Error())
+	}
+	return team, nil
+}
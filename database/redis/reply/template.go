@@ -0,0 +1,49 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// TriggerTemplate converts redis DB reply to moira.TriggerTemplate object
+func TriggerTemplate(rep interface{}, err error) (moira.TriggerTemplate, error) {
+	template := moira.TriggerTemplate{}
+	bytes, err := redis.Bytes(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return template, database.ErrNil
+		}
+		return template, fmt.Errorf("Failed to read trigger template: %s", err.Error())
+	}
+	err = json.Unmarshal(bytes, &template)
+	if err != nil {
+		return template, fmt.Errorf("Failed to parse trigger template json %s: %s", string(bytes), err.Error())
+	}
+	return template, nil
+}
+
+// TriggerTemplates converts redis DB reply to moira.TriggerTemplate objects array
+func TriggerTemplates(rep interface{}, err error) ([]*moira.TriggerTemplate, error) {
+	values, err := redis.Values(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return make([]*moira.TriggerTemplate, 0), nil
+		}
+		return nil, fmt.Errorf("Failed to read trigger templates: %s", err.Error())
+	}
+	templates := make([]*moira.TriggerTemplate, len(values))
+	for i, value := range values {
+		template, err2 := TriggerTemplate(value, err)
+		if err2 != nil && err2 != database.ErrNil {
+			return nil, err2
+		} else if err2 == database.ErrNil {
+			templates[i] = nil
+		} else {
+			templates[i] = &template
+		}
+	}
+	return templates, nil
+}
@@ -0,0 +1,49 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// UserToken converts redis DB reply to moira.UserToken object
+func UserToken(rep interface{}, err error) (moira.UserToken, error) {
+	token := moira.UserToken{}
+	bytes, err := redis.Bytes(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return token, database.ErrNil
+		}
+		return token, fmt.Errorf("Failed to read user token: %s", err.Error())
+	}
+	err = json.Unmarshal(bytes, &token)
+	if err != nil {
+		return token, fmt.Errorf("Failed to parse user token json %s: %s", string(bytes), err.Error())
+	}
+	return token, nil
+}
+
+// UserTokens converts redis DB reply to moira.UserToken objects array
+func UserTokens(rep interface{}, err error) ([]*moira.UserToken, error) {
+	values, err := redis.Values(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return make([]*moira.UserToken, 0), nil
+		}
+		return nil, fmt.Errorf("Failed to read user tokens: %s", err.Error())
+	}
+	tokens := make([]*moira.UserToken, len(values))
+	for i, value := range values {
+		token, err2 := UserToken(value, err)
+		if err2 != nil && err2 != database.ErrNil {
+			return nil, err2
+		} else if err2 == database.ErrNil {
+			tokens[i] = nil
+		} else {
+			tokens[i] = &token
+		}
+	}
+	return tokens, nil
+}
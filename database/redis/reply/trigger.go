@@ -11,19 +11,30 @@ import (
 
 // Duty hack for moira.Trigger TTL int64 and stored trigger TTL string compatibility
 type triggerStorageElement struct {
-	ID               string              `json:"id"`
-	Name             string              `json:"name"`
-	Desc             *string             `json:"desc,omitempty"`
-	Targets          []string            `json:"targets"`
-	WarnValue        *float64            `json:"warn_value"`
-	ErrorValue       *float64            `json:"error_value"`
-	Tags             []string            `json:"tags"`
-	TTLState         *string             `json:"ttl_state,omitempty"`
-	Schedule         *moira.ScheduleData `json:"sched,omitempty"`
-	Expression       *string             `json:"expr,omitempty"`
-	PythonExpression *string             `json:"expression,omitempty"`
-	Patterns         []string            `json:"patterns"`
-	TTL              string              `json:"ttl,omitempty"`
+	ID               string                    `json:"id"`
+	Name             string                    `json:"name"`
+	Desc             *string                   `json:"desc,omitempty"`
+	Targets          []string                  `json:"targets"`
+	WarnValue        *float64                  `json:"warn_value"`
+	ErrorValue       *float64                  `json:"error_value"`
+	Tags             []string                  `json:"tags"`
+	TTLState         *string                   `json:"ttl_state,omitempty"`
+	Schedule         *moira.ScheduleData       `json:"sched,omitempty"`
+	Expression       *string                   `json:"expr,omitempty"`
+	PythonExpression *string                   `json:"expression,omitempty"`
+	Patterns         []string                  `json:"patterns"`
+	TTL              string                    `json:"ttl,omitempty"`
+	FiringPoints     int64                     `json:"firing_points,omitempty"`
+	FiringDuration   int64                     `json:"firing_duration,omitempty"`
+	RecoveryPoints   int64                     `json:"recovery_points,omitempty"`
+	RecoveryDuration int64                     `json:"recovery_duration,omitempty"`
+	RemindIntervals  map[string]int64          `json:"remind_intervals,omitempty"`
+	Baseline         *moira.BaselineSettings   `json:"baseline,omitempty"`
+	IsRemote         bool                      `json:"is_remote"`
+	Aggregate        *moira.AggregateSettings  `json:"aggregate,omitempty"`
+	Inhibits         *moira.InhibitionSettings `json:"inhibits,omitempty"`
+	Template         *moira.TemplateLink       `json:"template,omitempty"`
+	TeamID           string                    `json:"team_id,omitempty"`
 }
 
 func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
@@ -41,6 +52,17 @@ func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
 		PythonExpression: storageElement.PythonExpression,
 		Patterns:         storageElement.Patterns,
 		TTL:              getTriggerTTL(storageElement.TTL),
+		FiringPoints:     storageElement.FiringPoints,
+		FiringDuration:   storageElement.FiringDuration,
+		RecoveryPoints:   storageElement.RecoveryPoints,
+		RecoveryDuration: storageElement.RecoveryDuration,
+		RemindIntervals:  storageElement.RemindIntervals,
+		Baseline:         storageElement.Baseline,
+		IsRemote:         storageElement.IsRemote,
+		Aggregate:        storageElement.Aggregate,
+		Inhibits:         storageElement.Inhibits,
+		Template:         storageElement.Template,
+		TeamID:           storageElement.TeamID,
 	}
 }
 
@@ -59,6 +81,17 @@ func toTriggerStorageElement(trigger *moira.Trigger, triggerID string) *triggerS
 		PythonExpression: trigger.PythonExpression,
 		Patterns:         trigger.Patterns,
 		TTL:              getTriggerTTLString(trigger.TTL),
+		FiringPoints:     trigger.FiringPoints,
+		FiringDuration:   trigger.FiringDuration,
+		RecoveryPoints:   trigger.RecoveryPoints,
+		RecoveryDuration: trigger.RecoveryDuration,
+		RemindIntervals:  trigger.RemindIntervals,
+		Baseline:         trigger.Baseline,
+		IsRemote:         trigger.IsRemote,
+		Aggregate:        trigger.Aggregate,
+		Inhibits:         trigger.Inhibits,
+		Template:         trigger.Template,
+		TeamID:           trigger.TeamID,
 	}
 }
 
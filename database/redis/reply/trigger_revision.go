@@ -0,0 +1,47 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// TriggerRevision converts redis DB reply to moira.TriggerRevision object
+func TriggerRevision(rep interface{}, err error) (moira.TriggerRevision, error) {
+	revision := moira.TriggerRevision{}
+	bytes, err := redis.Bytes(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return revision, database.ErrNil
+		}
+		return revision, fmt.Errorf("Failed to read trigger revision: %s", err.Error())
+	}
+	if err := json.Unmarshal(bytes, &revision); err != nil {
+		return revision, fmt.Errorf("Failed to parse trigger revision json %s: %s", string(bytes), err.Error())
+	}
+	return revision, nil
+}
+
+// TriggerRevisions converts redis DB reply to moira.TriggerRevision objects array numbered by their positions
+func TriggerRevisions(rep interface{}, err error) ([]*moira.TriggerRevision, error) {
+	values, err := redis.ByteSlices(rep, err)
+	if err != nil {
+		if err == redis.ErrNil {
+			return make([]*moira.TriggerRevision, 0), nil
+		}
+		return nil, fmt.Errorf("Failed to read trigger revisions: %s", err.Error())
+	}
+	revisions := make([]*moira.TriggerRevision, 0, len(values))
+	for i, bytes := range values {
+		revision := &moira.TriggerRevision{}
+		if err := json.Unmarshal(bytes, revision); err != nil {
+			return nil, fmt.Errorf("Failed to parse trigger revision json %s: %s", string(bytes), err.Error())
+		}
+		revision.Number = int64(i + 1)
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// GetTeam returns team by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTeam(teamID string) (moira.Team, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	team, err := reply.Team(c.Do("GET", teamKey(teamID)))
+	if err != nil {
+		return team, err
+	}
+	team.ID = teamID
+	return team, nil
+}
+
+// SaveTeam writes team data and updates teams lists of its current and former members
+func (connector *DbConnector) SaveTeam(team *moira.Team) error {
+	existing, getTeamErr := connector.GetTeam(team.ID)
+	if getTeamErr != nil && getTeamErr != database.ErrNil {
+		return getTeamErr
+	}
+	bytes, err := json.Marshal(team)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal team: %s", err.Error())
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("SET", teamKey(team.ID), bytes)
+	for login := range existing.Members {
+		if _, ok := team.Members[login]; !ok {
+			c.Send("SREM", userTeamsKey(login), team.ID)
+		}
+	}
+	for login := range team.Members {
+		c.Send("SADD", userTeamsKey(login), team.ID)
+	}
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveTeam deletes team data and removes team from teams lists of its members
+func (connector *DbConnector) RemoveTeam(teamID string) error {
+	existing, err := connector.GetTeam(teamID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("DEL", teamKey(teamID))
+	for login := range existing.Members {
+		c.Send("SREM", userTeamsKey(login), teamID)
+	}
+	c.Send("DEL", teamTriggersKey(teamID))
+	c.Send("DEL", teamContactsKey(teamID))
+	c.Send("DEL", teamSubscriptionsKey(teamID))
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// GetUserTeamIDs returns ids of teams where given user is member
+func (connector *DbConnector) GetUserTeamIDs(userLogin string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	teamIDs, err := redis.Strings(c.Do("SMEMBERS", userTeamsKey(userLogin)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get teams for user login %s: %s", userLogin, err.Error())
+	}
+	return teamIDs, nil
+}
+
+// GetTeamTriggerIDs returns ids of triggers owned by given team
+func (connector *DbConnector) GetTeamTriggerIDs(teamID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	triggerIDs, err := redis.Strings(c.Do("SMEMBERS", teamTriggersKey(teamID)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get triggers of team %s: %s", teamID, err.Error())
+	}
+	return triggerIDs, nil
+}
+
+// GetTeamContactIDs returns ids of contacts owned by given team
+func (connector *DbConnector) GetTeamContactIDs(teamID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	contactIDs, err := redis.Strings(c.Do("SMEMBERS", teamContactsKey(teamID)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get contacts of team %s: %s", teamID, err.Error())
+	}
+	return contactIDs, nil
+}
+
+// GetTeamSubscriptionIDs returns ids of subscriptions owned by given team
+func (connector *DbConnector) GetTeamSubscriptionIDs(teamID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	subscriptionIDs, err := redis.Strings(c.Do("SMEMBERS", teamSubscriptionsKey(teamID)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get subscriptions of team %s: %s", teamID, err.Error())
+	}
+	return subscriptionIDs, nil
+}
+
+func teamKey(teamID string) string {
+	return fmt.Sprintf("moira-team:%s", teamID)
+}
+
+func userTeamsKey(userLogin string) string {
+	return fmt.Sprintf("moira-user-teams:%s", userLogin)
+}
+
+func teamTriggersKey(teamID string) string {
+	return fmt.Sprintf("moira-team-triggers:%s", teamID)
+}
+
+func teamContactsKey(teamID string) string {
+	return fmt.Sprintf("moira-team-contacts:%s", teamID)
+}
+
+func teamSubscriptionsKey(teamID string) string {
+	return fmt.Sprintf("moira-team-subscriptions:%s", teamID)
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestTeams(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	team := moira.Team{ID: "team1", Name: "Team", Members: map[string]string{user1: moira.TeamRoleAdmin, user2: moira.TeamRoleViewer}}
+
+	Convey("Team manipulation", t, func() {
+		dataBase.flush()
+		_, err := dataBase.GetTeam(team.ID)
+		So(err, ShouldResemble, database.ErrNil)
+
+		err = dataBase.SaveTeam(&team)
+		So(err, ShouldBeNil)
+
+		actual, err := dataBase.GetTeam(team.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, team)
+
+		teamIDs, err := dataBase.GetUserTeamIDs(user2)
+		So(err, ShouldBeNil)
+		So(teamIDs, ShouldResemble, []string{team.ID})
+
+		Convey("Removed member loses team", func() {
+			updated := moira.Team{ID: team.ID, Name: team.Name, Members: map[string]string{user1: moira.TeamRoleAdmin}}
+			err = dataBase.SaveTeam(&updated)
+			So(err, ShouldBeNil)
+
+			teamIDs, err = dataBase.GetUserTeamIDs(user2)
+			So(err, ShouldBeNil)
+			So(teamIDs, ShouldBeEmpty)
+
+			teamIDs, err = dataBase.GetUserTeamIDs(user1)
+			So(err, ShouldBeNil)
+			So(teamIDs, ShouldResemble, []string{team.ID})
+		})
+
+		Convey("Removed team is removed from members teams", func() {
+			err = dataBase.RemoveTeam(team.ID)
+			So(err, ShouldBeNil)
+
+			_, err = dataBase.GetTeam(team.ID)
+			So(err, ShouldResemble, database.ErrNil)
+
+			teamIDs, err = dataBase.GetUserTeamIDs(user1)
+			So(err, ShouldBeNil)
+			So(teamIDs, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Team owned objects", t, func() {
+		dataBase.flush()
+		trigger := moira.Trigger{ID: "trigger1", Patterns: []string{"pattern"}, Tags: []string{"tag"}, TeamID: team.ID}
+		contact := moira.ContactData{ID: "contact1", Type: "mail", Value: "mail@example.com", User: user1, TeamID: team.ID}
+		subscription := moira.SubscriptionData{ID: "subscription1", Tags: []string{"tag"}, Contacts: []string{contact.ID}, User: user1, TeamID: team.ID}
+
+		So(dataBase.SaveTrigger(trigger.ID, &trigger), ShouldBeNil)
+		So(dataBase.SaveContact(&contact), ShouldBeNil)
+		So(dataBase.SaveSubscription(&subscription), ShouldBeNil)
+
+		triggerIDs, err := dataBase.GetTeamTriggerIDs(team.ID)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldResemble, []string{trigger.ID})
+		contactIDs, err := dataBase.GetTeamContactIDs(team.ID)
+		So(err, ShouldBeNil)
+		So(contactIDs, ShouldResemble, []string{contact.ID})
+		subscriptionIDs, err := dataBase.GetTeamSubscriptionIDs(team.ID)
+		So(err, ShouldBeNil)
+		So(subscriptionIDs, ShouldResemble, []string{subscription.ID})
+
+		Convey("Moved to other team", func() {
+			trigger.TeamID = "team2"
+			contact.TeamID = "team2"
+			subscription.TeamID = "team2"
+			So(dataBase.SaveTrigger(trigger.ID, &trigger), ShouldBeNil)
+			So(dataBase.SaveContact(&contact), ShouldBeNil)
+			So(dataBase.SaveSubscription(&subscription), ShouldBeNil)
+
+			triggerIDs, _ = dataBase.GetTeamTriggerIDs(team.ID)
+			So(triggerIDs, ShouldBeEmpty)
+			contactIDs, _ = dataBase.GetTeamContactIDs(team.ID)
+			So(contactIDs, ShouldBeEmpty)
+			subscriptionIDs, _ = dataBase.GetTeamSubscriptionIDs(team.ID)
+			So(subscriptionIDs, ShouldBeEmpty)
+
+			triggerIDs, _ = dataBase.GetTeamTriggerIDs("team2")
+			So(triggerIDs, ShouldResemble, []string{trigger.ID})
+			contactIDs, _ = dataBase.GetTeamContactIDs("team2")
+			So(contactIDs, ShouldResemble, []string{contact.ID})
+			subscriptionIDs, _ = dataBase.GetTeamSubscriptionIDs("team2")
+			So(subscriptionIDs, ShouldResemble, []string{subscription.ID})
+		})
+
+		Convey("Removed", func() {
+			So(dataBase.RemoveTrigger(trigger.ID), ShouldBeNil)
+			So(dataBase.RemoveContact(contact.ID), ShouldBeNil)
+			So(dataBase.RemoveSubscription(subscription.ID), ShouldBeNil)
+
+			triggerIDs, _ = dataBase.GetTeamTriggerIDs(team.ID)
+			So(triggerIDs, ShouldBeEmpty)
+			contactIDs, _ = dataBase.GetTeamContactIDs(team.ID)
+			So(contactIDs, ShouldBeEmpty)
+			subscriptionIDs, _ = dataBase.GetTeamSubscriptionIDs(team.ID)
+			So(subscriptionIDs, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestTeamsErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		_, err := dataBase.GetTeam("team1")
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.SaveTeam(&moira.Team{ID: "team1"})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveTeam("team1")
+		So(err, ShouldNotBeNil)
+
+		ids, err := dataBase.GetUserTeamIDs(user1)
+		So(err, ShouldNotBeNil)
+		So(ids, ShouldBeNil)
+
+		ids, err = dataBase.GetTeamTriggerIDs("team1")
+		So(err, ShouldNotBeNil)
+		So(ids, ShouldBeNil)
+
+		ids, err = dataBase.GetTeamContactIDs("team1")
+		So(err, ShouldNotBeNil)
+		So(ids, ShouldBeNil)
+
+		ids, err = dataBase.GetTeamSubscriptionIDs("team1")
+		So(err, ShouldNotBeNil)
+		So(ids, ShouldBeNil)
+	})
+}
@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// GetTriggerTemplate returns trigger template by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTriggerTemplate(templateID string) (moira.TriggerTemplate, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	template, err := reply.TriggerTemplate(c.Do("GET", triggerTemplateKey(templateID)))
+	if err != nil {
+		return template, err
+	}
+	template.ID = templateID
+	return template, nil
+}
+
+// GetTriggerTemplates returns all trigger templates
+func (connector *DbConnector) GetTriggerTemplates() ([]*moira.TriggerTemplate, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	templateIDs, err := redis.Strings(c.Do("SMEMBERS", triggerTemplatesListKey))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get trigger templates list: %s", err.Error())
+	}
+
+	c.Send("MULTI")
+	for _, templateID := range templateIDs {
+		c.Send("GET", triggerTemplateKey(templateID))
+	}
+	templates, err := reply.TriggerTemplates(c.Do("EXEC"))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*moira.TriggerTemplate, 0, len(templates))
+	for i, template := range templates {
+		if template != nil {
+			template.ID = templateIDs[i]
+			result = append(result, template)
+		}
+	}
+	return result, nil
+}
+
+// SaveTriggerTemplate writes trigger template data and adds it to templates list
+func (connector *DbConnector) SaveTriggerTemplate(template *moira.TriggerTemplate) error {
+	bytes, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal trigger template: %s", err.Error())
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("SET", triggerTemplateKey(template.ID), bytes)
+	c.Send("SADD", triggerTemplatesListKey, template.ID)
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveTriggerTemplate deletes trigger template data and removes it from templates list
+func (connector *DbConnector) RemoveTriggerTemplate(templateID string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("DEL", triggerTemplateKey(templateID))
+	c.Send("SREM", triggerTemplatesListKey, templateID)
+	_, err := c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// GetTemplateTriggerIDs returns IDs of triggers rendered from given template
+func (connector *DbConnector) GetTemplateTriggerIDs(templateID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	triggerIDs, err := redis.Strings(c.Do("SMEMBERS", templateTriggersKey(templateID)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get triggers of template %s: %s", templateID, err.Error())
+	}
+	return triggerIDs, nil
+}
+
+var triggerTemplatesListKey = "moira-trigger-templates-list"
+
+func triggerTemplateKey(templateID string) string {
+	return fmt.Sprintf("moira-trigger-template:%s", templateID)
+}
+
+func templateTriggersKey(templateID string) string {
+	return fmt.Sprintf("moira-template-triggers:%s", templateID)
+}
@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestTriggerTemplates(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	template := moira.TriggerTemplate{
+		ID:      "template",
+		Name:    "Service errors",
+		Trigger: json.RawMessage(`{"name":"{{service}} errors","targets":["{{service}}.errors"]}`),
+	}
+
+	Convey("Trigger templates manipulation", t, func() {
+		_, err := dataBase.GetTriggerTemplate(template.ID)
+		So(err, ShouldResemble, database.ErrNil)
+
+		templates, err := dataBase.GetTriggerTemplates()
+		So(err, ShouldBeNil)
+		So(templates, ShouldBeEmpty)
+
+		err = dataBase.SaveTriggerTemplate(&template)
+		So(err, ShouldBeNil)
+
+		actual, err := dataBase.GetTriggerTemplate(template.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, template)
+
+		templates, err = dataBase.GetTriggerTemplates()
+		So(err, ShouldBeNil)
+		So(templates, ShouldResemble, []*moira.TriggerTemplate{&template})
+
+		err = dataBase.RemoveTriggerTemplate(template.ID)
+		So(err, ShouldBeNil)
+
+		_, err = dataBase.GetTriggerTemplate(template.ID)
+		So(err, ShouldResemble, database.ErrNil)
+	})
+
+	Convey("Template triggers are tracked by trigger link", t, func() {
+		trigger := moira.Trigger{
+			ID:       "api-errors",
+			Name:     "api errors",
+			Targets:  []string{"api.errors"},
+			Patterns: []string{"api.errors"},
+			Template: &moira.TemplateLink{ID: template.ID, Parameters: map[string]interface{}{"service": "api"}},
+		}
+		err := dataBase.SaveTrigger(trigger.ID, &trigger)
+		So(err, ShouldBeNil)
+
+		triggerIDs, err := dataBase.GetTemplateTriggerIDs(template.ID)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldResemble, []string{trigger.ID})
+
+		actual, err := dataBase.GetTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+		So(actual.Template, ShouldResemble, trigger.Template)
+
+		trigger.Template = &moira.TemplateLink{ID: "other", Parameters: map[string]interface{}{"service": "api"}}
+		err = dataBase.SaveTrigger(trigger.ID, &trigger)
+		So(err, ShouldBeNil)
+
+		triggerIDs, err = dataBase.GetTemplateTriggerIDs(template.ID)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldBeEmpty)
+
+		triggerIDs, err = dataBase.GetTemplateTriggerIDs("other")
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldResemble, []string{trigger.ID})
+
+		err = dataBase.RemoveTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+
+		triggerIDs, err = dataBase.GetTemplateTriggerIDs("other")
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldBeEmpty)
+	})
+}
+
+func TestTriggerTemplatesErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		_, err := dataBase.GetTriggerTemplate("template")
+		So(err, ShouldNotBeNil)
+
+		templates, err := dataBase.GetTriggerTemplates()
+		So(err, ShouldNotBeNil)
+		So(templates, ShouldBeNil)
+
+		err = dataBase.SaveTriggerTemplate(&moira.TriggerTemplate{ID: "template"})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveTriggerTemplate("template")
+		So(err, ShouldNotBeNil)
+
+		ids, err := dataBase.GetTemplateTriggerIDs("template")
+		So(err, ShouldNotBeNil)
+		So(ids, ShouldBeNil)
+	})
+}
@@ -26,7 +26,41 @@ func (connector *DbConnector) SetTriggerThrottling(triggerID string, next time.T
 	return err
 }
 
-// DeleteTriggerThrottling deletes throttling and scheduled notifications delay for given triggerID
+// GetSubscriptionThrottling get throttling delay of given subscription notifications for given triggerID
+func (connector *DbConnector) GetSubscriptionThrottling(triggerID, subscriptionID string) (time.Time, time.Time) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	next, _ := redis.Int64(c.Do("HGET", notifierSubscriptionsNextKey(triggerID), subscriptionID))
+	beginning, _ := redis.Int64(c.Do("GET", notifierThrottlingBeginningKey(triggerID)))
+
+	return time.Unix(next, 0), time.Unix(beginning, 0)
+}
+
+// setSubscriptionThrottlingScript stores subscription throttling and moves trigger throttling forward in one step,
+// so concurrent senders can not move trigger throttling back
+var setSubscriptionThrottlingScript = redis.NewScript(2, `
+redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+if tonumber(ARGV[2]) > tonumber(redis.call("GET", KEYS[1]) or "0") then
+	redis.call("SET", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// SetSubscriptionThrottling store throttling delay of given subscription notifications for given triggerID.
+// Trigger throttling is moved forward if it is earlier than subscription one
+func (connector *DbConnector) SetSubscriptionThrottling(triggerID, subscriptionID string, next time.Time) error {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	_, err := setSubscriptionThrottlingScript.Do(c, notifierNextKey(triggerID), notifierSubscriptionsNextKey(triggerID), subscriptionID, next.Unix())
+	if err != nil {
+		return fmt.Errorf("Failed to set subscription throttling: %s", err.Error())
+	}
+	return nil
+}
+
+// DeleteTriggerThrottling deletes throttling and scheduled notifications delay for given triggerID and all its subscriptions
 func (connector *DbConnector) DeleteTriggerThrottling(triggerID string) error {
 	c := connector.pool.Get()
 	defer c.Close()
@@ -34,6 +68,7 @@ func (connector *DbConnector) DeleteTriggerThrottling(triggerID string) error {
 	c.Send("MULTI")
 	c.Send("SET", notifierThrottlingBeginningKey(triggerID), time.Now().Unix())
 	c.Send("DEL", notifierNextKey(triggerID))
+	c.Send("DEL", notifierSubscriptionsNextKey(triggerID))
 	_, err := c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("Failed to EXEC: %s", err.Error())
@@ -48,3 +83,7 @@ func notifierThrottlingBeginningKey(triggerID string) string {
 func notifierNextKey(triggerID string) string {
 	return fmt.Sprintf("moira-notifier-next:%s", triggerID)
 }
+
+func notifierSubscriptionsNextKey(triggerID string) string {
+	return fmt.Sprintf("moira-notifier-next-subscriptions:%s", triggerID)
+}
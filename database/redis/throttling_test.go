@@ -9,6 +9,40 @@ import (
 	"time"
 )
 
+func TestSubscriptionThrottling(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Subscription throttling should be stored per subscription", t, func() {
+		dataBase.flush()
+		triggerID := "trigger-1"
+		next := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+
+		So(dataBase.SetSubscriptionThrottling(triggerID, "subscription-1", next), ShouldBeNil)
+		actualNext, _ := dataBase.GetSubscriptionThrottling(triggerID, "subscription-1")
+		So(actualNext, ShouldResemble, next)
+		actualNext, _ = dataBase.GetSubscriptionThrottling(triggerID, "subscription-2")
+		So(actualNext, ShouldResemble, time.Unix(0, 0))
+
+		Convey("Trigger throttling should be the latest one", func() {
+			So(dataBase.SetSubscriptionThrottling(triggerID, "subscription-2", next.Add(-time.Minute)), ShouldBeNil)
+			triggerNext, _ := dataBase.GetTriggerThrottling(triggerID)
+			So(triggerNext, ShouldResemble, next)
+			actualNext, _ := dataBase.GetSubscriptionThrottling(triggerID, "subscription-2")
+			So(actualNext, ShouldResemble, next.Add(-time.Minute))
+		})
+
+		Convey("Deleting trigger throttling should reset subscriptions throttling", func() {
+			So(dataBase.DeleteTriggerThrottling(triggerID), ShouldBeNil)
+			actualNext, beginning := dataBase.GetSubscriptionThrottling(triggerID, "subscription-1")
+			So(actualNext, ShouldResemble, time.Unix(0, 0))
+			So(beginning.Unix(), ShouldBeGreaterThan, 0)
+		})
+	})
+}
+
 func TestThrottlingErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewDatabase(logger, emptyConfig)
@@ -24,5 +58,12 @@ func TestThrottlingErrorConnection(t *testing.T) {
 
 		err = dataBase.DeleteTriggerThrottling("")
 		So(err, ShouldNotBeNil)
+
+		t1, t2 = dataBase.GetSubscriptionThrottling("", "")
+		So(t1, ShouldResemble, time.Unix(0, 0))
+		So(t2, ShouldResemble, time.Unix(0, 0))
+
+		err = dataBase.SetSubscriptionThrottling("", "", time.Now())
+		So(err, ShouldNotBeNil)
 	})
 }
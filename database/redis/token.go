@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// GetUserToken returns user token by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetUserToken(tokenID string) (moira.UserToken, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	token, err := reply.UserToken(c.Do("GET", userTokenKey(tokenID)))
+	if err != nil {
+		return token, err
+	}
+	token.ID = tokenID
+	return token, nil
+}
+
+// GetUserTokens returns all tokens issued to given user
+func (connector *DbConnector) GetUserTokens(userLogin string) ([]*moira.UserToken, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	tokenIDs, err := redis.Strings(c.Do("SMEMBERS", userTokensKey(userLogin)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get tokens for user login %s: %s", userLogin, err.Error())
+	}
+
+	c.Send("MULTI")
+	for _, tokenID := range tokenIDs {
+		c.Send("GET", userTokenKey(tokenID))
+	}
+	tokens, err := reply.UserTokens(c.Do("EXEC"))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*moira.UserToken, 0, len(tokens))
+	for i, token := range tokens {
+		if token != nil {
+			token.ID = tokenIDs[i]
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+// SaveUserToken writes user token data and adds it to user tokens list
+func (connector *DbConnector) SaveUserToken(token *moira.UserToken) error {
+	bytes, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user token: %s", err.Error())
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("SET", userTokenKey(token.ID), bytes)
+	c.Send("SADD", userTokensKey(token.User), token.ID)
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveUserToken deletes user token data and removes it from user tokens list
+func (connector *DbConnector) RemoveUserToken(tokenID string) error {
+	existing, err := connector.GetUserToken(tokenID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil
+		}
+		return err
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("DEL", userTokenKey(tokenID))
+	c.Send("SREM", userTokensKey(existing.User), tokenID)
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+func userTokenKey(tokenID string) string {
+	return fmt.Sprintf("moira-user-token:%s", tokenID)
+}
+
+func userTokensKey(userLogin string) string {
+	return fmt.Sprintf("moira-user-tokens:%s", userLogin)
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestUserTokens(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	token := moira.UserToken{ID: "token1", User: user1, Name: "ci", Hash: moira.HashTokenSecret("secret"), CreatedAt: 1502719200}
+
+	Convey("User tokens manipulation", t, func() {
+		_, err := dataBase.GetUserToken(token.ID)
+		So(err, ShouldResemble, database.ErrNil)
+
+		tokens, err := dataBase.GetUserTokens(user1)
+		So(err, ShouldBeNil)
+		So(tokens, ShouldBeEmpty)
+
+		err = dataBase.SaveUserToken(&token)
+		So(err, ShouldBeNil)
+
+		actual, err := dataBase.GetUserToken(token.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, token)
+
+		tokens, err = dataBase.GetUserTokens(user1)
+		So(err, ShouldBeNil)
+		So(tokens, ShouldResemble, []*moira.UserToken{&token})
+
+		tokens, err = dataBase.GetUserTokens(user2)
+		So(err, ShouldBeNil)
+		So(tokens, ShouldBeEmpty)
+
+		err = dataBase.RemoveUserToken(token.ID)
+		So(err, ShouldBeNil)
+
+		_, err = dataBase.GetUserToken(token.ID)
+		So(err, ShouldResemble, database.ErrNil)
+
+		tokens, err = dataBase.GetUserTokens(user1)
+		So(err, ShouldBeNil)
+		So(tokens, ShouldBeEmpty)
+
+		err = dataBase.RemoveUserToken(token.ID)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestUserTokensErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		_, err := dataBase.GetUserToken("token1")
+		So(err, ShouldNotBeNil)
+
+		tokens, err := dataBase.GetUserTokens(user1)
+		So(err, ShouldNotBeNil)
+		So(tokens, ShouldBeNil)
+
+		err = dataBase.SaveUserToken(&moira.UserToken{ID: "token1", User: user1})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveUserToken("token1")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -22,6 +22,39 @@ func (connector *DbConnector) GetTriggerIDs() ([]string, error) {
 	return triggerIds, nil
 }
 
+// GetRemoteTriggerIDs gets all IDs of triggers checked against remote graphite
+func (connector *DbConnector) GetRemoteTriggerIDs() ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", remoteTriggersListKey))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get remote triggers-list: %s", err.Error())
+	}
+	return triggerIds, nil
+}
+
+// GetAggregateTriggerIDs gets all IDs of triggers evaluated over states of other triggers
+func (connector *DbConnector) GetAggregateTriggerIDs() ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", aggregateTriggersListKey))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get aggregate triggers-list: %s", err.Error())
+	}
+	return triggerIds, nil
+}
+
+// GetInhibitingTriggerIDs gets all IDs of triggers suppressing events of other triggers
+func (connector *DbConnector) GetInhibitingTriggerIDs() ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", inhibitingTriggersListKey))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get inhibiting triggers-list: %s", err.Error())
+	}
+	return triggerIds, nil
+}
+
 // GetTrigger gets trigger and trigger tags by given ID and return it in merged object
 func (connector *DbConnector) GetTrigger(triggerID string) (moira.Trigger, error) {
 	c := connector.pool.Get()
@@ -117,9 +150,36 @@ func (connector *DbConnector) SaveTrigger(triggerID string, trigger *moira.Trigg
 			c.Send("SREM", triggerTagsKey(triggerID), tag)
 			c.Send("SREM", tagTriggersKey(tag), triggerID)
 		}
+		if existing.Template != nil && (trigger.Template == nil || trigger.Template.ID != existing.Template.ID) {
+			c.Send("SREM", templateTriggersKey(existing.Template.ID), triggerID)
+		}
+		if existing.TeamID != "" && existing.TeamID != trigger.TeamID {
+			c.Send("SREM", teamTriggersKey(existing.TeamID), triggerID)
+		}
 	}
 	c.Do("SET", triggerKey(triggerID), bytes)
 	c.Do("SADD", triggersListKey, triggerID)
+	if trigger.IsRemote {
+		c.Do("SADD", remoteTriggersListKey, triggerID)
+	} else {
+		c.Do("SREM", remoteTriggersListKey, triggerID)
+	}
+	if trigger.Aggregate != nil {
+		c.Do("SADD", aggregateTriggersListKey, triggerID)
+	} else {
+		c.Do("SREM", aggregateTriggersListKey, triggerID)
+	}
+	if trigger.Inhibits != nil {
+		c.Do("SADD", inhibitingTriggersListKey, triggerID)
+	} else {
+		c.Do("SREM", inhibitingTriggersListKey, triggerID)
+	}
+	if trigger.Template != nil {
+		c.Send("SADD", templateTriggersKey(trigger.Template.ID), triggerID)
+	}
+	if trigger.TeamID != "" {
+		c.Send("SADD", teamTriggersKey(trigger.TeamID), triggerID)
+	}
 	for _, pattern := range trigger.Patterns {
 		c.Do("SADD", patternsListKey, pattern)
 		c.Do("SADD", patternTriggersKey(pattern), triggerID)
@@ -166,6 +226,15 @@ func (connector *DbConnector) RemoveTrigger(triggerID string) error {
 	c.Send("DEL", triggerKey(triggerID))
 	c.Send("DEL", triggerTagsKey(triggerID))
 	c.Send("SREM", triggersListKey, triggerID)
+	c.Send("SREM", remoteTriggersListKey, triggerID)
+	c.Send("SREM", aggregateTriggersListKey, triggerID)
+	c.Send("SREM", inhibitingTriggersListKey, triggerID)
+	if trigger.Template != nil {
+		c.Send("SREM", templateTriggersKey(trigger.Template.ID), triggerID)
+	}
+	if trigger.TeamID != "" {
+		c.Send("SREM", teamTriggersKey(trigger.TeamID), triggerID)
+	}
 	for _, tag := range trigger.Tags {
 		c.Send("SREM", tagTriggersKey(tag), triggerID)
 	}
@@ -274,6 +343,9 @@ func leftJoin(left, right []string) []string {
 }
 
 var triggersListKey = "moira-triggers-list"
+var remoteTriggersListKey = "moira-remote-triggers-list"
+var aggregateTriggersListKey = "moira-aggregate-triggers-list"
+var inhibitingTriggersListKey = "moira-inhibiting-triggers-list"
 
 func triggerKey(triggerID string) string {
 	return fmt.Sprintf("moira-trigger:%s", triggerID)
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+var triggerHistoryTTL int64 = 3600 * 24 * 30
+var triggerHistoryMaxSize int64 = 10000
+
+// AddTriggerStateSnapshot adds trigger state snapshot to trigger history and deletes snapshots who are older than 30 days,
+// history of one trigger keeps no more than 10000 latest snapshots
+func (connector *DbConnector) AddTriggerStateSnapshot(triggerID string, snapshot *moira.TriggerStateSnapshot) error {
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")
+	c.Send("ZADD", triggerHistoryKey(triggerID), snapshot.Timestamp, bytes)
+	c.Send("ZREMRANGEBYSCORE", triggerHistoryKey(triggerID), "-inf", time.Now().Unix()-triggerHistoryTTL)
+	c.Send("ZREMRANGEBYRANK", triggerHistoryKey(triggerID), 0, -triggerHistoryMaxSize-1)
+	_, err = c.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// GetTriggerStateHistory gets trigger state snapshots saved in given interval ordered by time.
+// The latest snapshot saved before interval goes first, so trigger and metrics states at interval start are known
+func (connector *DbConnector) GetTriggerStateHistory(triggerID string, from int64, to int64) ([]*moira.TriggerStateSnapshot, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")
+	c.Send("ZREVRANGEBYSCORE", triggerHistoryKey(triggerID), fmt.Sprintf("(%d", from), "-inf", "LIMIT", 0, 1)
+	c.Send("ZRANGEBYSCORE", triggerHistoryKey(triggerID), from, to)
+	rawResponse, err := redis.Values(c.Do("EXEC"))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	previous, err := reply.StateSnapshots(rawResponse[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := reply.StateSnapshots(rawResponse[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(previous, snapshots...), nil
+}
+
+func triggerHistoryKey(triggerID string) string {
+	return fmt.Sprintf("moira-trigger-history:%s", triggerID)
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestTriggerStateHistory(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+	snapshots := []*moira.TriggerStateSnapshot{
+		{Timestamp: now - 300, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+		{Timestamp: now - 200, State: "OK", Score: 100, Metrics: map[string]string{"m1": "ERROR"}},
+		{Timestamp: now - 100, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+	}
+
+	Convey("Trigger state history manipulation", t, func() {
+		for _, snapshot := range snapshots {
+			err := dataBase.AddTriggerStateSnapshot("trigger", snapshot)
+			So(err, ShouldBeNil)
+		}
+
+		Convey("Get history with snapshot before interval", func() {
+			actual, err := dataBase.GetTriggerStateHistory("trigger", now-250, now)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, snapshots)
+
+			actual, err = dataBase.GetTriggerStateHistory("trigger", now-200, now-200)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, snapshots[:2])
+		})
+
+		Convey("Get empty history", func() {
+			actual, err := dataBase.GetTriggerStateHistory("trigger", now-1000, now-500)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+
+			actual, err = dataBase.GetTriggerStateHistory("other", now-1000, now)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+		})
+
+		Convey("Old snapshots are removed", func() {
+			err := dataBase.AddTriggerStateSnapshot("old", &moira.TriggerStateSnapshot{Timestamp: now - triggerHistoryTTL - 1, State: "OK"})
+			So(err, ShouldBeNil)
+			actual, err := dataBase.GetTriggerStateHistory("old", 0, now)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+		})
+
+		Convey("History is removed with last check", func() {
+			err := dataBase.RemoveTriggerLastCheck("trigger")
+			So(err, ShouldBeNil)
+			actual, err := dataBase.GetTriggerStateHistory("trigger", 0, now)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestTriggerStateHistoryErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		err := dataBase.AddTriggerStateSnapshot("trigger", &moira.TriggerStateSnapshot{State: "OK"})
+		So(err, ShouldNotBeNil)
+
+		actual, err := dataBase.GetTriggerStateHistory("trigger", 0, 100)
+		So(err, ShouldNotBeNil)
+		So(actual, ShouldBeNil)
+	})
+}
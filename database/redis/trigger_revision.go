@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// AddTriggerRevision appends revision to trigger revisions list and sets its number. Revisions are kept
+// after trigger removal, so removed trigger can be restored
+func (connector *DbConnector) AddTriggerRevision(triggerID string, revision *moira.TriggerRevision) error {
+	bytes, err := json.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal trigger revision: %s", err.Error())
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	number, err := redis.Int64(c.Do("RPUSH", triggerRevisionsKey(triggerID), bytes))
+	if err != nil {
+		return fmt.Errorf("Failed to RPUSH: %s", err.Error())
+	}
+	revision.Number = number
+	return nil
+}
+
+// GetTriggerRevisions gets all trigger revisions ordered by number
+func (connector *DbConnector) GetTriggerRevisions(triggerID string) ([]*moira.TriggerRevision, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	return reply.TriggerRevisions(c.Do("LRANGE", triggerRevisionsKey(triggerID), 0, -1))
+}
+
+// GetTriggerRevision gets trigger revision by number, if no value, return database.ErrNil error
+func (connector *DbConnector) GetTriggerRevision(triggerID string, number int64) (moira.TriggerRevision, error) {
+	if number < 1 {
+		return moira.TriggerRevision{}, database.ErrNil
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	revision, err := reply.TriggerRevision(c.Do("LINDEX", triggerRevisionsKey(triggerID), number-1))
+	if err != nil {
+		return revision, err
+	}
+	revision.Number = number
+	return revision, nil
+}
+
+func triggerRevisionsKey(triggerID string) string {
+	return fmt.Sprintf("moira-trigger-revisions:%s", triggerID)
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestTriggerRevisions(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Trigger revisions manipulation", t, func() {
+		dataBase.flush()
+		revisions, err := dataBase.GetTriggerRevisions("trigger")
+		So(err, ShouldBeNil)
+		So(revisions, ShouldBeEmpty)
+
+		first := &moira.TriggerRevision{Timestamp: 100, User: user1, Trigger: moira.Trigger{ID: "trigger", Name: "first", Tags: []string{"tag"}, Patterns: []string{}}}
+		second := &moira.TriggerRevision{Timestamp: 200, User: user2, Trigger: moira.Trigger{ID: "trigger", Name: "second", Tags: []string{"tag"}, Patterns: []string{}}}
+		So(dataBase.AddTriggerRevision("trigger", first), ShouldBeNil)
+		So(first.Number, ShouldEqual, 1)
+		So(dataBase.AddTriggerRevision("trigger", second), ShouldBeNil)
+		So(second.Number, ShouldEqual, 2)
+
+		revisions, err = dataBase.GetTriggerRevisions("trigger")
+		So(err, ShouldBeNil)
+		So(revisions, ShouldResemble, []*moira.TriggerRevision{first, second})
+
+		revision, err := dataBase.GetTriggerRevision("trigger", 1)
+		So(err, ShouldBeNil)
+		So(revision, ShouldResemble, *first)
+
+		_, err = dataBase.GetTriggerRevision("trigger", 3)
+		So(err, ShouldResemble, database.ErrNil)
+		_, err = dataBase.GetTriggerRevision("trigger", 0)
+		So(err, ShouldResemble, database.ErrNil)
+
+		Convey("Revisions are kept after trigger removal", func() {
+			So(dataBase.RemoveTrigger("trigger"), ShouldBeNil)
+			revisions, err := dataBase.GetTriggerRevisions("trigger")
+			So(err, ShouldBeNil)
+			So(revisions, ShouldHaveLength, 2)
+		})
+	})
+}
+
+func TestTriggerRevisionsErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		err := dataBase.AddTriggerRevision("trigger", &moira.TriggerRevision{})
+		So(err, ShouldNotBeNil)
+
+		revisions, err := dataBase.GetTriggerRevisions("trigger")
+		So(err, ShouldNotBeNil)
+		So(revisions, ShouldBeNil)
+
+		_, err = dataBase.GetTriggerRevision("trigger", 1)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -2,6 +2,10 @@ package moira
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
 	"strings"
@@ -33,6 +37,7 @@ type NotificationEvent struct {
 	ContactID      string   `json:"contactId,omitempty"`
 	OldState       string   `json:"old_state"`
 	Message        *string  `json:"msg,omitempty"`
+	InhibitedBy    string   `json:"inhibited_by,omitempty"`
 }
 
 // NotificationEvents represents slice of NotificationEvent
@@ -51,21 +56,66 @@ type TriggerData struct {
 
 // ContactData represents contact object
 type ContactData struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
-	ID    string `json:"id"`
-	User  string `json:"user"`
+	Type           string `json:"type"`
+	Value          string `json:"value"`
+	ID             string `json:"id"`
+	User           string `json:"user"`
+	TeamID         string `json:"team_id,omitempty"`
+	DigestInterval int64  `json:"digest_interval,omitempty"`
+}
+
+// UserToken represents personal API token of user, token secret is shown once on creation and only its hash is stored
+type UserToken struct {
+	ID        string `json:"id"`
+	User      string `json:"user"`
+	Name      string `json:"name"`
+	Hash      string `json:"hash"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// HashTokenSecret returns hex encoded SHA-256 hash of user token secret
+func HashTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsSecretValid checks if given secret matches stored token hash
+func (token *UserToken) IsSecretValid(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(token.Hash), []byte(HashTokenSecret(secret))) == 1
+}
+
+// DigestItem represents events of single trigger sent to contact as part of digest
+type DigestItem struct {
+	Trigger   TriggerData        `json:"trigger"`
+	Events    NotificationEvents `json:"events"`
+	Throttled bool               `json:"throttled"`
 }
 
 // SubscriptionData represent user subscription
 type SubscriptionData struct {
-	Contacts          []string     `json:"contacts"`
-	Tags              []string     `json:"tags"`
-	Schedule          ScheduleData `json:"sched"`
-	ID                string       `json:"id"`
-	Enabled           bool         `json:"enabled"`
-	ThrottlingEnabled bool         `json:"throttling"`
-	User              string       `json:"user"`
+	Contacts          []string          `json:"contacts"`
+	Tags              []string          `json:"tags"`
+	Schedule          ScheduleData      `json:"sched"`
+	ID                string            `json:"id"`
+	Enabled           bool              `json:"enabled"`
+	ThrottlingEnabled bool              `json:"throttling"`
+	ThrottlingPolicy  *ThrottlingPolicy `json:"throttling_policy,omitempty"`
+	User              string            `json:"user"`
+	TeamID            string            `json:"team_id,omitempty"`
+}
+
+// ThrottlingPolicy represents subscription notifications throttling settings.
+// Levels are checked in given order and the first matched level sets notifications delay
+type ThrottlingPolicy struct {
+	Levels   []ThrottlingLevel `json:"levels"`
+	MaxDelay int64             `json:"max_delay,omitempty"`
+}
+
+// ThrottlingLevel delays notifications for Delay seconds if trigger has at least Count events in last Window seconds
+type ThrottlingLevel struct {
+	Window int64 `json:"window"`
+	Count  int64 `json:"count"`
+	Delay  int64 `json:"delay"`
 }
 
 // ScheduleData represent subscription schedule
@@ -111,19 +161,231 @@ type MetricValue struct {
 
 // Trigger represents trigger data object
 type Trigger struct {
-	ID               string        `json:"id"`
-	Name             string        `json:"name"`
-	Desc             *string       `json:"desc,omitempty"`
-	Targets          []string      `json:"targets"`
-	WarnValue        *float64      `json:"warn_value"`
-	ErrorValue       *float64      `json:"error_value"`
-	Tags             []string      `json:"tags"`
-	TTLState         *string       `json:"ttl_state,omitempty"`
-	TTL              int64         `json:"ttl,omitempty"`
-	Schedule         *ScheduleData `json:"sched,omitempty"`
-	Expression       *string       `json:"expression,omitempty"`
-	PythonExpression *string       `json:"python_expression,omitempty"`
-	Patterns         []string      `json:"patterns"`
+	ID               string              `json:"id"`
+	Name             string              `json:"name"`
+	Desc             *string             `json:"desc,omitempty"`
+	Targets          []string            `json:"targets"`
+	WarnValue        *float64            `json:"warn_value"`
+	ErrorValue       *float64            `json:"error_value"`
+	Tags             []string            `json:"tags"`
+	TTLState         *string             `json:"ttl_state,omitempty"`
+	TTL              int64               `json:"ttl,omitempty"`
+	Schedule         *ScheduleData       `json:"sched,omitempty"`
+	Expression       *string             `json:"expression,omitempty"`
+	PythonExpression *string             `json:"python_expression,omitempty"`
+	Patterns         []string            `json:"patterns"`
+	FiringPoints     int64               `json:"firing_points,omitempty"`
+	FiringDuration   int64               `json:"firing_duration,omitempty"`
+	RecoveryPoints   int64               `json:"recovery_points,omitempty"`
+	RecoveryDuration int64               `json:"recovery_duration,omitempty"`
+	RemindIntervals  map[string]int64    `json:"remind_intervals,omitempty"`
+	Baseline         *BaselineSettings   `json:"baseline,omitempty"`
+	IsRemote         bool                `json:"is_remote"`
+	Aggregate        *AggregateSettings  `json:"aggregate,omitempty"`
+	Inhibits         *InhibitionSettings `json:"inhibits,omitempty"`
+	Template         *TemplateLink       `json:"template,omitempty"`
+	TeamID           string              `json:"team_id,omitempty"`
+}
+
+// TriggerRevision is trigger definition saved by user, revisions of trigger are numbered from 1 in order of saving
+type TriggerRevision struct {
+	Number    int64   `json:"number"`
+	Timestamp int64   `json:"timestamp"`
+	User      string  `json:"user"`
+	Trigger   Trigger `json:"trigger"`
+}
+
+// AggregateSettings turns trigger into aggregate one, which state is evaluated by expression over states of other triggers.
+// Input triggers are given by IDs and by tags, trigger is selected by tags if it has all of them
+type AggregateSettings struct {
+	TriggerIDs []string `json:"trigger_ids,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Expression string   `json:"expression"`
+}
+
+// IsDependentOn checks if given trigger is input of aggregate trigger
+func (aggregate *AggregateSettings) IsDependentOn(trigger *Trigger) bool {
+	return isTriggerSelected(aggregate.TriggerIDs, aggregate.Tags, trigger)
+}
+
+// InhibitionSettings makes trigger parent one: while it is in one of given states events of triggers
+// given by IDs and triggers having all given tags are suppressed
+type InhibitionSettings struct {
+	TriggerIDs []string `json:"trigger_ids,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	States     []string `json:"states"`
+}
+
+// IsInhibiting checks if given trigger is inhibited by parent trigger being in given state
+func (inhibition *InhibitionSettings) IsInhibiting(trigger *Trigger, parentState string) bool {
+	for _, state := range inhibition.States {
+		if state == parentState {
+			return inhibition.IsSuppressing(trigger)
+		}
+	}
+	return false
+}
+
+// IsSuppressing checks if events of given trigger are suppressed while parent trigger is in one of inhibiting states
+func (inhibition *InhibitionSettings) IsSuppressing(trigger *Trigger) bool {
+	return isTriggerSelected(inhibition.TriggerIDs, inhibition.Tags, trigger)
+}
+
+// isTriggerSelected checks if trigger is given by ID or has all given tags
+func isTriggerSelected(triggerIDs []string, tags []string, trigger *Trigger) bool {
+	for _, triggerID := range triggerIDs {
+		if triggerID == trigger.ID {
+			return true
+		}
+	}
+	if len(tags) == 0 {
+		return false
+	}
+	triggerTags := make(map[string]bool, len(trigger.Tags))
+	for _, tag := range trigger.Tags {
+		triggerTags[tag] = true
+	}
+	for _, tag := range tags {
+		if !triggerTags[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// TriggerTemplate represents named trigger with {{parameter}} placeholders in its fields
+type TriggerTemplate struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Trigger json.RawMessage `json:"trigger"`
+}
+
+// TemplateLink links trigger to template and parameters it was rendered with
+type TemplateLink struct {
+	ID         string                 `json:"id"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// Team member roles, every role has all permissions of roles listed before it
+const (
+	TeamRoleViewer = "viewer"
+	TeamRoleEditor = "editor"
+	TeamRoleAdmin  = "admin"
+)
+
+var teamRoleLevels = map[string]int{
+	TeamRoleViewer: 1,
+	TeamRoleEditor: 2,
+	TeamRoleAdmin:  3,
+}
+
+// Team represents group of users sharing triggers, contacts and subscriptions. Members maps user login to role:
+// viewers see team contacts and subscriptions, editors change team objects and admins manage team members
+type Team struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Members map[string]string `json:"members"`
+}
+
+// IsValidTeamRole checks if given role is one of known team roles
+func IsValidTeamRole(role string) bool {
+	_, ok := teamRoleLevels[role]
+	return ok
+}
+
+// HasRole checks if user is team member with given or higher role
+func (team *Team) HasRole(login string, role string) bool {
+	level, ok := teamRoleLevels[team.Members[login]]
+	return ok && level >= teamRoleLevels[role]
+}
+
+// Audited configuration entities
+const (
+	AuditEntityTrigger         = "trigger"
+	AuditEntitySubscription    = "subscription"
+	AuditEntityContact         = "contact"
+	AuditEntityTag             = "tag"
+	AuditEntityMaintenance     = "maintenance"
+	AuditEntityThrottling      = "throttling"
+	AuditEntityTemplate        = "template"
+	AuditEntityAcknowledgement = "acknowledgement"
+)
+
+// Audited configuration change actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditRecord represents configuration change made by user through API. Before and After are JSON states
+// of changed entity, Before is empty for created entities and After is empty for deleted ones
+type AuditRecord struct {
+	ID        string          `json:"id"`
+	Timestamp int64           `json:"timestamp"`
+	User      string          `json:"user"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// NewAuditRecord creates record of change of entity made by user. Action is derived from given states:
+// entity without before state is created and entity without after state is deleted, nil states are not saved
+func NewAuditRecord(userLogin, entity, entityID string, before, after interface{}, timestamp int64) (*AuditRecord, error) {
+	record := &AuditRecord{
+		Timestamp: timestamp,
+		User:      userLogin,
+		Entity:    entity,
+		EntityID:  entityID,
+	}
+	var err error
+	if record.Before, err = marshalAuditState(before); err != nil {
+		return nil, err
+	}
+	if record.After, err = marshalAuditState(after); err != nil {
+		return nil, err
+	}
+	switch {
+	case record.Before == nil:
+		record.Action = AuditActionCreate
+	case record.After == nil:
+		record.Action = AuditActionDelete
+	default:
+		record.Action = AuditActionUpdate
+	}
+	return record, nil
+}
+
+func marshalAuditState(state interface{}) (json.RawMessage, error) {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal audit state: %s", err.Error())
+	}
+	if string(bytes) == "null" {
+		return nil, nil
+	}
+	return bytes, nil
+}
+
+// Baseline methods used by anomaly detection triggers
+const (
+	BaselineMeanStdDev = "stddev"
+	BaselineMedianMAD  = "mad"
+)
+
+// BaselineSettings turns trigger into anomaly detection one. Current value is compared with values
+// of the same series taken Period seconds ago, 2*Period seconds ago and so on up to Periods times
+type BaselineSettings struct {
+	Method  string `json:"method"`
+	Period  int64  `json:"period"`
+	Periods int64  `json:"periods"`
+}
+
+// GetHistoryDuration returns how far in past baseline values are taken from
+func (baseline *BaselineSettings) GetHistoryDuration() int64 {
+	return baseline.Period * baseline.Periods
 }
 
 // TriggerCheck represent trigger data with last check data and check timestamp
@@ -135,23 +397,44 @@ type TriggerCheck struct {
 
 // CheckData represent last trigger check data
 type CheckData struct {
-	Metrics        map[string]MetricState `json:"metrics"`
-	Score          int64                  `json:"score"`
-	State          string                 `json:"state"`
-	Timestamp      int64                  `json:"timestamp,omitempty"`
-	EventTimestamp int64                  `json:"event_timestamp,omitempty"`
-	Suppressed     bool                   `json:"suppressed,omitempty"`
-	Message        string                 `json:"msg,omitempty"`
+	Metrics         map[string]MetricState `json:"metrics"`
+	Score           int64                  `json:"score"`
+	State           string                 `json:"state"`
+	Timestamp       int64                  `json:"timestamp,omitempty"`
+	EventTimestamp  int64                  `json:"event_timestamp,omitempty"`
+	Suppressed      bool                   `json:"suppressed,omitempty"`
+	Message         string                 `json:"msg,omitempty"`
+	Acknowledgement *Acknowledgement       `json:"ack,omitempty"`
+}
+
+// TriggerStateSnapshot represents trigger and its metrics states and score saved to trigger history on state transition
+type TriggerStateSnapshot struct {
+	Timestamp int64             `json:"timestamp"`
+	State     string            `json:"state"`
+	Score     int64             `json:"score"`
+	Metrics   map[string]string `json:"metrics"`
 }
 
 // MetricState represent metric state data for given timestamp
 type MetricState struct {
-	EventTimestamp int64    `json:"event_timestamp"`
-	State          string   `json:"state"`
-	Suppressed     bool     `json:"suppressed"`
-	Timestamp      int64    `json:"timestamp"`
-	Value          *float64 `json:"value,omitempty"`
-	Maintenance    int64    `json:"maintenance,omitempty"`
+	EventTimestamp  int64            `json:"event_timestamp"`
+	State           string           `json:"state"`
+	Suppressed      bool             `json:"suppressed"`
+	Timestamp       int64            `json:"timestamp"`
+	Value           *float64         `json:"value,omitempty"`
+	Maintenance     int64            `json:"maintenance,omitempty"`
+	PendingState    string           `json:"pending_state,omitempty"`
+	PendingSince    int64            `json:"pending_since,omitempty"`
+	Acknowledgement *Acknowledgement `json:"ack,omitempty"`
+}
+
+// Acknowledgement represents user confirmation that bad state is being handled.
+// Reminders and repeated notifications are not sent until acknowledged state changes
+type Acknowledgement struct {
+	User      string `json:"user"`
+	Comment   string `json:"comment,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	State     string `json:"state"`
 }
 
 // MetricEvent represent filter metric event
@@ -230,6 +513,35 @@ func (eventData NotificationEvent) String() string {
 	return fmt.Sprintf("TriggerId: %s, Metric: %s, Value: %v, OldState: %s, State: %s, Message: '%s', Timestamp: %v", eventData.TriggerID, eventData.Metric, UseFloat64(eventData.Value), eventData.OldState, eventData.State, UseString(eventData.Message), eventData.Timestamp)
 }
 
+// Acknowledge sets acknowledgement to given metrics, or to trigger state and all its metrics if no metrics given.
+// States which are OK are not acknowledged, nil acknowledgement removes existing ones
+func (checkData *CheckData) Acknowledge(metrics []string, ack *Acknowledgement) {
+	if len(metrics) == 0 {
+		checkData.Acknowledgement = ack.forState(checkData.State)
+		for metric := range checkData.Metrics {
+			metrics = append(metrics, metric)
+		}
+	}
+	for _, metric := range metrics {
+		metricState, ok := checkData.Metrics[metric]
+		if !ok {
+			continue
+		}
+		metricState.Acknowledgement = ack.forState(metricState.State)
+		checkData.Metrics[metric] = metricState
+	}
+}
+
+// forState returns copy of acknowledgement for given state, nil is returned for OK state
+func (ack *Acknowledgement) forState(state string) *Acknowledgement {
+	if ack == nil || state == "OK" || state == "" {
+		return nil
+	}
+	stateAck := *ack
+	stateAck.State = state
+	return &stateAck
+}
+
 // GetOrCreateMetricState gets metric state from check data or create new if CheckData has no state for given metric
 func (checkData *CheckData) GetOrCreateMetricState(metric string, emptyTimestampValue int64) MetricState {
 	_, ok := checkData.Metrics[metric]
@@ -287,3 +599,52 @@ func (checkData *CheckData) UpdateScore() int64 {
 	}
 	return checkData.Score
 }
+
+// GetWorstState returns the worst of trigger and its metrics states, states are compared by their scores
+func (checkData *CheckData) GetWorstState() string {
+	state := checkData.State
+	for _, metricData := range checkData.Metrics {
+		if scores[metricData.State] > scores[state] {
+			state = metricData.State
+		}
+	}
+	return state
+}
+
+// GetStateSnapshot returns trigger and metrics states of check data to be saved to trigger history
+func (checkData *CheckData) GetStateSnapshot() *TriggerStateSnapshot {
+	snapshot := &TriggerStateSnapshot{
+		Timestamp: checkData.Timestamp,
+		State:     checkData.State,
+		Score:     checkData.Score,
+		Metrics:   make(map[string]string, len(checkData.Metrics)),
+	}
+	for metric, metricData := range checkData.Metrics {
+		snapshot.Metrics[metric] = metricData.State
+	}
+	return snapshot
+}
+
+// GetWorstState returns the worst of trigger and its metrics states saved in snapshot, states are compared by their scores
+func (snapshot *TriggerStateSnapshot) GetWorstState() string {
+	state := snapshot.State
+	for _, metricState := range snapshot.Metrics {
+		if scores[metricState] > scores[state] {
+			state = metricState
+		}
+	}
+	return state
+}
+
+// IsStateChanged checks if trigger or any metric state differs from given check data ones, appeared and removed metrics are state changes too
+func (snapshot *TriggerStateSnapshot) IsStateChanged(checkData *CheckData) bool {
+	if snapshot.State != checkData.State || len(snapshot.Metrics) != len(checkData.Metrics) {
+		return true
+	}
+	for metric, metricData := range checkData.Metrics {
+		if state, ok := snapshot.Metrics[metric]; !ok || state != metricData.State {
+			return true
+		}
+	}
+	return false
+}
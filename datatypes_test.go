@@ -1,6 +1,7 @@
 package moira
 
 import (
+	"encoding/json"
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
 )
@@ -206,6 +207,43 @@ func TestTrigger_IsSimple(t *testing.T) {
 	})
 }
 
+func TestAggregateSettings_IsDependentOn(t *testing.T) {
+	trigger := &Trigger{ID: "api-errors", Tags: []string{"api", "prod"}}
+
+	Convey("Trigger referenced by ID", t, func() {
+		aggregate := AggregateSettings{TriggerIDs: []string{"db-latency", "api-errors"}}
+		So(aggregate.IsDependentOn(trigger), ShouldBeTrue)
+	})
+
+	Convey("Trigger selected by tags", t, func() {
+		So((&AggregateSettings{Tags: []string{"prod"}}).IsDependentOn(trigger), ShouldBeTrue)
+		So((&AggregateSettings{Tags: []string{"api", "prod"}}).IsDependentOn(trigger), ShouldBeTrue)
+	})
+
+	Convey("Trigger is not input", t, func() {
+		So((&AggregateSettings{}).IsDependentOn(trigger), ShouldBeFalse)
+		So((&AggregateSettings{TriggerIDs: []string{"db-latency"}}).IsDependentOn(trigger), ShouldBeFalse)
+		So((&AggregateSettings{Tags: []string{"api", "dev"}}).IsDependentOn(trigger), ShouldBeFalse)
+	})
+}
+
+func TestInhibitionSettings_IsInhibiting(t *testing.T) {
+	trigger := &Trigger{ID: "api-errors", Tags: []string{"api", "prod"}}
+	inhibition := InhibitionSettings{TriggerIDs: []string{"db-latency"}, Tags: []string{"prod"}, States: []string{"ERROR", "NODATA"}}
+
+	Convey("Parent is in inhibiting state", t, func() {
+		So(inhibition.IsInhibiting(trigger, "ERROR"), ShouldBeTrue)
+		So(inhibition.IsInhibiting(trigger, "NODATA"), ShouldBeTrue)
+		So(inhibition.IsInhibiting(&Trigger{ID: "db-latency"}, "ERROR"), ShouldBeTrue)
+		So(inhibition.IsInhibiting(&Trigger{ID: "other", Tags: []string{"dev"}}, "ERROR"), ShouldBeFalse)
+	})
+
+	Convey("Parent is not in inhibiting state", t, func() {
+		So(inhibition.IsInhibiting(trigger, "OK"), ShouldBeFalse)
+		So(inhibition.IsInhibiting(trigger, "WARN"), ShouldBeFalse)
+	})
+}
+
 func TestCheckData_GetEventTimestamp(t *testing.T) {
 	Convey("Get event timestamp", t, func() {
 		checkData := CheckData{Timestamp: 800, EventTimestamp: 0}
@@ -283,3 +321,109 @@ func getDefaultSchedule() ScheduleData {
 		},
 	}
 }
+
+func TestCheckData_GetWorstState(t *testing.T) {
+	Convey("Get worst state", t, func() {
+		checkData := CheckData{State: "OK"}
+		So(checkData.GetWorstState(), ShouldEqual, "OK")
+
+		checkData.Metrics = map[string]MetricState{"m1": {State: "WARN"}, "m2": {State: "ERROR"}, "m3": {State: "OK"}}
+		So(checkData.GetWorstState(), ShouldEqual, "ERROR")
+
+		checkData.State = "NODATA"
+		So(checkData.GetWorstState(), ShouldEqual, "NODATA")
+	})
+}
+
+func TestTriggerStateSnapshot_IsStateChanged(t *testing.T) {
+	checkData := CheckData{
+		Timestamp: 1502719200,
+		State:     "OK",
+		Score:     100,
+		Metrics:   map[string]MetricState{"m1": {State: "ERROR", Timestamp: 1502719200}},
+	}
+	snapshot := checkData.GetStateSnapshot()
+
+	Convey("Get state snapshot", t, func() {
+		So(snapshot, ShouldResemble, &TriggerStateSnapshot{Timestamp: 1502719200, State: "OK", Score: 100, Metrics: map[string]string{"m1": "ERROR"}})
+	})
+
+	Convey("Get worst state", t, func() {
+		So(snapshot.GetWorstState(), ShouldEqual, "ERROR")
+		So((&TriggerStateSnapshot{State: "NODATA", Metrics: map[string]string{"m1": "WARN"}}).GetWorstState(), ShouldEqual, "NODATA")
+	})
+
+	Convey("Same states", t, func() {
+		So(snapshot.IsStateChanged(&CheckData{State: "OK", Metrics: map[string]MetricState{"m1": {State: "ERROR", Timestamp: 1502719260}}}), ShouldBeFalse)
+	})
+
+	Convey("Changed states", t, func() {
+		So(snapshot.IsStateChanged(&CheckData{State: "EXCEPTION", Metrics: map[string]MetricState{"m1": {State: "ERROR"}}}), ShouldBeTrue)
+		So(snapshot.IsStateChanged(&CheckData{State: "OK", Metrics: map[string]MetricState{"m1": {State: "OK"}}}), ShouldBeTrue)
+		So(snapshot.IsStateChanged(&CheckData{State: "OK", Metrics: map[string]MetricState{"m2": {State: "ERROR"}}}), ShouldBeTrue)
+		So(snapshot.IsStateChanged(&CheckData{State: "OK"}), ShouldBeTrue)
+	})
+}
+
+func TestUserToken_IsSecretValid(t *testing.T) {
+	token := UserToken{ID: "token", User: "user", Hash: HashTokenSecret("secret")}
+
+	Convey("Token secret is checked by hash", t, func() {
+		So(token.Hash, ShouldNotEqual, "secret")
+		So(token.IsSecretValid("secret"), ShouldBeTrue)
+		So(token.IsSecretValid("other"), ShouldBeFalse)
+		So(token.IsSecretValid(""), ShouldBeFalse)
+	})
+}
+
+func TestTeam_HasRole(t *testing.T) {
+	team := Team{ID: "team", Members: map[string]string{"viewer": TeamRoleViewer, "editor": TeamRoleEditor, "admin": TeamRoleAdmin, "unknown": "owner"}}
+
+	Convey("Higher roles have permissions of lower ones", t, func() {
+		So(team.HasRole("viewer", TeamRoleViewer), ShouldBeTrue)
+		So(team.HasRole("viewer", TeamRoleEditor), ShouldBeFalse)
+		So(team.HasRole("editor", TeamRoleViewer), ShouldBeTrue)
+		So(team.HasRole("editor", TeamRoleEditor), ShouldBeTrue)
+		So(team.HasRole("editor", TeamRoleAdmin), ShouldBeFalse)
+		So(team.HasRole("admin", TeamRoleAdmin), ShouldBeTrue)
+	})
+
+	Convey("Users without known role have no permissions", t, func() {
+		So(team.HasRole("other", TeamRoleViewer), ShouldBeFalse)
+		So(team.HasRole("unknown", TeamRoleViewer), ShouldBeFalse)
+		So(team.HasRole("", TeamRoleViewer), ShouldBeFalse)
+	})
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	var nilTrigger *Trigger
+	trigger := &Trigger{ID: "trigger"}
+	triggerJSON, _ := json.Marshal(trigger)
+
+	Convey("Action is derived from states", t, func() {
+		record, err := NewAuditRecord("user", AuditEntityTrigger, "trigger", nilTrigger, trigger, 100)
+		So(err, ShouldBeNil)
+		So(record, ShouldResemble, &AuditRecord{
+			Timestamp: 100,
+			User:      "user",
+			Entity:    AuditEntityTrigger,
+			EntityID:  "trigger",
+			Action:    AuditActionCreate,
+			After:     triggerJSON,
+		})
+
+		record, err = NewAuditRecord("user", AuditEntityTrigger, "trigger", trigger, trigger, 100)
+		So(err, ShouldBeNil)
+		So(record.Action, ShouldEqual, AuditActionUpdate)
+
+		record, err = NewAuditRecord("user", AuditEntityTrigger, "trigger", trigger, nil, 100)
+		So(err, ShouldBeNil)
+		So(record.Action, ShouldEqual, AuditActionDelete)
+		So(record.After, ShouldBeNil)
+	})
+
+	Convey("State which can not be marshaled", t, func() {
+		_, err := NewAuditRecord("user", AuditEntityTrigger, "trigger", nil, make(chan int), 100)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,73 @@
+package expression
+
+import (
+	"fmt"
+
+	"github.com/Knetic/govaluate"
+)
+
+// AggregateExpression represents aggregate trigger expression parameters, which are built from states of input triggers.
+// Besides state constants expression can use count(STATE) function, TOTAL number of input triggers,
+// SCORE as sum of input triggers scores and PREV_STATE. Expression result is state value or boolean,
+// true means ERROR and false means OK
+type AggregateExpression struct {
+	Expression    string
+	StatesCount   map[string]int64
+	Total         int64
+	Score         int64
+	PreviousState string
+}
+
+// Get realizing govaluate.Parameters interface used in evaluable expression
+func (aggregateExpression AggregateExpression) Get(name string) (interface{}, error) {
+	switch name {
+	case "OK", "ERROR", "NODATA", "EXCEPTION":
+		return name, nil
+	case "WARN", "WARNING":
+		return "WARN", nil
+	case "TOTAL":
+		return float64(aggregateExpression.Total), nil
+	case "SCORE":
+		return float64(aggregateExpression.Score), nil
+	case "PREV_STATE":
+		return aggregateExpression.PreviousState, nil
+	}
+	return nil, fmt.Errorf("no value with name %s", name)
+}
+
+// Evaluate evaluates aggregate expression for given input triggers states using govaluate
+func (aggregateExpression *AggregateExpression) Evaluate() (string, error) {
+	functions := map[string]govaluate.ExpressionFunction{
+		"count": aggregateExpression.count,
+	}
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(aggregateExpression.Expression, functions)
+	if err != nil {
+		return "", ErrInvalidExpression{internalError: err}
+	}
+	result, err := expr.Eval(aggregateExpression)
+	if err != nil {
+		return "", ErrInvalidExpression{internalError: err}
+	}
+	switch res := result.(type) {
+	case string:
+		return res, nil
+	case bool:
+		if res {
+			return "ERROR", nil
+		}
+		return "OK", nil
+	default:
+		return "", ErrInvalidExpression{internalError: fmt.Errorf("expression result must be state value or boolean")}
+	}
+}
+
+func (aggregateExpression *AggregateExpression) count(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("count function takes exactly one state")
+	}
+	state, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("count function argument must be state value")
+	}
+	return float64(aggregateExpression.StatesCount[state]), nil
+}
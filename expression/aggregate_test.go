@@ -0,0 +1,50 @@
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAggregateExpression(t *testing.T) {
+	statesCount := map[string]int64{"ERROR": 2, "OK": 1}
+
+	Convey("Boolean result", t, func() {
+		result, err := (&AggregateExpression{Expression: "count(ERROR) >= 2", StatesCount: statesCount, Total: 3}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "ERROR")
+
+		result, err = (&AggregateExpression{Expression: "count(ERROR) == TOTAL", StatesCount: statesCount, Total: 3}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "OK")
+	})
+
+	Convey("State result", t, func() {
+		result, err := (&AggregateExpression{Expression: "count(WARN) + count(ERROR) > 0 ? WARN : OK", StatesCount: statesCount}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "WARN")
+
+		result, err = (&AggregateExpression{Expression: "SCORE > 1000 ? ERROR : PREV_STATE", Score: 10, PreviousState: "NODATA"}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "NODATA")
+	})
+
+	Convey("Invalid expressions", t, func() {
+		_, err := (&AggregateExpression{Expression: "count(ERROR, OK) > 0"}).Evaluate()
+		So(err, ShouldHaveSameTypeAs, ErrInvalidExpression{})
+		So(err.Error(), ShouldEqual, "count function takes exactly one state")
+
+		_, err = (&AggregateExpression{Expression: "count(1) > 0"}).Evaluate()
+		So(err.Error(), ShouldEqual, "count function argument must be state value")
+
+		_, err = (&AggregateExpression{Expression: "t1 > 0"}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("no value with name t1")})
+
+		_, err = (&AggregateExpression{Expression: "TOTAL"}).Evaluate()
+		So(err.Error(), ShouldEqual, "expression result must be state value or boolean")
+
+		_, err = (&AggregateExpression{Expression: "sum(ERROR)"}).Evaluate()
+		So(err, ShouldHaveSameTypeAs, ErrInvalidExpression{})
+	})
+}
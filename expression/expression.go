@@ -11,6 +11,9 @@ import (
 var default1, _ = govaluate.NewEvaluableExpression("t1 >= ERROR_VALUE ? ERROR : (t1 >= WARN_VALUE ? WARN : OK)")
 var default2, _ = govaluate.NewEvaluableExpression("t1 <= ERROR_VALUE ? ERROR : (t1 <= WARN_VALUE ? WARN : OK)")
 
+// defaultAnomaly treats warn and error values of anomaly detection trigger as number of sigmas value deviates from baseline by
+var defaultAnomaly, _ = govaluate.NewEvaluableExpression("ZSCORE >= ERROR_VALUE || ZSCORE <= -ERROR_VALUE ? ERROR : (ZSCORE >= WARN_VALUE || ZSCORE <= -WARN_VALUE ? WARN : OK)")
+
 var cache = make(map[string]*govaluate.EvaluableExpression)
 var cacheLock sync.Mutex
 
@@ -33,6 +36,15 @@ type TriggerExpression struct {
 	MainTargetValue         float64
 	AdditionalTargetsValues map[string]float64
 	PreviousState           string
+
+	Baseline *Baseline
+}
+
+// Baseline represents values computed from history of main target series by anomaly detection trigger
+type Baseline struct {
+	Value     float64
+	Deviation float64
+	ZScore    float64
 }
 
 // Get realizing govaluate.Parameters interface used in evaluable expression
@@ -60,6 +72,11 @@ func (triggerExpression TriggerExpression) Get(name string) (interface{}, error)
 		return triggerExpression.MainTargetValue, nil
 	case "PREV_STATE":
 		return triggerExpression.PreviousState, nil
+	case "BASELINE", "STDDEV", "ZSCORE":
+		if triggerExpression.Baseline == nil {
+			return nil, fmt.Errorf("no value with name %s", name)
+		}
+		return triggerExpression.Baseline.get(name), nil
 	default:
 		value, ok := triggerExpression.AdditionalTargetsValues[name]
 		if !ok {
@@ -87,6 +104,16 @@ func (triggerExpression *TriggerExpression) Evaluate() (string, error) {
 	}
 }
 
+func (baseline *Baseline) get(name string) float64 {
+	switch name {
+	case "BASELINE":
+		return baseline.Value
+	case "STDDEV":
+		return baseline.Deviation
+	}
+	return baseline.ZScore
+}
+
 func getExpression(triggerExpression *TriggerExpression) (*govaluate.EvaluableExpression, error) {
 	if triggerExpression.Expression != nil && *triggerExpression.Expression != "" {
 		return getUserExpression(*triggerExpression.Expression)
@@ -98,6 +125,9 @@ func getSimpleExpression(triggerExpression *TriggerExpression) (*govaluate.Evalu
 	if triggerExpression.ErrorValue == nil || triggerExpression.WarnValue == nil {
 		return nil, fmt.Errorf("error value and Warning value can not be empty")
 	}
+	if triggerExpression.Baseline != nil {
+		return defaultAnomaly, nil
+	}
 	if *triggerExpression.ErrorValue >= *triggerExpression.WarnValue {
 		return default1, nil
 	}
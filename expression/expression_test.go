@@ -50,6 +50,16 @@ func TestExpression(t *testing.T) {
 		So(result, ShouldBeEmpty)
 	})
 
+	Convey("Test Default anomaly", t, func() {
+		warnValue := 2.0
+		errorValue := 3.0
+		for zScore, expected := range map[float64]string{0: "OK", 1.5: "OK", -2: "WARN", 2.5: "WARN", 3: "ERROR", -4: "ERROR"} {
+			result, err := (&TriggerExpression{MainTargetValue: 10.0, WarnValue: &warnValue, ErrorValue: &errorValue, Baseline: &Baseline{ZScore: zScore}}).Evaluate()
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, expected)
+		}
+	})
+
 	Convey("Test Custom", t, func() {
 		expression := "t1 > 10 && t2 > 3 ? ERROR : OK"
 		result, err := (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, AdditionalTargetsValues: map[string]float64{"t2": 4.0}}).Evaluate()
@@ -60,6 +70,15 @@ func TestExpression(t *testing.T) {
 		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, AdditionalTargetsValues: map[string]float64{"t2": 4.0}}).Evaluate()
 		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("functions is forbidden")})
 		So(result, ShouldBeEmpty)
+
+		expression = "t1 > BASELINE + 2 * STDDEV ? WARN : OK"
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, Baseline: &Baseline{Value: 5, Deviation: 2, ZScore: 3}}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, "WARN")
+
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("no value with name BASELINE")})
+		So(result, ShouldBeEmpty)
 	})
 }
 
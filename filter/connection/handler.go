@@ -16,17 +16,30 @@ type Handler struct {
 	patternsStorage *filter.PatternStorage
 	wg              sync.WaitGroup
 	terminate       chan bool
+	readMetrics     func(buffer *bufio.Reader) ([][]byte, error)
 }
 
-// NewConnectionsHandler creates new Handler
+// NewConnectionsHandler creates new Handler for graphite plaintext protocol
 func NewConnectionsHandler(logger moira.Logger, patternsStorage *filter.PatternStorage) *Handler {
 	return &Handler{
 		logger:          logger,
 		patternsStorage: patternsStorage,
 		terminate:       make(chan bool, 1),
+		readMetrics:     readPlaintextMetrics,
 	}
 }
 
+// NewPickleConnectionsHandler creates new Handler for graphite pickle protocol
+func NewPickleConnectionsHandler(logger moira.Logger, patternsStorage *filter.PatternStorage) *Handler {
+	handler := &Handler{
+		logger:          logger,
+		patternsStorage: patternsStorage,
+		terminate:       make(chan bool, 1),
+	}
+	handler.readMetrics = handler.readPickleMetrics
+	return handler
+}
+
 // HandleConnection convert every line from connection to metric and send it to MatchedMetric channel
 func (handler *Handler) HandleConnection(connection net.Conn, matchedMetricsChan chan *moira.MatchedMetric) {
 	handler.wg.Add(1)
@@ -45,7 +58,7 @@ func (handler *Handler) handle(connection net.Conn, matchedMetricsChan chan *moi
 	}(connection)
 
 	for {
-		lineBytes, err := buffer.ReadBytes('\n')
+		lines, err := handler.readMetrics(buffer)
 		if err != nil {
 			connection.Close()
 			if err != io.EOF {
@@ -53,15 +66,24 @@ func (handler *Handler) handle(connection net.Conn, matchedMetricsChan chan *moi
 			}
 			break
 		}
-		lineBytes = lineBytes[:len(lineBytes)-1]
-		handler.wg.Add(1)
-		func(ch chan *moira.MatchedMetric) {
-			defer handler.wg.Done()
-			if m := handler.patternsStorage.ProcessIncomingMetric(lineBytes); m != nil {
-				ch <- m
-			}
-		}(matchedMetricsChan)
+		for _, lineBytes := range lines {
+			handler.wg.Add(1)
+			func(ch chan *moira.MatchedMetric) {
+				defer handler.wg.Done()
+				if m := handler.patternsStorage.ProcessIncomingMetric(lineBytes); m != nil {
+					ch <- m
+				}
+			}(matchedMetricsChan)
+		}
+	}
+}
+
+func readPlaintextMetrics(buffer *bufio.Reader) ([][]byte, error) {
+	lineBytes, err := buffer.ReadBytes('\n')
+	if err != nil {
+		return nil, err
 	}
+	return [][]byte{lineBytes[:len(lineBytes)-1]}, nil
 }
 
 // StopHandlingConnections closes all open connections and wait for handling ramaining metrics
@@ -19,8 +19,17 @@ type MetricsListener struct {
 	tomb     tomb.Tomb
 }
 
-// NewListener creates new listener
+// NewListener creates new listener for graphite plaintext protocol
 func NewListener(port string, logger moira.Logger, patternStorage *filter.PatternStorage) (*MetricsListener, error) {
+	return newTCPListener(port, logger, NewConnectionsHandler(logger, patternStorage))
+}
+
+// NewPickleListener creates new listener for graphite pickle protocol
+func NewPickleListener(port string, logger moira.Logger, patternStorage *filter.PatternStorage) (*MetricsListener, error) {
+	return newTCPListener(port, logger, NewPickleConnectionsHandler(logger, patternStorage))
+}
+
+func newTCPListener(port string, logger moira.Logger, handler *Handler) (*MetricsListener, error) {
 	address, err := net.ResolveTCPAddr("tcp", port)
 	if nil != err {
 		return nil, fmt.Errorf("Failed to resolve tcp address [%s]: %s", port, err.Error())
@@ -32,7 +41,7 @@ func NewListener(port string, logger moira.Logger, patternStorage *filter.Patter
 	listener := MetricsListener{
 		listener: newListener,
 		logger:   logger,
-		handler:  NewConnectionsHandler(logger, patternStorage),
+		handler:  handler,
 	}
 	return &listener, nil
 }
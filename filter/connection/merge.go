@@ -0,0 +1,31 @@
+package connection
+
+import (
+	"sync"
+
+	"github.com/moira-alert/moira"
+)
+
+// MergeMetricsChannels fans in metrics from all given listeners channels into single channel.
+// Result channel is closed when all given channels are closed
+func MergeMetricsChannels(channels ...chan *moira.MatchedMetric) chan *moira.MatchedMetric {
+	if len(channels) == 1 {
+		return channels[0]
+	}
+	merged := make(chan *moira.MatchedMetric, 10000)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, channel := range channels {
+		go func(channel chan *moira.MatchedMetric) {
+			defer wg.Done()
+			for metric := range channel {
+				merged <- metric
+			}
+		}(channel)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
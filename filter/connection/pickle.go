@@ -0,0 +1,475 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Pickle opcodes which can be produced by python pickle module for lists of tuples with strings and numbers
+const (
+	pickleMark            = '('
+	pickleStop            = '.'
+	picklePop             = '0'
+	picklePopMark         = '1'
+	pickleDup             = '2'
+	pickleFloat           = 'F'
+	pickleInt             = 'I'
+	pickleBinInt          = 'J'
+	pickleBinInt1         = 'K'
+	pickleLong            = 'L'
+	pickleBinInt2         = 'M'
+	pickleNone            = 'N'
+	pickleString          = 'S'
+	pickleBinString       = 'T'
+	pickleShortBinString  = 'U'
+	pickleUnicode         = 'V'
+	pickleBinUnicode      = 'X'
+	pickleAppend          = 'a'
+	pickleGet             = 'g'
+	pickleBinGet          = 'h'
+	pickleLongBinGet      = 'j'
+	pickleList            = 'l'
+	picklePut             = 'p'
+	pickleBinPut          = 'q'
+	pickleLongBinPut      = 'r'
+	pickleTuple           = 't'
+	pickleAppends         = 'e'
+	pickleBinFloat        = 'G'
+	pickleEmptyList       = ']'
+	pickleEmptyTuple      = ')'
+	pickleBinBytes        = 'B'
+	pickleShortBinBytes   = 'C'
+	pickleProto           = 0x80
+	pickleTuple1          = 0x85
+	pickleTuple2          = 0x86
+	pickleTuple3          = 0x87
+	pickleNewTrue         = 0x88
+	pickleNewFalse        = 0x89
+	pickleLong1           = 0x8a
+	pickleShortBinUnicode = 0x8c
+	pickleBinUnicode8     = 0x8d
+	pickleMemoize         = 0x94
+	pickleFrame           = 0x95
+)
+
+// pickleMarker is pushed to stack by MARK opcode
+type pickleMarker struct{}
+
+// pickleListValue is mutable python list, it is shared between stack and memo
+type pickleListValue struct {
+	items []interface{}
+}
+
+// unpickler decodes subset of python pickle format which carbon uses to send metrics.
+// Lists are decoded as *pickleListValue, tuples as []interface{}, numbers as int64 or float64 and strings as string
+type unpickler struct {
+	data  []byte
+	stack []interface{}
+	memo  map[int]interface{}
+}
+
+// unpickle decodes single pickled object
+func unpickle(data []byte) (interface{}, error) {
+	decoder := &unpickler{
+		data:  data,
+		stack: make([]interface{}, 0),
+		memo:  make(map[int]interface{}),
+	}
+	return decoder.decode()
+}
+
+func (decoder *unpickler) decode() (interface{}, error) {
+	for {
+		opcode, err := decoder.readByte()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case pickleStop:
+			return decoder.pop()
+		case pickleProto:
+			_, err = decoder.read(1)
+		case pickleFrame:
+			_, err = decoder.read(8)
+		case pickleMark:
+			decoder.push(pickleMarker{})
+		case picklePop:
+			_, err = decoder.pop()
+		case picklePopMark:
+			_, err = decoder.popMark()
+		case pickleDup:
+			var value interface{}
+			if value, err = decoder.top(); err == nil {
+				decoder.push(value)
+			}
+		case pickleNone:
+			decoder.push(nil)
+		case pickleNewTrue:
+			decoder.push(true)
+		case pickleNewFalse:
+			decoder.push(false)
+		case pickleInt:
+			err = decoder.loadInt()
+		case pickleLong:
+			err = decoder.loadLong()
+		case pickleBinInt:
+			err = decoder.loadBinInt(4)
+		case pickleBinInt1:
+			err = decoder.loadBinInt(1)
+		case pickleBinInt2:
+			err = decoder.loadBinInt(2)
+		case pickleLong1:
+			err = decoder.loadLong1()
+		case pickleFloat:
+			err = decoder.loadFloat()
+		case pickleBinFloat:
+			var raw []byte
+			if raw, err = decoder.read(8); err == nil {
+				decoder.push(math.Float64frombits(binary.BigEndian.Uint64(raw)))
+			}
+		case pickleString:
+			err = decoder.loadString()
+		case pickleUnicode:
+			var line []byte
+			if line, err = decoder.readLine(); err == nil {
+				decoder.push(string(line))
+			}
+		case pickleBinString, pickleBinUnicode, pickleBinBytes:
+			err = decoder.loadBinString(4)
+		case pickleShortBinString, pickleShortBinUnicode, pickleShortBinBytes:
+			err = decoder.loadBinString(1)
+		case pickleBinUnicode8:
+			err = decoder.loadBinString(8)
+		case pickleEmptyList:
+			decoder.push(&pickleListValue{items: make([]interface{}, 0)})
+		case pickleEmptyTuple:
+			decoder.push([]interface{}{})
+		case pickleList:
+			var items []interface{}
+			if items, err = decoder.popMark(); err == nil {
+				decoder.push(&pickleListValue{items: items})
+			}
+		case pickleTuple:
+			var items []interface{}
+			if items, err = decoder.popMark(); err == nil {
+				decoder.push(items)
+			}
+		case pickleTuple1, pickleTuple2, pickleTuple3:
+			err = decoder.loadTupleN(int(opcode-pickleTuple1) + 1)
+		case pickleAppend:
+			err = decoder.loadAppend()
+		case pickleAppends:
+			err = decoder.loadAppends()
+		case picklePut:
+			err = decoder.loadPut()
+		case pickleBinPut:
+			err = decoder.loadBinPut(1)
+		case pickleLongBinPut:
+			err = decoder.loadBinPut(4)
+		case pickleMemoize:
+			var value interface{}
+			if value, err = decoder.top(); err == nil {
+				decoder.memo[len(decoder.memo)] = value
+			}
+		case pickleGet:
+			err = decoder.loadGet()
+		case pickleBinGet:
+			err = decoder.loadBinGet(1)
+		case pickleLongBinGet:
+			err = decoder.loadBinGet(4)
+		default:
+			return nil, fmt.Errorf("unsupported pickle opcode 0x%02x", opcode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (decoder *unpickler) push(value interface{}) {
+	decoder.stack = append(decoder.stack, value)
+}
+
+func (decoder *unpickler) top() (interface{}, error) {
+	if len(decoder.stack) == 0 {
+		return nil, fmt.Errorf("pickle stack is empty")
+	}
+	return decoder.stack[len(decoder.stack)-1], nil
+}
+
+func (decoder *unpickler) pop() (interface{}, error) {
+	value, err := decoder.top()
+	if err != nil {
+		return nil, err
+	}
+	decoder.stack = decoder.stack[:len(decoder.stack)-1]
+	return value, nil
+}
+
+// popMark pops all values pushed after last MARK
+func (decoder *unpickler) popMark() ([]interface{}, error) {
+	for i := len(decoder.stack) - 1; i >= 0; i-- {
+		if _, ok := decoder.stack[i].(pickleMarker); ok {
+			items := make([]interface{}, len(decoder.stack)-i-1)
+			copy(items, decoder.stack[i+1:])
+			decoder.stack = decoder.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, fmt.Errorf("pickle mark not found")
+}
+
+func (decoder *unpickler) read(n int) ([]byte, error) {
+	if n < 0 || len(decoder.data) < n {
+		return nil, fmt.Errorf("unexpected end of pickle data")
+	}
+	result := decoder.data[:n]
+	decoder.data = decoder.data[n:]
+	return result, nil
+}
+
+func (decoder *unpickler) readByte() (byte, error) {
+	raw, err := decoder.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return raw[0], nil
+}
+
+func (decoder *unpickler) readLine() ([]byte, error) {
+	index := bytes.IndexByte(decoder.data, '\n')
+	if index < 0 {
+		return nil, fmt.Errorf("unexpected end of pickle data")
+	}
+	line := decoder.data[:index]
+	decoder.data = decoder.data[index+1:]
+	return line, nil
+}
+
+func (decoder *unpickler) readUint(size int) (uint64, error) {
+	raw, err := decoder.read(size)
+	if err != nil {
+		return 0, err
+	}
+	var result uint64
+	for i := size - 1; i >= 0; i-- {
+		result = result<<8 | uint64(raw[i])
+	}
+	return result, nil
+}
+
+func (decoder *unpickler) loadInt() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	switch string(line) {
+	case "00":
+		decoder.push(false)
+		return nil
+	case "01":
+		decoder.push(true)
+		return nil
+	}
+	value, err := strconv.ParseInt(string(line), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid pickle int: %s", err.Error())
+	}
+	decoder.push(value)
+	return nil
+}
+
+func (decoder *unpickler) loadLong() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseInt(string(bytes.TrimSuffix(line, []byte("L"))), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid pickle long: %s", err.Error())
+	}
+	decoder.push(value)
+	return nil
+}
+
+func (decoder *unpickler) loadBinInt(size int) error {
+	value, err := decoder.readUint(size)
+	if err != nil {
+		return err
+	}
+	if size == 4 {
+		decoder.push(int64(int32(value)))
+	} else {
+		decoder.push(int64(value))
+	}
+	return nil
+}
+
+func (decoder *unpickler) loadLong1() error {
+	size, err := decoder.readByte()
+	if err != nil {
+		return err
+	}
+	if size > 8 {
+		return fmt.Errorf("pickle long of %d bytes is too big", size)
+	}
+	if size == 0 {
+		decoder.push(int64(0))
+		return nil
+	}
+	value, err := decoder.readUint(int(size))
+	if err != nil {
+		return err
+	}
+	// Sign-extend two's complement value
+	shift := 64 - 8*uint(size)
+	decoder.push(int64(value<<shift) >> shift)
+	return nil
+}
+
+func (decoder *unpickler) loadFloat() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseFloat(string(line), 64)
+	if err != nil {
+		return fmt.Errorf("invalid pickle float: %s", err.Error())
+	}
+	decoder.push(value)
+	return nil
+}
+
+func (decoder *unpickler) loadString() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	if len(line) < 2 || (line[0] != '\'' && line[0] != '"') || line[len(line)-1] != line[0] {
+		return fmt.Errorf("invalid pickle string %q", line)
+	}
+	value, err := strconv.Unquote("\"" + string(line[1:len(line)-1]) + "\"")
+	if err != nil {
+		// Python escapes differ from go escapes, fallback to raw value
+		value = string(line[1 : len(line)-1])
+	}
+	decoder.push(value)
+	return nil
+}
+
+func (decoder *unpickler) loadBinString(lengthSize int) error {
+	length, err := decoder.readUint(lengthSize)
+	if err != nil {
+		return err
+	}
+	if length > uint64(len(decoder.data)) {
+		return fmt.Errorf("unexpected end of pickle data")
+	}
+	raw, err := decoder.read(int(length))
+	if err != nil {
+		return err
+	}
+	decoder.push(string(raw))
+	return nil
+}
+
+func (decoder *unpickler) loadTupleN(n int) error {
+	if len(decoder.stack) < n {
+		return fmt.Errorf("pickle stack is too short for tuple of %d", n)
+	}
+	items := make([]interface{}, n)
+	copy(items, decoder.stack[len(decoder.stack)-n:])
+	decoder.stack = decoder.stack[:len(decoder.stack)-n]
+	decoder.push(items)
+	return nil
+}
+
+func (decoder *unpickler) loadAppend() error {
+	value, err := decoder.pop()
+	if err != nil {
+		return err
+	}
+	return decoder.appendToList(value)
+}
+
+func (decoder *unpickler) loadAppends() error {
+	items, err := decoder.popMark()
+	if err != nil {
+		return err
+	}
+	return decoder.appendToList(items...)
+}
+
+func (decoder *unpickler) appendToList(values ...interface{}) error {
+	top, err := decoder.top()
+	if err != nil {
+		return err
+	}
+	list, ok := top.(*pickleListValue)
+	if !ok {
+		return fmt.Errorf("pickle append to %T", top)
+	}
+	list.items = append(list.items, values...)
+	return nil
+}
+
+func (decoder *unpickler) loadPut() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	index, err := strconv.Atoi(string(line))
+	if err != nil {
+		return fmt.Errorf("invalid pickle memo index: %s", err.Error())
+	}
+	return decoder.memoize(index)
+}
+
+func (decoder *unpickler) loadBinPut(size int) error {
+	index, err := decoder.readUint(size)
+	if err != nil {
+		return err
+	}
+	return decoder.memoize(int(index))
+}
+
+func (decoder *unpickler) memoize(index int) error {
+	value, err := decoder.top()
+	if err != nil {
+		return err
+	}
+	decoder.memo[index] = value
+	return nil
+}
+
+func (decoder *unpickler) loadGet() error {
+	line, err := decoder.readLine()
+	if err != nil {
+		return err
+	}
+	index, err := strconv.Atoi(string(line))
+	if err != nil {
+		return fmt.Errorf("invalid pickle memo index: %s", err.Error())
+	}
+	return decoder.pushMemo(index)
+}
+
+func (decoder *unpickler) loadBinGet(size int) error {
+	index, err := decoder.readUint(size)
+	if err != nil {
+		return err
+	}
+	return decoder.pushMemo(int(index))
+}
+
+func (decoder *unpickler) pushMemo(index int) error {
+	value, ok := decoder.memo[index]
+	if !ok {
+		return fmt.Errorf("pickle memo %d not found", index)
+	}
+	decoder.push(value)
+	return nil
+}
@@ -0,0 +1,101 @@
+package connection
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxPickleMessageSize is the same limit as carbon uses for pickle receiver
+const maxPickleMessageSize = 1 << 20
+
+// readPickleMetrics reads single length-prefixed pickle message and converts it to plaintext metric lines
+func (handler *Handler) readPickleMetrics(buffer *bufio.Reader) ([][]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(buffer, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > maxPickleMessageSize {
+		return nil, fmt.Errorf("pickle message size %d exceeds limit %d", size, maxPickleMessageSize)
+	}
+	message := make([]byte, size)
+	if _, err := io.ReadFull(buffer, message); err != nil {
+		return nil, err
+	}
+	lines, err := parsePickleMetrics(message)
+	if err != nil {
+		handler.logger.Infof("Failed to parse pickle message: %s", err.Error())
+	}
+	return lines, nil
+}
+
+// parsePickleMetrics converts pickled list of (metric, (timestamp, value)) tuples to plaintext metric lines.
+// Invalid list items are skipped
+func parsePickleMetrics(message []byte) ([][]byte, error) {
+	value, err := unpickle(message)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := pickleSequence(value)
+	if !ok {
+		return nil, fmt.Errorf("expected list of metrics, got %T", value)
+	}
+	lines := make([][]byte, 0, len(items))
+	for _, item := range items {
+		if line, ok := pickleMetricLine(item); ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func pickleMetricLine(item interface{}) ([]byte, bool) {
+	metric, ok := pickleSequence(item)
+	if !ok || len(metric) != 2 {
+		return nil, false
+	}
+	name, ok := metric[0].(string)
+	if !ok {
+		return nil, false
+	}
+	datapoint, ok := pickleSequence(metric[1])
+	if !ok || len(datapoint) != 2 {
+		return nil, false
+	}
+	timestamp, ok := formatPickleNumber(datapoint[0])
+	if !ok {
+		return nil, false
+	}
+	value, ok := formatPickleNumber(datapoint[1])
+	if !ok {
+		return nil, false
+	}
+	return []byte(name + " " + value + " " + timestamp), true
+}
+
+func pickleSequence(value interface{}) ([]interface{}, bool) {
+	switch sequence := value.(type) {
+	case []interface{}:
+		return sequence, true
+	case *pickleListValue:
+		return sequence.items, true
+	default:
+		return nil, false
+	}
+}
+
+func formatPickleNumber(value interface{}) (string, bool) {
+	switch number := value.(type) {
+	case int64:
+		return strconv.FormatInt(number, 10), true
+	case float64:
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	case string:
+		return number, true
+	default:
+		return "", false
+	}
+}
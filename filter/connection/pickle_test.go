@@ -0,0 +1,139 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+var pickledMetrics = map[string]string{
+	"protocol 0":                      "(lp0\n(VOne.two.three\np1\n(I1234567890\nF1.5\ntp2\ntp3\na(VFour.five\np4\n(F1234567891.5\nI-2\ntp5\ntp6\na(VSix\np7\n(I1234567890\nL1000000000000L\ntp8\ntp9\na.",
+	"protocol 0 with python2 strings": "(lp0\n(S'One.two.three'\np1\n(I1234567890\nF1.5\ntp2\ntp3\na(S'Four.five'\np4\n(F1234567891.5\nI-2\ntp5\ntp6\na(S'Six'\np7\n(I1234567890\nL1000000000000L\ntp8\ntp9\na.",
+	"protocol 2":                      "\x80\x02]q\x00(X\x0d\x00\x00\x00One.two.threeq\x01J\xd2\x02\x96IG?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x09\x00\x00\x00Four.fiveq\x04GA\xd2e\x80\xb4\xe0\x00\x00J\xfe\xff\xff\xff\x86q\x05\x86q\x06X\x03\x00\x00\x00Sixq\x07J\xd2\x02\x96I\x8a\x06\x00\x10\xa5\xd4\xe8\x00\x86q\x08\x86q\x09e.",
+	"protocol 4":                      "\x80\x04\x95\x5c\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0dOne.two.three\x94J\xd2\x02\x96IG?\xf8\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\x09Four.five\x94GA\xd2e\x80\xb4\xe0\x00\x00J\xfe\xff\xff\xff\x86\x94\x86\x94\x8c\x03Six\x94J\xd2\x02\x96I\x8a\x06\x00\x10\xa5\xd4\xe8\x00\x86\x94\x86\x94e.",
+}
+
+var expectedPickleLines = [][]byte{
+	[]byte("One.two.three 1.5 1234567890"),
+	[]byte("Four.five -2 1234567891.5"),
+	[]byte("Six 1000000000000 1234567890"),
+}
+
+func TestParsePickleMetrics(t *testing.T) {
+	Convey("Given metrics pickled by python, should return plaintext lines", t, func() {
+		for name, pickled := range pickledMetrics {
+			Convey(name, func() {
+				lines, err := parsePickleMetrics([]byte(pickled))
+				So(err, ShouldBeNil)
+				So(lines, ShouldResemble, expectedPickleLines)
+			})
+		}
+	})
+
+	Convey("Given list with invalid items, should skip them", t, func() {
+		// [("Valid", (1234567890, 1)), ("Invalid", 1), (1, (1234567890, 1)), ("Short", (1,))]
+		pickled := "\x80\x02]q\x00(X\x05\x00\x00\x00ValidJ\xd2\x02\x96IK\x01\x86\x86X\x07\x00\x00\x00InvalidK\x01\x86K\x01J\xd2\x02\x96IK\x01\x86\x86X\x05\x00\x00\x00ShortK\x01\x85\x86e."
+		lines, err := parsePickleMetrics([]byte(pickled))
+		So(err, ShouldBeNil)
+		So(lines, ShouldResemble, [][]byte{[]byte("Valid 1 1234567890")})
+	})
+
+	Convey("Given invalid pickle data, should return error", t, func() {
+		for _, pickled := range []string{"", "(lp0\n", "\xff.", "K\x01.", "a."} {
+			_, err := parsePickleMetrics([]byte(pickled))
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestReadPickleMetrics(t *testing.T) {
+	logger, _ := logging.GetLogger("Filter")
+	handler := &Handler{logger: logger}
+
+	Convey("Read length-prefixed messages one by one", t, func() {
+		var stream bytes.Buffer
+		stream.Write(framePickleMessage(pickledMetrics["protocol 2"]))
+		stream.Write(framePickleMessage("invalid"))
+		stream.Write(framePickleMessage(pickledMetrics["protocol 0"])[:10])
+		buffer := bufio.NewReader(&stream)
+
+		lines, err := handler.readPickleMetrics(buffer)
+		So(err, ShouldBeNil)
+		So(lines, ShouldResemble, expectedPickleLines)
+
+		lines, err = handler.readPickleMetrics(buffer)
+		So(err, ShouldBeNil)
+		So(lines, ShouldBeEmpty)
+
+		_, err = handler.readPickleMetrics(buffer)
+		So(err, ShouldEqual, io.ErrUnexpectedEOF)
+
+		_, err = handler.readPickleMetrics(buffer)
+		So(err, ShouldEqual, io.EOF)
+	})
+
+	Convey("Too big message should be rejected", t, func() {
+		header := make([]byte, 4)
+		binary.BigEndian.PutUint32(header, maxPickleMessageSize+1)
+		_, err := handler.readPickleMetrics(bufio.NewReader(bytes.NewReader(header)))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestUDPListener(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Filter")
+
+	database.EXPECT().GetPatterns().Return([]string{"Simple.*"}, nil)
+	patternStorage, err := filter.NewPatternStorage(database, metrics.ConfigureFilterMetrics("test"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Every line of datagram should be handled", t, func() {
+		listener, err := NewUDPListener("127.0.0.1:0", logger, patternStorage)
+		So(err, ShouldBeNil)
+		metricsChan := listener.Listen()
+
+		conn, err := net.Dial("udp", listener.conn.LocalAddr().String())
+		So(err, ShouldBeNil)
+		_, err = conn.Write([]byte("Simple.one 1 1234567890\nNot.matched 2 1234567890\n\nSimple.two 3 1234567890"))
+		So(err, ShouldBeNil)
+		conn.Close()
+
+		received := make([]string, 0)
+		for len(received) < 2 {
+			select {
+			case metric := <-metricsChan:
+				received = append(received, metric.Metric)
+			case <-time.After(time.Second * 5):
+				t.Fatal("Timeout waiting for metrics")
+			}
+		}
+		So(received, ShouldResemble, []string{"Simple.one", "Simple.two"})
+
+		So(listener.Stop(), ShouldBeNil)
+		_, ok := <-metricsChan
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func framePickleMessage(message string) []byte {
+	frame := make([]byte, 4, 4+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+	return append(frame, message...)
+}
@@ -0,0 +1,180 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+)
+
+// DefaultRemoteWriteMetricNameTemplate builds graphite metric name from job, instance and metric name labels
+const DefaultRemoteWriteMetricNameTemplate = "{{.job}}.{{.instance}}.{{.__name__}}"
+
+const remoteWriteShutdownTimeout = 10 * time.Second
+
+// Timeouts of remote write connections, so slow clients do not hold them forever
+const (
+	remoteWriteReadHeaderTimeout = 10 * time.Second
+	remoteWriteReadTimeout       = 30 * time.Second
+	remoteWriteWriteTimeout      = 30 * time.Second
+	remoteWriteIdleTimeout       = 2 * time.Minute
+)
+
+// maxRemoteWriteRequestSize limits compressed remote write request body, Prometheus sends much smaller batches
+const maxRemoteWriteRequestSize = 10 << 20
+
+// RemoteWriteListener accepts Prometheus remote write requests over HTTP,
+// flattens received samples to graphite metrics and handles them like plaintext lines
+type RemoteWriteListener struct {
+	listener       net.Listener
+	server         *http.Server
+	logger         moira.Logger
+	patternStorage *filter.PatternStorage
+	nameTemplate   *template.Template
+	metricsChan    chan *moira.MatchedMetric
+	tomb           tomb.Tomb
+}
+
+// NewRemoteWriteListener creates new Prometheus remote write listener on given address.
+// Metric names are built from sample labels with given text/template
+func NewRemoteWriteListener(address, metricNameTemplate string, logger moira.Logger, patternStorage *filter.PatternStorage) (*RemoteWriteListener, error) {
+	nameTemplate, err := parseMetricNameTemplate(metricNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse metric name template [%s]: %s", metricNameTemplate, err.Error())
+	}
+	newListener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to listen on [%s]: %s", address, err.Error())
+	}
+	listener := &RemoteWriteListener{
+		listener:       newListener,
+		logger:         logger,
+		patternStorage: patternStorage,
+		nameTemplate:   nameTemplate,
+	}
+	listener.server = &http.Server{
+		Handler:           listener,
+		ReadHeaderTimeout: remoteWriteReadHeaderTimeout,
+		ReadTimeout:       remoteWriteReadTimeout,
+		WriteTimeout:      remoteWriteWriteTimeout,
+		IdleTimeout:       remoteWriteIdleTimeout,
+	}
+	return listener, nil
+}
+
+// Listen starts serving remote write requests. All matched metrics sets to returned channel
+func (listener *RemoteWriteListener) Listen() chan *moira.MatchedMetric {
+	listener.metricsChan = make(chan *moira.MatchedMetric, 10000)
+	listener.tomb.Go(func() error {
+		err := listener.server.Serve(listener.listener)
+		if err != nil && err != http.ErrServerClosed {
+			listener.logger.Errorf("Prometheus remote write listener failed: %s", err.Error())
+		}
+		return nil
+	})
+	listener.tomb.Go(func() error {
+		<-listener.tomb.Dying()
+		listener.logger.Info("Stopping Prometheus remote write listener...")
+		ctx, cancel := context.WithTimeout(context.Background(), remoteWriteShutdownTimeout)
+		defer cancel()
+		if err := listener.server.Shutdown(ctx); err != nil {
+			listener.logger.Errorf("Failed to shutdown Prometheus remote write listener: %s", err.Error())
+		}
+		close(listener.metricsChan)
+		listener.logger.Info("Moira Filter Prometheus remote write listener stopped")
+		return nil
+	})
+	listener.logger.Infof("Moira Filter Prometheus remote write listener started on %s", listener.listener.Addr())
+	return listener.metricsChan
+}
+
+// Stop stops serving remote write requests
+func (listener *RemoteWriteListener) Stop() error {
+	listener.tomb.Kill(nil)
+	return listener.tomb.Wait()
+}
+
+// ServeHTTP handles single Prometheus remote write request
+func (listener *RemoteWriteListener) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, maxRemoteWriteRequestSize))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	timeSeries, err := decodeRemoteWriteRequest(body)
+	if err != nil {
+		listener.logger.Infof("Failed to decode remote write request from %s: %s", request.RemoteAddr, err.Error())
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, series := range timeSeries {
+		listener.handleTimeSeries(series)
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (listener *RemoteWriteListener) handleTimeSeries(series *remoteWriteTimeSeries) {
+	metricName, err := listener.buildMetricName(series.Labels)
+	if err != nil {
+		listener.logger.Infof("Failed to build metric name from labels %v: %s", series.Labels, err.Error())
+		return
+	}
+	for _, sample := range series.Samples {
+		// NaN is used by Prometheus as staleness marker
+		if math.IsNaN(sample.Value) {
+			continue
+		}
+		line := fmt.Sprintf("%s %s %d", metricName, strconv.FormatFloat(sample.Value, 'f', -1, 64), sample.Timestamp/1000)
+		if metric := listener.patternStorage.ProcessIncomingMetric([]byte(line)); metric != nil {
+			listener.metricsChan <- metric
+		}
+	}
+}
+
+func (listener *RemoteWriteListener) buildMetricName(labels map[string]string) (string, error) {
+	sanitized := make(map[string]string, len(labels))
+	for name, value := range labels {
+		sanitized[name] = sanitizeLabelValue(value)
+	}
+	var buffer bytes.Buffer
+	if err := listener.nameTemplate.Execute(&buffer, sanitized); err != nil {
+		return "", err
+	}
+	metricName := buffer.String()
+	if metricName == "" || strings.Contains(metricName, "..") || strings.HasPrefix(metricName, ".") || strings.HasSuffix(metricName, ".") {
+		return "", fmt.Errorf("metric name [%s] has empty nodes", metricName)
+	}
+	return metricName, nil
+}
+
+// parseMetricNameTemplate parses metric name template, missing labels are rendered as empty strings
+func parseMetricNameTemplate(text string) (*template.Template, error) {
+	return template.New("metric").Option("missingkey=zero").Parse(text)
+}
+
+// sanitizeLabelValue replaces characters which can not be used inside graphite metric node
+func sanitizeLabelValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r > unicode.MaxASCII || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,188 @@
+package connection
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/golang/snappy"
+)
+
+// Protobuf wire types used by Prometheus remote write messages
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Field numbers of WriteRequest, TimeSeries, Label and Sample messages, see prometheus prompb/remote.proto and prompb/types.proto
+const (
+	writeRequestTimeSeriesField = 1
+	timeSeriesLabelsField       = 1
+	timeSeriesSamplesField      = 2
+	labelNameField              = 1
+	labelValueField             = 2
+	sampleValueField            = 1
+	sampleTimestampField        = 2
+)
+
+// remoteWriteSample is single sample of Prometheus time series, timestamp is in milliseconds
+type remoteWriteSample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// remoteWriteTimeSeries is Prometheus time series with its label set
+type remoteWriteTimeSeries struct {
+	Labels  map[string]string
+	Samples []remoteWriteSample
+}
+
+// protoField is single decoded protobuf field. For varint and fixed types value is stored in Value, for length-delimited in Bytes
+type protoField struct {
+	Number   int
+	WireType int
+	Value    uint64
+	Bytes    []byte
+}
+
+// maxRemoteWriteMessageSize limits decompressed WriteRequest message, snappy header declares decoded length
+// before decoding, so large messages are rejected without allocating memory for them
+const maxRemoteWriteMessageSize = 64 << 20
+
+// decodeRemoteWriteRequest decodes snappy-compressed Prometheus WriteRequest message
+func decodeRemoteWriteRequest(compressed []byte) ([]*remoteWriteTimeSeries, error) {
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decompress request: %s", err.Error())
+	}
+	if size > maxRemoteWriteMessageSize {
+		return nil, fmt.Errorf("decompressed request size %d exceeds limit %d", size, maxRemoteWriteMessageSize)
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decompress request: %s", err.Error())
+	}
+	timeSeries := make([]*remoteWriteTimeSeries, 0)
+	err = walkProtoFields(data, func(field protoField) error {
+		if field.Number != writeRequestTimeSeriesField || field.WireType != wireBytes {
+			return nil
+		}
+		series, err := decodeRemoteWriteTimeSeries(field.Bytes)
+		if err != nil {
+			return err
+		}
+		timeSeries = append(timeSeries, series)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode write request: %s", err.Error())
+	}
+	return timeSeries, nil
+}
+
+func decodeRemoteWriteTimeSeries(data []byte) (*remoteWriteTimeSeries, error) {
+	series := &remoteWriteTimeSeries{
+		Labels:  make(map[string]string),
+		Samples: make([]remoteWriteSample, 0),
+	}
+	err := walkProtoFields(data, func(field protoField) error {
+		if field.WireType != wireBytes {
+			return nil
+		}
+		switch field.Number {
+		case timeSeriesLabelsField:
+			name, value, err := decodeRemoteWriteLabel(field.Bytes)
+			if err != nil {
+				return err
+			}
+			series.Labels[name] = value
+		case timeSeriesSamplesField:
+			sample, err := decodeRemoteWriteSample(field.Bytes)
+			if err != nil {
+				return err
+			}
+			series.Samples = append(series.Samples, sample)
+		}
+		return nil
+	})
+	return series, err
+}
+
+func decodeRemoteWriteLabel(data []byte) (string, string, error) {
+	var name, value string
+	err := walkProtoFields(data, func(field protoField) error {
+		if field.WireType != wireBytes {
+			return nil
+		}
+		switch field.Number {
+		case labelNameField:
+			name = string(field.Bytes)
+		case labelValueField:
+			value = string(field.Bytes)
+		}
+		return nil
+	})
+	return name, value, err
+}
+
+func decodeRemoteWriteSample(data []byte) (remoteWriteSample, error) {
+	sample := remoteWriteSample{}
+	err := walkProtoFields(data, func(field protoField) error {
+		switch {
+		case field.Number == sampleValueField && field.WireType == wireFixed64:
+			sample.Value = math.Float64frombits(field.Value)
+		case field.Number == sampleTimestampField && field.WireType == wireVarint:
+			sample.Timestamp = int64(field.Value)
+		}
+		return nil
+	})
+	return sample, err
+}
+
+// walkProtoFields calls handler for every top-level field of encoded protobuf message
+func walkProtoFields(data []byte, handler func(field protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("invalid field key")
+		}
+		data = data[n:]
+		field := protoField{Number: int(key >> 3), WireType: int(key & 7)}
+		switch field.WireType {
+		case wireVarint:
+			field.Value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint in field %d", field.Number)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("unexpected end of fixed64 field %d", field.Number)
+			}
+			field.Value = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("unexpected end of fixed32 field %d", field.Number)
+			}
+			field.Value = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return fmt.Errorf("invalid length of field %d", field.Number)
+			}
+			data = data[n:]
+			field.Bytes = data[:length]
+			data = data[length:]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", field.WireType, field.Number)
+		}
+		if err := handler(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
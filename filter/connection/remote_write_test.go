@@ -0,0 +1,218 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/snappy"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestDecodeRemoteWriteRequest(t *testing.T) {
+	Convey("Given valid write request, should decode all time series", t, func() {
+		request := encodeWriteRequest(
+			testTimeSeries{
+				labels:  map[string]string{"__name__": "up", "job": "node", "instance": "host:9100"},
+				samples: []remoteWriteSample{{Value: 1, Timestamp: 1234567890000}, {Value: 0.5, Timestamp: 1234567891500}},
+			},
+			testTimeSeries{
+				labels:  map[string]string{"__name__": "load"},
+				samples: []remoteWriteSample{{Value: -2, Timestamp: 1234567890000}},
+			},
+		)
+		timeSeries, err := decodeRemoteWriteRequest(request)
+		So(err, ShouldBeNil)
+		So(timeSeries, ShouldResemble, []*remoteWriteTimeSeries{
+			{
+				Labels:  map[string]string{"__name__": "up", "job": "node", "instance": "host:9100"},
+				Samples: []remoteWriteSample{{Value: 1, Timestamp: 1234567890000}, {Value: 0.5, Timestamp: 1234567891500}},
+			},
+			{
+				Labels:  map[string]string{"__name__": "load"},
+				Samples: []remoteWriteSample{{Value: -2, Timestamp: 1234567890000}},
+			},
+		})
+	})
+
+	Convey("Given not compressed request, should return error", t, func() {
+		_, err := decodeRemoteWriteRequest([]byte("some.metric 12 1234567890"))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given truncated message, should return error", t, func() {
+		_, err := decodeRemoteWriteRequest(snappy.Encode(nil, []byte{0x0a, 0x10, 0x0a}))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given message declaring too large decoded length, should return error without decoding", t, func() {
+		header := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(header, maxRemoteWriteMessageSize+1)
+		_, err := decodeRemoteWriteRequest(header[:n])
+		So(err, ShouldResemble, fmt.Errorf("decompressed request size %d exceeds limit %d", maxRemoteWriteMessageSize+1, maxRemoteWriteMessageSize))
+	})
+}
+
+func TestRemoteWriteListener(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Filter")
+
+	database.EXPECT().GetPatterns().Return([]string{"node.*.up", "node.*.load"}, nil)
+	patternStorage, err := filter.NewPatternStorage(database, metrics.ConfigureFilterMetrics("test"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := &RemoteWriteListener{
+		logger:         logger,
+		patternStorage: patternStorage,
+		metricsChan:    make(chan *moira.MatchedMetric, 10),
+	}
+
+	Convey("Build metric name", t, func() {
+		var err error
+		listener.nameTemplate, err = parseMetricNameTemplate(DefaultRemoteWriteMetricNameTemplate)
+		So(err, ShouldBeNil)
+
+		Convey("Label values should be sanitized", func() {
+			name, err := listener.buildMetricName(map[string]string{"__name__": "up", "job": "node", "instance": "10.0.0.1:9100"})
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "node.10_0_0_1:9100.up")
+
+			name, err = listener.buildMetricName(map[string]string{"__name__": "up", "job": "my job\n", "instance": "тест"})
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "my_job_.____.up")
+		})
+
+		Convey("Missing labels should produce error", func() {
+			_, err := listener.buildMetricName(map[string]string{"__name__": "up"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Server should limit slow clients", t, func() {
+		newListener, err := NewRemoteWriteListener("127.0.0.1:0", DefaultRemoteWriteMetricNameTemplate, logger, patternStorage)
+		So(err, ShouldBeNil)
+		defer newListener.listener.Close()
+		So(newListener.server.ReadHeaderTimeout, ShouldEqual, remoteWriteReadHeaderTimeout)
+		So(newListener.server.ReadTimeout, ShouldEqual, remoteWriteReadTimeout)
+		So(newListener.server.WriteTimeout, ShouldEqual, remoteWriteWriteTimeout)
+	})
+
+	Convey("Serve remote write request", t, func() {
+		var err error
+		listener.nameTemplate, err = parseMetricNameTemplate(DefaultRemoteWriteMetricNameTemplate)
+		So(err, ShouldBeNil)
+
+		Convey("Only POST is allowed", func() {
+			recorder := httptest.NewRecorder()
+			listener.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			So(recorder.Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+
+		Convey("Invalid body should be rejected", func() {
+			recorder := httptest.NewRecorder()
+			listener.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("invalid"))))
+			So(recorder.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Too large body should be rejected", func() {
+			recorder := httptest.NewRecorder()
+			body := bytes.NewReader(make([]byte, maxRemoteWriteRequestSize+1))
+			listener.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", body))
+			So(recorder.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+		})
+
+		Convey("Matched samples should be sent to metrics channel", func() {
+			request := encodeWriteRequest(
+				testTimeSeries{
+					labels:  map[string]string{"__name__": "up", "job": "node", "instance": "host"},
+					samples: []remoteWriteSample{{Value: 1, Timestamp: 1234567890000}, {Value: math.NaN(), Timestamp: 1234567950000}},
+				},
+				testTimeSeries{
+					labels:  map[string]string{"__name__": "load", "job": "node", "instance": "host"},
+					samples: []remoteWriteSample{{Value: 0.25, Timestamp: 1234567891999}},
+				},
+				testTimeSeries{
+					labels:  map[string]string{"__name__": "up", "job": "other", "instance": "host"},
+					samples: []remoteWriteSample{{Value: 1, Timestamp: 1234567890000}},
+				},
+			)
+			recorder := httptest.NewRecorder()
+			listener.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(request)))
+			So(recorder.Code, ShouldEqual, http.StatusNoContent)
+			So(len(listener.metricsChan), ShouldEqual, 2)
+
+			first := <-listener.metricsChan
+			So(first.Metric, ShouldEqual, "node.host.up")
+			So(first.Value, ShouldEqual, 1)
+			So(first.Timestamp, ShouldEqual, 1234567890)
+			So(first.Patterns, ShouldResemble, []string{"node.*.up"})
+
+			second := <-listener.metricsChan
+			So(second.Metric, ShouldEqual, "node.host.load")
+			So(second.Value, ShouldEqual, 0.25)
+			So(second.Timestamp, ShouldEqual, 1234567891)
+			So(second.Patterns, ShouldResemble, []string{"node.*.load"})
+		})
+	})
+}
+
+type testTimeSeries struct {
+	labels  map[string]string
+	samples []remoteWriteSample
+}
+
+func encodeWriteRequest(timeSeries ...testTimeSeries) []byte {
+	request := make([]byte, 0)
+	for _, series := range timeSeries {
+		encoded := make([]byte, 0)
+		names := make([]string, 0, len(series.labels))
+		for name := range series.labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			label := appendBytesField(nil, labelNameField, []byte(name))
+			label = appendBytesField(label, labelValueField, []byte(series.labels[name]))
+			encoded = appendBytesField(encoded, timeSeriesLabelsField, label)
+		}
+		for _, sample := range series.samples {
+			encodedSample := appendVarint(nil, sampleValueField<<3|wireFixed64)
+			value := make([]byte, 8)
+			binary.LittleEndian.PutUint64(value, math.Float64bits(sample.Value))
+			encodedSample = append(encodedSample, value...)
+			encodedSample = appendVarint(encodedSample, sampleTimestampField<<3|wireVarint)
+			encodedSample = appendVarint(encodedSample, uint64(sample.Timestamp))
+			encoded = appendBytesField(encoded, timeSeriesSamplesField, encodedSample)
+		}
+		request = appendBytesField(request, writeRequestTimeSeriesField, encoded)
+	}
+	return snappy.Encode(nil, request)
+}
+
+func appendBytesField(buffer []byte, field int, value []byte) []byte {
+	buffer = appendVarint(buffer, uint64(field<<3|wireBytes))
+	buffer = appendVarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
+
+func appendVarint(buffer []byte, value uint64) []byte {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(encoded, value)
+	return append(buffer, encoded[:n]...)
+}
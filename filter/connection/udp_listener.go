@@ -0,0 +1,91 @@
+package connection
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+)
+
+// maxUDPPacketSize is maximum size of UDP datagram payload
+const maxUDPPacketSize = 65535
+
+// UDPListener receives graphite plaintext metrics from UDP datagrams, every datagram may contain several lines
+type UDPListener struct {
+	conn           *net.UDPConn
+	logger         moira.Logger
+	patternStorage *filter.PatternStorage
+	tomb           tomb.Tomb
+}
+
+// NewUDPListener creates new UDP listener
+func NewUDPListener(port string, logger moira.Logger, patternStorage *filter.PatternStorage) (*UDPListener, error) {
+	address, err := net.ResolveUDPAddr("udp", port)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve udp address [%s]: %s", port, err.Error())
+	}
+	conn, err := net.ListenUDP("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to listen on udp [%s]: %s", port, err.Error())
+	}
+	return &UDPListener{
+		conn:           conn,
+		logger:         logger,
+		patternStorage: patternStorage,
+	}, nil
+}
+
+// Listen reads datagrams and handles every line in it. All handled data sets to metricsChan
+func (listener *UDPListener) Listen() chan *moira.MatchedMetric {
+	metricsChan := make(chan *moira.MatchedMetric, 10000)
+	listener.tomb.Go(func() error {
+		buffer := make([]byte, maxUDPPacketSize)
+		for {
+			select {
+			case <-listener.tomb.Dying():
+				{
+					listener.logger.Info("Stopping UDP listener...")
+					listener.conn.Close()
+					close(metricsChan)
+					listener.logger.Info("Moira Filter UDP Listener stopped")
+					return nil
+				}
+			default:
+			}
+			listener.conn.SetReadDeadline(time.Now().Add(1e9))
+			n, _, err := listener.conn.ReadFromUDP(buffer)
+			if err != nil {
+				if opErr, ok := err.(*net.OpError); ok && opErr.Timeout() {
+					continue
+				}
+				listener.logger.Infof("Failed to read udp packet: %s", err.Error())
+				continue
+			}
+			listener.handlePacket(buffer[:n], metricsChan)
+		}
+	})
+	listener.logger.Info("Moira Filter UDP Listener Started")
+	return metricsChan
+}
+
+// Stop stops reading datagrams
+func (listener *UDPListener) Stop() error {
+	listener.tomb.Kill(nil)
+	return listener.tomb.Wait()
+}
+
+func (listener *UDPListener) handlePacket(packet []byte, metricsChan chan *moira.MatchedMetric) {
+	for _, lineBytes := range bytes.Split(packet, []byte{'\n'}) {
+		if len(lineBytes) == 0 {
+			continue
+		}
+		if m := listener.patternStorage.ProcessIncomingMetric(lineBytes); m != nil {
+			metricsChan <- m
+		}
+	}
+}
@@ -22,11 +22,20 @@ type Database interface {
 	GetTriggerLastCheck(triggerID string) (CheckData, error)
 	SetTriggerLastCheck(triggerID string, checkData *CheckData) error
 	RemoveTriggerLastCheck(triggerID string) error
+	AddTriggerStateSnapshot(triggerID string, snapshot *TriggerStateSnapshot) error
+	GetTriggerStateHistory(triggerID string, from int64, to int64) ([]*TriggerStateSnapshot, error)
+	AddTriggerRevision(triggerID string, revision *TriggerRevision) error
+	GetTriggerRevisions(triggerID string) ([]*TriggerRevision, error)
+	GetTriggerRevision(triggerID string, number int64) (TriggerRevision, error)
 	GetTriggerCheckIDs(tags []string, onlyErrors bool) ([]string, error)
 	SetTriggerCheckMetricsMaintenance(triggerID string, metrics map[string]int64) error
+	SetTriggerCheckAcknowledgement(triggerID string, metrics []string, ack *Acknowledgement) error
 
 	// Trigger storing
 	GetTriggerIDs() ([]string, error)
+	GetRemoteTriggerIDs() ([]string, error)
+	GetAggregateTriggerIDs() ([]string, error)
+	GetInhibitingTriggerIDs() ([]string, error)
 	GetTrigger(triggerID string) (Trigger, error)
 	GetTriggers(triggerIDs []string) ([]*Trigger, error)
 	GetTriggerChecks(triggerIDs []string) ([]*TriggerCheck, error)
@@ -35,10 +44,19 @@ type Database interface {
 	GetPatternTriggerIDs(pattern string) ([]string, error)
 	RemovePatternTriggerIDs(pattern string) error
 
+	// TriggerTemplate storing
+	GetTriggerTemplate(templateID string) (TriggerTemplate, error)
+	GetTriggerTemplates() ([]*TriggerTemplate, error)
+	SaveTriggerTemplate(template *TriggerTemplate) error
+	RemoveTriggerTemplate(templateID string) error
+	GetTemplateTriggerIDs(templateID string) ([]string, error)
+
 	// Throttling
 	GetTriggerThrottling(triggerID string) (time.Time, time.Time)
 	SetTriggerThrottling(triggerID string, next time.Time) error
 	DeleteTriggerThrottling(triggerID string) error
+	GetSubscriptionThrottling(triggerID, subscriptionID string) (time.Time, time.Time)
+	SetSubscriptionThrottling(triggerID, subscriptionID string, next time.Time) error
 
 	// NotificationEvent storing
 	GetNotificationEvents(triggerID string, start, size int64) ([]*NotificationEvent, error)
@@ -54,6 +72,25 @@ type Database interface {
 	SaveContact(contact *ContactData) error
 	GetUserContactIDs(userLogin string) ([]string, error)
 
+	// UserToken storing
+	GetUserToken(tokenID string) (UserToken, error)
+	GetUserTokens(userLogin string) ([]*UserToken, error)
+	SaveUserToken(token *UserToken) error
+	RemoveUserToken(tokenID string) error
+
+	// Team storing
+	GetTeam(teamID string) (Team, error)
+	SaveTeam(team *Team) error
+	RemoveTeam(teamID string) error
+	GetUserTeamIDs(userLogin string) ([]string, error)
+	GetTeamTriggerIDs(teamID string) ([]string, error)
+	GetTeamContactIDs(teamID string) ([]string, error)
+	GetTeamSubscriptionIDs(teamID string) ([]string, error)
+
+	// AuditRecord storing
+	AddAuditRecord(record *AuditRecord) error
+	GetAuditRecords(from int64, to int64) ([]*AuditRecord, error)
+
 	// SubscriptionData storing
 	GetSubscription(id string) (SubscriptionData, error)
 	GetSubscriptions(subscriptionIDs []string) ([]*SubscriptionData, error)
@@ -84,6 +121,7 @@ type Database interface {
 	GetMetricsValues(metrics []string, from int64, until int64) (map[string][]*MetricValue, error)
 	RemoveMetricValues(metric string, toTime int64) error
 	RemoveMetricsValues(metrics []string, toTime int64) error
+	KeepMetricsValues(metrics []string, period int64) error
 
 	// TriggerCheckLock storing
 	AcquireTriggerCheckLock(triggerID string, timeout int) error
@@ -119,3 +157,24 @@ type Sender interface {
 	SendEvents(events NotificationEvents, contact ContactData, trigger TriggerData, throttled bool) error
 	Init(senderSettings map[string]string, logger Logger, location *time.Location) error
 }
+
+// DigestSender is implemented by senders able to render events of several triggers as one grouped message.
+// Digests to contacts of other senders are sent as separate per-trigger messages
+type DigestSender interface {
+	SendDigest(items []DigestItem, contact ContactData) error
+}
+
+// SenderBrokenContactError is returned by Sender if notification can not be delivered because of invalid contact,
+// such notifications will never be delivered and should not be resent
+type SenderBrokenContactError struct {
+	SenderError error
+}
+
+// NewSenderBrokenContactError wraps given sender error to SenderBrokenContactError
+func NewSenderBrokenContactError(senderError error) SenderBrokenContactError {
+	return SenderBrokenContactError{SenderError: senderError}
+}
+
+func (err SenderBrokenContactError) Error() string {
+	return err.SenderError.Error()
+}
@@ -0,0 +1,8 @@
+package prometheus
+
+// Config for prometheus metrics endpoint settings
+type Config struct {
+	Enabled        bool
+	Listen         string
+	TriggerIDLabel bool
+}
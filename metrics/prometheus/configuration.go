@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"regexp"
+
+	"github.com/moira-alert/moira/metrics/graphite"
+)
+
+const namespace = "moira"
+
+// Label names
+const (
+	senderLabel    = "sender"
+	triggerIDLabel = "trigger_id"
+)
+
+// ConfigureFilterMetrics initialize prometheus filter metrics
+func ConfigureFilterMetrics(registry *Registry, prefix string) *graphite.FilterMetrics {
+	return &graphite.FilterMetrics{
+		TotalMetricsReceived:    registry.newCounter(metricName(prefix, "received_total"), "Total number of received metrics", nil),
+		ValidMetricsReceived:    registry.newCounter(metricName(prefix, "received_valid_total"), "Number of received metrics with valid format", nil),
+		MatchingMetricsReceived: registry.newCounter(metricName(prefix, "received_matching_total"), "Number of received metrics matched with any pattern", nil),
+		MatchingTimer:           registry.newTimer(metricName(prefix, "match_duration_seconds"), "Time spent on matching metric with patterns", nil),
+		SavingTimer:             registry.newTimer(metricName(prefix, "save_duration_seconds"), "Time spent on saving matched metrics", nil),
+		BuildTreeTimer:          registry.newTimer(metricName(prefix, "build_tree_duration_seconds"), "Time spent on building patterns tree", nil),
+	}
+}
+
+// ConfigureNotifierMetrics is notifier metrics configurator
+func ConfigureNotifierMetrics(registry *Registry, prefix string) *graphite.NotifierMetrics {
+	return &graphite.NotifierMetrics{
+		SubsMalformed:          registry.newMeter(metricName(prefix, "subscriptions_malformed_total"), "Number of malformed subscriptions", nil),
+		EventsReceived:         registry.newMeter(metricName(prefix, "events_received_total"), "Number of received trigger events", nil),
+		EventsMalformed:        registry.newMeter(metricName(prefix, "events_malformed_total"), "Number of malformed trigger events", nil),
+		EventsProcessingFailed: registry.newMeter(metricName(prefix, "events_failed_total"), "Number of trigger events failed to process", nil),
+		SendingFailed:          registry.newMeter(metricName(prefix, "sending_failed_total"), "Number of notification packages failed to send", nil),
+		SendersOkMetrics:       newMeterMap(registry, metricName(prefix, "sends_ok_total"), "Number of successfully sent notification packages by sender", senderLabel),
+		SendersFailedMetrics:   newMeterMap(registry, metricName(prefix, "sends_failed_total"), "Number of notification packages failed to send by sender", senderLabel),
+	}
+}
+
+// ConfigureCheckerMetrics is checker metrics configurator.
+// Trigger check time is exposed per trigger only if withTriggerID is set, because it produces series for every trigger
+func ConfigureCheckerMetrics(registry *Registry, prefix string, withTriggerID bool) *graphite.CheckerMetrics {
+	triggerLabel := ""
+	if withTriggerID {
+		triggerLabel = triggerIDLabel
+	}
+	return &graphite.CheckerMetrics{
+		CheckError:                registry.newMeter(metricName(prefix, "check_errors_total"), "Number of failed trigger checks", nil),
+		HandleError:               registry.newMeter(metricName(prefix, "handle_errors_total"), "Number of errors during handling trigger checks", nil),
+		TriggersCheckTime:         registry.newTimer(metricName(prefix, "triggers_check_duration_seconds"), "Time spent on checking batch of triggers", nil),
+		TriggerCheckTime:          newTimerMap(registry, metricName(prefix, "trigger_check_duration_seconds"), "Time spent on checking single trigger", triggerLabel),
+		TriggersToCheckChannelLen: registry.newHistogram(metricName(prefix, "triggers_to_check_channel_length"), "Length of channel with triggers to check", nil),
+		MetricEventsChannelLen:    registry.newHistogram(metricName(prefix, "metric_events_channel_length"), "Length of channel with metric events", nil),
+		MetricEventsHandleTime:    registry.newTimer(metricName(prefix, "metric_events_handle_duration_seconds"), "Time spent on handling metric events", nil),
+	}
+}
+
+var invalidMetricNameChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+func metricName(prefix, metric string) string {
+	return invalidMetricNameChars.ReplaceAllString(namespace+"_"+prefix+"_"+metric, "_")
+}
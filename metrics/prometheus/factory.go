@@ -0,0 +1,25 @@
+package prometheus
+
+import (
+	goMetrics "github.com/rcrowley/go-metrics"
+)
+
+func (registry *Registry) newCounter(name, help string, labels labelPairs) *Counter {
+	return registry.register(name, help, typeCounter, labels, &Counter{goMetrics.NewCounter()}).(*Counter)
+}
+
+func (registry *Registry) newMeter(name, help string, labels labelPairs) *Meter {
+	return registry.register(name, help, typeCounter, labels, &Meter{goMetrics.NewMeter()}).(*Meter)
+}
+
+func (registry *Registry) newGauge(name, help string, labels labelPairs) *Gauge {
+	return registry.register(name, help, typeGauge, labels, &Gauge{goMetrics.NewGauge()}).(*Gauge)
+}
+
+func (registry *Registry) newTimer(name, help string, labels labelPairs) *Timer {
+	return registry.register(name, help, typeSummary, labels, &Timer{goMetrics.NewTimer()}).(*Timer)
+}
+
+func (registry *Registry) newHistogram(name, help string, labels labelPairs) *Histogram {
+	return registry.register(name, help, typeSummary, labels, &Histogram{goMetrics.NewHistogram(goMetrics.NewExpDecaySample(1028, 0.015))}).(*Histogram)
+}
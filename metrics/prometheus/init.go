@@ -0,0 +1,26 @@
+package prometheus
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+// Init starts HTTP server which exposes metrics of given registry on /metrics
+func Init(config Config, registry *Registry, logger moira.Logger) error {
+	listener, err := net.Listen("tcp", config.Listen)
+	if err != nil {
+		return fmt.Errorf("Can not listen prometheus metrics on %s: %s", config.Listen, err.Error())
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			logger.Infof("Prometheus metrics server stopped: %v", err)
+		}
+	}()
+	logger.Infof("Prometheus metrics are exposed at %s/metrics", config.Listen)
+	return nil
+}
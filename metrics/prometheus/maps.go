@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"sync"
+
+	"github.com/moira-alert/moira/metrics/graphite"
+)
+
+// MeterMap is collection of meters of single Prometheus metric distinguished by label value
+type MeterMap struct {
+	registry *Registry
+	name     string
+	help     string
+	label    string
+	lock     sync.RWMutex
+	metrics  map[string]*Meter
+}
+
+func newMeterMap(registry *Registry, name, help, label string) *MeterMap {
+	return &MeterMap{
+		registry: registry,
+		name:     name,
+		help:     help,
+		label:    label,
+		metrics:  make(map[string]*Meter),
+	}
+}
+
+// AddMetric adds meter with given name used as label value, graphite path is ignored
+func (metricsMap *MeterMap) AddMetric(name, path string) {
+	meter := metricsMap.registry.newMeter(metricsMap.name, metricsMap.help, labelPairs{{name: metricsMap.label, value: name}})
+	metricsMap.lock.Lock()
+	metricsMap.metrics[name] = meter
+	metricsMap.lock.Unlock()
+}
+
+// GetMetric returns meter by given name
+func (metricsMap *MeterMap) GetMetric(name string) (graphite.Meter, bool) {
+	metricsMap.lock.RLock()
+	defer metricsMap.lock.RUnlock()
+	value, found := metricsMap.metrics[name]
+	if !found {
+		return nil, false
+	}
+	return value, true
+}
+
+// TimerMap is collection of timers of single Prometheus metric.
+// If label is empty all names share single timer to avoid high-cardinality series
+type TimerMap struct {
+	registry *Registry
+	name     string
+	help     string
+	label    string
+	lock     sync.RWMutex
+	metrics  map[string]*Timer
+	shared   *Timer
+}
+
+func newTimerMap(registry *Registry, name, help, label string) *TimerMap {
+	timerMap := &TimerMap{
+		registry: registry,
+		name:     name,
+		help:     help,
+		label:    label,
+		metrics:  make(map[string]*Timer),
+	}
+	if label == "" {
+		timerMap.shared = registry.newTimer(name, help, nil)
+	}
+	return timerMap
+}
+
+// GetOrAdd gets timer and, if it does not exists, add it do map. Graphite path is ignored
+func (timerMap *TimerMap) GetOrAdd(name, graphitePath string) graphite.Timer {
+	if timerMap.shared != nil {
+		return timerMap.shared
+	}
+	timerMap.lock.RLock()
+	timer, ok := timerMap.metrics[name]
+	timerMap.lock.RUnlock()
+	if ok {
+		return timer
+	}
+	timerMap.lock.Lock()
+	defer timerMap.lock.Unlock()
+	if timer, ok := timerMap.metrics[name]; ok {
+		return timer
+	}
+	timer = timerMap.registry.newTimer(timerMap.name, timerMap.help, labelPairs{{name: timerMap.label, value: name}})
+	timerMap.metrics[name] = timer
+	return timer
+}
@@ -0,0 +1,229 @@
+// nolint
+package prometheus
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	goMetrics "github.com/rcrowley/go-metrics"
+)
+
+// quantiles are exposed for every summary metric
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+// Counter is go-metrics counter exposed as Prometheus counter
+type Counter struct {
+	counter goMetrics.Counter
+}
+
+func (counter *Counter) Clear() {
+	counter.counter.Clear()
+}
+
+func (counter *Counter) Count() int64 {
+	return counter.counter.Count()
+}
+
+func (counter *Counter) Dec(val int64) {
+	counter.counter.Dec(val)
+}
+
+func (counter *Counter) Inc(val int64) {
+	counter.counter.Inc(val)
+}
+
+func (counter *Counter) write(writer io.Writer, name string, labels labelPairs) {
+	writeSample(writer, name, labels, float64(counter.Count()))
+}
+
+// Meter is go-metrics meter exposed as Prometheus counter, rates are expected to be calculated by Prometheus
+type Meter struct {
+	meter goMetrics.Meter
+}
+
+func (metric *Meter) Count() int64 {
+	return metric.meter.Count()
+}
+
+func (metric *Meter) Mark(value int64) {
+	metric.meter.Mark(value)
+}
+
+func (metric *Meter) Rate1() float64 {
+	return metric.meter.Rate1()
+}
+
+func (metric *Meter) Rate5() float64 {
+	return metric.meter.Rate5()
+}
+
+func (metric *Meter) Rate15() float64 {
+	return metric.meter.Rate15()
+}
+
+func (metric *Meter) RateMean() float64 {
+	return metric.meter.RateMean()
+}
+
+func (metric *Meter) write(writer io.Writer, name string, labels labelPairs) {
+	writeSample(writer, name, labels, float64(metric.Count()))
+}
+
+// Gauge is go-metrics gauge exposed as Prometheus gauge
+type Gauge struct {
+	gauge goMetrics.Gauge
+}
+
+func (gauge *Gauge) Update(v int64) {
+	gauge.gauge.Update(v)
+}
+
+func (gauge *Gauge) Value() int64 {
+	return gauge.gauge.Value()
+}
+
+func (gauge *Gauge) write(writer io.Writer, name string, labels labelPairs) {
+	writeSample(writer, name, labels, float64(gauge.Value()))
+}
+
+// Timer is go-metrics timer exposed as Prometheus summary in seconds
+type Timer struct {
+	timer goMetrics.Timer
+}
+
+func (timer *Timer) Count() int64 {
+	return timer.timer.Count()
+}
+
+func (timer *Timer) Max() int64 {
+	return timer.timer.Max()
+}
+
+func (timer *Timer) Mean() float64 {
+	return timer.timer.Mean()
+}
+
+func (timer *Timer) Min() int64 {
+	return timer.timer.Min()
+}
+
+func (timer *Timer) Percentile(p float64) float64 {
+	return timer.timer.Percentile(p)
+}
+
+func (timer *Timer) Percentiles(p []float64) []float64 {
+	return timer.timer.Percentiles(p)
+}
+
+func (timer *Timer) Rate1() float64 {
+	return timer.timer.Rate1()
+}
+
+func (timer *Timer) Rate5() float64 {
+	return timer.timer.Rate5()
+}
+
+func (timer *Timer) Rate15() float64 {
+	return timer.timer.Rate15()
+}
+
+func (timer *Timer) RateMean() float64 {
+	return timer.timer.RateMean()
+}
+
+func (timer *Timer) StdDev() float64 {
+	return timer.timer.StdDev()
+}
+
+func (timer *Timer) Sum() int64 {
+	return timer.timer.Sum()
+}
+
+func (timer *Timer) Time(f func()) {
+	timer.timer.Time(f)
+}
+
+func (timer *Timer) Update(time time.Duration) {
+	timer.timer.Update(time)
+}
+
+func (timer *Timer) UpdateSince(time time.Time) {
+	timer.timer.UpdateSince(time)
+}
+
+func (timer *Timer) Variance() float64 {
+	return timer.timer.Variance()
+}
+
+func (timer *Timer) write(writer io.Writer, name string, labels labelPairs) {
+	snapshot := timer.timer.Snapshot()
+	writeSummary(writer, name, labels, snapshot.Percentiles(quantiles), float64(snapshot.Sum()), snapshot.Count(), float64(time.Second))
+}
+
+// Histogram is go-metrics histogram with exponentially decaying reservoir exposed as Prometheus summary
+type Histogram struct {
+	histogram goMetrics.Histogram
+}
+
+func (histogram *Histogram) Clear() {
+	histogram.histogram.Clear()
+}
+
+func (histogram *Histogram) Count() int64 {
+	return histogram.histogram.Count()
+}
+
+func (histogram *Histogram) Max() int64 {
+	return histogram.histogram.Max()
+}
+
+func (histogram *Histogram) Mean() float64 {
+	return histogram.histogram.Mean()
+}
+
+func (histogram *Histogram) Min() int64 {
+	return histogram.histogram.Min()
+}
+
+func (histogram *Histogram) Percentile(p float64) float64 {
+	return histogram.histogram.Percentile(p)
+}
+
+func (histogram *Histogram) Percentiles(p []float64) []float64 {
+	return histogram.histogram.Percentiles(p)
+}
+
+func (histogram *Histogram) StdDev() float64 {
+	return histogram.histogram.StdDev()
+}
+
+func (histogram *Histogram) Sum() int64 {
+	return histogram.histogram.Sum()
+}
+
+func (histogram *Histogram) Update(v int64) {
+	histogram.histogram.Update(v)
+}
+
+func (histogram *Histogram) Variance() float64 {
+	return histogram.histogram.Variance()
+}
+
+func (histogram *Histogram) write(writer io.Writer, name string, labels labelPairs) {
+	snapshot := histogram.histogram.Snapshot()
+	writeSummary(writer, name, labels, snapshot.Percentiles(quantiles), float64(snapshot.Sum()), snapshot.Count(), 1)
+}
+
+// writeSummary writes quantiles, sum and count of summary, all values are divided by given unit
+func writeSummary(writer io.Writer, name string, labels labelPairs, values []float64, sum float64, count int64, unit float64) {
+	for i, quantile := range quantiles {
+		writeSample(writer, name, labels.with("quantile", formatFloat(quantile)), values[i]/unit)
+	}
+	writeSample(writer, name+"_sum", labels, sum/unit)
+	writeSample(writer, name+"_count", labels, float64(count))
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
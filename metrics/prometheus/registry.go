@@ -0,0 +1,145 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Prometheus metric types
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+	typeSummary = "summary"
+)
+
+// collector writes samples of single series in Prometheus text format
+type collector interface {
+	write(writer io.Writer, name string, labels labelPairs)
+}
+
+// labelPair is single Prometheus label
+type labelPair struct {
+	name  string
+	value string
+}
+
+type labelPairs []labelPair
+
+// with returns new label set extended with given label
+func (labels labelPairs) with(name, value string) labelPairs {
+	result := make(labelPairs, len(labels), len(labels)+1)
+	copy(result, labels)
+	return append(result, labelPair{name: name, value: value})
+}
+
+// String formats labels as {name="value",...}, empty set is formatted as empty string
+func (labels labelPairs) String() string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label.name, escapeLabelValue(label.value)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+type series struct {
+	labels    labelPairs
+	collector collector
+}
+
+type family struct {
+	name       string
+	help       string
+	metricType string
+	series     map[string]*series
+}
+
+// Registry holds all registered metrics and exposes them in Prometheus text format
+type Registry struct {
+	lock     sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry creates empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// register adds new series to metrics family, if series with same labels already exists it is returned instead of given one
+func (registry *Registry) register(name, help, metricType string, labels labelPairs, metric collector) collector {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	metricFamily, ok := registry.families[name]
+	if !ok {
+		metricFamily = &family{
+			name:       name,
+			help:       help,
+			metricType: metricType,
+			series:     make(map[string]*series),
+		}
+		registry.families[name] = metricFamily
+	}
+	key := labels.String()
+	if existing, ok := metricFamily.series[key]; ok {
+		return existing.collector
+	}
+	metricFamily.series[key] = &series{labels: labels, collector: metric}
+	return metric
+}
+
+// WriteTo writes all registered metrics to given writer in Prometheus text format
+func (registry *Registry) WriteTo(writer io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+
+	registry.lock.Lock()
+	names := make([]string, 0, len(registry.families))
+	for name := range registry.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		metricFamily := registry.families[name]
+		fmt.Fprintf(&buffer, "# HELP %s %s\n", metricFamily.name, metricFamily.help)
+		fmt.Fprintf(&buffer, "# TYPE %s %s\n", metricFamily.name, metricFamily.metricType)
+		keys := make([]string, 0, len(metricFamily.series))
+		for key := range metricFamily.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			metricSeries := metricFamily.series[key]
+			metricSeries.collector.write(&buffer, metricFamily.name, metricSeries.labels)
+		}
+	}
+	registry.lock.Unlock()
+
+	return buffer.WriteTo(writer)
+}
+
+// ServeHTTP handles Prometheus scrape request
+func (registry *Registry) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", contentType)
+	registry.WriteTo(writer)
+}
+
+func writeSample(writer io.Writer, name string, labels labelPairs, value float64) {
+	fmt.Fprintf(writer, "%s%s %s\n", name, labels, formatFloat(value))
+}
@@ -0,0 +1,132 @@
+package prometheus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegistry(t *testing.T) {
+	Convey("Registered metrics should be written in text format", t, func() {
+		registry := NewRegistry()
+		counter := registry.newCounter("moira_test_total", "Test counter", nil)
+		gauge := registry.newGauge("moira_test_gauge", "Test gauge", labelPairs{{name: "type", value: `a "quoted"\value`}})
+		counter.Inc(3)
+		gauge.Update(-5)
+
+		var buffer bytes.Buffer
+		_, err := registry.WriteTo(&buffer)
+		So(err, ShouldBeNil)
+		So(buffer.String(), ShouldEqual, `# HELP moira_test_gauge Test gauge
+# TYPE moira_test_gauge gauge
+moira_test_gauge{type="a \"quoted\"\\value"} -5
+# HELP moira_test_total Test counter
+# TYPE moira_test_total counter
+moira_test_total 3
+`)
+	})
+
+	Convey("Registering same series twice should return existing metric", t, func() {
+		registry := NewRegistry()
+		first := registry.newMeter("moira_test_total", "Test", labelPairs{{name: "sender", value: "mail"}})
+		second := registry.newMeter("moira_test_total", "Test", labelPairs{{name: "sender", value: "mail"}})
+		first.Mark(1)
+		So(second.Count(), ShouldEqual, 1)
+	})
+
+	Convey("Timer should be written as summary in seconds", t, func() {
+		registry := NewRegistry()
+		timer := registry.newTimer("moira_test_duration_seconds", "Test timer", nil)
+		timer.Update(time.Second * 2)
+		timer.Update(time.Second * 2)
+
+		var buffer bytes.Buffer
+		registry.WriteTo(&buffer)
+		So(buffer.String(), ShouldEqual, `# HELP moira_test_duration_seconds Test timer
+# TYPE moira_test_duration_seconds summary
+moira_test_duration_seconds{quantile="0.5"} 2
+moira_test_duration_seconds{quantile="0.75"} 2
+moira_test_duration_seconds{quantile="0.95"} 2
+moira_test_duration_seconds{quantile="0.99"} 2
+moira_test_duration_seconds_sum 4
+moira_test_duration_seconds_count 2
+`)
+	})
+
+	Convey("Registry should serve metrics over HTTP", t, func() {
+		registry := NewRegistry()
+		registry.newCounter("moira_test_total", "Test counter", nil).Inc(1)
+		recorder := httptest.NewRecorder()
+		registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(recorder.Header().Get("Content-Type"), ShouldEqual, contentType)
+		So(recorder.Body.String(), ShouldContainSubstring, "moira_test_total 1\n")
+	})
+}
+
+func TestConfigureMetrics(t *testing.T) {
+	Convey("Notifier sender metrics should be labeled by sender type", t, func() {
+		registry := NewRegistry()
+		notifierMetrics := ConfigureNotifierMetrics(registry, "notifier")
+		notifierMetrics.SendersOkMetrics.AddMetric("mail", "notifier.mail.sends_ok")
+		notifierMetrics.SendersOkMetrics.AddMetric("slack", "notifier.slack.sends_ok")
+		notifierMetrics.SendersFailedMetrics.AddMetric("mail", "notifier.mail.sends_failed")
+
+		metric, found := notifierMetrics.SendersOkMetrics.GetMetric("mail")
+		So(found, ShouldBeTrue)
+		metric.Mark(2)
+		_, found = notifierMetrics.SendersOkMetrics.GetMetric("telegram")
+		So(found, ShouldBeFalse)
+
+		var buffer bytes.Buffer
+		registry.WriteTo(&buffer)
+		So(buffer.String(), ShouldContainSubstring, "moira_notifier_sends_ok_total{sender=\"mail\"} 2\n")
+		So(buffer.String(), ShouldContainSubstring, "moira_notifier_sends_ok_total{sender=\"slack\"} 0\n")
+		So(buffer.String(), ShouldContainSubstring, "moira_notifier_sends_failed_total{sender=\"mail\"} 0\n")
+	})
+
+	Convey("Trigger check time", t, func() {
+		Convey("Without trigger id label should be shared", func() {
+			registry := NewRegistry()
+			checkerMetrics := ConfigureCheckerMetrics(registry, "checker", false)
+			checkerMetrics.TriggerCheckTime.GetOrAdd("trigger-1", "trigger-1").Update(time.Second)
+			checkerMetrics.TriggerCheckTime.GetOrAdd("trigger-2", "trigger-2").Update(time.Second)
+
+			var buffer bytes.Buffer
+			registry.WriteTo(&buffer)
+			So(buffer.String(), ShouldContainSubstring, "moira_checker_trigger_check_duration_seconds_count 2\n")
+			So(buffer.String(), ShouldNotContainSubstring, "trigger_id")
+		})
+
+		Convey("With trigger id label should be exposed per trigger", func() {
+			registry := NewRegistry()
+			checkerMetrics := ConfigureCheckerMetrics(registry, "checker", true)
+			checkerMetrics.TriggerCheckTime.GetOrAdd("trigger-1", "trigger-1").Update(time.Second)
+			checkerMetrics.TriggerCheckTime.GetOrAdd("trigger-2", "trigger-2").Update(time.Second)
+			checkerMetrics.TriggerCheckTime.GetOrAdd("trigger-2", "trigger-2").Update(time.Second)
+
+			var buffer bytes.Buffer
+			registry.WriteTo(&buffer)
+			So(buffer.String(), ShouldContainSubstring, "moira_checker_trigger_check_duration_seconds_count{trigger_id=\"trigger-1\"} 1\n")
+			So(buffer.String(), ShouldContainSubstring, "moira_checker_trigger_check_duration_seconds_count{trigger_id=\"trigger-2\"} 2\n")
+		})
+	})
+
+	Convey("Metric names should be valid", t, func() {
+		registry := NewRegistry()
+		ConfigureFilterMetrics(registry, "my-filter")
+		var buffer bytes.Buffer
+		registry.WriteTo(&buffer)
+		So(buffer.String(), ShouldContainSubstring, "moira_my_filter_received_total 0\n")
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			if !strings.HasPrefix(line, "#") {
+				So(line, ShouldStartWith, "moira_my_filter_")
+			}
+		}
+	})
+}
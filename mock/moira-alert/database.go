@@ -48,6 +48,18 @@ func (mr *MockDatabaseMockRecorder) AcquireTriggerCheckLock(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireTriggerCheckLock", reflect.TypeOf((*MockDatabase)(nil).AcquireTriggerCheckLock), arg0, arg1)
 }
 
+// AddAuditRecord mocks base method
+func (m *MockDatabase) AddAuditRecord(arg0 *moira.AuditRecord) error {
+	ret := m.ctrl.Call(m, "AddAuditRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuditRecord indicates an expected call of AddAuditRecord
+func (mr *MockDatabaseMockRecorder) AddAuditRecord(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditRecord", reflect.TypeOf((*MockDatabase)(nil).AddAuditRecord), arg0)
+}
+
 // AddNotification mocks base method
 func (m *MockDatabase) AddNotification(arg0 *moira.ScheduledNotification) error {
 	ret := m.ctrl.Call(m, "AddNotification", arg0)
@@ -84,6 +96,30 @@ func (mr *MockDatabaseMockRecorder) AddPatternMetric(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPatternMetric", reflect.TypeOf((*MockDatabase)(nil).AddPatternMetric), arg0, arg1)
 }
 
+// AddTriggerRevision mocks base method
+func (m *MockDatabase) AddTriggerRevision(arg0 string, arg1 *moira.TriggerRevision) error {
+	ret := m.ctrl.Call(m, "AddTriggerRevision", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTriggerRevision indicates an expected call of AddTriggerRevision
+func (mr *MockDatabaseMockRecorder) AddTriggerRevision(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTriggerRevision", reflect.TypeOf((*MockDatabase)(nil).AddTriggerRevision), arg0, arg1)
+}
+
+// AddTriggerStateSnapshot mocks base method
+func (m *MockDatabase) AddTriggerStateSnapshot(arg0 string, arg1 *moira.TriggerStateSnapshot) error {
+	ret := m.ctrl.Call(m, "AddTriggerStateSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTriggerStateSnapshot indicates an expected call of AddTriggerStateSnapshot
+func (mr *MockDatabaseMockRecorder) AddTriggerStateSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTriggerStateSnapshot", reflect.TypeOf((*MockDatabase)(nil).AddTriggerStateSnapshot), arg0, arg1)
+}
+
 // DeleteTriggerCheckLock mocks base method
 func (m *MockDatabase) DeleteTriggerCheckLock(arg0 string) error {
 	ret := m.ctrl.Call(m, "DeleteTriggerCheckLock", arg0)
@@ -156,6 +192,19 @@ func (mr *MockDatabaseMockRecorder) FetchNotifications(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNotifications", reflect.TypeOf((*MockDatabase)(nil).FetchNotifications), arg0)
 }
 
+// GetAggregateTriggerIDs mocks base method
+func (m *MockDatabase) GetAggregateTriggerIDs() ([]string, error) {
+	ret := m.ctrl.Call(m, "GetAggregateTriggerIDs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAggregateTriggerIDs indicates an expected call of GetAggregateTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetAggregateTriggerIDs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregateTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetAggregateTriggerIDs))
+}
+
 // GetAllContacts mocks base method
 func (m *MockDatabase) GetAllContacts() ([]*moira.ContactData, error) {
 	ret := m.ctrl.Call(m, "GetAllContacts")
@@ -169,6 +218,19 @@ func (mr *MockDatabaseMockRecorder) GetAllContacts() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllContacts", reflect.TypeOf((*MockDatabase)(nil).GetAllContacts))
 }
 
+// GetAuditRecords mocks base method
+func (m *MockDatabase) GetAuditRecords(arg0, arg1 int64) ([]*moira.AuditRecord, error) {
+	ret := m.ctrl.Call(m, "GetAuditRecords", arg0, arg1)
+	ret0, _ := ret[0].([]*moira.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditRecords indicates an expected call of GetAuditRecords
+func (mr *MockDatabaseMockRecorder) GetAuditRecords(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecords", reflect.TypeOf((*MockDatabase)(nil).GetAuditRecords), arg0, arg1)
+}
+
 // GetChecksUpdatesCount mocks base method
 func (m *MockDatabase) GetChecksUpdatesCount() (int64, error) {
 	ret := m.ctrl.Call(m, "GetChecksUpdatesCount")
@@ -221,6 +283,19 @@ func (mr *MockDatabaseMockRecorder) GetIDByUsername(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDByUsername", reflect.TypeOf((*MockDatabase)(nil).GetIDByUsername), arg0, arg1)
 }
 
+// GetInhibitingTriggerIDs mocks base method
+func (m *MockDatabase) GetInhibitingTriggerIDs() ([]string, error) {
+	ret := m.ctrl.Call(m, "GetInhibitingTriggerIDs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInhibitingTriggerIDs indicates an expected call of GetInhibitingTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetInhibitingTriggerIDs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInhibitingTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetInhibitingTriggerIDs))
+}
+
 // GetMetricRetention mocks base method
 func (m *MockDatabase) GetMetricRetention(arg0 string) (int64, error) {
 	ret := m.ctrl.Call(m, "GetMetricRetention", arg0)
@@ -338,6 +413,19 @@ func (mr *MockDatabaseMockRecorder) GetPatterns() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPatterns", reflect.TypeOf((*MockDatabase)(nil).GetPatterns))
 }
 
+// GetRemoteTriggerIDs mocks base method
+func (m *MockDatabase) GetRemoteTriggerIDs() ([]string, error) {
+	ret := m.ctrl.Call(m, "GetRemoteTriggerIDs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteTriggerIDs indicates an expected call of GetRemoteTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetRemoteTriggerIDs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggerIDs))
+}
+
 // GetSubscription mocks base method
 func (m *MockDatabase) GetSubscription(arg0 string) (moira.SubscriptionData, error) {
 	ret := m.ctrl.Call(m, "GetSubscription", arg0)
@@ -351,6 +439,19 @@ func (mr *MockDatabaseMockRecorder) GetSubscription(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockDatabase)(nil).GetSubscription), arg0)
 }
 
+// GetSubscriptionThrottling mocks base method
+func (m *MockDatabase) GetSubscriptionThrottling(arg0, arg1 string) (time.Time, time.Time) {
+	ret := m.ctrl.Call(m, "GetSubscriptionThrottling", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(time.Time)
+	return ret0, ret1
+}
+
+// GetSubscriptionThrottling indicates an expected call of GetSubscriptionThrottling
+func (mr *MockDatabaseMockRecorder) GetSubscriptionThrottling(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionThrottling", reflect.TypeOf((*MockDatabase)(nil).GetSubscriptionThrottling), arg0, arg1)
+}
+
 // GetSubscriptions mocks base method
 func (m *MockDatabase) GetSubscriptions(arg0 []string) ([]*moira.SubscriptionData, error) {
 	ret := m.ctrl.Call(m, "GetSubscriptions", arg0)
@@ -403,6 +504,71 @@ func (mr *MockDatabaseMockRecorder) GetTagsSubscriptions(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsSubscriptions", reflect.TypeOf((*MockDatabase)(nil).GetTagsSubscriptions), arg0)
 }
 
+// GetTeam mocks base method
+func (m *MockDatabase) GetTeam(arg0 string) (moira.Team, error) {
+	ret := m.ctrl.Call(m, "GetTeam", arg0)
+	ret0, _ := ret[0].(moira.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeam indicates an expected call of GetTeam
+func (mr *MockDatabaseMockRecorder) GetTeam(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockDatabase)(nil).GetTeam), arg0)
+}
+
+// GetTeamContactIDs mocks base method
+func (m *MockDatabase) GetTeamContactIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetTeamContactIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamContactIDs indicates an expected call of GetTeamContactIDs
+func (mr *MockDatabaseMockRecorder) GetTeamContactIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamContactIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamContactIDs), arg0)
+}
+
+// GetTeamSubscriptionIDs mocks base method
+func (m *MockDatabase) GetTeamSubscriptionIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetTeamSubscriptionIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamSubscriptionIDs indicates an expected call of GetTeamSubscriptionIDs
+func (mr *MockDatabaseMockRecorder) GetTeamSubscriptionIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamSubscriptionIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamSubscriptionIDs), arg0)
+}
+
+// GetTeamTriggerIDs mocks base method
+func (m *MockDatabase) GetTeamTriggerIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetTeamTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamTriggerIDs indicates an expected call of GetTeamTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetTeamTriggerIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamTriggerIDs), arg0)
+}
+
+// GetTemplateTriggerIDs mocks base method
+func (m *MockDatabase) GetTemplateTriggerIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetTemplateTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateTriggerIDs indicates an expected call of GetTemplateTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetTemplateTriggerIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetTemplateTriggerIDs), arg0)
+}
+
 // GetTrigger mocks base method
 func (m *MockDatabase) GetTrigger(arg0 string) (moira.Trigger, error) {
 	ret := m.ctrl.Call(m, "GetTrigger", arg0)
@@ -468,6 +634,71 @@ func (mr *MockDatabaseMockRecorder) GetTriggerLastCheck(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerLastCheck", reflect.TypeOf((*MockDatabase)(nil).GetTriggerLastCheck), arg0)
 }
 
+// GetTriggerRevision mocks base method
+func (m *MockDatabase) GetTriggerRevision(arg0 string, arg1 int64) (moira.TriggerRevision, error) {
+	ret := m.ctrl.Call(m, "GetTriggerRevision", arg0, arg1)
+	ret0, _ := ret[0].(moira.TriggerRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerRevision indicates an expected call of GetTriggerRevision
+func (mr *MockDatabaseMockRecorder) GetTriggerRevision(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerRevision", reflect.TypeOf((*MockDatabase)(nil).GetTriggerRevision), arg0, arg1)
+}
+
+// GetTriggerRevisions mocks base method
+func (m *MockDatabase) GetTriggerRevisions(arg0 string) ([]*moira.TriggerRevision, error) {
+	ret := m.ctrl.Call(m, "GetTriggerRevisions", arg0)
+	ret0, _ := ret[0].([]*moira.TriggerRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerRevisions indicates an expected call of GetTriggerRevisions
+func (mr *MockDatabaseMockRecorder) GetTriggerRevisions(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerRevisions", reflect.TypeOf((*MockDatabase)(nil).GetTriggerRevisions), arg0)
+}
+
+// GetTriggerStateHistory mocks base method
+func (m *MockDatabase) GetTriggerStateHistory(arg0 string, arg1, arg2 int64) ([]*moira.TriggerStateSnapshot, error) {
+	ret := m.ctrl.Call(m, "GetTriggerStateHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*moira.TriggerStateSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerStateHistory indicates an expected call of GetTriggerStateHistory
+func (mr *MockDatabaseMockRecorder) GetTriggerStateHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerStateHistory", reflect.TypeOf((*MockDatabase)(nil).GetTriggerStateHistory), arg0, arg1, arg2)
+}
+
+// GetTriggerTemplate mocks base method
+func (m *MockDatabase) GetTriggerTemplate(arg0 string) (moira.TriggerTemplate, error) {
+	ret := m.ctrl.Call(m, "GetTriggerTemplate", arg0)
+	ret0, _ := ret[0].(moira.TriggerTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerTemplate indicates an expected call of GetTriggerTemplate
+func (mr *MockDatabaseMockRecorder) GetTriggerTemplate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerTemplate", reflect.TypeOf((*MockDatabase)(nil).GetTriggerTemplate), arg0)
+}
+
+// GetTriggerTemplates mocks base method
+func (m *MockDatabase) GetTriggerTemplates() ([]*moira.TriggerTemplate, error) {
+	ret := m.ctrl.Call(m, "GetTriggerTemplates")
+	ret0, _ := ret[0].([]*moira.TriggerTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerTemplates indicates an expected call of GetTriggerTemplates
+func (mr *MockDatabaseMockRecorder) GetTriggerTemplates() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerTemplates", reflect.TypeOf((*MockDatabase)(nil).GetTriggerTemplates))
+}
+
 // GetTriggerThrottling mocks base method
 func (m *MockDatabase) GetTriggerThrottling(arg0 string) (time.Time, time.Time) {
 	ret := m.ctrl.Call(m, "GetTriggerThrottling", arg0)
@@ -520,6 +751,57 @@ func (mr *MockDatabaseMockRecorder) GetUserSubscriptionIDs(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSubscriptionIDs", reflect.TypeOf((*MockDatabase)(nil).GetUserSubscriptionIDs), arg0)
 }
 
+// GetUserTeamIDs mocks base method
+func (m *MockDatabase) GetUserTeamIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetUserTeamIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTeamIDs indicates an expected call of GetUserTeamIDs
+func (mr *MockDatabaseMockRecorder) GetUserTeamIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTeamIDs", reflect.TypeOf((*MockDatabase)(nil).GetUserTeamIDs), arg0)
+}
+
+// GetUserToken mocks base method
+func (m *MockDatabase) GetUserToken(arg0 string) (moira.UserToken, error) {
+	ret := m.ctrl.Call(m, "GetUserToken", arg0)
+	ret0, _ := ret[0].(moira.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserToken indicates an expected call of GetUserToken
+func (mr *MockDatabaseMockRecorder) GetUserToken(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserToken", reflect.TypeOf((*MockDatabase)(nil).GetUserToken), arg0)
+}
+
+// GetUserTokens mocks base method
+func (m *MockDatabase) GetUserTokens(arg0 string) ([]*moira.UserToken, error) {
+	ret := m.ctrl.Call(m, "GetUserTokens", arg0)
+	ret0, _ := ret[0].([]*moira.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTokens indicates an expected call of GetUserTokens
+func (mr *MockDatabaseMockRecorder) GetUserTokens(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTokens", reflect.TypeOf((*MockDatabase)(nil).GetUserTokens), arg0)
+}
+
+// KeepMetricsValues mocks base method
+func (m *MockDatabase) KeepMetricsValues(arg0 []string, arg1 int64) error {
+	ret := m.ctrl.Call(m, "KeepMetricsValues", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// KeepMetricsValues indicates an expected call of KeepMetricsValues
+func (mr *MockDatabaseMockRecorder) KeepMetricsValues(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepMetricsValues", reflect.TypeOf((*MockDatabase)(nil).KeepMetricsValues), arg0, arg1)
+}
+
 // PushNotificationEvent mocks base method
 func (m *MockDatabase) PushNotificationEvent(arg0 *moira.NotificationEvent, arg1 bool) error {
 	ret := m.ctrl.Call(m, "PushNotificationEvent", arg0, arg1)
@@ -665,6 +947,18 @@ func (mr *MockDatabaseMockRecorder) RemoveTag(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTag", reflect.TypeOf((*MockDatabase)(nil).RemoveTag), arg0)
 }
 
+// RemoveTeam mocks base method
+func (m *MockDatabase) RemoveTeam(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveTeam", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTeam indicates an expected call of RemoveTeam
+func (mr *MockDatabaseMockRecorder) RemoveTeam(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeam", reflect.TypeOf((*MockDatabase)(nil).RemoveTeam), arg0)
+}
+
 // RemoveTrigger mocks base method
 func (m *MockDatabase) RemoveTrigger(arg0 string) error {
 	ret := m.ctrl.Call(m, "RemoveTrigger", arg0)
@@ -689,6 +983,18 @@ func (mr *MockDatabaseMockRecorder) RemoveTriggerLastCheck(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTriggerLastCheck", reflect.TypeOf((*MockDatabase)(nil).RemoveTriggerLastCheck), arg0)
 }
 
+// RemoveTriggerTemplate mocks base method
+func (m *MockDatabase) RemoveTriggerTemplate(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveTriggerTemplate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTriggerTemplate indicates an expected call of RemoveTriggerTemplate
+func (mr *MockDatabaseMockRecorder) RemoveTriggerTemplate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTriggerTemplate", reflect.TypeOf((*MockDatabase)(nil).RemoveTriggerTemplate), arg0)
+}
+
 // RemoveUser mocks base method
 func (m *MockDatabase) RemoveUser(arg0, arg1 string) error {
 	ret := m.ctrl.Call(m, "RemoveUser", arg0, arg1)
@@ -701,6 +1007,18 @@ func (mr *MockDatabaseMockRecorder) RemoveUser(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUser", reflect.TypeOf((*MockDatabase)(nil).RemoveUser), arg0, arg1)
 }
 
+// RemoveUserToken mocks base method
+func (m *MockDatabase) RemoveUserToken(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveUserToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserToken indicates an expected call of RemoveUserToken
+func (mr *MockDatabaseMockRecorder) RemoveUserToken(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserToken", reflect.TypeOf((*MockDatabase)(nil).RemoveUserToken), arg0)
+}
+
 // RenewBotRegistration mocks base method
 func (m *MockDatabase) RenewBotRegistration(arg0 string) bool {
 	ret := m.ctrl.Call(m, "RenewBotRegistration", arg0)
@@ -761,6 +1079,18 @@ func (mr *MockDatabaseMockRecorder) SaveSubscriptions(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubscriptions", reflect.TypeOf((*MockDatabase)(nil).SaveSubscriptions), arg0)
 }
 
+// SaveTeam mocks base method
+func (m *MockDatabase) SaveTeam(arg0 *moira.Team) error {
+	ret := m.ctrl.Call(m, "SaveTeam", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTeam indicates an expected call of SaveTeam
+func (mr *MockDatabaseMockRecorder) SaveTeam(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTeam", reflect.TypeOf((*MockDatabase)(nil).SaveTeam), arg0)
+}
+
 // SaveTrigger mocks base method
 func (m *MockDatabase) SaveTrigger(arg0 string, arg1 *moira.Trigger) error {
 	ret := m.ctrl.Call(m, "SaveTrigger", arg0, arg1)
@@ -773,6 +1103,54 @@ func (mr *MockDatabaseMockRecorder) SaveTrigger(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTrigger", reflect.TypeOf((*MockDatabase)(nil).SaveTrigger), arg0, arg1)
 }
 
+// SaveTriggerTemplate mocks base method
+func (m *MockDatabase) SaveTriggerTemplate(arg0 *moira.TriggerTemplate) error {
+	ret := m.ctrl.Call(m, "SaveTriggerTemplate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTriggerTemplate indicates an expected call of SaveTriggerTemplate
+func (mr *MockDatabaseMockRecorder) SaveTriggerTemplate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTriggerTemplate", reflect.TypeOf((*MockDatabase)(nil).SaveTriggerTemplate), arg0)
+}
+
+// SaveUserToken mocks base method
+func (m *MockDatabase) SaveUserToken(arg0 *moira.UserToken) error {
+	ret := m.ctrl.Call(m, "SaveUserToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserToken indicates an expected call of SaveUserToken
+func (mr *MockDatabaseMockRecorder) SaveUserToken(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserToken", reflect.TypeOf((*MockDatabase)(nil).SaveUserToken), arg0)
+}
+
+// SetSubscriptionThrottling mocks base method
+func (m *MockDatabase) SetSubscriptionThrottling(arg0, arg1 string, arg2 time.Time) error {
+	ret := m.ctrl.Call(m, "SetSubscriptionThrottling", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSubscriptionThrottling indicates an expected call of SetSubscriptionThrottling
+func (mr *MockDatabaseMockRecorder) SetSubscriptionThrottling(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubscriptionThrottling", reflect.TypeOf((*MockDatabase)(nil).SetSubscriptionThrottling), arg0, arg1, arg2)
+}
+
+// SetTriggerCheckAcknowledgement mocks base method
+func (m *MockDatabase) SetTriggerCheckAcknowledgement(arg0 string, arg1 []string, arg2 *moira.Acknowledgement) error {
+	ret := m.ctrl.Call(m, "SetTriggerCheckAcknowledgement", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTriggerCheckAcknowledgement indicates an expected call of SetTriggerCheckAcknowledgement
+func (mr *MockDatabaseMockRecorder) SetTriggerCheckAcknowledgement(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTriggerCheckAcknowledgement", reflect.TypeOf((*MockDatabase)(nil).SetTriggerCheckAcknowledgement), arg0, arg1, arg2)
+}
+
 // SetTriggerCheckLock mocks base method
 func (m *MockDatabase) SetTriggerCheckLock(arg0 string) (bool, error) {
 	ret := m.ctrl.Call(m, "SetTriggerCheckLock", arg0)
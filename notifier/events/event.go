@@ -67,6 +67,11 @@ func (worker *FetchEventsWorker) processEvent(event moira.NotificationEvent) err
 		triggerData   moira.TriggerData
 	)
 
+	if event.InhibitedBy != "" {
+		worker.Logger.Debugf("Skip event of trigger id %s for metric %s inhibited by trigger id %s", event.TriggerID, event.Metric, event.InhibitedBy)
+		return nil
+	}
+
 	if event.State != "TEST" {
 		worker.Logger.Debugf("Processing trigger id %s for metric %s == %f, %s -> %s", event.TriggerID, event.Metric, moira.UseFloat64(event.Value), event.OldState, event.State)
 
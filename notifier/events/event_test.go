@@ -129,6 +129,33 @@ func TestNoSubscription(t *testing.T) {
 	})
 }
 
+func TestInhibitedEvent(t *testing.T) {
+	Convey("When event is inhibited by parent trigger, should not get subscriptions", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		logger, _ := logging.GetLogger("Events")
+
+		worker := FetchEventsWorker{
+			Database:  dataBase,
+			Logger:    logger,
+			Metrics:   metrics2,
+			Scheduler: notifier.NewScheduler(dataBase, logger, metrics2),
+		}
+
+		event := moira.NotificationEvent{
+			Metric:      "generate.event.1",
+			State:       "ERROR",
+			OldState:    "OK",
+			TriggerID:   triggerData.ID,
+			InhibitedBy: "parent",
+		}
+
+		err := worker.processEvent(event)
+		So(err, ShouldBeEmpty)
+	})
+}
+
 func TestDisabledNotification(t *testing.T) {
 	Convey("When subscription event tags is disabled, should not call AddNotification", t, func() {
 		mockCtrl := gomock.NewController(t)
@@ -52,6 +52,20 @@ func (worker *FetchNotificationsWorker) processScheduledNotifications() error {
 	}
 	notificationPackages := make(map[string]*notifier.NotificationPackage)
 	for _, notification := range notifications {
+		if notification.Contact.DigestInterval > 0 {
+			packageKey := fmt.Sprintf("%s:%s", notification.Contact.Type, notification.Contact.Value)
+			p, found := notificationPackages[packageKey]
+			if !found {
+				p = &notifier.NotificationPackage{
+					Contact:   notification.Contact,
+					FailCount: notification.SendFail,
+					Digest:    make([]moira.DigestItem, 0),
+				}
+				notificationPackages[packageKey] = p
+			}
+			addToDigest(p, notification)
+			continue
+		}
 		packageKey := fmt.Sprintf("%s:%s:%s", notification.Contact.Type, notification.Contact.Value, notification.Event.TriggerID)
 		p, found := notificationPackages[packageKey]
 		if !found {
@@ -66,6 +80,12 @@ func (worker *FetchNotificationsWorker) processScheduledNotifications() error {
 		p.Events = append(p.Events, notification.Event)
 		notificationPackages[packageKey] = p
 	}
+	for _, p := range notificationPackages {
+		if len(p.Digest) == 1 {
+			p.Events, p.Trigger, p.Throttled = p.Digest[0].Events, p.Digest[0].Trigger, p.Digest[0].Throttled
+			p.Digest = nil
+		}
+	}
 	var sendingWG sync.WaitGroup
 	for _, pkg := range notificationPackages {
 		worker.Notifier.Send(pkg, &sendingWG)
@@ -73,3 +93,22 @@ func (worker *FetchNotificationsWorker) processScheduledNotifications() error {
 	sendingWG.Wait()
 	return nil
 }
+
+// addToDigest adds notification event to digest item of notification trigger
+func addToDigest(pkg *notifier.NotificationPackage, notification *moira.ScheduledNotification) {
+	if notification.SendFail > pkg.FailCount {
+		pkg.FailCount = notification.SendFail
+	}
+	for i := range pkg.Digest {
+		if pkg.Digest[i].Trigger.ID == notification.Event.TriggerID {
+			pkg.Digest[i].Events = append(pkg.Digest[i].Events, notification.Event)
+			pkg.Digest[i].Throttled = pkg.Digest[i].Throttled || notification.Throttled
+			return
+		}
+	}
+	pkg.Digest = append(pkg.Digest, moira.DigestItem{
+		Trigger:   notification.Trigger,
+		Events:    moira.NotificationEvents{notification.Event},
+		Throttled: notification.Throttled,
+	})
+}
@@ -117,6 +117,70 @@ func TestProcessScheduledEvent(t *testing.T) {
 		So(err, ShouldBeEmpty)
 		mockCtrl.Finish()
 	})
+
+	Convey("Notifications to digest contact, should send one digest package", t, func() {
+		digestContact := contact2
+		digestContact.DigestInterval = 600
+		digestNotification1 := notification2
+		digestNotification1.Contact = digestContact
+		digestNotification1.Trigger = moira.TriggerData{ID: "triggerID-00000000000001"}
+		digestNotification2 := notification3
+		digestNotification2.Contact = digestContact
+		digestNotification2.Trigger = moira.TriggerData{ID: "triggerID-00000000000001"}
+		digestNotification3 := notification1
+		digestNotification3.Contact = digestContact
+		digestNotification3.Event.TriggerID = "triggerID-00000000000002"
+		digestNotification3.Trigger = moira.TriggerData{ID: "triggerID-00000000000002"}
+		digestNotification3.Throttled = true
+		digestNotification3.SendFail = 2
+
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{
+			&digestNotification1,
+			&digestNotification2,
+			&digestNotification3,
+		}, nil)
+
+		pkg := notifier2.NotificationPackage{
+			Contact:   digestContact,
+			FailCount: 2,
+			Digest: []moira.DigestItem{
+				{
+					Trigger: digestNotification1.Trigger,
+					Events:  moira.NotificationEvents{digestNotification1.Event, digestNotification2.Event},
+				},
+				{
+					Trigger:   digestNotification3.Trigger,
+					Events:    moira.NotificationEvents{digestNotification3.Event},
+					Throttled: true,
+				},
+			},
+		}
+
+		notifier.EXPECT().Send(&pkg, gomock.Any())
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+		mockCtrl.Finish()
+	})
+
+	Convey("Notifications of one trigger to digest contact, should send regular package", t, func() {
+		digestContact := contact2
+		digestContact.DigestInterval = 600
+		digestNotification := notification2
+		digestNotification.Contact = digestContact
+
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&digestNotification}, nil)
+
+		pkg := notifier2.NotificationPackage{
+			Trigger: digestNotification.Trigger,
+			Contact: digestContact,
+			Events:  moira.NotificationEvents{digestNotification.Event},
+		}
+
+		notifier.EXPECT().Send(&pkg, gomock.Any())
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+		mockCtrl.Finish()
+	})
 }
 
 func TestGoRoutine(t *testing.T) {
@@ -9,7 +9,8 @@ import (
 	"github.com/moira-alert/moira/metrics/graphite"
 )
 
-// NotificationPackage represent sending data
+// NotificationPackage represent sending data. Package with non empty Digest contains events of several triggers,
+// its Events and Trigger are not used
 type NotificationPackage struct {
 	Events     []moira.NotificationEvent
 	Trigger    moira.TriggerData
@@ -17,12 +18,36 @@ type NotificationPackage struct {
 	FailCount  int
 	Throttled  bool
 	DontResend bool
+	Digest     []moira.DigestItem
 }
 
 func (pkg NotificationPackage) String() string {
+	if len(pkg.Digest) > 0 {
+		eventsCount := 0
+		for _, item := range pkg.Digest {
+			eventsCount += len(item.Events)
+		}
+		return fmt.Sprintf("digest of %d notifications for %d triggers to %s", eventsCount, len(pkg.Digest), pkg.Contact.Value)
+	}
 	return fmt.Sprintf("package of %d notifications to %s", len(pkg.Events), pkg.Contact.Value)
 }
 
+// splitDigest returns separate package for every trigger of digest
+func (pkg NotificationPackage) splitDigest() []NotificationPackage {
+	packages := make([]NotificationPackage, 0, len(pkg.Digest))
+	for _, item := range pkg.Digest {
+		packages = append(packages, NotificationPackage{
+			Events:     item.Events,
+			Trigger:    item.Trigger,
+			Contact:    pkg.Contact,
+			FailCount:  pkg.FailCount,
+			Throttled:  item.Throttled,
+			DontResend: pkg.DontResend,
+		})
+	}
+	return packages
+}
+
 // Notifier implements notification functionality
 type Notifier interface {
 	Send(pkg *NotificationPackage, waitGroup *sync.WaitGroup)
@@ -96,10 +121,16 @@ func (notifier *StandardNotifier) resend(pkg *NotificationPackage, reason string
 	if time.Duration(pkg.FailCount)*time.Minute > notifier.config.ResendingTimeout {
 		notifier.logger.Error("Stop resending. Notification interval is timed out")
 	} else {
-		for _, event := range pkg.Events {
-			notification := notifier.scheduler.ScheduleNotification(time.Now(), event, pkg.Trigger, pkg.Contact, pkg.Throttled, pkg.FailCount+1)
-			if err := notifier.database.AddNotification(notification); err != nil {
-				notifier.logger.Errorf("Failed to save scheduled notification: %s", err)
+		packages := []NotificationPackage{*pkg}
+		if len(pkg.Digest) > 0 {
+			packages = pkg.splitDigest()
+		}
+		for _, triggerPackage := range packages {
+			for _, event := range triggerPackage.Events {
+				notification := notifier.scheduler.ScheduleNotification(time.Now(), event, triggerPackage.Trigger, triggerPackage.Contact, triggerPackage.Throttled, triggerPackage.FailCount+1)
+				if err := notifier.database.AddNotification(notification); err != nil {
+					notifier.logger.Errorf("Failed to save scheduled notification: %s", err)
+				}
 			}
 		}
 	}
@@ -108,13 +139,36 @@ func (notifier *StandardNotifier) resend(pkg *NotificationPackage, reason string
 func (notifier *StandardNotifier) run(sender moira.Sender, ch chan NotificationPackage) {
 	defer notifier.waitGroup.Done()
 	for pkg := range ch {
-		err := sender.SendEvents(pkg.Events, pkg.Contact, pkg.Trigger, pkg.Throttled)
-		if err == nil {
-			if metric, found := notifier.metrics.SendersOkMetrics.GetMetric(pkg.Contact.Type); found {
-				metric.Mark(1)
-			}
-		} else {
-			notifier.resend(&pkg, err.Error())
+		if len(pkg.Digest) == 0 {
+			notifier.handleSendResult(pkg, sender.SendEvents(pkg.Events, pkg.Contact, pkg.Trigger, pkg.Throttled))
+			continue
+		}
+		if digestSender, ok := sender.(moira.DigestSender); ok {
+			notifier.handleSendResult(pkg, digestSender.SendDigest(pkg.Digest, pkg.Contact))
+			continue
+		}
+		for _, triggerPackage := range pkg.splitDigest() {
+			notifier.handleSendResult(triggerPackage, sender.SendEvents(triggerPackage.Events, triggerPackage.Contact, triggerPackage.Trigger, triggerPackage.Throttled))
+		}
+	}
+}
+
+// handleSendResult marks sending metrics and resends package if sender failed
+func (notifier *StandardNotifier) handleSendResult(pkg NotificationPackage, err error) {
+	if err == nil {
+		if metric, found := notifier.metrics.SendersOkMetrics.GetMetric(pkg.Contact.Type); found {
+			metric.Mark(1)
+		}
+		return
+	}
+	switch e := err.(type) {
+	case moira.SenderBrokenContactError:
+		notifier.metrics.SendingFailed.Mark(1)
+		if metric, found := notifier.metrics.SendersFailedMetrics.GetMetric(pkg.Contact.Type); found {
+			metric.Mark(1)
 		}
+		notifier.logger.Errorf("Cannot send %s, notification will not be resent: %s", pkg, e.Error())
+	default:
+		notifier.resend(&pkg, err.Error())
 	}
 }
@@ -69,6 +69,62 @@ func TestFailSendEvent(t *testing.T) {
 	time.Sleep(time.Second * 2)
 }
 
+func TestFailSendEventToBrokenContact(t *testing.T) {
+	configureNotifier(t)
+	defer afterTest()
+
+	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
+
+	pkg := NotificationPackage{
+		Events: eventsData,
+		Contact: moira.ContactData{
+			Type: "test",
+		},
+	}
+	sender.EXPECT().SendEvents(eventsData, pkg.Contact, pkg.Trigger, pkg.Throttled).Return(moira.NewSenderBrokenContactError(fmt.Errorf("Contact not found")))
+	failed := notif.metrics.SendingFailed.Count()
+
+	var wg sync.WaitGroup
+	notif.Send(&pkg, &wg)
+	wg.Wait()
+	// senders are stopped after they handle sent packages, so send result is handled here
+	notif.StopSenders()
+
+	Convey("Failed sending should be counted and notification should not be rescheduled", t, func() {
+		So(notif.metrics.SendingFailed.Count(), ShouldEqual, failed+1)
+	})
+}
+
+func TestSendDigestWithoutDigestSender(t *testing.T) {
+	configureNotifier(t)
+	defer afterTest()
+
+	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
+	trigger1 := moira.TriggerData{ID: "triggerID-0000000000001"}
+	trigger2 := moira.TriggerData{ID: "triggerID-0000000000002"}
+
+	pkg := NotificationPackage{
+		Contact: moira.ContactData{
+			Type:           "test",
+			DigestInterval: 600,
+		},
+		Digest: []moira.DigestItem{
+			{Trigger: trigger1, Events: eventsData},
+			{Trigger: trigger2, Events: eventsData, Throttled: true},
+		},
+	}
+	notification := moira.ScheduledNotification{}
+	sender.EXPECT().SendEvents(eventsData, pkg.Contact, trigger1, false).Return(nil)
+	sender.EXPECT().SendEvents(eventsData, pkg.Contact, trigger2, true).Return(fmt.Errorf("Cant't send"))
+	scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, trigger2, pkg.Contact, true, pkg.FailCount+1).Return(&notification)
+	dataBase.EXPECT().AddNotification(&notification).Return(nil)
+
+	var wg sync.WaitGroup
+	notif.Send(&pkg, &wg)
+	wg.Wait()
+	time.Sleep(time.Second * 2)
+}
+
 func TestTimeout(t *testing.T) {
 	configureNotifier(t)
 	defer afterTest()
@@ -13,6 +13,7 @@ import (
 	"github.com/moira-alert/moira/senders/slack"
 	"github.com/moira-alert/moira/senders/telegram"
 	"github.com/moira-alert/moira/senders/twilio"
+	"github.com/moira-alert/moira/senders/webhook"
 )
 
 // RegisterSenders watch on senders config and register all configured senders
@@ -48,6 +49,10 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 			if err := notifier.RegisterSender(senderSettings, &twilio.Sender{}); err != nil {
 				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
 			}
+		case "webhook":
+			if err := notifier.RegisterSender(senderSettings, &webhook.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
 		// case "email":
 		// 	if err := notifier.RegisterSender(senderSettings, &kontur.MailSender{}); err != nil {
 		// 	}
@@ -64,7 +69,7 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 // RegisterSender adds sender for notification type and registers metrics
 func (notifier *StandardNotifier) RegisterSender(senderSettings map[string]string, sender moira.Sender) error {
 	var senderIdent string
-	if senderSettings["type"] == "script" {
+	if senderSettings["type"] == "script" || (senderSettings["type"] == "webhook" && senderSettings["name"] != "") {
 		senderIdent = senderSettings["name"]
 	} else {
 		senderIdent = senderSettings["type"]
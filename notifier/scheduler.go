@@ -19,10 +19,13 @@ type StandardScheduler struct {
 	metrics  *graphite.NotifierMetrics
 }
 
-type throttlingLevel struct {
-	duration time.Duration
-	delay    time.Duration
-	count    int64
+// defaultThrottlingPolicy is used by subscriptions with enabled throttling and without own policy:
+// if trigger switches more than Count times in Window seconds, delay next delivery for Delay seconds
+var defaultThrottlingPolicy = moira.ThrottlingPolicy{
+	Levels: []moira.ThrottlingLevel{
+		{Window: 3 * 3600, Count: 20, Delay: 3600},
+		{Window: 3600, Count: 10, Delay: 1800},
+	},
 }
 
 // NewScheduler is initializer for StandardScheduler
@@ -49,6 +52,9 @@ func (scheduler *StandardScheduler) ScheduleNotification(now time.Time, event mo
 			throttled = false
 		} else {
 			next, throttled = scheduler.calculateNextDelivery(now, &event)
+			if contact.DigestInterval > 0 {
+				next = alignToDigestWindow(next, contact.DigestInterval)
+			}
 		}
 	}
 	notification := &moira.ScheduledNotification{
@@ -68,16 +74,20 @@ func (scheduler *StandardScheduler) ScheduleNotification(now time.Time, event mo
 }
 
 func (scheduler *StandardScheduler) calculateNextDelivery(now time.Time, event *moira.NotificationEvent) (time.Time, bool) {
-	// if trigger switches more than .count times in .length seconds, delay next delivery for .delay seconds
-	// processing stops after first condition matches
-	throttlingLevels := []throttlingLevel{
-		{3 * time.Hour, time.Hour, 20},
-		{time.Hour, time.Hour / 2, 10},
+	subscription, err := scheduler.database.GetSubscription(moira.UseString(event.SubscriptionID))
+	if err != nil {
+		scheduler.metrics.SubsMalformed.Mark(1)
+		scheduler.logger.Debugf("Failed get subscription by id: %s. %s", moira.UseString(event.SubscriptionID), err.Error())
+		next, _ := scheduler.database.GetTriggerThrottling(event.TriggerID)
+		if next.After(now) {
+			return next, true
+		}
+		return now, false
 	}
 
 	alarmFatigue := false
 
-	next, beginning := scheduler.database.GetTriggerThrottling(event.TriggerID)
+	next, beginning := scheduler.database.GetSubscriptionThrottling(event.TriggerID, subscription.ID)
 
 	if next.After(now) {
 		alarmFatigue = true
@@ -85,32 +95,30 @@ func (scheduler *StandardScheduler) calculateNextDelivery(now time.Time, event *
 		next = now
 	}
 
-	subscription, err := scheduler.database.GetSubscription(moira.UseString(event.SubscriptionID))
-	if err != nil {
-		scheduler.metrics.SubsMalformed.Mark(1)
-		scheduler.logger.Debugf("Failed get subscription by id: %s. %s", moira.UseString(event.SubscriptionID), err.Error())
-		return next, alarmFatigue
-	}
-
 	if subscription.ThrottlingEnabled {
+		policy := getThrottlingPolicy(&subscription)
 		if next.After(now) {
-			scheduler.logger.Debugf("Using existing throttling for trigger %s: %s", event.TriggerID, next)
+			scheduler.logger.Debugf("Using existing throttling for trigger %s and subscription %s: %s", event.TriggerID, subscription.ID, next)
+			next = capThrottlingDelay(policy, now, next)
 		} else {
-			for _, level := range throttlingLevels {
-				from := now.Add(-level.duration)
+			// processing stops after first level matches
+			for _, level := range policy.Levels {
+				window := time.Duration(level.Window) * time.Second
+				delay := time.Duration(level.Delay) * time.Second
+				from := now.Add(-window)
 				if from.Before(beginning) {
 					from = beginning
 				}
 				count := scheduler.database.GetNotificationEventCount(event.TriggerID, from.Unix())
-				if count >= level.count {
-					next = now.Add(level.delay)
-					scheduler.logger.Debugf("Trigger %s switched %d times in last %s, delaying next notification for %s", event.TriggerID, count, level.duration, level.delay)
-					if err = scheduler.database.SetTriggerThrottling(event.TriggerID, next); err != nil {
-						scheduler.logger.Errorf("Failed to set trigger throttling timestamp: %s", err)
+				if count >= level.Count {
+					next = capThrottlingDelay(policy, now, now.Add(delay))
+					scheduler.logger.Debugf("Trigger %s switched %d times in last %s, delaying next notification for subscription %s until %s", event.TriggerID, count, window, subscription.ID, next)
+					if err = scheduler.database.SetSubscriptionThrottling(event.TriggerID, subscription.ID, next); err != nil {
+						scheduler.logger.Errorf("Failed to set subscription throttling timestamp: %s", err)
 					}
 					alarmFatigue = true
 					break
-				} else if count == level.count-1 {
+				} else if count == level.Count-1 {
 					alarmFatigue = true
 				}
 			}
@@ -125,6 +133,35 @@ func (scheduler *StandardScheduler) calculateNextDelivery(now time.Time, event *
 	return next, alarmFatigue
 }
 
+// alignToDigestWindow moves delivery time to the end of digest window, so notifications of all contact triggers
+// scheduled during the window are fetched and sent together
+func alignToDigestWindow(next time.Time, digestInterval int64) time.Time {
+	timestamp := next.Unix()
+	if remainder := timestamp % digestInterval; remainder != 0 {
+		timestamp += digestInterval - remainder
+	}
+	return time.Unix(timestamp, 0)
+}
+
+func getThrottlingPolicy(subscription *moira.SubscriptionData) *moira.ThrottlingPolicy {
+	if subscription.ThrottlingPolicy != nil {
+		return subscription.ThrottlingPolicy
+	}
+	return &defaultThrottlingPolicy
+}
+
+// capThrottlingDelay limits next delivery time with policy max delay
+func capThrottlingDelay(policy *moira.ThrottlingPolicy, now time.Time, next time.Time) time.Time {
+	if policy.MaxDelay <= 0 {
+		return next
+	}
+	maxNext := now.Add(time.Duration(policy.MaxDelay) * time.Second)
+	if next.After(maxNext) {
+		return maxNext
+	}
+	return next
+}
+
 func calculateNextDelivery(schedule *moira.ScheduleData, nextTime time.Time) (time.Time, error) {
 
 	if len(schedule.Days) != 0 && len(schedule.Days) != 7 {
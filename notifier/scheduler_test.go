@@ -133,7 +133,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 		subscription.ThrottlingEnabled = false
 		Convey("When current time is allowed, should send notification now", func() {
 			subscription.Schedule = schedule1
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
@@ -144,7 +144,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 
 		Convey("When allowed time is today, should send notification at the beginning of allowed interval", func() {
 			subscription.Schedule = schedule2
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
@@ -156,7 +156,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 		Convey("When allowed time is in a future day, should send notification at the beginning of allowed interval", func() {
 			now = time.Unix(1441101600, 0)
 			subscription.Schedule = schedule1
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
@@ -167,7 +167,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 
 		Convey("Trigger already alarm fatigue, but now throttling disabled, should send notification now", func() {
 			subscription.Schedule = schedule1
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(1441187215, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(1441187215, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
@@ -182,7 +182,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 		subscription.ThrottlingEnabled = true
 
 		Convey("Has trigger events count slightly less than low throttling level, should next timestamp now minutes, but throttling", func() {
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Hour*3).Unix()).Return(int64(13))
 			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Hour).Unix()).Return(int64(9))
@@ -194,11 +194,11 @@ func TestSubscriptionSchedule(t *testing.T) {
 		})
 
 		Convey("Has trigger events count event more than low throttling level, should next timestamp in 30 minutes", func() {
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Hour*3).Unix()).Return(int64(10))
 			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Hour).Unix()).Return(int64(10))
-			dataBase.EXPECT().SetTriggerThrottling(event.TriggerID, now.Add(time.Hour/2)).Return(nil)
+			dataBase.EXPECT().SetSubscriptionThrottling(event.TriggerID, subID, now.Add(time.Hour/2)).Return(nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
 			So(next, ShouldResemble, time.Unix(1441135800, 0))
@@ -207,10 +207,10 @@ func TestSubscriptionSchedule(t *testing.T) {
 		})
 
 		Convey("Has trigger event more than high throttling level, should next timestamp in 1 hour", func() {
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Hour*3).Unix()).Return(int64(20))
-			dataBase.EXPECT().SetTriggerThrottling(event.TriggerID, now.Add(time.Hour)).Return(nil)
+			dataBase.EXPECT().SetSubscriptionThrottling(event.TriggerID, subID, now.Add(time.Hour)).Return(nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
 			So(next, ShouldResemble, now.Add(time.Hour))
@@ -219,7 +219,7 @@ func TestSubscriptionSchedule(t *testing.T) {
 		})
 
 		Convey("Trigger already alarm fatigue, should has old throttled value", func() {
-			dataBase.EXPECT().GetTriggerThrottling(event.TriggerID).Return(time.Unix(1441148000, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(1441148000, 0), time.Unix(0, 0))
 			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
 
 			next, throttled := scheduler.calculateNextDelivery(now, &event)
@@ -228,6 +228,51 @@ func TestSubscriptionSchedule(t *testing.T) {
 			mockCtrl.Finish()
 		})
 	})
+
+	Convey("Subscription throttling policy", t, func() {
+		now := time.Unix(1441134000, 0)
+		subscription.ThrottlingEnabled = true
+		subscription.ThrottlingPolicy = &moira.ThrottlingPolicy{
+			Levels: []moira.ThrottlingLevel{
+				{Window: 600, Count: 3, Delay: 7200},
+			},
+			MaxDelay: 3600,
+		}
+		defer func() { subscription.ThrottlingPolicy = nil }()
+
+		Convey("Has trigger events count more than policy level, should delay next timestamp no more than max delay", func() {
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(time.Unix(0, 0), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
+			dataBase.EXPECT().GetNotificationEventCount(event.TriggerID, now.Add(-time.Minute*10).Unix()).Return(int64(3))
+			dataBase.EXPECT().SetSubscriptionThrottling(event.TriggerID, subID, now.Add(time.Hour)).Return(nil)
+
+			next, throttled := scheduler.calculateNextDelivery(now, &event)
+			So(next, ShouldResemble, now.Add(time.Hour))
+			So(throttled, ShouldBeTrue)
+			mockCtrl.Finish()
+		})
+
+		Convey("Has existing throttling longer than max delay, should cap it", func() {
+			dataBase.EXPECT().GetSubscriptionThrottling(event.TriggerID, subID).Return(now.Add(time.Hour*5), time.Unix(0, 0))
+			dataBase.EXPECT().GetSubscription(*event.SubscriptionID).Return(subscription, nil)
+
+			next, throttled := scheduler.calculateNextDelivery(now, &event)
+			So(next, ShouldResemble, now.Add(time.Hour))
+			So(throttled, ShouldBeTrue)
+			mockCtrl.Finish()
+		})
+	})
+}
+
+func TestAlignToDigestWindow(t *testing.T) {
+	Convey("Next delivery should be moved to the end of digest window", t, func() {
+		So(alignToDigestWindow(time.Unix(1441134001, 0), 600), ShouldResemble, time.Unix(1441134600, 0))
+		So(alignToDigestWindow(time.Unix(1441134599, 0), 600), ShouldResemble, time.Unix(1441134600, 0))
+	})
+
+	Convey("Next delivery at the end of digest window should not be changed", t, func() {
+		So(alignToDigestWindow(time.Unix(1441134600, 0), 600), ShouldResemble, time.Unix(1441134600, 0))
+	})
 }
 
 var schedule1 = moira.ScheduleData{
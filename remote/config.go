@@ -0,0 +1,33 @@
+package remote
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config represents settings of graphite render API remote triggers are checked against
+type Config struct {
+	URL           string
+	User          string
+	Password      string
+	Timeout       time.Duration
+	CheckInterval time.Duration
+	Enabled       bool
+
+	clientOnce sync.Once
+	client     *http.Client
+}
+
+// IsEnabled returns true if remote triggers checking is turned on and graphite URL is set
+func (config *Config) IsEnabled() bool {
+	return config != nil && config.Enabled && config.URL != ""
+}
+
+// getClient returns http client shared by all requests to graphite, it is created on first use
+func (config *Config) getClient() *http.Client {
+	config.clientOnce.Do(func() {
+		config.client = &http.Client{Timeout: config.Timeout}
+	})
+	return config.client
+}
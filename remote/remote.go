@@ -0,0 +1,129 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultStep is used as series step if graphite returned less than two points
+const defaultStep = 60
+
+// maxResponseSize limits size of graphite response read into memory
+var maxResponseSize int64 = 64 << 20
+
+// ErrRemoteUnavailable is returned if graphite render API can not be reached or returned invalid response
+type ErrRemoteUnavailable struct {
+	internalError error
+	target        string
+}
+
+// Error is implementation of golang error interface for ErrRemoteUnavailable struct
+func (err ErrRemoteUnavailable) Error() string {
+	return fmt.Sprintf("Remote server unavailable, failed to fetch target %s: %s", err.target, err.internalError.Error())
+}
+
+// Series is single graphite timeseries, absent values are NaN
+type Series struct {
+	Name      string
+	StartTime int64
+	StopTime  int64
+	StepTime  int64
+	Values    []float64
+}
+
+type renderResponse struct {
+	Target     string        `json:"target"`
+	DataPoints [][2]*float64 `json:"datapoints"`
+}
+
+// Fetch evaluates target for given interval using graphite render API
+func Fetch(config *Config, target string, from, until int64) ([]*Series, error) {
+	request, err := prepareRequest(config, target, from, until)
+	if err != nil {
+		return nil, err
+	}
+	response, err := config.getClient().Do(request)
+	if err != nil {
+		return nil, ErrRemoteUnavailable{internalError: err, target: target}
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, ErrRemoteUnavailable{internalError: err, target: target}
+	}
+	if int64(len(body)) > maxResponseSize {
+		return nil, ErrRemoteUnavailable{internalError: fmt.Errorf("response size exceeds limit %d", maxResponseSize), target: target}
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, ErrRemoteUnavailable{
+			internalError: fmt.Errorf("bad response status %d: %s", response.StatusCode, strings.TrimSpace(string(body))),
+			target:        target,
+		}
+	}
+	series, err := decodeBody(body)
+	if err != nil {
+		return nil, ErrRemoteUnavailable{internalError: err, target: target}
+	}
+	return series, nil
+}
+
+func prepareRequest(config *Config, target string, from, until int64) (*http.Request, error) {
+	query := url.Values{
+		"target": []string{target},
+		"from":   []string{strconv.FormatInt(from, 10)},
+		"until":  []string{strconv.FormatInt(until, 10)},
+		"format": []string{"json"},
+	}
+	request, err := http.NewRequest(http.MethodGet, strings.TrimRight(config.URL, "/")+"/render?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create remote request: %s", err.Error())
+	}
+	if config.User != "" && config.Password != "" {
+		request.SetBasicAuth(config.User, config.Password)
+	}
+	return request, nil
+}
+
+func decodeBody(body []byte) ([]*Series, error) {
+	var response []renderResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %s", err.Error())
+	}
+	result := make([]*Series, 0, len(response))
+	for _, item := range response {
+		series := &Series{
+			Name:     item.Target,
+			StepTime: defaultStep,
+			Values:   make([]float64, 0, len(item.DataPoints)),
+		}
+		for i, point := range item.DataPoints {
+			if point[1] == nil {
+				return nil, fmt.Errorf("datapoint without timestamp in series %s", item.Target)
+			}
+			timestamp := int64(*point[1])
+			if i == 0 {
+				series.StartTime = timestamp
+			} else if i == 1 {
+				series.StepTime = timestamp - series.StartTime
+				if series.StepTime <= 0 {
+					return nil, fmt.Errorf("datapoints of series %s are not ordered by time", item.Target)
+				}
+			}
+			if point[0] == nil {
+				series.Values = append(series.Values, math.NaN())
+			} else {
+				series.Values = append(series.Values, *point[0])
+			}
+		}
+		series.StopTime = series.StartTime + int64(len(series.Values))*series.StepTime
+		result = append(result, series)
+	}
+	return result, nil
+}
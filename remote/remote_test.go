@@ -0,0 +1,108 @@
+package remote
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFetch(t *testing.T) {
+	var request *http.Request
+	responseStatus := http.StatusOK
+	responseBody := ""
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		request = r
+		writer.WriteHeader(responseStatus)
+		writer.Write([]byte(responseBody))
+	}))
+	defer server.Close()
+
+	config := &Config{URL: server.URL + "/", User: "user", Password: "pass", Timeout: time.Second}
+
+	Convey("Good response", t, func() {
+		responseStatus = http.StatusOK
+		responseBody = `[{"target": "my.metric", "datapoints": [[1.5, 60], [null, 120], [3, 180]]}, {"target": "empty", "datapoints": []}]`
+		series, err := Fetch(config, "sumSeries(my.*)", 10, 200)
+		So(err, ShouldBeNil)
+
+		So(request.URL.Path, ShouldEqual, "/render")
+		query := request.URL.Query()
+		So(query.Get("target"), ShouldEqual, "sumSeries(my.*)")
+		So(query.Get("from"), ShouldEqual, "10")
+		So(query.Get("until"), ShouldEqual, "200")
+		So(query.Get("format"), ShouldEqual, "json")
+		user, password, ok := request.BasicAuth()
+		So(ok, ShouldBeTrue)
+		So(user, ShouldEqual, "user")
+		So(password, ShouldEqual, "pass")
+
+		So(series, ShouldHaveLength, 2)
+		So(series[0].Name, ShouldEqual, "my.metric")
+		So(series[0].StartTime, ShouldEqual, 60)
+		So(series[0].StepTime, ShouldEqual, 60)
+		So(series[0].StopTime, ShouldEqual, 240)
+		So(series[0].Values, ShouldHaveLength, 3)
+		So(series[0].Values[0], ShouldEqual, 1.5)
+		So(math.IsNaN(series[0].Values[1]), ShouldBeTrue)
+		So(series[0].Values[2], ShouldEqual, 3)
+		So(series[1].Name, ShouldEqual, "empty")
+		So(series[1].Values, ShouldBeEmpty)
+	})
+
+	Convey("Bad response status", t, func() {
+		responseStatus = http.StatusInternalServerError
+		responseBody = "Internal error"
+		series, err := Fetch(config, "my.metric", 10, 200)
+		So(series, ShouldBeNil)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteUnavailable{})
+		So(err.Error(), ShouldEqual, "Remote server unavailable, failed to fetch target my.metric: bad response status 500: Internal error")
+	})
+
+	Convey("Invalid response body", t, func() {
+		responseStatus = http.StatusOK
+		responseBody = "<html></html>"
+		_, err := Fetch(config, "my.metric", 10, 200)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteUnavailable{})
+	})
+
+	Convey("Unordered datapoints", t, func() {
+		responseStatus = http.StatusOK
+		responseBody = `[{"target": "my.metric", "datapoints": [[1, 120], [2, 60]]}]`
+		_, err := Fetch(config, "my.metric", 10, 200)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteUnavailable{})
+	})
+
+	Convey("Too large response", t, func() {
+		defaultMaxResponseSize := maxResponseSize
+		defer func() { maxResponseSize = defaultMaxResponseSize }()
+		maxResponseSize = 16
+		responseStatus = http.StatusOK
+		responseBody = `[{"target": "my.metric", "datapoints": []}]`
+		series, err := Fetch(config, "my.metric", 10, 200)
+		So(series, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "Remote server unavailable, failed to fetch target my.metric: response size exceeds limit 16")
+	})
+
+	Convey("Client is created once", t, func() {
+		So(config.getClient(), ShouldEqual, config.getClient())
+		So(config.getClient().Timeout, ShouldEqual, time.Second)
+	})
+
+	Convey("Server is down", t, func() {
+		_, err := Fetch(&Config{URL: "http://127.0.0.1:0", Timeout: time.Second}, "my.metric", 10, 200)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteUnavailable{})
+	})
+}
+
+func TestIsEnabled(t *testing.T) {
+	Convey("Remote is enabled only with URL", t, func() {
+		So((*Config)(nil).IsEnabled(), ShouldBeFalse)
+		So((&Config{Enabled: true}).IsEnabled(), ShouldBeFalse)
+		So((&Config{URL: "http://graphite"}).IsEnabled(), ShouldBeFalse)
+		So((&Config{Enabled: true, URL: "http://graphite"}).IsEnabled(), ShouldBeTrue)
+	})
+}
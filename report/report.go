@@ -0,0 +1,230 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/moira-alert/moira"
+)
+
+// States are trigger states time shares are reported for
+var States = []string{"OK", "WARN", "ERROR", "NODATA", "EXCEPTION"}
+
+// eventsPageSize is number of notification events read from database at once
+const eventsPageSize = 100
+
+// Stats represents time trigger or triggers spent in each state within report range.
+// Trigger state is the worst of its trigger and metrics states, time before first saved state is not covered.
+// Incident is trigger leaving OK state, it is recovered when trigger returns to OK state.
+// Notifications are trigger and metrics events sent to notifier within report range, inhibited events are counted apart
+type Stats struct {
+	Durations     map[string]int64   `json:"durations"`
+	Percentages   map[string]float64 `json:"percentages"`
+	Covered       int64              `json:"covered"`
+	Incidents     int64              `json:"incidents"`
+	Recovered     int64              `json:"recovered"`
+	MTTR          int64              `json:"mttr"`
+	Notifications int64              `json:"notifications"`
+	Inhibited     int64              `json:"inhibited"`
+	recoveryTime  int64
+}
+
+// TriggerReport represents availability of one trigger
+type TriggerReport struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+	Stats
+}
+
+// TagReport represents availability of all reported triggers having tag
+type TagReport struct {
+	Tag      string `json:"tag"`
+	Triggers int64  `json:"triggers"`
+	Stats
+}
+
+// Report represents triggers and tags availability within time range
+type Report struct {
+	From     int64            `json:"from"`
+	To       int64            `json:"to"`
+	Triggers []*TriggerReport `json:"triggers"`
+	Tags     []*TagReport     `json:"tags"`
+}
+
+// Build computes availability report of triggers having all given tags from trigger state history
+func Build(dataBase moira.Database, tags []string, from, to int64) (*Report, error) {
+	triggerIDs, err := dataBase.GetTriggerCheckIDs(tags, false)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := dataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		From:     from,
+		To:       to,
+		Triggers: make([]*TriggerReport, 0, len(triggers)),
+		Tags:     make([]*TagReport, 0),
+	}
+	tagReports := make(map[string]*TagReport)
+	for _, trigger := range triggers {
+		if trigger == nil {
+			continue
+		}
+		history, err := dataBase.GetTriggerStateHistory(trigger.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		triggerReport := &TriggerReport{
+			ID:    trigger.ID,
+			Name:  trigger.Name,
+			Tags:  trigger.Tags,
+			Stats: getHistoryStats(history, from, to),
+		}
+		if err := triggerReport.addEventsStats(dataBase, trigger.ID, from, to); err != nil {
+			return nil, err
+		}
+		report.Triggers = append(report.Triggers, triggerReport)
+		for _, tag := range trigger.Tags {
+			tagReport, ok := tagReports[tag]
+			if !ok {
+				tagReport = &TagReport{Tag: tag, Stats: newStats()}
+				tagReports[tag] = tagReport
+				report.Tags = append(report.Tags, tagReport)
+			}
+			tagReport.Triggers++
+			tagReport.add(&triggerReport.Stats)
+		}
+	}
+	for _, tagReport := range report.Tags {
+		tagReport.update()
+	}
+	sort.Slice(report.Triggers, func(i, j int) bool { return report.Triggers[i].ID < report.Triggers[j].ID })
+	sort.Slice(report.Tags, func(i, j int) bool { return report.Tags[i].Tag < report.Tags[j].Tag })
+	return report, nil
+}
+
+// WriteCSV writes triggers and tags availability as CSV rows, state columns contain percentage of covered time
+func (report *Report) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	header := append([]string{"type", "id", "name", "covered"}, States...)
+	header = append(header, "incidents", "recovered", "mttr", "notifications", "inhibited")
+	csvWriter.Write(header)
+	for _, triggerReport := range report.Triggers {
+		csvWriter.Write(triggerReport.Stats.getRecord("trigger", triggerReport.ID, triggerReport.Name))
+	}
+	for _, tagReport := range report.Tags {
+		csvWriter.Write(tagReport.Stats.getRecord("tag", tagReport.Tag, tagReport.Tag))
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func newStats() Stats {
+	return Stats{
+		Durations:   make(map[string]int64),
+		Percentages: make(map[string]float64),
+	}
+}
+
+// getHistoryStats computes stats from trigger state snapshots ordered by time, first snapshot can be saved before range start.
+// State and incident started before range start are counted from range start
+func getHistoryStats(history []*moira.TriggerStateSnapshot, from, to int64) Stats {
+	stats := newStats()
+	var state string
+	var stateStart, incidentStart int64
+	for i, snapshot := range history {
+		snapshotState := snapshot.GetWorstState()
+		timestamp := snapshot.Timestamp
+		if timestamp < from {
+			timestamp = from
+		}
+		if i > 0 {
+			stats.Durations[state] += timestamp - stateStart
+		}
+		if snapshotState != "OK" && (i == 0 || state == "OK") {
+			stats.Incidents++
+			incidentStart = timestamp
+		}
+		if snapshotState == "OK" && i > 0 && state != "OK" {
+			stats.Recovered++
+			stats.recoveryTime += timestamp - incidentStart
+		}
+		state = snapshotState
+		stateStart = timestamp
+	}
+	if len(history) > 0 {
+		stats.Durations[state] += to - stateStart
+	}
+	stats.update()
+	return stats
+}
+
+// addEventsStats counts notification events of trigger saved within range, events are read newest first
+func (stats *Stats) addEventsStats(dataBase moira.Database, triggerID string, from, to int64) error {
+	for start := int64(0); ; start += eventsPageSize {
+		events, err := dataBase.GetNotificationEvents(triggerID, start, eventsPageSize-1)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event.Timestamp < from {
+				return nil
+			}
+			if event.Timestamp > to {
+				continue
+			}
+			if event.InhibitedBy != "" {
+				stats.Inhibited++
+			} else {
+				stats.Notifications++
+			}
+		}
+		if len(events) < eventsPageSize {
+			return nil
+		}
+	}
+}
+
+func (stats *Stats) add(other *Stats) {
+	for state, duration := range other.Durations {
+		stats.Durations[state] += duration
+	}
+	stats.Incidents += other.Incidents
+	stats.Recovered += other.Recovered
+	stats.recoveryTime += other.recoveryTime
+	stats.Notifications += other.Notifications
+	stats.Inhibited += other.Inhibited
+}
+
+// update computes covered time, states percentages and mean time to recover from durations and incidents
+func (stats *Stats) update() {
+	stats.Covered = 0
+	for _, duration := range stats.Durations {
+		stats.Covered += duration
+	}
+	for _, state := range States {
+		stats.Percentages[state] = 0
+		if stats.Covered > 0 {
+			stats.Percentages[state] = float64(stats.Durations[state]) * 100 / float64(stats.Covered)
+		}
+	}
+	stats.MTTR = 0
+	if stats.Recovered > 0 {
+		stats.MTTR = stats.recoveryTime / stats.Recovered
+	}
+}
+
+func (stats *Stats) getRecord(recordType, id, name string) []string {
+	record := []string{recordType, id, name, strconv.FormatInt(stats.Covered, 10)}
+	for _, state := range States {
+		record = append(record, fmt.Sprintf("%.3f", stats.Percentages[state]))
+	}
+	return append(record, strconv.FormatInt(stats.Incidents, 10), strconv.FormatInt(stats.Recovered, 10), strconv.FormatInt(stats.MTTR, 10),
+		strconv.FormatInt(stats.Notifications, 10), strconv.FormatInt(stats.Inhibited, 10))
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetHistoryStats(t *testing.T) {
+	Convey("Empty history is not covered", t, func() {
+		stats := getHistoryStats(nil, 1000, 2000)
+		So(stats.Covered, ShouldEqual, 0)
+		So(stats.Percentages["OK"], ShouldEqual, 0)
+		So(stats.Incidents, ShouldEqual, 0)
+	})
+
+	Convey("States durations and incidents", t, func() {
+		history := []*moira.TriggerStateSnapshot{
+			{Timestamp: 900, State: "OK", Metrics: map[string]string{"m1": "ERROR"}},
+			{Timestamp: 1100, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+			{Timestamp: 1500, State: "OK", Metrics: map[string]string{"m1": "WARN"}},
+			{Timestamp: 1600, State: "NODATA", Metrics: map[string]string{"m1": "WARN"}},
+			{Timestamp: 1700, State: "OK", Metrics: map[string]string{"m1": "OK"}},
+			{Timestamp: 1900, State: "EXCEPTION"},
+		}
+		stats := getHistoryStats(history, 1000, 2000)
+		So(stats.Durations, ShouldResemble, map[string]int64{"ERROR": 100, "OK": 600, "WARN": 100, "NODATA": 100, "EXCEPTION": 100})
+		So(stats.Covered, ShouldEqual, 1000)
+		So(stats.Percentages, ShouldResemble, map[string]float64{"OK": 60, "WARN": 10, "ERROR": 10, "NODATA": 10, "EXCEPTION": 10})
+		So(stats.Incidents, ShouldEqual, 3)
+		So(stats.Recovered, ShouldEqual, 2)
+		So(stats.MTTR, ShouldEqual, 150)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggers := []*moira.Trigger{
+		{ID: "t2", Name: "Errors", Tags: []string{"api", "prod"}},
+		{ID: "t1", Name: "Latency", Tags: []string{"api"}},
+		nil,
+	}
+
+	Convey("Build triggers and tags report", t, func() {
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"api"}, false).Return([]string{"t2", "t1", "removed"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"t2", "t1", "removed"}).Return(triggers, nil)
+		dataBase.EXPECT().GetTriggerStateHistory("t2", int64(1000), int64(2000)).Return([]*moira.TriggerStateSnapshot{
+			{Timestamp: 1000, State: "OK"},
+			{Timestamp: 1500, State: "ERROR"},
+			{Timestamp: 1600, State: "OK"},
+		}, nil)
+		dataBase.EXPECT().GetTriggerStateHistory("t1", int64(1000), int64(2000)).Return([]*moira.TriggerStateSnapshot{
+			{Timestamp: 1500, State: "OK"},
+			{Timestamp: 1800, State: "OK", Metrics: map[string]string{"m1": "WARN"}},
+		}, nil)
+		dataBase.EXPECT().GetNotificationEvents("t2", int64(0), int64(eventsPageSize-1)).Return([]*moira.NotificationEvent{
+			{Timestamp: 2100, State: "OK", OldState: "ERROR"},
+			{Timestamp: 1600, State: "OK", OldState: "ERROR"},
+			{Timestamp: 1500, State: "ERROR", OldState: "OK"},
+			{Timestamp: 900, State: "OK", OldState: "NODATA"},
+		}, nil)
+		dataBase.EXPECT().GetNotificationEvents("t1", int64(0), int64(eventsPageSize-1)).Return([]*moira.NotificationEvent{
+			{Timestamp: 1800, State: "WARN", OldState: "OK", Metric: "m1", InhibitedBy: "t2"},
+		}, nil)
+
+		report, err := Build(dataBase, []string{"api"}, 1000, 2000)
+		So(err, ShouldBeNil)
+		So(report.Triggers, ShouldHaveLength, 2)
+		So(report.Triggers[0].ID, ShouldEqual, "t1")
+		So(report.Triggers[0].Covered, ShouldEqual, 500)
+		So(report.Triggers[0].Incidents, ShouldEqual, 1)
+		So(report.Triggers[0].MTTR, ShouldEqual, 0)
+		So(report.Triggers[0].Notifications, ShouldEqual, 0)
+		So(report.Triggers[0].Inhibited, ShouldEqual, 1)
+		So(report.Triggers[1].ID, ShouldEqual, "t2")
+		So(report.Triggers[1].Percentages["ERROR"], ShouldEqual, 10)
+		So(report.Triggers[1].MTTR, ShouldEqual, 100)
+		So(report.Triggers[1].Notifications, ShouldEqual, 2)
+
+		So(report.Tags, ShouldHaveLength, 2)
+		So(report.Tags[0].Tag, ShouldEqual, "api")
+		So(report.Tags[0].Triggers, ShouldEqual, 2)
+		So(report.Tags[0].Covered, ShouldEqual, 1500)
+		So(report.Tags[0].Durations, ShouldResemble, map[string]int64{"OK": 1200, "WARN": 200, "ERROR": 100})
+		So(report.Tags[0].Incidents, ShouldEqual, 2)
+		So(report.Tags[0].Recovered, ShouldEqual, 1)
+		So(report.Tags[0].MTTR, ShouldEqual, 100)
+		So(report.Tags[0].Notifications, ShouldEqual, 2)
+		So(report.Tags[0].Inhibited, ShouldEqual, 1)
+		So(report.Tags[1].Tag, ShouldEqual, "prod")
+		So(report.Tags[1].Triggers, ShouldEqual, 1)
+
+		Convey("Write report as CSV", func() {
+			report.Tags = report.Tags[1:]
+			buffer := &bytes.Buffer{}
+			err := report.WriteCSV(buffer)
+			So(err, ShouldBeNil)
+			So(buffer.String(), ShouldEqual, "type,id,name,covered,OK,WARN,ERROR,NODATA,EXCEPTION,incidents,recovered,mttr,notifications,inhibited\n"+
+				"trigger,t1,Latency,500,60.000,40.000,0.000,0.000,0.000,1,0,0,0,1\n"+
+				"trigger,t2,Errors,1000,90.000,0.000,10.000,0.000,0.000,1,1,100,2,0\n"+
+				"tag,prod,prod,1000,90.000,0.000,10.000,0.000,0.000,1,1,100,2,0\n")
+		})
+	})
+
+	Convey("Get history error", t, func() {
+		expected := fmt.Errorf("oops")
+		dataBase.EXPECT().GetTriggerCheckIDs(nil, false).Return([]string{"t1"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"t1"}).Return(triggers[1:2], nil)
+		dataBase.EXPECT().GetTriggerStateHistory("t1", int64(1000), int64(2000)).Return(nil, expected)
+		report, err := Build(dataBase, nil, 1000, 2000)
+		So(err, ShouldResemble, expected)
+		So(report, ShouldBeNil)
+	})
+
+	Convey("Notification events are read page by page until range start", t, func() {
+		page := make([]*moira.NotificationEvent, 0, eventsPageSize)
+		for i := 0; i < eventsPageSize; i++ {
+			page = append(page, &moira.NotificationEvent{Timestamp: 1500})
+		}
+		dataBase.EXPECT().GetTriggerCheckIDs(nil, false).Return([]string{"t1"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"t1"}).Return(triggers[1:2], nil)
+		dataBase.EXPECT().GetTriggerStateHistory("t1", int64(1000), int64(2000)).Return([]*moira.TriggerStateSnapshot{}, nil)
+		dataBase.EXPECT().GetNotificationEvents("t1", int64(0), int64(eventsPageSize-1)).Return(page, nil)
+		dataBase.EXPECT().GetNotificationEvents("t1", int64(eventsPageSize), int64(eventsPageSize-1)).Return([]*moira.NotificationEvent{
+			{Timestamp: 1200},
+			{Timestamp: 800},
+		}, nil)
+		report, err := Build(dataBase, nil, 1000, 2000)
+		So(err, ShouldBeNil)
+		So(report.Triggers[0].Notifications, ShouldEqual, eventsPageSize+1)
+	})
+}
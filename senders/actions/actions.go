@@ -0,0 +1,232 @@
+package actions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// Names of actions available from notification buttons
+const (
+	Acknowledge = "ack"
+	Maintenance = "maintenance"
+)
+
+const separator = "|"
+
+// maintenanceDurations are durations in seconds offered by maintenance buttons
+var maintenanceDurations = []int64{3600, 86400}
+
+// ErrUnknownUser is returned if messenger user can not be matched to any moira user contact
+type ErrUnknownUser struct {
+	Username string
+}
+
+func (err ErrUnknownUser) Error() string {
+	return fmt.Sprintf("User %s has no contacts in Moira", err.Username)
+}
+
+// ErrNotPermitted is returned if moira user can not change team trigger action is applied to
+type ErrNotPermitted struct {
+	UserLogin string
+	TriggerID string
+}
+
+func (err ErrNotPermitted) Error() string {
+	return fmt.Sprintf("User %s is not permitted to change trigger %s", err.UserLogin, err.TriggerID)
+}
+
+// acknowledgementState is audited state of acknowledgement, it has same fields as acknowledgement set through API
+type acknowledgementState struct {
+	Metrics []string `json:"metrics,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Action is user reaction on notification, sent back by messenger when notification button is pressed
+type Action struct {
+	Name      string
+	TriggerID string
+	Duration  int64
+}
+
+// NotificationActions returns actions offered as buttons under notification,
+// there are no actions for test notifications and notifications about recovery
+func NotificationActions(events moira.NotificationEvents) []Action {
+	if len(events) == 0 || events[0].TriggerID == "" {
+		return nil
+	}
+	state := events.GetSubjectState()
+	if state == "OK" || state == "TEST" {
+		return nil
+	}
+	actions := []Action{{Name: Acknowledge, TriggerID: events[0].TriggerID}}
+	for _, duration := range maintenanceDurations {
+		actions = append(actions, Action{Name: Maintenance, TriggerID: events[0].TriggerID, Duration: duration})
+	}
+	return actions
+}
+
+// String encodes action to button callback data, result fits 64 bytes telegram limits callback data to
+func (action Action) String() string {
+	if action.Name == Maintenance {
+		return strings.Join([]string{action.Name, strconv.FormatInt(action.Duration, 10), action.TriggerID}, separator)
+	}
+	return strings.Join([]string{action.Name, action.TriggerID}, separator)
+}
+
+// Label returns button text of action
+func (action Action) Label() string {
+	if action.Name == Maintenance {
+		if action.Duration%86400 == 0 {
+			return fmt.Sprintf("Maintenance %dd", action.Duration/86400)
+		}
+		return fmt.Sprintf("Maintenance %dh", action.Duration/3600)
+	}
+	return "Acknowledge"
+}
+
+// Parse decodes action from button callback data
+func Parse(data string) (Action, error) {
+	parts := strings.Split(data, separator)
+	switch {
+	case len(parts) == 2 && parts[0] == Acknowledge && parts[1] != "":
+		return Action{Name: Acknowledge, TriggerID: parts[1]}, nil
+	case len(parts) == 3 && parts[0] == Maintenance && parts[2] != "":
+		duration, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || duration <= 0 {
+			return Action{}, fmt.Errorf("Invalid maintenance duration [%s]", parts[1])
+		}
+		return Action{Name: Maintenance, TriggerID: parts[2], Duration: duration}, nil
+	}
+	return Action{}, fmt.Errorf("Unknown action [%s]", data)
+}
+
+// Apply applies action to trigger on behalf of given user and returns text to reply with.
+// Acknowledgement is set to whole trigger, maintenance is set to all trigger metrics.
+// Team trigger can be changed by team editors only and changes are audited, like in API
+func (action Action) Apply(dataBase moira.Database, logger moira.Logger, userLogin string, now int64) (string, error) {
+	if err := checkPermissions(dataBase, action.TriggerID, userLogin); err != nil {
+		return "", err
+	}
+	switch action.Name {
+	case Acknowledge:
+		ack := &moira.Acknowledgement{User: userLogin, Timestamp: now}
+		if err := dataBase.SetTriggerCheckAcknowledgement(action.TriggerID, nil, ack); err != nil {
+			return "", err
+		}
+		addAuditRecord(dataBase, logger, userLogin, moira.AuditEntityAcknowledgement, action.TriggerID, nil, acknowledgementState{}, now)
+		return fmt.Sprintf("Acknowledged by %s", userLogin), nil
+	case Maintenance:
+		lastCheck, err := dataBase.GetTriggerLastCheck(action.TriggerID)
+		if err != nil && err != database.ErrNil {
+			return "", err
+		}
+		before := make(map[string]int64, len(lastCheck.Metrics))
+		maintenance := make(map[string]int64, len(lastCheck.Metrics))
+		for metric, state := range lastCheck.Metrics {
+			before[metric] = state.Maintenance
+			maintenance[metric] = now + action.Duration
+		}
+		if err := dataBase.SetTriggerCheckMetricsMaintenance(action.TriggerID, maintenance); err != nil {
+			return "", err
+		}
+		addAuditRecord(dataBase, logger, userLogin, moira.AuditEntityMaintenance, action.TriggerID, before, maintenance, now)
+		return fmt.Sprintf("%s set by %s", action.Label(), userLogin), nil
+	}
+	return "", fmt.Errorf("Unknown action [%s]", action.Name)
+}
+
+// checkPermissions returns database.ErrNil if trigger does not exist and ErrNotPermitted
+// if trigger is owned by team where user is not editor
+func checkPermissions(dataBase moira.Database, triggerID, userLogin string) error {
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil {
+		return err
+	}
+	allowed, err := hasTeamRole(dataBase, trigger.TeamID, userLogin, moira.TeamRoleEditor)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrNotPermitted{UserLogin: userLogin, TriggerID: triggerID}
+	}
+	return nil
+}
+
+// IsTriggerVisible returns true if trigger is not owned by team or user is member of trigger team, like in API.
+// Checked teams roles are cached in given map by team ID
+func IsTriggerVisible(dataBase moira.Database, trigger *moira.Trigger, userLogin string, teams map[string]bool) (bool, error) {
+	visible, ok := teams[trigger.TeamID]
+	if ok {
+		return visible, nil
+	}
+	visible, err := hasTeamRole(dataBase, trigger.TeamID, userLogin, moira.TeamRoleViewer)
+	if err != nil {
+		return false, err
+	}
+	teams[trigger.TeamID] = visible
+	return visible, nil
+}
+
+// hasTeamRole returns true if object is not owned by team or user has given role in its team
+func hasTeamRole(dataBase moira.Database, teamID, userLogin, role string) (bool, error) {
+	if teamID == "" {
+		return true, nil
+	}
+	team, err := dataBase.GetTeam(teamID)
+	if err != nil && err != database.ErrNil {
+		return false, err
+	}
+	return err == nil && team.HasRole(userLogin, role), nil
+}
+
+// addAuditRecord records change of trigger state made by user, see moira.NewAuditRecord. Change is already saved
+// when it is recorded, so failure to record it is logged and is not reported to user, like in API
+func addAuditRecord(dataBase moira.Database, logger moira.Logger, userLogin, entity, triggerID string, before, after interface{}, now int64) {
+	record, err := moira.NewAuditRecord(userLogin, entity, triggerID, before, after, now)
+	if err == nil {
+		err = dataBase.AddAuditRecord(record)
+	}
+	if err != nil {
+		logger.Errorf("Failed to add audit record of %s %s changed by %s: %s", entity, triggerID, userLogin, err.Error())
+	}
+}
+
+// GetUserLogin returns login of moira user who owns contact of given messenger type with username as value.
+// Contacts with chat id registered by messenger bot for username are matched too
+func GetUserLogin(dataBase moira.Database, messenger, username string) (string, error) {
+	values := map[string]bool{username: true}
+	id, err := dataBase.GetIDByUsername(messenger, username)
+	if err != nil && err != database.ErrNil {
+		return "", err
+	}
+	if id != "" {
+		values[id] = true
+	}
+	contacts, err := dataBase.GetAllContacts()
+	if err != nil {
+		return "", err
+	}
+	for _, contact := range contacts {
+		if contact != nil && contact.Type == messenger && values[contact.Value] && contact.User != "" {
+			return contact.User, nil
+		}
+	}
+	return "", ErrUnknownUser{Username: username}
+}
+
+// Handle matches messenger user to moira login and applies action encoded in callback data on behalf of this user
+func Handle(dataBase moira.Database, logger moira.Logger, messenger, username, data string, now int64) (string, error) {
+	action, err := Parse(data)
+	if err != nil {
+		return "", err
+	}
+	userLogin, err := GetUserLogin(dataBase, messenger, username)
+	if err != nil {
+		return "", err
+	}
+	return action.Apply(dataBase, logger, userLogin, now)
+}
@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+const triggerID = "a3a9a7b8-9c3d-4ad3-bd3c-d4b0e5e6f7a8"
+
+func TestNotificationActions(t *testing.T) {
+	Convey("Bad state notification should have acknowledge and maintenance actions", t, func() {
+		actions := NotificationActions(moira.NotificationEvents{{TriggerID: triggerID, State: "ERROR"}})
+		So(actions, ShouldResemble, []Action{
+			{Name: Acknowledge, TriggerID: triggerID},
+			{Name: Maintenance, TriggerID: triggerID, Duration: 3600},
+			{Name: Maintenance, TriggerID: triggerID, Duration: 86400},
+		})
+		So(actions[1].Label(), ShouldEqual, "Maintenance 1h")
+		So(actions[2].Label(), ShouldEqual, "Maintenance 1d")
+	})
+
+	Convey("Recovery and test notifications should have no actions", t, func() {
+		So(NotificationActions(moira.NotificationEvents{{TriggerID: triggerID, State: "OK"}}), ShouldBeEmpty)
+		So(NotificationActions(moira.NotificationEvents{{State: "TEST"}}), ShouldBeEmpty)
+	})
+}
+
+func TestParse(t *testing.T) {
+	Convey("Encoded actions should be parsed back and fit telegram callback data", t, func() {
+		for _, action := range NotificationActions(moira.NotificationEvents{{TriggerID: triggerID, State: "WARN"}}) {
+			So(len(action.String()), ShouldBeLessThanOrEqualTo, 64)
+			actual, err := Parse(action.String())
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, action)
+		}
+	})
+
+	Convey("Invalid data should not be parsed", t, func() {
+		for _, data := range []string{"", "ack", "ack|", "maintenance|1h|" + triggerID, "maintenance|-1|" + triggerID, "delete|" + triggerID} {
+			_, err := Parse(data)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestHandle(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+	contacts := []*moira.ContactData{
+		{Type: "slack", Value: "@user", User: "slack-user"},
+		{Type: "telegram", Value: "123456", User: "telegram-user"},
+	}
+
+	Convey("Acknowledge by user with contact registered in bot storage", t, func() {
+		dataBase.EXPECT().GetIDByUsername("telegram", "@user").Return("123456", nil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, []string(nil), &moira.Acknowledgement{User: "telegram-user", Timestamp: 100}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(&moira.AuditRecord{
+			Timestamp: 100,
+			User:      "telegram-user",
+			Entity:    moira.AuditEntityAcknowledgement,
+			EntityID:  triggerID,
+			Action:    moira.AuditActionCreate,
+			After:     []byte(`{}`),
+		}).Return(nil)
+		reply, err := Handle(dataBase, logger, "telegram", "@user", "ack|"+triggerID, 100)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Acknowledged by telegram-user")
+	})
+
+	Convey("Maintenance should be set to all trigger metrics", t, func() {
+		dataBase.EXPECT().GetIDByUsername("slack", "@user").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"slack-user": moira.TeamRoleEditor}}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{Metrics: map[string]moira.MetricState{"m1": {Maintenance: 50}, "m2": {}}}, nil)
+		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"m1": 3700, "m2": 3700}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(&moira.AuditRecord{
+			Timestamp: 100,
+			User:      "slack-user",
+			Entity:    moira.AuditEntityMaintenance,
+			EntityID:  triggerID,
+			Action:    moira.AuditActionUpdate,
+			Before:    []byte(`{"m1":50,"m2":0}`),
+			After:     []byte(`{"m1":3700,"m2":3700}`),
+		}).Return(nil)
+		reply, err := Handle(dataBase, logger, "slack", "@user", "maintenance|3600|"+triggerID, 100)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Maintenance 1h set by slack-user")
+	})
+
+	Convey("Unknown user should not be able to apply action", t, func() {
+		dataBase.EXPECT().GetIDByUsername("telegram", "@stranger").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		_, err := Handle(dataBase, logger, "telegram", "@stranger", "ack|"+triggerID, 100)
+		So(err, ShouldResemble, ErrUnknownUser{Username: "@stranger"})
+	})
+
+	Convey("User not in trigger team should not be able to apply action", t, func() {
+		dataBase.EXPECT().GetIDByUsername("slack", "@user").Return("", database.ErrNil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleAdmin}}, nil)
+		_, err := Handle(dataBase, logger, "slack", "@user", "maintenance|3600|"+triggerID, 100)
+		So(err, ShouldResemble, ErrNotPermitted{UserLogin: "slack-user", TriggerID: triggerID})
+		So(err.Error(), ShouldEqual, "User slack-user is not permitted to change trigger "+triggerID)
+	})
+
+	Convey("Team viewer should not be able to apply action", t, func() {
+		dataBase.EXPECT().GetIDByUsername("telegram", "@user").Return("123456", nil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"telegram-user": moira.TeamRoleViewer}}, nil)
+		_, err := Handle(dataBase, logger, "telegram", "@user", "ack|"+triggerID, 100)
+		So(err, ShouldResemble, ErrNotPermitted{UserLogin: "telegram-user", TriggerID: triggerID})
+	})
+
+	Convey("Action should not be applied to removed trigger", t, func() {
+		dataBase.EXPECT().GetIDByUsername("telegram", "@user").Return("123456", nil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+		_, err := Handle(dataBase, logger, "telegram", "@user", "ack|"+triggerID, 100)
+		So(err, ShouldResemble, database.ErrNil)
+	})
+
+	Convey("Audit failure should not fail applied action", t, func() {
+		dataBase.EXPECT().GetIDByUsername("telegram", "@user").Return("123456", nil)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, []string(nil), &moira.Acknowledgement{User: "telegram-user", Timestamp: 100}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Return(fmt.Errorf("ERR unknown command 'XADD'"))
+		reply, err := Handle(dataBase, logger, "telegram", "@user", "ack|"+triggerID, 100)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Acknowledged by telegram-user")
+	})
+
+	Convey("Database error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not get contacts")
+		dataBase.EXPECT().GetIDByUsername("telegram", "@user").Return("123456", nil)
+		dataBase.EXPECT().GetAllContacts().Return(nil, expected)
+		_, err := Handle(dataBase, logger, "telegram", "@user", "ack|"+triggerID, 100)
+		So(err, ShouldResemble, expected)
+	})
+}
@@ -25,7 +25,9 @@ type Sender struct {
 	TemplateFile string
 	log          moira.Logger
 	Template     *template.Template
-	location     *time.Location
+	// DigestTemplate renders message with events of several triggers
+	DigestTemplate *template.Template
+	location       *time.Location
 }
 
 type templateRow struct {
@@ -39,6 +41,15 @@ type templateRow struct {
 	Message    string
 }
 
+type digestTemplateTrigger struct {
+	Name        string
+	Tags        string
+	Link        string
+	Description string
+	Throttled   bool
+	Items       []*templateRow
+}
+
 // Init read yaml config
 func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location) error {
 	sender.setLogger(logger)
@@ -50,6 +61,7 @@ func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger
 	sender.Password = senderSettings["smtp_pass"]
 	sender.Username = senderSettings["smtp_user"]
 	sender.TemplateFile = senderSettings["template_file"]
+	digestTemplateFile := senderSettings["digest_template_file"]
 	sender.location = location
 
 	if sender.Username == "" {
@@ -67,6 +79,14 @@ func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger
 			return err
 		}
 	}
+	if digestTemplateFile == "" {
+		sender.DigestTemplate = template.Must(template.New("digest").Parse(defaultDigestTemplate))
+	} else {
+		var err error
+		if sender.DigestTemplate, err = template.New("digest").ParseFiles(digestTemplateFile); err != nil {
+			return err
+		}
+	}
 
 	t, err := smtp.Dial(fmt.Sprintf("%s:%d", sender.SMTPhost, sender.SMTPport))
 	if err != nil {
@@ -89,9 +109,15 @@ func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger
 
 // SendEvents implements Sender interface Send
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	return sender.dialAndSend(sender.makeMessage(events, contact, trigger, throttled))
+}
 
-	m := sender.makeMessage(events, contact, trigger, throttled)
+// SendDigest implements DigestSender interface and sends events of all triggers in one message
+func (sender *Sender) SendDigest(items []moira.DigestItem, contact moira.ContactData) error {
+	return sender.dialAndSend(sender.makeDigestMessage(items, contact))
+}
 
+func (sender *Sender) dialAndSend(m *gomail.Message) error {
 	d := gomail.Dialer{
 		Host: sender.SMTPhost,
 		Port: int(sender.SMTPport),
@@ -129,30 +155,68 @@ func (sender *Sender) makeMessage(events moira.NotificationEvents, contact moira
 		Items:       make([]*templateRow, 0, len(events)),
 	}
 
-	for _, event := range events {
-		templateData.Items = append(templateData.Items, &templateRow{
-			Metric:     event.Metric,
-			Timestamp:  time.Unix(event.Timestamp, 0).In(sender.location).Format("15:04 02.01.2006"),
-			Oldstate:   event.OldState,
-			State:      event.State,
-			Value:      strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64),
-			WarnValue:  strconv.FormatFloat(trigger.WarnValue, 'f', -1, 64),
-			ErrorValue: strconv.FormatFloat(trigger.ErrorValue, 'f', -1, 64),
-			Message:    moira.UseString(event.Message),
+	templateData.Items = sender.makeTemplateRows(events, trigger)
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", sender.From)
+	m.SetHeader("To", contact.Value)
+	m.SetHeader("Subject", subject)
+	m.AddAlternativeWriter("text/html", func(w io.Writer) error {
+		return sender.Template.Execute(w, templateData)
+	})
+
+	return m
+}
+
+func (sender *Sender) makeDigestMessage(items []moira.DigestItem, contact moira.ContactData) *gomail.Message {
+	events := make(moira.NotificationEvents, 0)
+	templateData := struct {
+		Triggers []*digestTemplateTrigger
+	}{
+		Triggers: make([]*digestTemplateTrigger, 0, len(items)),
+	}
+	for _, item := range items {
+		events = append(events, item.Events...)
+		templateData.Triggers = append(templateData.Triggers, &digestTemplateTrigger{
+			Name:        item.Trigger.Name,
+			Tags:        item.Trigger.GetTags(),
+			Link:        fmt.Sprintf("%s/trigger/%s", sender.FrontURI, item.Trigger.ID),
+			Description: item.Trigger.Desc,
+			Throttled:   item.Throttled,
+			Items:       sender.makeTemplateRows(item.Events, item.Trigger),
 		})
 	}
 
+	subject := fmt.Sprintf("%s Moira digest: %d triggers (%d)", events.GetSubjectState(), len(items), len(events))
+
 	m := gomail.NewMessage()
 	m.SetHeader("From", sender.From)
 	m.SetHeader("To", contact.Value)
 	m.SetHeader("Subject", subject)
 	m.AddAlternativeWriter("text/html", func(w io.Writer) error {
-		return sender.Template.Execute(w, templateData)
+		return sender.DigestTemplate.Execute(w, templateData)
 	})
 
 	return m
 }
 
+func (sender *Sender) makeTemplateRows(events moira.NotificationEvents, trigger moira.TriggerData) []*templateRow {
+	rows := make([]*templateRow, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, &templateRow{
+			Metric:     event.Metric,
+			Timestamp:  time.Unix(event.Timestamp, 0).In(sender.location).Format("15:04 02.01.2006"),
+			Oldstate:   event.OldState,
+			State:      event.State,
+			Value:      strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64),
+			WarnValue:  strconv.FormatFloat(trigger.WarnValue, 'f', -1, 64),
+			ErrorValue: strconv.FormatFloat(trigger.ErrorValue, 'f', -1, 64),
+			Message:    moira.UseString(event.Message),
+		})
+	}
+	return rows
+}
+
 func (sender *Sender) setLogger(logger moira.Logger) {
 	sender.log = logger
 }
@@ -53,6 +53,21 @@ func TestMail(t *testing.T) {
 		So(message.GetHeader("To")[0], ShouldEqual, contact.Value)
 		message.WriteTo(os.Stdout)
 	})
+
+	Convey("Make digest message", t, func() {
+		sender.DigestTemplate = template.Must(template.New("digest").Parse(defaultDigestTemplate))
+		trigger2 := trigger
+		trigger2.ID = "triggerID-0000000000002"
+		trigger2.Name = "test trigger 2"
+		items := []moira.DigestItem{
+			{Trigger: trigger, Events: events[:5]},
+			{Trigger: trigger2, Events: events[5:], Throttled: true},
+		}
+		message := sender.makeDigestMessage(items, contact)
+		So(message.GetHeader("To")[0], ShouldEqual, contact.Value)
+		So(message.GetHeader("Subject")[0], ShouldContainSubstring, "Moira digest: 2 triggers (10)")
+		message.WriteTo(os.Stdout)
+	})
 }
 
 func generateTestEvents(n int, subscriptionID string) chan *moira.NotificationEvent {
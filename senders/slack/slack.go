@@ -7,10 +7,14 @@ import (
 	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/actions"
 
 	"github.com/nlopes/slack"
 )
 
+// actionsCallbackID identifies notification buttons in interactions sent by slack to moira api
+const actionsCallbackID = "moira_actions"
+
 // Sender implements moira sender interface via slack
 type Sender struct {
 	APIToken string
@@ -33,9 +37,61 @@ func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger
 
 // SendEvents implements Sender interface Send
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
-	api := slack.New(sender.APIToken)
+	var message bytes.Buffer
+	icon := sender.writeTriggerEvents(&message, events, trigger, throttled)
+	return sender.postMessage(contact, message.String(), icon, sender.getActionsAttachments(actions.NotificationActions(events), events[0].TriggerID))
+}
+
+// SendDigest implements DigestSender interface and sends events of all triggers in one message
+func (sender *Sender) SendDigest(items []moira.DigestItem, contact moira.ContactData) error {
+	message, icon := sender.buildDigest(items)
+	return sender.postMessage(contact, message, icon, nil)
+}
 
+// buildDigest returns digest message and icon, digest has error icon if any of its triggers has not OK event
+func (sender *Sender) buildDigest(items []moira.DigestItem) (string, string) {
 	var message bytes.Buffer
+	okIcon := fmt.Sprintf("%s/public/fav72_ok.png", sender.FrontURI)
+	icon := okIcon
+	for i, item := range items {
+		if i > 0 {
+			message.WriteString("\n\n")
+		}
+		if triggerIcon := sender.writeTriggerEvents(&message, item.Events, item.Trigger, item.Throttled); triggerIcon != okIcon {
+			icon = triggerIcon
+		}
+	}
+	return message.String(), icon
+}
+
+// getActionsAttachments returns attachment with notification action buttons and link to trigger page
+func (sender *Sender) getActionsAttachments(notificationActions []actions.Action, triggerID string) []slack.Attachment {
+	if len(notificationActions) == 0 {
+		return nil
+	}
+	attachment := slack.Attachment{
+		Fallback:   fmt.Sprintf("%s/trigger/%s", sender.FrontURI, triggerID),
+		CallbackID: actionsCallbackID,
+	}
+	for _, action := range notificationActions {
+		attachment.Actions = append(attachment.Actions, slack.AttachmentAction{
+			Name:  action.Name,
+			Text:  action.Label(),
+			Type:  "button",
+			Value: action.String(),
+		})
+	}
+	attachment.Actions = append(attachment.Actions, slack.AttachmentAction{
+		Name: "open",
+		Text: "Open in Moira",
+		Type: "button",
+		URL:  fmt.Sprintf("%s/trigger/%s", sender.FrontURI, triggerID),
+	})
+	return []slack.Attachment{attachment}
+}
+
+// writeTriggerEvents writes trigger events to message and returns icon for the most critical event state
+func (sender *Sender) writeTriggerEvents(message *bytes.Buffer, events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
 	state := events.GetSubjectState()
 	tags := trigger.GetTags()
 	message.WriteString(fmt.Sprintf("*%s* %s <%s/trigger/%s|%s>\n %s \n```", state, tags, sender.FrontURI, events[0].TriggerID, trigger.Name, trigger.Desc))
@@ -56,16 +112,22 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	if throttled {
 		message.WriteString("\nPlease, *fix your system or tune this trigger* to generate less events.")
 	}
+	return icon
+}
+
+func (sender *Sender) postMessage(contact moira.ContactData, message string, icon string, attachments []slack.Attachment) error {
+	api := slack.New(sender.APIToken)
 
-	sender.log.Debugf("Calling slack with message body %s", message.String())
+	sender.log.Debugf("Calling slack with message body %s", message)
 
 	params := slack.PostMessageParameters{
-		Username: "Moira",
-		IconURL:  icon,
-		Markdown: true,
+		Username:    "Moira",
+		IconURL:     icon,
+		Markdown:    true,
+		Attachments: attachments,
 	}
 
-	_, _, err := api.PostMessage(contact.Value, message.String(), params)
+	_, _, err := api.PostMessage(contact.Value, message, params)
 	if err != nil {
 		return fmt.Errorf("Failed to send message to slack [%s]: %s", contact.Value, err.Error())
 	}
@@ -0,0 +1,36 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestBuildDigest(t *testing.T) {
+	sender := Sender{FrontURI: "http://moira.url", location: time.UTC}
+	value := float64(97)
+	failed := moira.DigestItem{
+		Trigger: moira.TriggerData{ID: "trigger1", Name: "Failed"},
+		Events:  moira.NotificationEvents{{TriggerID: "trigger1", Metric: "metric1", Value: &value, OldState: "OK", State: "ERROR", Timestamp: 1500000000}},
+	}
+	recovered := moira.DigestItem{
+		Trigger: moira.TriggerData{ID: "trigger2", Name: "Recovered"},
+		Events:  moira.NotificationEvents{{TriggerID: "trigger2", Metric: "metric2", Value: &value, OldState: "ERROR", State: "OK", Timestamp: 1500000060}},
+	}
+
+	Convey("Digest of OK events has OK icon", t, func() {
+		message, icon := sender.buildDigest([]moira.DigestItem{recovered})
+		So(icon, ShouldEqual, "http://moira.url/public/fav72_ok.png")
+		So(message, ShouldContainSubstring, "Recovered")
+	})
+
+	Convey("Digest ending with OK item keeps error icon", t, func() {
+		message, icon := sender.buildDigest([]moira.DigestItem{failed, recovered})
+		So(icon, ShouldEqual, "http://moira.url/public/fav72_error.png")
+		So(message, ShouldContainSubstring, "Failed")
+		So(message, ShouldContainSubstring, "Recovered")
+	})
+}
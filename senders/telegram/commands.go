@@ -0,0 +1,274 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/senders/actions"
+)
+
+// commandListLimit limits number of triggers and metrics listed in command reply
+const commandListLimit = 30
+
+const commandsHelp = `Available commands:
+/status [tag] - list triggers in bad state
+/trigger <id> - show trigger metrics states
+/mute <id> <duration> - set maintenance to all trigger metrics, e.g. /mute <id> 2h
+/subscriptions - list subscriptions sending notifications to this chat`
+
+// errUnregisteredChat is replied to commands from chats unknown to bot
+var errUnregisteredChat = fmt.Errorf("This chat is not registered. Send /start to register private chat or any message to register group")
+
+// chatInfo identifies chat command was sent from. Name is "@username" for private chats and title for groups
+type chatInfo struct {
+	ID   string
+	Name string
+}
+
+// isCommand returns true if message is bot command
+func isCommand(text string) bool {
+	return strings.HasPrefix(text, "/")
+}
+
+// executeCommand runs bot command on behalf of moira user owning telegram contact of chat and returns reply text.
+// Returned error is internal one and is not shown to user
+func (sender *Sender) executeCommand(chat chatInfo, text string, now int64) (string, error) {
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return commandsHelp, nil
+	}
+	command := args[0]
+	if index := strings.Index(command, "@"); index != -1 {
+		command = command[:index]
+	}
+	args = args[1:]
+
+	if command == "/help" {
+		return commandsHelp, nil
+	}
+	userLogin, err := sender.authorizeChat(chat)
+	if err != nil {
+		return replyOnError(err)
+	}
+
+	switch command {
+	case "/status":
+		return sender.getStatus(args, userLogin)
+	case "/trigger":
+		if len(args) != 1 {
+			return "Usage: /trigger <id>", nil
+		}
+		return sender.getTriggerState(args[0], userLogin, now)
+	case "/mute":
+		if len(args) != 2 {
+			return "Usage: /mute <id> <duration>", nil
+		}
+		return sender.muteTrigger(args[0], args[1], userLogin, now)
+	case "/subscriptions":
+		return sender.getChatSubscriptions(chat, userLogin)
+	}
+	return fmt.Sprintf("Unknown command %s\n\n%s", command, commandsHelp), nil
+}
+
+// authorizeChat checks that chat is registered by bot before and returns login of moira user owning chat contact
+func (sender *Sender) authorizeChat(chat chatInfo) (string, error) {
+	id, err := sender.DataBase.GetIDByUsername(messenger, chat.Name)
+	if err != nil && err != database.ErrNil {
+		return "", err
+	}
+	if id == "" || id != chat.ID {
+		return "", errUnregisteredChat
+	}
+	return actions.GetUserLogin(sender.DataBase, messenger, chat.Name)
+}
+
+// getStatus lists bad triggers having all given tags, team triggers are listed to team members only
+func (sender *Sender) getStatus(tags []string, userLogin string) (string, error) {
+	triggerIDs, err := sender.DataBase.GetTriggerCheckIDs(tags, true)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(triggerIDs)
+	allTriggers, err := sender.DataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return "", err
+	}
+	teams := make(map[string]bool)
+	triggers := make([]*moira.Trigger, 0, len(allTriggers))
+	for _, trigger := range allTriggers {
+		if trigger == nil {
+			continue
+		}
+		visible, err := actions.IsTriggerVisible(sender.DataBase, trigger, userLogin, teams)
+		if err != nil {
+			return "", err
+		}
+		if visible {
+			triggers = append(triggers, trigger)
+		}
+	}
+	if len(triggers) == 0 {
+		return "All triggers are OK", nil
+	}
+	listed := triggers
+	if len(listed) > commandListLimit {
+		listed = listed[:commandListLimit]
+	}
+
+	var reply bytes.Buffer
+	reply.WriteString(fmt.Sprintf("Triggers in bad state: %d\n", len(triggers)))
+	for _, trigger := range listed {
+		lastCheck, err := sender.DataBase.GetTriggerLastCheck(trigger.ID)
+		if err != nil && err != database.ErrNil {
+			return "", err
+		}
+		state := lastCheck.GetWorstState()
+		reply.WriteString(fmt.Sprintf("\n%s%s %s\n/trigger %s", emojiStates[state], state, trigger.Name, trigger.ID))
+	}
+	if len(triggers) > len(listed) {
+		reply.WriteString(fmt.Sprintf("\n\n...and %d more triggers.", len(triggers)-len(listed)))
+	}
+	return reply.String(), nil
+}
+
+// getTriggerState shows trigger metrics states, team trigger is shown to team members only
+func (sender *Sender) getTriggerState(triggerID, userLogin string, now int64) (string, error) {
+	trigger, err := sender.DataBase.GetTrigger(triggerID)
+	if err != nil {
+		return replyOnError(err)
+	}
+	visible, err := actions.IsTriggerVisible(sender.DataBase, &trigger, userLogin, make(map[string]bool))
+	if err != nil {
+		return "", err
+	}
+	if !visible {
+		return replyOnError(database.ErrNil)
+	}
+	lastCheck, err := sender.DataBase.GetTriggerLastCheck(triggerID)
+	if err != nil && err != database.ErrNil {
+		return "", err
+	}
+
+	var reply bytes.Buffer
+	triggerData := moira.TriggerData{Tags: trigger.Tags}
+	state := lastCheck.GetWorstState()
+	reply.WriteString(fmt.Sprintf("%s%s %s %s\n", emojiStates[state], state, trigger.Name, triggerData.GetTags()))
+	metrics := make([]string, 0, len(lastCheck.Metrics))
+	for metric := range lastCheck.Metrics {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	for i, metric := range metrics {
+		if i == commandListLimit {
+			reply.WriteString(fmt.Sprintf("\n...and %d more metrics.", len(metrics)-commandListLimit))
+			break
+		}
+		state := lastCheck.Metrics[metric]
+		value := strconv.FormatFloat(moira.UseFloat64(state.Value), 'f', -1, 64)
+		reply.WriteString(fmt.Sprintf("\n%s = %s (%s)", metric, value, state.State))
+		if state.Maintenance > now {
+			reply.WriteString(fmt.Sprintf(", muted until %s", time.Unix(state.Maintenance, 0).In(sender.location).Format("02.01 15:04")))
+		}
+	}
+	reply.WriteString(fmt.Sprintf("\n\n%s/trigger/%s", sender.FrontURI, triggerID))
+	return reply.String(), nil
+}
+
+func (sender *Sender) muteTrigger(triggerID, durationString, userLogin string, now int64) (string, error) {
+	duration, err := parseDuration(durationString)
+	if err != nil {
+		return err.Error(), nil
+	}
+	action := actions.Action{Name: actions.Maintenance, TriggerID: triggerID, Duration: int64(duration.Seconds())}
+	if _, err := action.Apply(sender.DataBase, sender.logger, userLogin, now); err != nil {
+		return replyOnError(err)
+	}
+	return fmt.Sprintf("Trigger metrics are muted until %s", time.Unix(now+action.Duration, 0).In(sender.location).Format("02.01 15:04")), nil
+}
+
+func (sender *Sender) getChatSubscriptions(chat chatInfo, userLogin string) (string, error) {
+	contactIDs, err := sender.DataBase.GetUserContactIDs(userLogin)
+	if err != nil {
+		return "", err
+	}
+	contacts, err := sender.DataBase.GetContacts(contactIDs)
+	if err != nil {
+		return "", err
+	}
+	chatContacts := make(map[string]bool)
+	for _, contact := range contacts {
+		if contact != nil && contact.Type == messenger && (contact.Value == chat.Name || contact.Value == chat.ID) {
+			chatContacts[contact.ID] = true
+		}
+	}
+
+	subscriptionIDs, err := sender.DataBase.GetUserSubscriptionIDs(userLogin)
+	if err != nil {
+		return "", err
+	}
+	subscriptions, err := sender.DataBase.GetSubscriptions(subscriptionIDs)
+	if err != nil {
+		return "", err
+	}
+	var reply bytes.Buffer
+	for _, subscription := range subscriptions {
+		if subscription == nil || !hasAnyContact(subscription, chatContacts) {
+			continue
+		}
+		reply.WriteString(fmt.Sprintf("\n%s", strings.Join(subscription.Tags, ", ")))
+		if !subscription.Enabled {
+			reply.WriteString(" (disabled)")
+		}
+	}
+	if reply.Len() == 0 {
+		return "There are no subscriptions for this chat", nil
+	}
+	return "Subscriptions for this chat:" + reply.String(), nil
+}
+
+func hasAnyContact(subscription *moira.SubscriptionData, contactIDs map[string]bool) bool {
+	for _, contactID := range subscription.Contacts {
+		if contactIDs[contactID] {
+			return true
+		}
+	}
+	return false
+}
+
+// replyOnError returns text for errors caused by user input and passes other errors through
+func replyOnError(err error) (string, error) {
+	switch err {
+	case database.ErrNil:
+		return "Trigger not found", nil
+	case errUnregisteredChat:
+		return err.Error(), nil
+	}
+	switch err.(type) {
+	case actions.ErrUnknownUser, actions.ErrNotPermitted:
+		return err.Error(), nil
+	}
+	return "", err
+}
+
+// parseDuration parses positive duration, days are supported in addition to time.ParseDuration units
+func parseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+	if strings.HasSuffix(value, "d") {
+		var days int64
+		days, err = strconv.ParseInt(strings.TrimSuffix(value, "d"), 10, 64)
+		duration = time.Duration(days) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(value)
+	}
+	if err != nil || duration < time.Minute {
+		return 0, fmt.Errorf("Invalid duration %s, use values like 30m, 2h or 1d", value)
+	}
+	return duration, nil
+}
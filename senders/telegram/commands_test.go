@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestExecuteCommand(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+	sender := Sender{DataBase: dataBase, FrontURI: "http://moira", location: time.UTC, logger: logger}
+	chat := chatInfo{ID: "123", Name: "@user"}
+	contacts := []*moira.ContactData{{ID: "contact", Type: messenger, Value: "@user", User: "login"}}
+	var now int64 = 1500000000
+
+	authorize := func() {
+		dataBase.EXPECT().GetIDByUsername(messenger, "@user").Return("123", nil).Times(2)
+		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
+	}
+
+	Convey("Help should be available without authorization", t, func() {
+		reply, err := sender.executeCommand(chat, "/help@moira_bot", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, commandsHelp)
+	})
+
+	Convey("Commands from unregistered chat should be rejected", t, func() {
+		dataBase.EXPECT().GetIDByUsername(messenger, "@user").Return("", database.ErrNil)
+		reply, err := sender.executeCommand(chat, "/status", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, errUnregisteredChat.Error())
+	})
+
+	Convey("Status should list bad triggers", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{"tag"}, true).Return([]string{"trigger"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"trigger"}).Return([]*moira.Trigger{{ID: "trigger", Name: "Trigger"}}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("trigger").Return(moira.CheckData{State: "OK", Metrics: map[string]moira.MetricState{"m1": {State: "ERROR"}}}, nil)
+		reply, err := sender.executeCommand(chat, "/status tag", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Triggers in bad state: 1\n\n"+emojiStates["ERROR"]+"ERROR Trigger\n/trigger trigger")
+	})
+
+	Convey("Status should hide triggers of teams user is not in", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTriggerCheckIDs([]string{}, true).Return([]string{"t1", "t2", "t3"}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"t1", "t2", "t3"}).Return([]*moira.Trigger{
+			{ID: "t1", Name: "Own", TeamID: "team"},
+			{ID: "t2", Name: "Other", TeamID: "other"},
+			{ID: "t3", Name: "Foreign", TeamID: "other"},
+		}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"login": moira.TeamRoleViewer}}, nil)
+		dataBase.EXPECT().GetTeam("other").Return(moira.Team{}, database.ErrNil)
+		dataBase.EXPECT().GetTriggerLastCheck("t1").Return(moira.CheckData{State: "NODATA"}, nil)
+		reply, err := sender.executeCommand(chat, "/status", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Triggers in bad state: 1\n\n"+emojiStates["NODATA"]+"NODATA Own\n/trigger t1")
+	})
+
+	Convey("Trigger of team user is not in should not be shown", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleAdmin}}, nil)
+		reply, err := sender.executeCommand(chat, "/trigger trigger", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Trigger not found")
+	})
+
+	Convey("Mute should set maintenance to trigger metrics", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger"}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("trigger").Return(moira.CheckData{Metrics: map[string]moira.MetricState{"m1": {}}}, nil)
+		dataBase.EXPECT().SetTriggerCheckMetricsMaintenance("trigger", map[string]int64{"m1": now + 7200}).Return(nil)
+		dataBase.EXPECT().AddAuditRecord(gomock.Any()).Do(func(record *moira.AuditRecord) {
+			So(record.Entity, ShouldEqual, moira.AuditEntityMaintenance)
+			So(record.EntityID, ShouldEqual, "trigger")
+		}).Return(nil)
+		reply, err := sender.executeCommand(chat, "/mute trigger 2h", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Trigger metrics are muted until 14.07 04:40")
+	})
+
+	Convey("Mute of unknown trigger", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{}, database.ErrNil)
+		reply, err := sender.executeCommand(chat, "/mute trigger 1d", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Trigger not found")
+	})
+
+	Convey("Mute of trigger owned by team user is not in", t, func() {
+		authorize()
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{ID: "trigger", TeamID: "team"}, nil)
+		dataBase.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Members: map[string]string{"other": moira.TeamRoleEditor}}, nil)
+		reply, err := sender.executeCommand(chat, "/mute trigger 1d", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "User login is not permitted to change trigger trigger")
+	})
+
+	Convey("Subscriptions should be filtered by chat contacts", t, func() {
+		authorize()
+		dataBase.EXPECT().GetUserContactIDs("login").Return([]string{"contact", "other"}, nil)
+		dataBase.EXPECT().GetContacts([]string{"contact", "other"}).Return(append(contacts, &moira.ContactData{ID: "other", Type: "mail"}), nil)
+		dataBase.EXPECT().GetUserSubscriptionIDs("login").Return([]string{"s1", "s2"}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{"s1", "s2"}).Return([]*moira.SubscriptionData{
+			{ID: "s1", Contacts: []string{"contact"}, Tags: []string{"a", "b"}},
+			{ID: "s2", Contacts: []string{"other"}, Tags: []string{"c"}, Enabled: true},
+		}, nil)
+		reply, err := sender.executeCommand(chat, "/subscriptions", now)
+		So(err, ShouldBeNil)
+		So(reply, ShouldEqual, "Subscriptions for this chat:\na, b (disabled)")
+	})
+}
+
+func TestParseDuration(t *testing.T) {
+	Convey("Durations", t, func() {
+		duration, err := parseDuration("1d")
+		So(err, ShouldBeNil)
+		So(duration, ShouldEqual, 24*time.Hour)
+		duration, err = parseDuration("30m")
+		So(err, ShouldBeNil)
+		So(duration, ShouldEqual, 30*time.Minute)
+		for _, value := range []string{"", "d", "10s", "-1h", "forever"} {
+			_, err = parseDuration(value)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tucnak/telebot"
+
+	"github.com/moira-alert/moira/senders/actions"
+)
+
+// handleCallback handles notification buttons pressing and applies chosen action on behalf of moira user owning telegram contact
+func (sender *Sender) handleCallback(callback *telebot.Callback) error {
+	reply, err := actions.Handle(sender.DataBase, sender.logger, messenger, "@"+callback.Sender.Username, callback.Data, time.Now().Unix())
+	if err != nil {
+		// internal error is returned to be logged and is not shown to user
+		if reply, err = replyOnError(err); err != nil {
+			reply = "Failed to apply action, try again later"
+		}
+		if respondErr := sender.bot.Respond(callback, &telebot.CallbackResponse{Text: reply}); respondErr != nil {
+			return respondErr
+		}
+		return err
+	}
+	if err := sender.bot.Respond(callback, &telebot.CallbackResponse{Text: reply}); err != nil {
+		return err
+	}
+	if callback.Message != nil {
+		_, err = sender.bot.Send(callback.Message.Chat, reply)
+	}
+	return err
+}
+
+// getReplyMarkup returns inline keyboard with notification action buttons and link to trigger page
+func (sender *Sender) getReplyMarkup(notificationActions []actions.Action, triggerID string) *telebot.ReplyMarkup {
+	if len(notificationActions) == 0 {
+		return nil
+	}
+	row := make([]telebot.InlineButton, 0, len(notificationActions)+1)
+	for _, action := range notificationActions {
+		row = append(row, telebot.InlineButton{Text: action.Label(), Data: action.String()})
+	}
+	row = append(row, telebot.InlineButton{Text: "Open in Moira", URL: fmt.Sprintf("%s/trigger/%s", sender.FrontURI, triggerID)})
+	return &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{row}}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tucnak/telebot"
+
+	"github.com/moira-alert/moira/database"
 )
 
 // handleMessage handles incoming messages to start sending events to subscribers chats
@@ -28,17 +31,41 @@ func (sender *Sender) handleMessage(message *telebot.Message) error {
 			sender.bot.Send(message.Chat, fmt.Sprintf("Okay, %s, your id is %s", userTitle, id))
 		}
 	case chatType == "supergroup" || chatType == "group":
-		uid, _ := sender.DataBase.GetIDByUsername(messenger, title)
-		if uid == "" {
-			sender.bot.Send(message.Chat, fmt.Sprintf("Hi, all!\nI will send alerts in this group (%s).", title))
+		// Commands are authorized by group registered before and never register it, known title is not moved
+		// to another chat, so group renamed to title of other group can not act on its behalf
+		if isCommand(message.Text) {
+			return sender.replyCommand(message.Chat, chatInfo{ID: id, Name: title}, message.Text)
 		}
-		fmt.Println(chatType, title)
-		err = sender.DataBase.SetUsernameID(messenger, title, id)
-		if err != nil {
+		uid, err := sender.DataBase.GetIDByUsername(messenger, title)
+		if err != nil && err != database.ErrNil {
 			return err
 		}
+		switch uid {
+		case "":
+			if err = sender.DataBase.SetUsernameID(messenger, title, id); err != nil {
+				return err
+			}
+			sender.bot.Send(message.Chat, fmt.Sprintf("Hi, all!\nI will send alerts in this group (%s).", title))
+		case id:
+		default:
+			sender.bot.Send(message.Chat, fmt.Sprintf("Group %s is already registered by another chat. Rename this group to register it.", title))
+		}
+		return nil
+	case isCommand(message.Text):
+		return sender.replyCommand(message.Chat, chatInfo{ID: id, Name: "@" + username}, message.Text)
 	default:
 		sender.bot.Send(message.Chat, "I don't understand you :(")
 	}
 	return err
 }
+
+// replyCommand executes bot command and sends result to chat
+func (sender *Sender) replyCommand(chat *telebot.Chat, info chatInfo, text string) error {
+	reply, err := sender.executeCommand(info, text, time.Now().Unix())
+	if err != nil {
+		sender.bot.Send(chat, "Failed to execute command, try again later")
+		return err
+	}
+	_, err = sender.bot.Send(chat, reply)
+	return err
+}
@@ -59,6 +59,11 @@ func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger
 			sender.logger.Errorf("Error handling incoming message: %s", err.Error())
 		}
 	})
+	sender.bot.Handle(telebot.OnCallback, func(callback *telebot.Callback) {
+		if err := sender.handleCallback(callback); err != nil {
+			sender.logger.Errorf("Error handling callback: %s", err.Error())
+		}
+	})
 
 	err = sender.runTelebot()
 	if err != nil {
@@ -6,7 +6,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/tucnak/telebot"
+
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/actions"
 )
 
 // SendEvents implements Sender interface Send
@@ -48,14 +51,15 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 
 	sender.logger.Debugf("Calling telegram api with chat_id %s and message body %s", contact.Value, message.String())
 
-	if err := sender.talk(contact.Value, message.String()); err != nil {
+	markup := sender.getReplyMarkup(actions.NotificationActions(events), events[0].TriggerID)
+	if err := sender.talk(contact.Value, message.String(), markup); err != nil {
 		return fmt.Errorf("Failed to send message to telegram contact %s: %s. ", contact.Value, err)
 	}
 	return nil
 }
 
 // talk processes one talk
-func (sender *Sender) talk(username, message string) error {
+func (sender *Sender) talk(username, message string, markup *telebot.ReplyMarkup) error {
 	var err error
 	uid, err := sender.DataBase.GetIDByUsername(messenger, username)
 	if err != nil {
@@ -65,7 +69,11 @@ func (sender *Sender) talk(username, message string) error {
 	if err != nil {
 		return fmt.Errorf("can't find recepient %s: %s", uid, err.Error())
 	}
-	_, err = sender.bot.Send(chat, message)
+	if markup != nil {
+		_, err = sender.bot.Send(chat, message, markup)
+	} else {
+		_, err = sender.bot.Send(chat, message)
+	}
 	if err != nil {
 		return fmt.Errorf("can't send message [%s] to %s: %s", message, uid, err.Error())
 	}
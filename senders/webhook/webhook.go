@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gosexy/to"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultContentType = "application/json"
+	headerPrefix       = "header_"
+	// maxResponseSize limits part of response body included to error message
+	maxResponseSize = 1024
+)
+
+// defaultBodyTemplate renders the same JSON document as script sender writes to stdin
+const defaultBodyTemplate = "{{ json . }}"
+
+// Sender implements moira sender interface via HTTP requests to contact url
+type Sender struct {
+	FrontURI    string
+	ContentType string
+	Headers     map[string]string
+	User        string
+	Password    string
+	Token       string
+	Template    *template.Template
+	client      *http.Client
+	log         moira.Logger
+}
+
+// payload is data passed to body template, digest payload has only Digest, Contact and Timestamp set
+type payload struct {
+	Events     moira.NotificationEvents `json:"events,omitempty"`
+	Trigger    moira.TriggerData        `json:"trigger"`
+	Contact    moira.ContactData        `json:"contact"`
+	Throttled  bool                     `json:"throttled"`
+	Timestamp  int64                    `json:"timestamp"`
+	TriggerURI string                   `json:"trigger_uri,omitempty"`
+	Digest     []moira.DigestItem       `json:"digest,omitempty"`
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		result, err := json.Marshal(value)
+		return string(result), err
+	},
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location) error {
+	sender.log = logger
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.User = senderSettings["user"]
+	sender.Password = senderSettings["password"]
+	sender.Token = senderSettings["token"]
+	if sender.Token != "" && sender.User != "" {
+		return fmt.Errorf("Only one of basic auth user and bearer token can be set")
+	}
+
+	sender.ContentType = senderSettings["content_type"]
+	if sender.ContentType == "" {
+		sender.ContentType = defaultContentType
+	}
+	sender.Headers = make(map[string]string)
+	for key, value := range senderSettings {
+		if strings.HasPrefix(key, headerPrefix) && len(key) > len(headerPrefix) {
+			sender.Headers[key[len(headerPrefix):]] = value
+		}
+	}
+
+	timeout := defaultTimeout
+	if senderSettings["timeout"] != "" {
+		timeout = to.Duration(senderSettings["timeout"])
+		if timeout <= 0 {
+			return fmt.Errorf("Invalid timeout [%s]", senderSettings["timeout"])
+		}
+	}
+	sender.client = &http.Client{Timeout: timeout}
+
+	var err error
+	switch {
+	case senderSettings["body_template"] != "":
+		sender.Template, err = template.New("webhook").Funcs(templateFuncs).Parse(senderSettings["body_template"])
+	case senderSettings["body_template_file"] != "":
+		var templateBytes []byte
+		if templateBytes, err = ioutil.ReadFile(senderSettings["body_template_file"]); err == nil {
+			sender.Template, err = template.New("webhook").Funcs(templateFuncs).Parse(string(templateBytes))
+		}
+	default:
+		sender.Template, err = template.New("webhook").Funcs(templateFuncs).Parse(defaultBodyTemplate)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to parse body template: %s", err.Error())
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	return sender.send(contact, &payload{
+		Events:     events,
+		Trigger:    trigger,
+		Contact:    contact,
+		Throttled:  throttled,
+		Timestamp:  time.Now().Unix(),
+		TriggerURI: fmt.Sprintf("%s/trigger/%s", sender.FrontURI, trigger.ID),
+	})
+}
+
+// SendDigest implements DigestSender interface and sends events of all triggers in one request
+func (sender *Sender) SendDigest(items []moira.DigestItem, contact moira.ContactData) error {
+	return sender.send(contact, &payload{
+		Contact:   contact,
+		Timestamp: time.Now().Unix(),
+		Digest:    items,
+	})
+}
+
+func (sender *Sender) send(contact moira.ContactData, data *payload) error {
+	requestURL, err := url.Parse(contact.Value)
+	if err != nil || (requestURL.Scheme != "http" && requestURL.Scheme != "https") || requestURL.Host == "" {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("Invalid webhook url [%s]", contact.Value))
+	}
+
+	body, err := sender.buildBody(data)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, requestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return moira.NewSenderBrokenContactError(err)
+	}
+	request.Header.Set("Content-Type", sender.ContentType)
+	request.Header.Set("User-Agent", "Moira")
+	for name, value := range sender.Headers {
+		request.Header.Set(name, value)
+	}
+	if sender.User != "" {
+		request.SetBasicAuth(sender.User, sender.Password)
+	}
+	if sender.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+sender.Token)
+	}
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("Failed to send webhook request to [%s]: %s", contact.Value, err.Error())
+	}
+	defer response.Body.Close()
+	responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("Webhook [%s] responded with %s: %s", contact.Value, response.Status, string(responseBody))
+	if isRetryableStatus(response.StatusCode) {
+		return err
+	}
+	return moira.NewSenderBrokenContactError(err)
+}
+
+func (sender *Sender) buildBody(data *payload) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := sender.Template.Execute(&buffer, data); err != nil {
+		return nil, fmt.Errorf("Failed to execute body template: %s", err.Error())
+	}
+	return buffer.Bytes(), nil
+}
+
+// isRetryableStatus returns false for client errors, which will not disappear on resend.
+// Request timeout and rate limiting are client errors but can be retried
+func isRetryableStatus(statusCode int) bool {
+	if statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return statusCode < 400 || statusCode >= 500
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+var (
+	trigger = moira.TriggerData{
+		ID:   "triggerID-0000000000001",
+		Name: "test trigger 1",
+		Tags: []string{"test-tag-1"},
+	}
+	events = moira.NotificationEvents{
+		{
+			Timestamp: 1234567890,
+			Metric:    "test.metric.1",
+			State:     "ERROR",
+			OldState:  "OK",
+			TriggerID: "triggerID-0000000000001",
+		},
+	}
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.GetLogger("webhook")
+	location, _ := time.LoadLocation("UTC")
+
+	Convey("Init with default settings", t, func() {
+		sender := Sender{}
+		err := sender.Init(map[string]string{"type": "webhook", "header_X-Api-Key": "secret"}, logger, location)
+		So(err, ShouldBeNil)
+		So(sender.ContentType, ShouldEqual, defaultContentType)
+		So(sender.Headers, ShouldResemble, map[string]string{"X-Api-Key": "secret"})
+		So(sender.client.Timeout, ShouldEqual, defaultTimeout)
+	})
+
+	Convey("Init with invalid settings", t, func() {
+		sender := Sender{}
+		So(sender.Init(map[string]string{"user": "user", "token": "token"}, logger, location), ShouldNotBeNil)
+		So(sender.Init(map[string]string{"timeout": "sometimes"}, logger, location), ShouldNotBeNil)
+		So(sender.Init(map[string]string{"body_template": "{{ .Events"}, logger, location), ShouldNotBeNil)
+		So(sender.Init(map[string]string{"body_template_file": "/nonexistent/template"}, logger, location), ShouldNotBeNil)
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("webhook")
+	location, _ := time.LoadLocation("UTC")
+	requests := make(chan receivedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		requests <- receivedRequest{header: request.Header, body: body}
+		status := http.StatusOK
+		if request.URL.Query().Get("status") != "" {
+			status, _ = strconv.Atoi(request.URL.Query().Get("status"))
+		}
+		writer.WriteHeader(status)
+		writer.Write([]byte("response"))
+	}))
+	defer server.Close()
+	contact := moira.ContactData{ID: "contactID", Type: "webhook", Value: server.URL + "/hook", User: "user"}
+
+	Convey("Default body is JSON with all notification data", t, func() {
+		sender := Sender{}
+		So(sender.Init(map[string]string{"front_uri": "http://moira", "token": "secret"}, logger, location), ShouldBeNil)
+		So(sender.SendEvents(events, contact, trigger, true), ShouldBeNil)
+
+		request := <-requests
+		So(request.header.Get("Content-Type"), ShouldEqual, "application/json")
+		So(request.header.Get("Authorization"), ShouldEqual, "Bearer secret")
+		var body payload
+		So(json.Unmarshal(request.body, &body), ShouldBeNil)
+		So(body.Events, ShouldResemble, events)
+		So(body.Trigger, ShouldResemble, trigger)
+		So(body.Contact, ShouldResemble, contact)
+		So(body.Throttled, ShouldBeTrue)
+		So(body.TriggerURI, ShouldEqual, "http://moira/trigger/triggerID-0000000000001")
+	})
+
+	Convey("Custom template, headers and basic auth", t, func() {
+		sender := Sender{}
+		settings := map[string]string{
+			"body_template":   `{"text": "{{ .Trigger.Name }}: {{ range .Events }}{{ .Metric }} {{ .OldState }}->{{ .State }}{{ end }}", "tags": {{ json .Trigger.Tags }}}`,
+			"content_type":    "application/vnd.test+json",
+			"header_X-Source": "moira",
+			"user":            "login",
+			"password":        "pass",
+		}
+		So(sender.Init(settings, logger, location), ShouldBeNil)
+		So(sender.SendEvents(events, contact, trigger, false), ShouldBeNil)
+
+		request := <-requests
+		So(string(request.body), ShouldEqual, `{"text": "test trigger 1: test.metric.1 OK->ERROR", "tags": ["test-tag-1"]}`)
+		So(request.header.Get("Content-Type"), ShouldEqual, "application/vnd.test+json")
+		So(request.header.Get("X-Source"), ShouldEqual, "moira")
+		So(request.header.Get("Authorization"), ShouldEqual, "Basic bG9naW46cGFzcw==")
+	})
+
+	Convey("Digest body contains events of all triggers", t, func() {
+		sender := Sender{}
+		So(sender.Init(map[string]string{}, logger, location), ShouldBeNil)
+		items := []moira.DigestItem{
+			{Trigger: trigger, Events: events},
+			{Trigger: moira.TriggerData{ID: "triggerID-0000000000002"}, Events: events, Throttled: true},
+		}
+		So(sender.SendDigest(items, contact), ShouldBeNil)
+
+		request := <-requests
+		var body payload
+		So(json.Unmarshal(request.body, &body), ShouldBeNil)
+		So(body.Digest, ShouldResemble, items)
+		So(body.Contact, ShouldResemble, contact)
+		So(body.Events, ShouldBeEmpty)
+	})
+
+	Convey("Errors", t, func() {
+		sender := Sender{}
+		So(sender.Init(map[string]string{}, logger, location), ShouldBeNil)
+
+		Convey("Invalid url should not be retried", func() {
+			err := sender.SendEvents(events, moira.ContactData{Value: "not a url"}, trigger, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+
+		Convey("Client error should not be retried", func() {
+			err := sender.SendEvents(events, contactWithStatus(contact, http.StatusNotFound), trigger, false)
+			<-requests
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			So(err.Error(), ShouldContainSubstring, "404")
+			So(err.Error(), ShouldContainSubstring, "response")
+		})
+
+		Convey("Server error and rate limiting should be retried", func() {
+			for _, status := range []int{http.StatusInternalServerError, http.StatusTooManyRequests} {
+				err := sender.SendEvents(events, contactWithStatus(contact, status), trigger, false)
+				<-requests
+				So(err, ShouldNotBeNil)
+				_, brokenContact := err.(moira.SenderBrokenContactError)
+				So(brokenContact, ShouldBeFalse)
+			}
+		})
+	})
+}
+
+func contactWithStatus(contact moira.ContactData, status int) moira.ContactData {
+	contact.Value = contact.Value + "?status=" + strconv.Itoa(status)
+	return contact
+}
@@ -0,0 +1,37 @@
+package target
+
+import (
+	"github.com/go-graphite/carbonapi/expr"
+
+	"github.com/moira-alert/moira/remote"
+)
+
+// EvaluateRemoteTarget evaluates target using graphite render API instead of metrics stored in DB.
+// Remote timeseries do not refer to any local patterns and metrics
+func EvaluateRemoteTarget(config *remote.Config, target string, from int64, until int64) (*EvaluationResult, error) {
+	series, err := remote.Fetch(config, target, from, until)
+	if err != nil {
+		return nil, err
+	}
+	result := &EvaluationResult{
+		TimeSeries: make([]*TimeSeries, 0, len(series)),
+		Patterns:   make([]string, 0),
+		Metrics:    make([]string, 0),
+	}
+	for _, item := range series {
+		metricData := createMetricData(item.Name, item.StartTime, item.StopTime, item.StepTime, item.Values)
+		result.TimeSeries = append(result.TimeSeries, &TimeSeries{MetricData: *metricData})
+	}
+	return result, nil
+}
+
+// ValidateRemoteTarget checks syntax of target evaluated by remote graphite
+func ValidateRemoteTarget(target string) error {
+	if _, _, err := expr.ParseExpr(target); err != nil {
+		return ErrParseExpr{
+			internalError: err,
+			target:        target,
+		}
+	}
+	return nil
+}